@@ -0,0 +1,70 @@
+package astql
+
+import (
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// AnalysisReport describes the shape of a query without rendering it.
+// It is intended for guardrails such as "no DELETE without WHERE" in tests and CI.
+type AnalysisReport struct {
+	Operation types.Operation
+	// TableCount counts the target, joined tables, and tables referenced by subqueries.
+	// A derived table counts once for itself plus the tables inside it.
+	TableCount int
+	// JoinCount counts JOIN clauses on the outer query only.
+	JoinCount int
+	// SubqueryDepth is the deepest subquery nesting level (0 when there are none).
+	SubqueryDepth int
+	// HasWhere reports whether the outer query has a WHERE clause.
+	HasWhere bool
+	// UnfilteredDML is true for UPDATE or DELETE without a WHERE clause,
+	// and for every TRUNCATE.
+	UnfilteredDML bool
+	// LimitWithoutOrderBy is true when LIMIT or OFFSET is set without ORDER BY,
+	// which makes the returned rows nondeterministic.
+	LimitWithoutOrderBy bool
+}
+
+// Analyze returns a report describing the query shape.
+// It is a read-only traversal and does not require a dialect or a valid AST.
+func (b *Builder) Analyze() AnalysisReport {
+	ast := b.ast
+	tables, depth := analyzeTables(ast, 0)
+
+	report := AnalysisReport{
+		Operation:     ast.Operation,
+		TableCount:    tables,
+		JoinCount:     len(ast.Joins),
+		SubqueryDepth: depth,
+		HasWhere:      ast.WhereClause != nil,
+	}
+
+	switch ast.Operation {
+	case types.OpUpdate, types.OpDelete:
+		report.UnfilteredDML = ast.WhereClause == nil
+	case types.OpTruncate:
+		report.UnfilteredDML = true
+	}
+
+	if (ast.Limit != nil || ast.Offset != nil) && len(ast.Ordering) == 0 {
+		report.LimitWithoutOrderBy = true
+	}
+
+	return report
+}
+
+// analyzeTables counts tables in the AST and its subqueries, returning the
+// table count and the deepest subquery level reached below depth.
+func analyzeTables(ast *types.AST, depth int) (tables int, maxDepth int) {
+	tables = 1 + len(ast.Joins)
+	maxDepth = depth
+
+	for _, sub := range ast.Subqueries() {
+		subTables, subDepth := analyzeTables(sub, depth+1)
+		tables += subTables
+		if subDepth > maxDepth {
+			maxDepth = subDepth
+		}
+	}
+	return tables, maxDepth
+}
@@ -0,0 +1,150 @@
+package astql_test
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func TestAnalyze_SimpleSelect(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	report := astql.Select(instance.T("users")).
+		Fields(instance.F("id")).
+		Analyze()
+
+	if report.Operation != astql.OpSelect {
+		t.Errorf("Operation = %s, want SELECT", report.Operation)
+	}
+	if report.TableCount != 1 {
+		t.Errorf("TableCount = %d, want 1", report.TableCount)
+	}
+	if report.JoinCount != 0 {
+		t.Errorf("JoinCount = %d, want 0", report.JoinCount)
+	}
+	if report.SubqueryDepth != 0 {
+		t.Errorf("SubqueryDepth = %d, want 0", report.SubqueryDepth)
+	}
+	if report.HasWhere {
+		t.Error("HasWhere should be false")
+	}
+	if report.UnfilteredDML {
+		t.Error("UnfilteredDML should be false for SELECT")
+	}
+}
+
+func TestAnalyze_JoinsAndSubqueries(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	inner := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(astql.CSub(instance.F("id"), astql.IN, astql.Sub(
+			astql.Select(instance.T("posts")).Fields(instance.F("id")),
+		)))
+
+	report := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("id"), "u")).
+		InnerJoin(
+			instance.T("posts", "p"),
+			astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+		).
+		Where(astql.CSub(instance.F("id"), astql.IN, astql.Sub(inner))).
+		Analyze()
+
+	if report.TableCount != 4 {
+		t.Errorf("TableCount = %d, want 4", report.TableCount)
+	}
+	if report.JoinCount != 1 {
+		t.Errorf("JoinCount = %d, want 1", report.JoinCount)
+	}
+	if report.SubqueryDepth != 2 {
+		t.Errorf("SubqueryDepth = %d, want 2", report.SubqueryDepth)
+	}
+	if !report.HasWhere {
+		t.Error("HasWhere should be true")
+	}
+}
+
+func TestAnalyze_SubqueriesOutsideWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	vm := instance.ValueMap()
+	vm[instance.F("title")] = instance.P("title")
+	insert := astql.Insert(instance.T("posts")).
+		Values(vm).
+		ValueSubquery(instance.F("user_id"), astql.Sub(astql.Select(instance.T("users")).Fields(instance.F("id")))).
+		Analyze()
+	if insert.TableCount != 2 || insert.SubqueryDepth != 1 {
+		t.Errorf("INSERT TableCount, SubqueryDepth = %d, %d, want 2, 1", insert.TableCount, insert.SubqueryDepth)
+	}
+
+	latest := astql.Select(instance.T("posts")).
+		Fields(instance.F("id"), instance.F("title")).
+		Where(instance.C(instance.F("user_id"), astql.EQ, instance.P("user_id")))
+	update := astql.Update(instance.T("users")).
+		SetRow([]types.Field{instance.F("age"), instance.F("username")}, latest).
+		Analyze()
+	if update.TableCount != 2 || update.SubqueryDepth != 1 {
+		t.Errorf("UPDATE TableCount, SubqueryDepth = %d, %d, want 2, 1", update.TableCount, update.SubqueryDepth)
+	}
+
+	authors := astql.Sub(astql.Select(instance.T("posts")).Fields(instance.F("user_id")))
+	selected := astql.Select(instance.T("users")).
+		SelectExpr(astql.Case().
+			When(astql.CSub(instance.F("id"), astql.IN, authors), instance.P("author")).
+			Else(instance.P("reader")).
+			As("role").
+			Build()).
+		Analyze()
+	if selected.TableCount != 2 || selected.SubqueryDepth != 1 {
+		t.Errorf("CASE TableCount, SubqueryDepth = %d, %d, want 2, 1", selected.TableCount, selected.SubqueryDepth)
+	}
+}
+
+func TestAnalyze_DeleteWithoutWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	report := astql.Delete(instance.T("users")).Analyze()
+	if !report.UnfilteredDML {
+		t.Error("UnfilteredDML should be true for DELETE without WHERE")
+	}
+
+	report = astql.Delete(instance.T("users")).
+		Where(instance.C(instance.F("id"), "=", instance.P("id"))).
+		Analyze()
+	if report.UnfilteredDML {
+		t.Error("UnfilteredDML should be false for DELETE with WHERE")
+	}
+}
+
+func TestAnalyze_UpdateWithoutWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	report := astql.Update(instance.T("users")).
+		Set(instance.F("username"), instance.P("name")).
+		Analyze()
+	if report.Operation != astql.OpUpdate {
+		t.Errorf("Operation = %s, want UPDATE", report.Operation)
+	}
+	if !report.UnfilteredDML {
+		t.Error("UnfilteredDML should be true for UPDATE without WHERE")
+	}
+}
+
+func TestAnalyze_LimitWithoutOrderBy(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	report := astql.Select(instance.T("users")).Limit(10).Analyze()
+	if !report.LimitWithoutOrderBy {
+		t.Error("LimitWithoutOrderBy should be true")
+	}
+
+	report = astql.Select(instance.T("users")).
+		OrderBy(instance.F("id"), astql.ASC).
+		Limit(10).
+		Analyze()
+	if report.LimitWithoutOrderBy {
+		t.Error("LimitWithoutOrderBy should be false when ORDER BY is set")
+	}
+}
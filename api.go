@@ -69,11 +69,12 @@ type Operation = types.Operation
 
 // Re-export operation constants for public API.
 const (
-	OpSelect = types.OpSelect
-	OpInsert = types.OpInsert
-	OpUpdate = types.OpUpdate
-	OpDelete = types.OpDelete
-	OpCount  = types.OpCount
+	OpSelect   = types.OpSelect
+	OpInsert   = types.OpInsert
+	OpUpdate   = types.OpUpdate
+	OpDelete   = types.OpDelete
+	OpCount    = types.OpCount
+	OpTruncate = types.OpTruncate
 )
 
 // Direction represents sort direction.
@@ -94,6 +95,17 @@ const (
 	NullsLast  = types.NullsLast
 )
 
+// GroupByMode represents the grouping form used by a GROUP BY clause.
+type GroupByMode = types.GroupByMode
+
+// Re-export GROUP BY mode constants for public API.
+const (
+	GroupPlain  = types.GroupPlain
+	GroupRollup = types.GroupRollup
+	GroupCube   = types.GroupCube
+	GroupSets   = types.GroupSets
+)
+
 // Operator represents SQL comparison operators.
 type Operator = types.Operator
 
@@ -119,6 +131,10 @@ const (
 	EXISTS    = types.EXISTS
 	NotExists = types.NotExists
 
+	// NULL-safe comparison operators.
+	IsDistinctFrom    = types.IsDistinctFrom
+	IsNotDistinctFrom = types.IsNotDistinctFrom
+
 	// Regex operators (PostgreSQL).
 	RegexMatch     = types.RegexMatch
 	RegexIMatch    = types.RegexIMatch
@@ -137,6 +153,16 @@ const (
 	VectorL1Distance     = types.VectorL1Distance
 )
 
+// Quantifier represents an ANY/ALL quantified subquery comparison.
+type Quantifier = types.Quantifier
+
+// Re-export quantifier constants for public API.
+const (
+	QuantNone = types.QuantNone
+	QuantAny  = types.QuantAny
+	QuantAll  = types.QuantAll
+)
+
 // ConditionItem represents either a single condition or a group of conditions.
 type ConditionItem = types.ConditionItem
 
@@ -147,10 +173,40 @@ type AggregateCondition = types.AggregateCondition
 // BetweenCondition represents a BETWEEN condition with two bounds.
 type BetweenCondition = types.BetweenCondition
 
+// TupleCondition represents a row value comparison: (a, b) = (:p1, :p2) or (a, b) IN (subquery).
+type TupleCondition = types.TupleCondition
+
 // BinaryExpression represents a binary operation between a field and a parameter.
 // Used for expressions like vector distance calculations: field <-> :param
 type BinaryExpression = types.BinaryExpression
 
+// ArithmeticExpression represents arithmetic between two expressions or params.
+type ArithmeticExpression = types.ArithmeticExpression
+
+// ArithmeticOperator represents an arithmetic operator (+, -, *, /, %).
+type ArithmeticOperator = types.ArithmeticOperator
+
+// Re-export arithmetic operator constants for public API.
+const (
+	ArithAdd = types.ArithAdd
+	ArithSub = types.ArithSub
+	ArithMul = types.ArithMul
+	ArithDiv = types.ArithDiv
+	ArithMod = types.ArithMod
+)
+
+// GroupingExpression represents a GROUPING(col, ...) call for grouping sets.
+type GroupingExpression = types.GroupingExpression
+
+// JSONObjectExpression represents a JSON object built from key/value pairs.
+type JSONObjectExpression = types.JSONObjectExpression
+
+// ArrayExpression represents an ARRAY[...] constructor built from params.
+type ArrayExpression = types.ArrayExpression
+
+// OrderedAggregate represents an aggregate with its own ORDER BY, such as STRING_AGG.
+type OrderedAggregate = types.OrderedAggregate
+
 // AggregateFunc represents SQL aggregate functions.
 type AggregateFunc = types.AggregateFunc
 
@@ -162,6 +218,29 @@ const (
 	AggMax           = types.AggMax
 	AggCountField    = types.AggCountField
 	AggCountDistinct = types.AggCountDistinct
+	AggStringAgg     = types.AggStringAgg
+	AggArrayAgg      = types.AggArrayAgg
+)
+
+// DateDiffExpression represents the number of date parts between two dates.
+type DateDiffExpression = types.DateDiffExpression
+
+// DatePart represents date/time parts for EXTRACT, DATE_TRUNC, and date arithmetic.
+type DatePart = types.DatePart
+
+// Re-export date part constants for public API.
+const (
+	PartYear      = types.PartYear
+	PartMonth     = types.PartMonth
+	PartDay       = types.PartDay
+	PartHour      = types.PartHour
+	PartMinute    = types.PartMinute
+	PartSecond    = types.PartSecond
+	PartWeek      = types.PartWeek
+	PartQuarter   = types.PartQuarter
+	PartDayOfWeek = types.PartDayOfWeek
+	PartDayOfYear = types.PartDayOfYear
+	PartEpoch     = types.PartEpoch
 )
 
 // CastType represents allowed PostgreSQL data types for casting.
@@ -186,8 +265,15 @@ const (
 	CastJSON            = types.CastJSON
 	CastJSONB           = types.CastJSONB
 	CastBytea           = types.CastBytea
+	CastVarchar         = types.CastVarchar
 )
 
+// CastSpec is a cast target with type parameters, built with Numeric or Varchar.
+type CastSpec = types.CastSpec
+
+// CastTarget is accepted by Cast: a CastType or a CastSpec.
+type CastTarget = types.CastTarget
+
 // WindowFunc represents window function types.
 type WindowFunc = types.WindowFunc
 
@@ -211,6 +297,38 @@ const (
 	FrameUnboundedPreceding = types.FrameUnboundedPreceding
 	FrameCurrentRow         = types.FrameCurrentRow
 	FrameUnboundedFollowing = types.FrameUnboundedFollowing
+	FramePreceding          = types.FramePreceding
+	FrameFollowing          = types.FrameFollowing
+)
+
+// FrameUnit represents the unit of a window frame (ROWS, RANGE, GROUPS).
+type FrameUnit = types.FrameUnit
+
+// Re-export frame unit constants for public API.
+const (
+	FrameRows   = types.FrameRows
+	FrameRange  = types.FrameRange
+	FrameGroups = types.FrameGroups
+)
+
+// FrameExclude represents the EXCLUDE option of a window frame.
+type FrameExclude = types.FrameExclude
+
+// Re-export frame exclusion constants for public API.
+const (
+	ExcludeNoOthers   = types.ExcludeNoOthers
+	ExcludeCurrentRow = types.ExcludeCurrentRow
+	ExcludeTies       = types.ExcludeTies
+	ExcludeGroup      = types.ExcludeGroup
+)
+
+// NullTreatment represents IGNORE NULLS or RESPECT NULLS on a window function.
+type NullTreatment = types.NullTreatment
+
+// Re-export null treatment constants for public API.
+const (
+	NullsRespect = types.NullsRespect
+	NullsIgnore  = types.NullsIgnore
 )
 
 // WindowSpec represents a window specification.
@@ -2,6 +2,8 @@ package astql
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/zoobzio/astql/internal/types"
 )
@@ -54,6 +56,36 @@ func Select(t types.Table) *Builder {
 	}
 }
 
+// SelectFrom creates a new SELECT query builder over a derived table:
+// SELECT ... FROM (subquery) AS alias.
+func SelectFrom(sub *Builder, alias string) *Builder {
+	b := &Builder{
+		ast: &types.AST{
+			Operation:   types.OpSelect,
+			TargetAlias: alias,
+		},
+	}
+	if sub == nil {
+		b.err = fmt.Errorf("derived table target requires a subquery")
+		return b
+	}
+	subAST, err := sub.Build()
+	if err != nil {
+		b.err = fmt.Errorf("invalid derived table subquery: %w", err)
+		return b
+	}
+	if subAST.Operation != types.OpSelect {
+		b.err = fmt.Errorf("derived table subquery must be a SELECT query")
+		return b
+	}
+	if !isValidSQLIdentifier(alias) {
+		b.err = fmt.Errorf("invalid derived table alias: %s", alias)
+		return b
+	}
+	b.ast.TargetSubquery = subAST
+	return b
+}
+
 // Insert creates a new INSERT query builder.
 func Insert(t types.Table) *Builder {
 	return &Builder{
@@ -85,6 +117,17 @@ func Delete(t types.Table) *Builder {
 	}
 }
 
+// Truncate creates a new TRUNCATE TABLE builder, which removes every row of
+// the table. It takes no conditions; use Delete to remove selected rows.
+func Truncate(t types.Table) *Builder {
+	return &Builder{
+		ast: &types.AST{
+			Operation: types.OpTruncate,
+			Target:    t,
+		},
+	}
+}
+
 // Count creates a new COUNT query builder.
 func Count(t types.Table) *Builder {
 	return &Builder{
@@ -108,7 +151,18 @@ func (b *Builder) Fields(fields ...types.Field) *Builder {
 	return b
 }
 
-// Where sets or adds conditions.
+// FieldsIf sets the fields to select only when cond is true.
+// Otherwise the builder is returned unchanged.
+func (b *Builder) FieldsIf(cond bool, fields ...types.Field) *Builder {
+	if !cond {
+		return b
+	}
+	return b.Fields(fields...)
+}
+
+// Where sets or adds conditions. Calling it again never replaces the
+// existing clause: the new condition is combined with it using AND, so two
+// calls produce (a AND b). Use OrWhere to combine with OR instead.
 func (b *Builder) Where(condition types.ConditionItem) *Builder {
 	if b.err != nil {
 		return b
@@ -124,11 +178,98 @@ func (b *Builder) Where(condition types.ConditionItem) *Builder {
 	return b
 }
 
+// AndWhere adds a condition combined with AND. It behaves exactly like a
+// repeated Where call and exists for chains that want the intent spelled out.
+func (b *Builder) AndWhere(condition types.ConditionItem) *Builder {
+	return b.Where(condition)
+}
+
+// OrWhere adds a condition combined with OR: the existing WHERE clause and
+// the new condition become (a OR b). With no existing clause it sets the
+// condition, like Where. Each call wraps everything before it, so
+// Where(a).Where(b).OrWhere(c) renders ((a AND b) OR c).
+func (b *Builder) OrWhere(condition types.ConditionItem) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if b.ast.WhereClause == nil {
+		b.ast.WhereClause = condition
+	} else {
+		b.ast.WhereClause = types.ConditionGroup{
+			Logic:      types.OR,
+			Conditions: []types.ConditionItem{b.ast.WhereClause, condition},
+		}
+	}
+
+	return b
+}
+
+// WhereIf adds a condition only when cond is true, so optional filters can
+// stay in a fluent chain. Otherwise the builder is returned unchanged.
+func (b *Builder) WhereIf(cond bool, condition types.ConditionItem) *Builder {
+	if !cond {
+		return b
+	}
+	return b.Where(condition)
+}
+
 // WhereField is a convenience method for simple field conditions.
 func (b *Builder) WhereField(f types.Field, op types.Operator, p types.Param) *Builder {
 	return b.Where(c(f, op, p))
 }
 
+// WhereInValues adds field IN (:a, :b, ...) against an explicit parameter list.
+// An empty list renders the always-false guard 1 = 0 rather than invalid
+// IN () syntax; renderers configured with StrictEmptyIn return an error instead.
+func (b *Builder) WhereInValues(f types.Field, params []types.Param) *Builder {
+	return b.Where(types.InListCondition{Field: f, Operator: types.IN, Values: params})
+}
+
+// WhereInValuesTable adds field IN (VALUES (:a), (:b), ...), matching the
+// same rows as WhereInValues. Planners join against the inline VALUES table
+// instead of testing each value in turn, which is faster for large lists.
+// SQL Server, where VALUES is only a derived table, renders
+// field IN (SELECT [v] FROM (VALUES ...) AS [vals]([v])). An empty list
+// renders the same guard as WhereInValues.
+func (b *Builder) WhereInValuesTable(f types.Field, params []types.Param) *Builder {
+	return b.Where(types.InListCondition{Field: f, Operator: types.IN, Values: params, ValuesTable: true})
+}
+
+// KeysetAfter pages past a cursor: it adds WHERE (f1, f2, ...) > (:v1, :v2, ...)
+// and ORDER BY f1 ASC, f2 ASC, ... so each page resumes after the last row of
+// the previous one. Pass the cursor columns with a unique column last, then
+// call Limit for the page size. A single field renders a plain comparison.
+// SQL Server, which lacks row value comparisons, renders the equivalent
+// (f1 > :v1 OR (f1 = :v1 AND f2 > :v2) ...).
+func (b *Builder) KeysetAfter(fields []types.Field, values []types.Param) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("KeysetAfter() can only be used with SELECT queries")
+		return b
+	}
+	if len(fields) == 0 {
+		b.err = fmt.Errorf("KeysetAfter() requires at least one field")
+		return b
+	}
+	if len(fields) != len(values) {
+		b.err = fmt.Errorf("KeysetAfter() has %d fields but %d values", len(fields), len(values))
+		return b
+	}
+
+	if len(fields) == 1 {
+		b.Where(types.Condition{Field: fields[0], Operator: types.GT, Value: values[0]})
+	} else {
+		b.Where(types.TupleCondition{Fields: fields, Operator: types.GT, Values: values})
+	}
+	for _, field := range fields {
+		b.OrderBy(field, types.ASC)
+	}
+	return b
+}
+
 // Set adds a field update for UPDATE queries.
 func (b *Builder) Set(f types.Field, p types.Param) *Builder {
 	if b.err != nil {
@@ -166,6 +307,34 @@ func (b *Builder) SetExpr(f types.Field, expr types.FieldExpression) *Builder {
 	return b
 }
 
+// SetRow assigns several fields from the single row sub returns, for UPDATE
+// queries: SET (a, b) = (SELECT x, y FROM ...). The subquery must select one
+// column per field, and its parameters are namespaced like other subqueries.
+// PostgreSQL and SQLite support it; MariaDB and SQL Server reject it.
+func (b *Builder) SetRow(fields []types.Field, sub *Builder) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpUpdate {
+		b.err = fmt.Errorf("SetRow() can only be used with UPDATE queries")
+		return b
+	}
+	if sub == nil {
+		b.err = fmt.Errorf("SetRow() requires a subquery")
+		return b
+	}
+	subAST, err := sub.Build()
+	if err != nil {
+		b.err = fmt.Errorf("SetRow() subquery: %w", err)
+		return b
+	}
+	b.ast.UpdateRows = append(b.ast.UpdateRows, types.RowUpdate{
+		Fields:   append([]types.Field(nil), fields...),
+		Subquery: types.Subquery{AST: subAST},
+	})
+	return b
+}
+
 // Values adds a row of field-value pairs for INSERT queries.
 // Call Values() multiple times to insert multiple rows.
 // Use instance.ValueMap() to create the map programmatically.
@@ -188,6 +357,143 @@ func (b *Builder) Values(valueMap map[types.Field]types.Param) *Builder {
 	return b
 }
 
+// AddRow appends one row to a multi-row INSERT. Unlike Values, it checks that
+// the row has exactly the columns of the first row, so a mismatch is reported
+// with the row number and both column lists when the query is built.
+func (b *Builder) AddRow(row map[types.Field]types.Param) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpInsert {
+		b.err = fmt.Errorf("AddRow() can only be used with INSERT queries")
+		return b
+	}
+	if len(b.ast.Values) > 0 {
+		want := b.ast.InsertColumns()
+		got := make([]types.Field, 0, len(row))
+		for field := range row {
+			got = append(got, field)
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+		if !sameColumns(got, want) {
+			b.err = fmt.Errorf("AddRow() row %d has columns %s, want %s like row 0",
+				len(b.ast.Values), columnList(got), columnList(want))
+			return b
+		}
+	}
+	return b.Values(row)
+}
+
+// Rows appends each row to a multi-row INSERT with AddRow, rendering a
+// single INSERT ... VALUES (...), (...) statement.
+func (b *Builder) Rows(rows ...map[types.Field]types.Param) *Builder {
+	for _, row := range rows {
+		b.AddRow(row)
+	}
+	return b
+}
+
+func sameColumns(a, b []types.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func columnList(fields []types.Field) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// ValueSubquery sets a column of the most recent Values() set to a scalar subquery,
+// e.g. INSERT ... VALUES (:a, (SELECT MAX("n") FROM "t")).
+// Subquery parameters are namespaced like other subqueries.
+func (b *Builder) ValueSubquery(field types.Field, subquery types.Subquery) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpInsert {
+		b.err = fmt.Errorf("ValueSubquery() can only be used with INSERT queries")
+		return b
+	}
+	if len(b.ast.Values) == 0 {
+		b.err = fmt.Errorf("ValueSubquery() requires a preceding Values() set")
+		return b
+	}
+	row := len(b.ast.Values) - 1
+	for len(b.ast.ValueSubqueries) <= row {
+		b.ast.ValueSubqueries = append(b.ast.ValueSubqueries, nil)
+	}
+	if b.ast.ValueSubqueries[row] == nil {
+		b.ast.ValueSubqueries[row] = make(map[types.Field]types.Subquery)
+	}
+	b.ast.ValueSubqueries[row][field] = subquery
+	return b
+}
+
+// ValueArray sets a column of the most recent Values() set to an ARRAY
+// constructor built from individual params, e.g. INSERT ... VALUES (ARRAY[:a, :b]).
+// Only PostgreSQL renders array constructors.
+func (b *Builder) ValueArray(field types.Field, values ...types.Param) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpInsert {
+		b.err = fmt.Errorf("ValueArray() can only be used with INSERT queries")
+		return b
+	}
+	if len(b.ast.Values) == 0 {
+		b.err = fmt.Errorf("ValueArray() requires a preceding Values() set")
+		return b
+	}
+	if len(values) == 0 {
+		b.err = fmt.Errorf("ValueArray() requires at least one value")
+		return b
+	}
+	row := len(b.ast.Values) - 1
+	for len(b.ast.ValueArrays) <= row {
+		b.ast.ValueArrays = append(b.ast.ValueArrays, nil)
+	}
+	if b.ast.ValueArrays[row] == nil {
+		b.ast.ValueArrays[row] = make(map[types.Field]types.ArrayExpression)
+	}
+	b.ast.ValueArrays[row][field] = types.ArrayExpression{Values: values}
+	return b
+}
+
+// DefineWindow adds a named window to the WINDOW clause.
+// Reference it from window functions with WindowBuilder.OverWindow.
+func (b *Builder) DefineWindow(name string, spec types.WindowSpec) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("WINDOW can only be used with SELECT queries")
+		return b
+	}
+	if !isValidSQLIdentifier(name) {
+		b.err = fmt.Errorf("invalid window name '%s'", name)
+		return b
+	}
+	if _, exists := b.ast.Windows[name]; exists {
+		b.err = fmt.Errorf("window '%s' is already defined", name)
+		return b
+	}
+	if b.ast.Windows == nil {
+		b.ast.Windows = make(map[string]types.WindowSpec)
+	}
+	b.ast.Windows[name] = spec
+	return b
+}
+
 // OrderBy adds ordering.
 func (b *Builder) OrderBy(f types.Field, direction types.Direction) *Builder {
 	if b.err != nil {
@@ -236,16 +542,37 @@ func (b *Builder) OrderByExpr(f types.Field, op types.Operator, p types.Param, d
 	return b
 }
 
-// Limit sets the limit to a static integer value.
+// Limit sets the limit to a static integer value. A negative limit is an error.
 func (b *Builder) Limit(limit int) *Builder {
 	if b.err != nil {
 		return b
 	}
+	if limit < 0 {
+		b.err = fmt.Errorf("LIMIT must be non-negative, got %d", limit)
+		return b
+	}
 	b.ast.Limit = &types.PaginationValue{Static: &limit}
 	return b
 }
 
+// WithTies extends LIMIT to also return rows that tie with the last one on
+// ORDER BY, rendering FETCH FIRST n ROWS WITH TIES (TOP (n) WITH TIES on
+// SQL Server). The query must have LIMIT and ORDER BY. SQLite has no
+// equivalent and returns an UnsupportedFeatureError.
+func (b *Builder) WithTies() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("WithTies() can only be used with SELECT queries")
+		return b
+	}
+	b.ast.WithTies = true
+	return b
+}
+
 // LimitParam sets the limit to a parameterized value.
+// The bound value is not checked; reject negative input before executing.
 func (b *Builder) LimitParam(param types.Param) *Builder {
 	if b.err != nil {
 		return b
@@ -254,16 +581,21 @@ func (b *Builder) LimitParam(param types.Param) *Builder {
 	return b
 }
 
-// Offset sets the offset to a static integer value.
+// Offset sets the offset to a static integer value. A negative offset is an error.
 func (b *Builder) Offset(offset int) *Builder {
 	if b.err != nil {
 		return b
 	}
+	if offset < 0 {
+		b.err = fmt.Errorf("OFFSET must be non-negative, got %d", offset)
+		return b
+	}
 	b.ast.Offset = &types.PaginationValue{Static: &offset}
 	return b
 }
 
 // OffsetParam sets the offset to a parameterized value.
+// As with LimitParam, the bound value is not checked.
 func (b *Builder) OffsetParam(param types.Param) *Builder {
 	if b.err != nil {
 		return b
@@ -304,6 +636,15 @@ func (b *Builder) Render(renderer Renderer) (*QueryResult, error) {
 	return renderer.Render(ast)
 }
 
+// RenderWithOptions builds the AST and renders it with per-call options.
+func (b *Builder) RenderWithOptions(renderer Renderer, opts RenderOptions) (*QueryResult, error) {
+	ast, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return renderer.RenderWithOptions(ast, opts)
+}
+
 // MustRender builds and renders the AST with the provided renderer, or panics on error.
 func (b *Builder) MustRender(renderer Renderer) *QueryResult {
 	result, err := b.Render(renderer)
@@ -348,6 +689,131 @@ func (b *Builder) DistinctOn(fields ...types.Field) *Builder {
 	return b
 }
 
+// Names used by DistinctOnAsWindow for the derived table and its rank column.
+const (
+	distinctOnAlias = "d"
+	distinctOnRank  = "distinct_rn"
+)
+
+// DistinctOnAsWindow rewrites a DISTINCT ON query into the portable form that
+// keeps the first row of each group with ROW_NUMBER():
+//
+//	SELECT d."id", ... FROM (SELECT "id", ..., ROW_NUMBER() OVER
+//	(PARTITION BY <distinct on> ORDER BY <order by>) AS "distinct_rn" FROM ...)
+//	AS d WHERE d."distinct_rn" = 1 ORDER BY ...
+//
+// The rewrite renders the same way on every dialect, PostgreSQL included, so
+// it can be used to check that both forms return the same rows. ORDER BY,
+// LIMIT, and OFFSET move to the outer query. The select list must be explicit,
+// every expression in it aliased, every output column uniquely named, and every
+// ORDER BY field a selected column: the window cannot order by an expression
+// alias, so order by the underlying column instead. Call it
+// once the query is otherwise complete: later builder calls apply to the
+// outer query.
+func (b *Builder) DistinctOnAsWindow() *Builder {
+	if b.err != nil {
+		return b
+	}
+	inner := *b.ast
+	if inner.Operation != types.OpSelect || len(inner.DistinctOn) == 0 {
+		b.err = fmt.Errorf("DistinctOnAsWindow() can only be used with DISTINCT ON queries")
+		return b
+	}
+	if inner.Lock != nil {
+		b.err = fmt.Errorf("DistinctOnAsWindow() cannot be used with row locks")
+		return b
+	}
+	if len(inner.Fields) == 0 && len(inner.FieldExpressions) == 0 {
+		b.err = fmt.Errorf("DistinctOnAsWindow() requires an explicit select list")
+		return b
+	}
+
+	columns := make([]types.Field, 0, len(inner.Fields)+len(inner.FieldExpressions))
+	names := map[string]bool{distinctOnRank: true}
+	for _, field := range inner.Fields {
+		if field.JSONBTextKey != nil || field.JSONBPathKey != nil || field.JSONKey != "" || field.JSONPath != "" {
+			b.err = fmt.Errorf("DistinctOnAsWindow() cannot select JSON fields; select them through an aliased expression")
+			return b
+		}
+		if names[field.Name] {
+			b.err = fmt.Errorf("DistinctOnAsWindow() cannot select two columns named %s; alias one through a select expression", field.Name)
+			return b
+		}
+		names[field.Name] = true
+		columns = append(columns, types.Field{Name: field.Name, Table: distinctOnAlias})
+	}
+	for _, expr := range inner.FieldExpressions {
+		if expr.Alias == "" {
+			b.err = fmt.Errorf("DistinctOnAsWindow() requires an alias on every select expression")
+			return b
+		}
+		if names[expr.Alias] {
+			b.err = fmt.Errorf("DistinctOnAsWindow() cannot select two columns named %s; alias one through a select expression", expr.Alias)
+			return b
+		}
+		names[expr.Alias] = true
+		columns = append(columns, types.Field{Name: expr.Alias, Table: distinctOnAlias})
+	}
+
+	ordering := make([]types.OrderBy, len(inner.Ordering))
+	for i, order := range inner.Ordering {
+		if order.Operator != "" {
+			b.err = fmt.Errorf("DistinctOnAsWindow() cannot move ORDER BY expressions to the outer query")
+			return b
+		}
+		if !selectsColumn(&inner, order.Field) {
+			if order.Field.Table == "" && names[order.Field.Name] {
+				b.err = fmt.Errorf("DistinctOnAsWindow() cannot order by select expression alias %s; order by a selected column", order.Field.Name)
+				return b
+			}
+			b.err = fmt.Errorf("DistinctOnAsWindow() requires ORDER BY field %s in the select list", order.Field.Name)
+			return b
+		}
+		order.Field = types.Field{Name: order.Field.Name, Table: distinctOnAlias}
+		ordering[i] = order
+	}
+
+	rank := &types.WindowExpression{
+		Function: types.WinRowNumber,
+		Window: types.WindowSpec{
+			PartitionBy: inner.DistinctOn,
+			OrderBy:     inner.Ordering,
+		},
+	}
+	inner.FieldExpressions = append(append([]types.FieldExpression{}, inner.FieldExpressions...),
+		types.FieldExpression{Window: rank, Alias: distinctOnRank})
+	inner.DistinctOn = nil
+	inner.Ordering = nil
+	inner.Limit = nil
+	inner.Offset = nil
+
+	b.ast = &types.AST{
+		Operation:      types.OpSelect,
+		TargetSubquery: &inner,
+		TargetAlias:    distinctOnAlias,
+		Fields:         columns,
+		WhereClause: types.Condition{
+			Field:    types.Field{Name: distinctOnRank, Table: distinctOnAlias},
+			Operator: types.EQ,
+			Value:    types.Param{Literal: types.LiteralOne},
+		},
+		Ordering: ordering,
+		Limit:    b.ast.Limit,
+		Offset:   b.ast.Offset,
+	}
+	return b
+}
+
+// selectsColumn reports whether field is one of the selected columns of ast.
+func selectsColumn(ast *types.AST, field types.Field) bool {
+	for _, selected := range ast.Fields {
+		if selected.Name == field.Name && selected.Table == field.Table {
+			return true
+		}
+	}
+	return false
+}
+
 // ForUpdate adds FOR UPDATE row locking.
 func (b *Builder) ForUpdate() *Builder {
 	if b.err != nil {
@@ -362,6 +828,26 @@ func (b *Builder) ForUpdate() *Builder {
 	return b
 }
 
+// ForUpdateOf adds FOR UPDATE OF row locking, restricting the lock to the
+// given tables. Each table must be the target or a joined table.
+func (b *Builder) ForUpdateOf(tables ...types.Table) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("FOR UPDATE OF can only be used with SELECT queries")
+		return b
+	}
+	if len(tables) == 0 {
+		b.err = fmt.Errorf("FOR UPDATE OF requires at least one table")
+		return b
+	}
+	lock := types.LockForUpdate
+	b.ast.Lock = &lock
+	b.ast.LockOf = tables
+	return b
+}
+
 // ForNoKeyUpdate adds FOR NO KEY UPDATE row locking.
 func (b *Builder) ForNoKeyUpdate() *Builder {
 	if b.err != nil {
@@ -404,6 +890,26 @@ func (b *Builder) ForKeyShare() *Builder {
 	return b
 }
 
+// SkipLocked adds SKIP LOCKED to the row lock, skipping rows locked by
+// other transactions. Requires a lock mode such as ForUpdate.
+func (b *Builder) SkipLocked() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("SKIP LOCKED can only be used with SELECT queries")
+		return b
+	}
+	b.ast.SkipLocked = true
+	return b
+}
+
+// DequeueLock sets FOR UPDATE SKIP LOCKED with LIMIT :limit, the canonical
+// job-queue dequeue: each worker claims up to limit unlocked rows.
+func (b *Builder) DequeueLock(limit types.Param) *Builder {
+	return b.ForUpdate().SkipLocked().LimitParam(limit)
+}
+
 // Join adds an INNER JOIN.
 func (b *Builder) Join(table types.Table, on types.ConditionItem) *Builder {
 	return b.addJoin(types.InnerJoin, table, on)
@@ -429,12 +935,147 @@ func (b *Builder) FullOuterJoin(table types.Table, on types.ConditionItem) *Buil
 	return b.addJoin(types.FullOuterJoin, table, on)
 }
 
+// FullJoin adds a FULL OUTER JOIN. Alias for FullOuterJoin.
+func (b *Builder) FullJoin(table types.Table, on types.ConditionItem) *Builder {
+	return b.addJoin(types.FullJoin, table, on)
+}
+
+// NaturalJoin adds a NATURAL JOIN (columns are matched by name, no ON clause).
+func (b *Builder) NaturalJoin(table types.Table) *Builder {
+	return b.addJoin(types.NaturalJoin, table, nil)
+}
+
 // CrossJoin adds a CROSS JOIN (no ON clause needed).
 func (b *Builder) CrossJoin(table types.Table) *Builder {
 	return b.addJoin(types.CrossJoin, table, nil)
 }
 
+// SelfJoin joins the target table to itself, aliasing the target as targetAlias
+// and the joined copy as joinAlias, ON targetAlias.targetField = joinAlias.joinField.
+// Example: Select(T("employees")).SelfJoin("e", "m", F("manager_id"), F("id"))
+// -> FROM "employees" e INNER JOIN "employees" m ON e."manager_id" = m."id"
+func (b *Builder) SelfJoin(targetAlias, joinAlias string, targetField, joinField types.Field) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.TargetSubquery != nil {
+		b.err = fmt.Errorf("SELF JOIN requires a table target, not a derived table")
+		return b
+	}
+	for _, alias := range []string{targetAlias, joinAlias} {
+		if !isValidTableAlias(alias) {
+			b.err = fmt.Errorf("alias must be single lowercase letter (a-z), got: %s", alias)
+			return b
+		}
+	}
+	if targetAlias == joinAlias {
+		b.err = fmt.Errorf("SELF JOIN requires distinct aliases, got '%s' twice", targetAlias)
+		return b
+	}
+	if b.ast.Target.Alias != "" && b.ast.Target.Alias != targetAlias {
+		b.err = fmt.Errorf("target table is already aliased as '%s'", b.ast.Target.Alias)
+		return b
+	}
+
+	b.ast.Target.Alias = targetAlias
+	targetField.Table = targetAlias
+	joinField.Table = joinAlias
+	return b.addJoin(types.InnerJoin, types.Table{Name: b.ast.Target.Name, Alias: joinAlias}, types.FieldComparison{
+		LeftField:  targetField,
+		Operator:   types.EQ,
+		RightField: joinField,
+	})
+}
+
+// JoinSub adds INNER JOIN (subquery) AS alias ON condition against a derived table.
+// Subquery parameters are namespaced at render time like other subqueries.
+func (b *Builder) JoinSub(sub *Builder, alias string, on types.ConditionItem) *Builder {
+	return b.addDerivedJoin(types.InnerJoin, sub, alias, on, false, nil)
+}
+
+// CrossJoinLateral adds CROSS JOIN LATERAL (subquery) AS alias(columns...).
+// The subquery may reference columns of tables earlier in the FROM clause.
+func (b *Builder) CrossJoinLateral(sub *Builder, alias string, columns ...string) *Builder {
+	return b.addDerivedJoin(types.CrossJoin, sub, alias, nil, true, columns)
+}
+
+// LeftJoinLateral adds LEFT JOIN LATERAL (subquery) AS alias(columns...) ON condition.
+func (b *Builder) LeftJoinLateral(sub *Builder, alias string, on types.ConditionItem, columns ...string) *Builder {
+	return b.addDerivedJoin(types.LeftJoin, sub, alias, on, true, columns)
+}
+
+// addDerivedJoin adds a join against a derived table built from sub.
+func (b *Builder) addDerivedJoin(joinType types.JoinType, sub *Builder, alias string, on types.ConditionItem, lateral bool, columns []string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if sub == nil {
+		b.err = fmt.Errorf("derived table join requires a subquery")
+		return b
+	}
+	subAST, err := sub.Build()
+	if err != nil {
+		b.err = fmt.Errorf("invalid derived table subquery: %w", err)
+		return b
+	}
+	if subAST.Operation != types.OpSelect {
+		b.err = fmt.Errorf("derived table subquery must be a SELECT query")
+		return b
+	}
+	if !isValidSQLIdentifier(alias) {
+		b.err = fmt.Errorf("invalid derived table alias: %s", alias)
+		return b
+	}
+	for _, col := range columns {
+		if !isValidSQLIdentifier(col) {
+			b.err = fmt.Errorf("invalid derived table column alias: %s", col)
+			return b
+		}
+	}
+
+	b.addJoin(joinType, types.Table{Alias: alias}, on)
+	if b.err != nil {
+		return b
+	}
+	join := &b.ast.Joins[len(b.ast.Joins)-1]
+	join.Subquery = &types.Subquery{AST: subAST}
+	join.Lateral = lateral
+	join.Columns = columns
+	return b
+}
+
+// Using sets a USING column list on the most recently added join, in place of ON.
+// Pass nil as the ON condition when adding the join:
+//
+//	Select(users).InnerJoin(orders, nil).Using(userID)
+func (b *Builder) Using(fields ...types.Field) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.ast.Joins) == 0 {
+		b.err = fmt.Errorf("USING requires a preceding JOIN")
+		return b
+	}
+	if len(fields) == 0 {
+		b.err = fmt.Errorf("USING requires at least one column")
+		return b
+	}
+	join := &b.ast.Joins[len(b.ast.Joins)-1]
+	if !join.Type.RequiresOn() {
+		b.err = fmt.Errorf("%s cannot have USING clause", join.Type)
+		return b
+	}
+	if join.On != nil {
+		b.err = fmt.Errorf("%s cannot have both ON and USING clauses", join.Type)
+		return b
+	}
+	join.Using = append(join.Using, fields...)
+	return b
+}
+
 // addJoin is a helper to add joins.
+// A missing ON condition is allowed here so Using can follow; Build rejects
+// joins that end up with neither.
 func (b *Builder) addJoin(joinType types.JoinType, table types.Table, on types.ConditionItem) *Builder {
 	if b.err != nil {
 		return b
@@ -443,12 +1084,8 @@ func (b *Builder) addJoin(joinType types.JoinType, table types.Table, on types.C
 		b.err = fmt.Errorf("JOIN can only be used with SELECT or COUNT queries")
 		return b
 	}
-	if joinType == types.CrossJoin && on != nil {
-		b.err = fmt.Errorf("CROSS JOIN cannot have ON clause")
-		return b
-	}
-	if joinType != types.CrossJoin && on == nil {
-		b.err = fmt.Errorf("%s requires ON clause", joinType)
+	if !joinType.RequiresOn() && on != nil {
+		b.err = fmt.Errorf("%s cannot have ON clause", joinType)
 		return b
 	}
 
@@ -462,7 +1099,8 @@ func (b *Builder) addJoin(joinType types.JoinType, table types.Table, on types.C
 	return b
 }
 
-// GroupBy adds GROUP BY fields.
+// GroupBy adds GROUP BY fields. It cannot follow GroupByRollup, GroupByCube,
+// or GroupBySets, whose fields would otherwise absorb the plain ones.
 func (b *Builder) GroupBy(fields ...types.Field) *Builder {
 	if b.err != nil {
 		return b
@@ -471,6 +1109,105 @@ func (b *Builder) GroupBy(fields ...types.Field) *Builder {
 		b.err = fmt.Errorf("GROUP BY can only be used with SELECT queries")
 		return b
 	}
+	if b.ast.GroupByMode != types.GroupPlain {
+		b.err = fmt.Errorf("GROUP BY fields cannot be combined with %s", b.ast.GroupByMode)
+		return b
+	}
+	b.ast.GroupBy = append(b.ast.GroupBy, fields...)
+	return b
+}
+
+// GroupByAll groups by every plain projected field, resolved at render time,
+// so the select list does not have to be repeated. Aggregate, window, and
+// other field expressions are skipped. Explicit GroupBy fields come first.
+func (b *Builder) GroupByAll() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("GROUP BY can only be used with SELECT queries")
+		return b
+	}
+	if b.ast.GroupByMode != types.GroupPlain {
+		b.err = fmt.Errorf("GroupByAll cannot be combined with GROUP BY %s", b.ast.GroupByMode)
+		return b
+	}
+	b.ast.GroupByAll = true
+	return b
+}
+
+// GroupByCoalesce adds a GROUP BY COALESCE(field, :sentinel) entry so that
+// NULL values of a nullable column are grouped under the sentinel value.
+// Coalesced entries render after the plain GroupBy fields.
+func (b *Builder) GroupByCoalesce(field types.Field, sentinel types.Param) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("GROUP BY can only be used with SELECT queries")
+		return b
+	}
+	if b.ast.GroupByMode != types.GroupPlain {
+		b.err = fmt.Errorf("GROUP BY COALESCE cannot be combined with GROUP BY %s", b.ast.GroupByMode)
+		return b
+	}
+	b.ast.CoalescedGroupBy = append(b.ast.CoalescedGroupBy, types.CoalescedGroup{Field: field, Sentinel: sentinel})
+	return b
+}
+
+// GroupByRollup adds GROUP BY ROLLUP(...) fields, producing subtotal rows
+// for each prefix of the field list plus a grand total.
+func (b *Builder) GroupByRollup(fields ...types.Field) *Builder {
+	return b.groupByMode(types.GroupRollup, fields)
+}
+
+// GroupByCube adds GROUP BY CUBE(...) fields, producing subtotal rows
+// for every combination of the fields.
+func (b *Builder) GroupByCube(fields ...types.Field) *Builder {
+	return b.groupByMode(types.GroupCube, fields)
+}
+
+// GroupBySets adds GROUP BY GROUPING SETS(...) entries.
+// Each set is rendered as a parenthesized list; an empty set yields the grand total.
+func (b *Builder) GroupBySets(sets ...[]types.Field) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("GROUP BY can only be used with SELECT queries")
+		return b
+	}
+	if len(sets) == 0 {
+		b.err = fmt.Errorf("GROUPING SETS requires at least one grouping set")
+		return b
+	}
+	if len(b.ast.GroupBy) > 0 || (b.ast.GroupByMode != types.GroupPlain && b.ast.GroupByMode != types.GroupSets) {
+		b.err = fmt.Errorf("GROUPING SETS cannot be combined with other GROUP BY forms")
+		return b
+	}
+	b.ast.GroupByMode = types.GroupSets
+	b.ast.GroupingSets = append(b.ast.GroupingSets, sets...)
+	return b
+}
+
+// groupByMode sets a ROLLUP or CUBE grouping mode and appends its fields.
+func (b *Builder) groupByMode(mode types.GroupByMode, fields []types.Field) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpSelect {
+		b.err = fmt.Errorf("GROUP BY can only be used with SELECT queries")
+		return b
+	}
+	if len(fields) == 0 {
+		b.err = fmt.Errorf("GROUP BY %s requires at least one field", mode)
+		return b
+	}
+	if b.ast.GroupByMode != mode && (b.ast.GroupByMode != types.GroupPlain || len(b.ast.GroupBy) > 0 || len(b.ast.CoalescedGroupBy) > 0) {
+		b.err = fmt.Errorf("GROUP BY %s cannot be combined with other GROUP BY forms", mode)
+		return b
+	}
+	b.ast.GroupByMode = mode
 	b.ast.GroupBy = append(b.ast.GroupBy, fields...)
 	return b
 }
@@ -485,7 +1222,7 @@ func (b *Builder) Having(conditions ...types.Condition) *Builder {
 		b.err = fmt.Errorf("HAVING can only be used with SELECT queries")
 		return b
 	}
-	if len(b.ast.GroupBy) == 0 {
+	if !b.ast.HasGroupBy() {
 		b.err = fmt.Errorf("HAVING requires GROUP BY")
 		return b
 	}
@@ -505,7 +1242,7 @@ func (b *Builder) HavingAgg(conditions ...types.AggregateCondition) *Builder {
 		b.err = fmt.Errorf("HAVING can only be used with SELECT queries")
 		return b
 	}
-	if len(b.ast.GroupBy) == 0 {
+	if !b.ast.HasGroupBy() {
 		b.err = fmt.Errorf("HAVING requires GROUP BY")
 		return b
 	}
@@ -529,6 +1266,52 @@ func (b *Builder) Returning(fields ...types.Field) *Builder {
 	return b
 }
 
+// ReturningCount makes an UPDATE or DELETE answer with the number of rows it
+// changed, for setups where the driver does not surface rows affected.
+// PostgreSQL renders WITH "affected" AS (... RETURNING 1) SELECT COUNT(*)
+// FROM "affected"; other dialects return an UnsupportedFeatureError. It
+// cannot be combined with Returning.
+func (b *Builder) ReturningCount() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpUpdate && b.ast.Operation != types.OpDelete {
+		b.err = fmt.Errorf("ReturningCount() can only be used with UPDATE or DELETE queries")
+		return b
+	}
+	b.ast.ReturningCount = true
+	return b
+}
+
+// RestartIdentity adds RESTART IDENTITY to a TRUNCATE, resetting sequences
+// owned by the table's columns (PostgreSQL). MariaDB and SQL Server always
+// reset auto-increment and identity counters on TRUNCATE.
+func (b *Builder) RestartIdentity() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpTruncate {
+		b.err = fmt.Errorf("RestartIdentity() can only be used with TRUNCATE")
+		return b
+	}
+	b.ast.RestartIdentity = true
+	return b
+}
+
+// Cascade adds CASCADE to a TRUNCATE, also truncating tables with foreign
+// keys referencing it (PostgreSQL only).
+func (b *Builder) Cascade() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.ast.Operation != types.OpTruncate {
+		b.err = fmt.Errorf("Cascade() can only be used with TRUNCATE")
+		return b
+	}
+	b.ast.Cascade = true
+	return b
+}
+
 // OnConflict adds ON CONFLICT clause for INSERT.
 func (b *Builder) OnConflict(columns ...types.Field) *ConflictBuilder {
 	if b.err != nil {
@@ -553,6 +1336,17 @@ type ConflictBuilder struct {
 	err     error
 }
 
+// Where sets the partial-index predicate of the conflict target, as in
+// ON CONFLICT (email) WHERE active = :active. It lets PostgreSQL and SQLite
+// infer a partial unique index.
+func (cb *ConflictBuilder) Where(cond types.ConditionItem) *ConflictBuilder {
+	if cb.err != nil {
+		return cb
+	}
+	cb.builder.ast.OnConflict.ConflictPredicate = cond
+	return cb
+}
+
 // DoNothing sets the conflict action to DO NOTHING.
 func (cb *ConflictBuilder) DoNothing() *Builder {
 	if cb.err != nil {
@@ -591,11 +1385,32 @@ func (ub *UpdateBuilder) Set(field types.Field, param types.Param) *UpdateBuilde
 	return ub
 }
 
+// Where limits the DO UPDATE action to conflicting rows matching cond,
+// rendered after the SET list.
+func (ub *UpdateBuilder) Where(cond types.ConditionItem) *UpdateBuilder {
+	if ub.err != nil {
+		return ub
+	}
+	ub.builder.ast.OnConflict.DoUpdateWhere = cond
+	return ub
+}
+
 // Build finalizes the update and returns the builder.
 func (ub *UpdateBuilder) Build() *Builder {
 	return ub.builder
 }
 
+// Excluded references the value the conflicting INSERT proposed for field,
+// for use as a DO UPDATE assignment:
+//
+//	DoUpdate().Set(instance.F("name"), astql.Excluded(instance.F("name")))
+//
+// It renders as EXCLUDED."name" in PostgreSQL and SQLite and as
+// VALUES(`name`) in MariaDB.
+func Excluded(field types.Field) types.Param {
+	return types.Param{Excluded: &types.ExcludedRef{Field: field}}
+}
+
 // SelectExpr adds a field expression (aggregate, case, etc) to SELECT.
 func (b *Builder) SelectExpr(expr types.FieldExpression) *Builder {
 	if b.err != nil {
@@ -832,6 +1647,10 @@ func (cb *CompoundBuilder) Limit(limit int) *CompoundBuilder {
 	if cb.err != nil {
 		return cb
 	}
+	if limit < 0 {
+		cb.err = fmt.Errorf("LIMIT must be non-negative, got %d", limit)
+		return cb
+	}
 	cb.query.Limit = &types.PaginationValue{Static: &limit}
 	return cb
 }
@@ -850,6 +1669,10 @@ func (cb *CompoundBuilder) Offset(offset int) *CompoundBuilder {
 	if cb.err != nil {
 		return cb
 	}
+	if offset < 0 {
+		cb.err = fmt.Errorf("OFFSET must be non-negative, got %d", offset)
+		return cb
+	}
 	cb.query.Offset = &types.PaginationValue{Static: &offset}
 	return cb
 }
@@ -2,11 +2,15 @@ package astql_test
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/astql/internal/types"
+	"github.com/zoobzio/astql/mssql"
 	"github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/astql/sqlite"
 	"github.com/zoobzio/dbml"
 )
 
@@ -100,6 +104,30 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestTruncate(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	ast, err := astql.Truncate(instance.T("posts")).RestartIdentity().Cascade().Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ast.Operation != types.OpTruncate || !ast.RestartIdentity || !ast.Cascade {
+		t.Errorf("Expected TRUNCATE with RESTART IDENTITY and CASCADE, got %+v", ast)
+	}
+
+	if _, err := astql.Delete(instance.T("posts")).Cascade().Build(); err == nil {
+		t.Error("Expected error for Cascade() outside TRUNCATE")
+	}
+	if _, err := astql.Update(instance.T("posts")).RestartIdentity().Build(); err == nil {
+		t.Error("Expected error for RestartIdentity() outside TRUNCATE")
+	}
+	if _, err := astql.Truncate(instance.T("posts")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))).
+		Build(); err == nil {
+		t.Error("Expected error for TRUNCATE with WHERE")
+	}
+}
+
 func TestCount(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 	table := instance.T("users")
@@ -296,6 +324,49 @@ func TestSetExpr_WrongOperation(t *testing.T) {
 	}
 }
 
+func TestSetRow(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	latest := astql.Select(instance.T("posts")).
+		Fields(instance.F("id"), instance.F("title")).
+		Where(instance.C(instance.F("user_id"), astql.EQ, instance.P("user_id")))
+
+	ast, err := astql.Update(instance.T("users")).
+		SetRow([]types.Field{instance.F("age"), instance.F("username")}, latest).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(ast.UpdateRows) != 1 || len(ast.UpdateRows[0].Fields) != 2 {
+		t.Fatalf("Expected 1 row update with 2 fields, got %+v", ast.UpdateRows)
+	}
+}
+
+func TestSetRow_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	fields := []types.Field{instance.F("age"), instance.F("username")}
+	sub := astql.Select(instance.T("posts")).Fields(instance.F("id"), instance.F("title"))
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"wrong operation", astql.Select(instance.T("users")).SetRow(fields, sub)},
+		{"nil subquery", astql.Update(instance.T("users")).SetRow(fields, nil)},
+		{"invalid subquery", astql.Update(instance.T("users")).SetRow(fields, astql.Update(instance.T("posts")))},
+		{"arity mismatch", astql.Update(instance.T("users")).SetRow(fields[:1], sub)},
+		{"duplicate field", astql.Update(instance.T("users")).Set(instance.F("age"), instance.P("age")).SetRow(fields, sub)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
 func TestValues(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 	table := instance.T("users")
@@ -490,6 +561,40 @@ func TestOffsetParam(t *testing.T) {
 	}
 }
 
+func TestLimitOffset_Negative(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	table := instance.T("users")
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+		want    string
+	}{
+		{"negative limit", astql.Select(table).Limit(-1), "LIMIT must be non-negative, got -1"},
+		{"negative offset", astql.Select(table).Offset(-5), "OFFSET must be non-negative, got -5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Build() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	result, err := astql.Select(table).Limit(0).Offset(0).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := `SELECT * FROM "users" LIMIT 0 OFFSET 0`; result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	if _, err := astql.Select(table).Union(astql.Select(table)).Limit(-1).Build(); err == nil {
+		t.Error("Expected error for a negative compound LIMIT")
+	}
+}
+
 func TestMustBuild_Success(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 	table := instance.T("users")
@@ -667,6 +772,120 @@ func TestDistinctOn_Multiple(t *testing.T) {
 	}
 }
 
+func TestDistinctOnAsWindow(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	distinctOn := func() *astql.Builder {
+		return astql.Select(instance.T("posts")).
+			DistinctOn(instance.F("user_id")).
+			Fields(instance.F("user_id"), instance.F("title")).
+			OrderBy(instance.F("user_id"), types.ASC).
+			OrderBy(instance.F("title"), types.DESC).
+			Limit(10)
+	}
+
+	got, err := distinctOn().DistinctOnAsWindow().Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	inner := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id"), instance.F("title")).
+		SelectExpr(astql.RowNumber().
+			PartitionBy(instance.F("user_id")).
+			OrderBy(instance.F("user_id"), types.ASC).
+			OrderBy(instance.F("title"), types.DESC).
+			As("distinct_rn"))
+	want, err := astql.SelectFrom(inner, "d").
+		Fields(
+			instance.WithTable(instance.F("user_id"), "d"),
+			instance.WithTable(instance.F("title"), "d"),
+		).
+		Where(types.Condition{
+			Field:    types.Field{Name: "distinct_rn", Table: "d"},
+			Operator: types.EQ,
+			Value:    types.Param{Literal: types.LiteralOne},
+		}).
+		OrderBy(instance.WithTable(instance.F("user_id"), "d"), types.ASC).
+		OrderBy(instance.WithTable(instance.F("title"), "d"), types.DESC).
+		Limit(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if d := astql.Diff(got, want); d != "" {
+		t.Errorf("rewrite differs from the hand-written window query: %s", d)
+	}
+
+	original, err := distinctOn().Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	rewritten := got.TargetSubquery
+	window := rewritten.FieldExpressions[0].Window.Window
+	if !reflect.DeepEqual(window.PartitionBy, original.DistinctOn) {
+		t.Errorf("PARTITION BY = %v, want DISTINCT ON fields %v", window.PartitionBy, original.DistinctOn)
+	}
+	if !reflect.DeepEqual(window.OrderBy, original.Ordering) {
+		t.Errorf("window ORDER BY = %v, want query ORDER BY %v", window.OrderBy, original.Ordering)
+	}
+
+	result, err := postgres.New().Render(got)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT d."user_id", d."title" FROM (SELECT "user_id", "title", ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "user_id" ASC, "title" DESC) AS "distinct_rn" FROM "posts") AS d WHERE d."distinct_rn" = 1 ORDER BY d."user_id" ASC, d."title" DESC LIMIT 10`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if _, err := sqlite.New().Render(got); err != nil {
+		t.Errorf("rewrite should render on SQLite: %v", err)
+	}
+}
+
+func TestDistinctOnAsWindow_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+		want    string
+	}{
+		{"no distinct on", astql.Select(instance.T("posts")).Fields(instance.F("id")), "DISTINCT ON queries"},
+		{"select star", astql.Select(instance.T("posts")).DistinctOn(instance.F("user_id")), "explicit select list"},
+		{"unselected order by", astql.Select(instance.T("posts")).
+			DistinctOn(instance.F("user_id")).
+			Fields(instance.F("user_id")).
+			OrderBy(instance.F("title"), types.ASC), "ORDER BY field title"},
+		{"row lock", astql.Select(instance.T("posts")).
+			DistinctOn(instance.F("user_id")).
+			Fields(instance.F("user_id")).
+			ForUpdate(), "row locks"},
+		{"order by expression alias", astql.Select(instance.T("posts")).
+			DistinctOn(instance.F("user_id")).
+			Fields(instance.F("user_id")).
+			SelectExpr(astql.As(astql.Upper(instance.F("title")), "upper_title")).
+			OrderBy(instance.F("user_id"), types.ASC).
+			OrderBy(types.Field{Name: "upper_title"}, types.ASC), "select expression alias upper_title"},
+		{"duplicate column names", astql.Select(instance.T("users", "u")).
+			InnerJoin(instance.T("posts", "p"), astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p"))).
+			DistinctOn(instance.WithTable(instance.F("id"), "u")).
+			Fields(instance.WithTable(instance.F("id"), "u"), instance.WithTable(instance.F("id"), "p")), "two columns named id"},
+		{"alias shadows column", astql.Select(instance.T("posts")).
+			DistinctOn(instance.F("user_id")).
+			Fields(instance.F("title")).
+			SelectExpr(astql.As(astql.Upper(instance.F("title")), "title")), "two columns named title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.DistinctOnAsWindow().Build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // FOR UPDATE/FOR SHARE Tests
 // =============================================================================
@@ -689,6 +908,36 @@ func TestForUpdate(t *testing.T) {
 	}
 }
 
+func TestForUpdateOf(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	posts := instance.T("posts", "p")
+	result, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("id"), "u")).
+		InnerJoin(posts, astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p"))).
+		ForUpdateOf(posts).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT u."id" FROM "users" u INNER JOIN "posts" p ON u."id" = p."user_id" FOR UPDATE OF p`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestForUpdateOf_TableNotInQuery(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	_, err := astql.Select(instance.T("users", "u")).
+		ForUpdateOf(instance.T("posts", "p")).
+		Render(postgres.New())
+	if err == nil {
+		t.Error("Expected error locking a table not in FROM or JOIN")
+	}
+}
+
 func TestForNoKeyUpdate(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
@@ -797,193 +1046,736 @@ func TestFullOuterJoin(t *testing.T) {
 	}
 }
 
-// =============================================================================
-// Compound Query (UNION/INTERSECT/EXCEPT) Tests
-// =============================================================================
-
-func TestCompoundQuery_Union(t *testing.T) {
+func TestGroupByAll(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).
-		Fields(instance.F("id"), instance.F("username")).
-		Where(instance.C(instance.F("age"), ">", instance.P("min_age")))
+	result, err := astql.Select(instance.T("users")).
+		GroupByAll().
+		Fields(instance.F("username"), instance.F("email")).
+		SelectExpr(astql.CountField(instance.F("id"))).
+		SelectExpr(astql.SumOver(instance.F("age")).Build()).
+		HavingAgg(astql.HavingCount(astql.GT, instance.P("min_count"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
 
-	query2 := astql.Select(instance.T("users")).
-		Fields(instance.F("id"), instance.F("username")).
-		Where(instance.C(instance.F("email"), "LIKE", instance.P("pattern")))
+	expected := `SELECT "username", "email", COUNT("id"), SUM("age") OVER () FROM "users" GROUP BY "username", "email" HAVING COUNT(*) > :min_count`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
 
-	result, err := astql.Union(query1, query2).Render(postgres.New())
+func TestGroupByAll_WithExplicitFields(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("username"), instance.F("age")).
+		SelectExpr(astql.CountField(instance.F("id"))).
+		GroupBy(instance.F("age"), instance.F("email")).
+		GroupByAll().
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "username", "age", COUNT("id") FROM "users" GROUP BY "age", "email", "username"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 
-	// Params should be namespaced
-	hasQ0 := false
-	hasQ1 := false
-	for _, p := range result.RequiredParams {
-		if p[:3] == "q0_" {
-			hasQ0 = true
-		}
-		if p[:3] == "q1_" {
-			hasQ1 = true
-		}
+	// Only aggregates projected: nothing to group by.
+	result, err = astql.Select(instance.T("users")).
+		SelectExpr(astql.CountField(instance.F("id"))).
+		GroupByAll().
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
 	}
-	if !hasQ0 || !hasQ1 {
-		t.Errorf("Expected namespaced params (q0_, q1_), got: %v", result.RequiredParams)
+	if expected := `SELECT COUNT("id") FROM "users"`; result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_UnionAll(t *testing.T) {
+func TestGroupByAll_Errors(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	if _, err := astql.Delete(instance.T("users")).GroupByAll().Build(); err == nil {
+		t.Error("Expected error for GroupByAll on DELETE")
+	}
+	if _, err := astql.Select(instance.T("users")).GroupByRollup(instance.F("age")).GroupByAll().Build(); err == nil {
+		t.Error("Expected error for GroupByAll with ROLLUP")
+	}
+}
 
-	result, err := astql.UnionAll(query1, query2).Render(postgres.New())
+func TestGroupByRollup(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id"), instance.F("title")).
+		GroupByRollup(instance.F("user_id"), instance.F("title")).
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "user_id", "title" FROM "posts" GROUP BY ROLLUP("user_id", "title")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_Intersect(t *testing.T) {
+func TestGroupByCube(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-
-	result, err := astql.Intersect(query1, query2).Render(postgres.New())
+	result, err := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		GroupByCube(instance.F("user_id"), instance.F("title")).
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "user_id" FROM "posts" GROUP BY CUBE("user_id", "title")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_Except(t *testing.T) {
+func TestGroupBySets(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-
-	result, err := astql.Except(query1, query2).Render(postgres.New())
+	result, err := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		GroupBySets(
+			[]types.Field{instance.F("user_id")},
+			[]types.Field{instance.F("title")},
+			[]types.Field{},
+		).
+		HavingAgg(astql.HavingCount(">", instance.P("min"))).
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "user_id" FROM "posts" GROUP BY GROUPING SETS(("user_id"), ("title"), ()) HAVING COUNT(*) > :min`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_IntersectAll(t *testing.T) {
+func TestGroupByCoalesce(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-
-	result, err := astql.IntersectAll(query1, query2).Render(postgres.New())
+	result, err := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		GroupBy(instance.F("user_id")).
+		GroupByCoalesce(instance.F("title"), instance.P("untitled")).
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "user_id" FROM "posts" GROUP BY "user_id", COALESCE("title", :untitled)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "untitled" {
+		t.Errorf("RequiredParams = %v, want [untitled]", result.RequiredParams)
 	}
 }
 
-func TestCompoundQuery_ExceptAll(t *testing.T) {
+func TestGroupByModes_Conflict(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"rollup then cube", astql.Select(instance.T("posts")).
+			GroupByRollup(instance.F("user_id")).
+			GroupByCube(instance.F("title"))},
+		{"plain then rollup", astql.Select(instance.T("posts")).
+			GroupBy(instance.F("user_id")).
+			GroupByRollup(instance.F("title"))},
+		{"rollup then plain", astql.Select(instance.T("posts")).
+			GroupByRollup(instance.F("user_id")).
+			GroupBy(instance.F("title"))},
+		{"cube then plain", astql.Select(instance.T("posts")).
+			GroupByCube(instance.F("user_id")).
+			GroupBy(instance.F("title"))},
+		{"sets then plain", astql.Select(instance.T("posts")).
+			GroupBySets([]types.Field{instance.F("user_id")}).
+			GroupBy(instance.F("title"))},
+		{"rollup on update", astql.Update(instance.T("posts")).
+			GroupByRollup(instance.F("user_id"))},
+		{"empty sets", astql.Select(instance.T("posts")).GroupBySets()},
+		{"coalesce then rollup", astql.Select(instance.T("posts")).
+			GroupByCoalesce(instance.F("title"), instance.P("untitled")).
+			GroupByRollup(instance.F("user_id"))},
+		{"rollup then coalesce", astql.Select(instance.T("posts")).
+			GroupByRollup(instance.F("user_id")).
+			GroupByCoalesce(instance.F("title"), instance.P("untitled"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
 
-	result, err := astql.ExceptAll(query1, query2).Render(postgres.New())
+func TestFullJoin(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		FullJoin(
+			instance.T("posts"),
+			astql.CF(instance.F("id"), "=", instance.F("user_id")),
+		).
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "username" FROM "users" FULL OUTER JOIN "posts" ON "id" = "user_id"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_WithOrderByLimit(t *testing.T) {
+func TestNaturalJoin(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"), instance.F("username"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"), instance.F("username"))
-
-	result, err := astql.Union(query1, query2).
-		OrderBy(instance.F("username"), types.ASC).
-		Limit(10).
-		Offset(5).
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		NaturalJoin(instance.T("posts")).
 		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "username" FROM "users" NATURAL JOIN "posts"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-func TestCompoundQuery_Chain(t *testing.T) {
+func TestJoinUsing(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query3 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-
-	result, err := astql.Union(query1, query2).
-		Intersect(query3).
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		InnerJoin(instance.T("posts"), nil).
+		Using(instance.F("id")).
 		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	expected := `SELECT "username" FROM "users" INNER JOIN "posts" USING ("id")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
 }
 
-// Test Builder.Union method (not top-level Union function).
-func TestBuilder_Union(t *testing.T) {
+func TestJoinUsing_Errors(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-
-	result, err := query1.Union(query2).Render(postgres.New())
-	if err != nil {
-		t.Fatalf("Render failed: %v", err)
-	}
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"no join", astql.Select(instance.T("users")).Using(instance.F("id"))},
+		{"no columns", astql.Select(instance.T("users")).InnerJoin(instance.T("posts"), nil).Using()},
+		{"with ON", astql.Select(instance.T("users")).
+			InnerJoin(instance.T("posts"), astql.CF(instance.F("id"), "=", instance.F("user_id"))).
+			Using(instance.F("id"))},
+		{"cross join", astql.Select(instance.T("users")).CrossJoin(instance.T("posts")).Using(instance.F("id"))},
+		{"neither ON nor USING", astql.Select(instance.T("users")).InnerJoin(instance.T("posts"), nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
 	}
 }
 
-func TestBuilder_UnionAll(t *testing.T) {
+func TestCrossJoinLateral(t *testing.T) {
 	instance := createBuilderTestInstance(t)
 
-	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
-	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	latest := astql.Select(instance.T("posts", "p")).
+		Fields(instance.WithTable(instance.F("id"), "p"), instance.WithTable(instance.F("title"), "p")).
+		Where(astql.CF(instance.WithTable(instance.F("user_id"), "p"), "=", instance.WithTable(instance.F("id"), "u"))).
+		OrderBy(instance.WithTable(instance.F("id"), "p"), astql.DESC).
+		Limit(3)
 
-	result, err := query1.UnionAll(query2).Render(postgres.New())
+	result, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u"), instance.WithTable(instance.F("title"), "x")).
+		CrossJoinLateral(latest, "x", "post_id", "title").
+		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
-	if result.SQL == "" {
-		t.Error("Expected non-empty SQL")
+
+	expected := `SELECT u."username", x."title" FROM "users" u CROSS JOIN LATERAL (SELECT p."id", p."title" FROM "posts" p WHERE p."user_id" = u."id" ORDER BY p."id" DESC LIMIT 3) AS x("post_id", "title")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestCrossJoinLateral_CorrelatedWithParams(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	// Top N posts per user: the outer column u."id" stays a plain field
+	// reference while the inner params are namespaced.
+	topPosts := astql.Select(instance.T("posts", "p")).
+		Fields(instance.WithTable(instance.F("title"), "p")).
+		Where(instance.And(
+			astql.CF(instance.WithTable(instance.F("user_id"), "p"), "=", instance.WithTable(instance.F("id"), "u")),
+			instance.C(instance.WithTable(instance.F("title"), "p"), astql.NE, instance.P("title")),
+		)).
+		OrderBy(instance.WithTable(instance.F("id"), "p"), astql.DESC).
+		LimitParam(instance.P("per_user"))
+
+	result, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u"), instance.WithTable(instance.F("title"), "x")).
+		CrossJoinLateral(topPosts, "x").
+		Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("age"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT u."username", x."title" FROM "users" u CROSS JOIN LATERAL (SELECT p."title" FROM "posts" p WHERE (p."user_id" = u."id" AND p."title" != :sq1_title) ORDER BY p."id" DESC LIMIT :sq1_per_user) AS x WHERE u."age" >= :age`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	if fmt.Sprint(result.RequiredParams) != "[sq1_title sq1_per_user age]" {
+		t.Errorf("RequiredParams = %v, want [sq1_title sq1_per_user age]", result.RequiredParams)
+	}
+}
+
+func TestSelfJoin(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("posts")).
+		Fields(instance.WithTable(instance.F("title"), "p"), instance.WithTable(instance.F("title"), "q")).
+		SelfJoin("p", "q", instance.F("user_id"), instance.F("user_id")).
+		Where(astql.CF(instance.WithTable(instance.F("id"), "p"), astql.LT, instance.WithTable(instance.F("id"), "q"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT p."title", q."title" FROM "posts" p INNER JOIN "posts" q ON p."user_id" = q."user_id" WHERE p."id" < q."id"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestSelfJoin_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	id, userID := instance.F("id"), instance.F("user_id")
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"same alias", astql.Select(instance.T("posts")).SelfJoin("p", "p", userID, id)},
+		{"invalid alias", astql.Select(instance.T("posts")).SelfJoin("parent", "q", userID, id)},
+		{"conflicting target alias", astql.Select(instance.T("posts", "x")).SelfJoin("p", "q", userID, id)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWhereIf(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	byAge := instance.C(instance.F("age"), astql.GT, instance.P("min_age"))
+	byName := instance.C(instance.F("username"), astql.EQ, instance.P("name"))
+
+	omitted, err := astql.Select(instance.T("users")).
+		Where(byAge).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	skipped, err := astql.Select(instance.T("users")).
+		WhereIf(false, byName).
+		Where(byAge).
+		WhereIf(false, byName).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if skipped.SQL != omitted.SQL {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", omitted.SQL, skipped.SQL)
+	}
+	if fmt.Sprint(skipped.RequiredParams) != "[min_age]" {
+		t.Errorf("RequiredParams = %v, want [min_age]", skipped.RequiredParams)
+	}
+
+	applied, err := astql.Select(instance.T("users")).
+		Where(byAge).
+		WhereIf(true, byName).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" WHERE ("age" > :min_age AND "username" = :name)`
+	if applied.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, applied.SQL)
+	}
+}
+
+func TestFieldsIf(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	skipped, err := astql.Select(instance.T("users")).
+		FieldsIf(false, instance.F("id"), instance.F("email")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := `SELECT * FROM "users"`; skipped.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, skipped.SQL)
+	}
+
+	applied, err := astql.Select(instance.T("users")).
+		FieldsIf(true, instance.F("id"), instance.F("email")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := `SELECT "id", "email" FROM "users"`; applied.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, applied.SQL)
+	}
+
+	// A false condition skips the SELECT-only check as well.
+	if _, err := astql.Delete(instance.T("users")).FieldsIf(false, instance.F("id")).Build(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestJoinSub(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	counts := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("title"), astql.NE, instance.P("title"))).
+		GroupBy(instance.F("user_id"))
+
+	result, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		JoinSub(counts, "t", astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "t"))).
+		Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GT, instance.P("age"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT u."username" FROM "users" u INNER JOIN (SELECT "user_id" FROM "posts" WHERE "title" != :sq1_title GROUP BY "user_id") AS t ON u."id" = t."user_id" WHERE u."age" > :age`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if !contains(result.RequiredParams, "sq1_title") || !contains(result.RequiredParams, "age") {
+		t.Errorf("RequiredParams = %v, want sq1_title and age", result.RequiredParams)
+	}
+}
+
+func TestJoinSub_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	sub := func() *astql.Builder { return astql.Select(instance.T("posts")) }
+	on := astql.CF(instance.F("id"), "=", instance.F("user_id"))
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"empty alias", astql.Select(instance.T("users")).JoinSub(sub(), "", on)},
+		{"invalid alias", astql.Select(instance.T("users")).JoinSub(sub(), "bad alias", on)},
+		{"missing ON", astql.Select(instance.T("users")).JoinSub(sub(), "t", nil)},
+		{"nil subquery", astql.Select(instance.T("users")).JoinSub(nil, "t", on)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLeftJoinLateral_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	sub := func() *astql.Builder { return astql.Select(instance.T("posts")) }
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"invalid alias", astql.Select(instance.T("users")).LeftJoinLateral(sub(), "bad alias", astql.CF(instance.F("id"), "=", instance.F("user_id")))},
+		{"missing ON", astql.Select(instance.T("users")).LeftJoinLateral(sub(), "x", nil)},
+		{"invalid column", astql.Select(instance.T("users")).CrossJoinLateral(sub(), "x", "bad;col")},
+		{"nil subquery", astql.Select(instance.T("users")).CrossJoinLateral(nil, "x")},
+		{"non-select subquery", astql.Select(instance.T("users")).CrossJoinLateral(astql.Delete(instance.T("posts")), "x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestSelectFrom(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	sub := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id"), instance.F("title")).
+		Where(instance.C(instance.F("title"), "=", instance.P("title")))
+
+	result, err := astql.SelectFrom(sub, "sub").
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("user_id"), "=", instance.P("user_id"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "user_id" FROM (SELECT "user_id", "title" FROM "posts" WHERE "title" = :sq1_title) AS sub WHERE "user_id" = :user_id`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestSelectFrom_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"nil subquery", astql.SelectFrom(nil, "sub")},
+		{"invalid alias", astql.SelectFrom(astql.Select(instance.T("posts")), "bad alias")},
+		{"empty alias", astql.SelectFrom(astql.Select(instance.T("posts")), "")},
+		{"non-select subquery", astql.SelectFrom(astql.Delete(instance.T("posts")), "sub")},
+		{"invalid subquery", astql.SelectFrom(astql.Select(instance.T("posts")).Set(instance.F("title"), instance.P("t")), "sub")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Compound Query (UNION/INTERSECT/EXCEPT) Tests
+// =============================================================================
+
+func TestCompoundQuery_Union(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("username")).
+		Where(instance.C(instance.F("age"), ">", instance.P("min_age")))
+
+	query2 := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("username")).
+		Where(instance.C(instance.F("email"), "LIKE", instance.P("pattern")))
+
+	result, err := astql.Union(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+
+	// Params should be namespaced
+	hasQ0 := false
+	hasQ1 := false
+	for _, p := range result.RequiredParams {
+		if p[:3] == "q0_" {
+			hasQ0 = true
+		}
+		if p[:3] == "q1_" {
+			hasQ1 = true
+		}
+	}
+	if !hasQ0 || !hasQ1 {
+		t.Errorf("Expected namespaced params (q0_, q1_), got: %v", result.RequiredParams)
+	}
+}
+
+func TestCompoundQuery_UnionAll(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.UnionAll(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_Intersect(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.Intersect(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_Except(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.Except(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_IntersectAll(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.IntersectAll(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_ExceptAll(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.ExceptAll(query1, query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_WithOrderByLimit(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"), instance.F("username"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"), instance.F("username"))
+
+	result, err := astql.Union(query1, query2).
+		OrderBy(instance.F("username"), types.ASC).
+		Limit(10).
+		Offset(5).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestCompoundQuery_Chain(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query3 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := astql.Union(query1, query2).
+		Intersect(query3).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+// Test Builder.Union method (not top-level Union function).
+func TestBuilder_Union(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := query1.Union(query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
+	}
+}
+
+func TestBuilder_UnionAll(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+	query2 := astql.Select(instance.T("users")).Fields(instance.F("id"))
+
+	result, err := query1.UnionAll(query2).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if result.SQL == "" {
+		t.Error("Expected non-empty SQL")
 	}
 }
 
@@ -1153,3 +1945,392 @@ func TestCompoundBuilder_MustRender(t *testing.T) {
 		t.Error("Expected non-empty SQL")
 	}
 }
+
+func TestBuilder_RenderWithOptions_LowerKeywords(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("username")).
+		Where(instance.C(instance.F("age"), astql.GT, instance.P("minAge"))).
+		Limit(10).
+		RenderWithOptions(postgres.New(), astql.RenderOptions{KeywordCase: astql.KeywordLower})
+	if err != nil {
+		t.Fatalf("RenderWithOptions failed: %v", err)
+	}
+
+	expected := `select "id", "username" from "users" where "age" > :minAge limit 10`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestInsert_ValueSubquery(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	vm := instance.ValueMap()
+	vm[instance.F("title")] = instance.P("title")
+
+	author := astql.Sub(astql.Select(instance.T("users")).
+		Fields(instance.F("id")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("username"))))
+
+	result, err := astql.Insert(instance.T("posts")).
+		Values(vm).
+		ValueSubquery(instance.F("user_id"), author).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `INSERT INTO "posts" ("title", "user_id") VALUES (:title, (SELECT "id" FROM "users" WHERE "username" = :sq1_username))`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 2 || result.RequiredParams[0] != "title" || result.RequiredParams[1] != "sq1_username" {
+		t.Errorf("Expected [title sq1_username], got %v", result.RequiredParams)
+	}
+}
+
+func TestInsert_ValueSubqueryRequiresValues(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	_, err := astql.Insert(instance.T("posts")).
+		ValueSubquery(instance.F("user_id"), astql.Sub(astql.Select(instance.T("users")).Fields(instance.F("id")))).
+		Build()
+	if err == nil {
+		t.Error("Expected error for ValueSubquery without Values")
+	}
+}
+
+func TestWhereInValues(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		WhereInValues(instance.F("id"), []types.Param{instance.P("a"), instance.P("b")}).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" IN (:a, :b)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestWhereInValuesTable(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	params := []types.Param{instance.P("a"), instance.P("b"), instance.P("c")}
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		list     string
+		table    string
+	}{
+		{"postgres", postgres.New(), `SELECT * FROM "users" WHERE "id" IN (:a, :b, :c)`, `SELECT * FROM "users" WHERE "id" IN (VALUES (:a), (:b), (:c))`},
+		{"sqlite", sqlite.New(), `SELECT * FROM "users" WHERE "id" IN (:a, :b, :c)`, `SELECT * FROM "users" WHERE "id" IN (VALUES (:a), (:b), (:c))`},
+		{"mssql", mssql.New(), `SELECT * FROM [users] WHERE [id] IN (:a, :b, :c)`, `SELECT * FROM [users] WHERE [id] IN (SELECT [v] FROM (VALUES (:a), (:b), (:c)) AS [vals]([v]))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := astql.Select(instance.T("users")).WhereInValues(instance.F("id"), params).Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			table, err := astql.Select(instance.T("users")).WhereInValuesTable(instance.F("id"), params).Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			if list.SQL != tt.list {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.list, list.SQL)
+			}
+			if table.SQL != tt.table {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.table, table.SQL)
+			}
+			// Both forms bind the same parameters in the same order.
+			if fmt.Sprint(table.RequiredParams) != fmt.Sprint(list.RequiredParams) {
+				t.Errorf("RequiredParams = %v, want %v", table.RequiredParams, list.RequiredParams)
+			}
+		})
+	}
+
+	empty, err := astql.Select(instance.T("users")).WhereInValuesTable(instance.F("id"), nil).Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := `SELECT * FROM "users" WHERE 1 = 0`; empty.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, empty.SQL)
+	}
+}
+
+func TestWhereInValues_Empty(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("age"), astql.GT, instance.P("age"))).
+		WhereInValues(instance.F("id"), nil)
+
+	result, err := query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("age" > :age AND 1 = 0)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	if _, err := query.Render(postgres.NewWithOptions(postgres.Options{StrictEmptyIn: true})); err == nil {
+		t.Error("expected error for empty IN list in strict mode")
+	}
+}
+
+func TestKeysetAfter(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+	keyset := func() *astql.Builder {
+		return astql.Select(instance.T("users")).
+			Fields(instance.F("id"), instance.F("username")).
+			KeysetAfter(
+				[]types.Field{instance.F("age"), instance.F("id")},
+				[]types.Param{instance.P("cursor_age"), instance.P("cursor_id")},
+			).
+			Limit(20)
+	}
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		expected string
+	}{
+		{"postgres", postgres.New(), `SELECT "id", "username" FROM "users" WHERE ("age", "id") > (:cursor_age, :cursor_id) ORDER BY "age" ASC, "id" ASC LIMIT 20`},
+		{"mssql", mssql.New(), `SELECT TOP (20) [id], [username] FROM [users] WHERE ([age] > :cursor_age OR ([age] = :cursor_age AND [id] > :cursor_id)) ORDER BY [age] ASC, [id] ASC`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := keyset().Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+		})
+	}
+
+	single, err := astql.Select(instance.T("users")).
+		KeysetAfter([]types.Field{instance.F("id")}, []types.Param{instance.P("cursor")}).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" WHERE "id" > :cursor ORDER BY "id" ASC`
+	if single.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, single.SQL)
+	}
+
+	_, err = astql.Select(instance.T("users")).
+		KeysetAfter([]types.Field{instance.F("age"), instance.F("id")}, []types.Param{instance.P("cursor")}).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "2 fields but 1 values") {
+		t.Errorf("Build() error = %v, want field/value count mismatch", err)
+	}
+}
+
+func TestRows(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	rows := make([]map[types.Field]types.Param, 3)
+	for i := range rows {
+		rows[i] = map[types.Field]types.Param{
+			instance.F("username"): instance.P(fmt.Sprintf("username_%d", i)),
+			instance.F("email"):    instance.P(fmt.Sprintf("email_%d", i)),
+		}
+	}
+
+	result, err := astql.Insert(instance.T("users")).
+		Rows(rows[:2]...).
+		AddRow(rows[2]).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `INSERT INTO "users" ("email", "username") VALUES (:email_0, :username_0), (:email_1, :username_1), (:email_2, :username_2)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	seen := make(map[string]bool)
+	for _, name := range result.RequiredParams {
+		if seen[name] {
+			t.Errorf("param %q bound by more than one row", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("RequiredParams = %v, want 6 distinct params", result.RequiredParams)
+	}
+}
+
+func TestRows_Errors(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	_, err := astql.Insert(instance.T("users")).
+		Rows(
+			map[types.Field]types.Param{instance.F("username"): instance.P("u0"), instance.F("email"): instance.P("e0")},
+			map[types.Field]types.Param{instance.F("username"): instance.P("u1")},
+		).
+		Build()
+	want := "AddRow() row 1 has columns (username), want (email, username) like row 0"
+	if err == nil || err.Error() != want {
+		t.Errorf("Build() error = %v, want %q", err, want)
+	}
+
+	_, err = astql.Select(instance.T("users")).AddRow(instance.ValueMap()).Build()
+	if err == nil || !strings.Contains(err.Error(), "can only be used with INSERT queries") {
+		t.Errorf("Build() error = %v, want INSERT-only error", err)
+	}
+}
+
+func TestWithTies(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		OrderBy(instance.F("age"), types.DESC).
+		LimitParam(instance.P("n")).
+		WithTies().
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" ORDER BY "age" DESC FETCH FIRST :n ROWS WITH TIES`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	_, err = astql.Select(instance.T("users")).Limit(5).WithTies().Build()
+	if err == nil || err.Error() != "WITH TIES requires ORDER BY" {
+		t.Errorf("Build() error = %v, want WITH TIES requires ORDER BY", err)
+	}
+	_, err = astql.Select(instance.T("users")).OrderBy(instance.F("age"), types.DESC).WithTies().Build()
+	if err == nil || err.Error() != "WITH TIES requires LIMIT" {
+		t.Errorf("Build() error = %v, want WITH TIES requires LIMIT", err)
+	}
+}
+
+func TestReturningCount(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Update(instance.T("users")).
+		Set(instance.F("age"), instance.P("age")).
+		Where(instance.C(instance.F("age"), astql.LT, instance.P("min_age"))).
+		ReturningCount().
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `WITH "affected" AS (UPDATE "users" SET "age" = :age WHERE "age" < :min_age RETURNING 1) SELECT COUNT(*) FROM "affected"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	_, err = astql.Delete(instance.T("users")).ReturningCount().Returning(instance.F("id")).Build()
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined with RETURNING fields") {
+		t.Errorf("Build() error = %v, want RETURNING conflict", err)
+	}
+	_, err = astql.Select(instance.T("users")).ReturningCount().Build()
+	if err == nil || !strings.Contains(err.Error(), "UPDATE or DELETE") {
+		t.Errorf("Build() error = %v, want UPDATE or DELETE error", err)
+	}
+	_, err = astql.Delete(instance.T("users")).ReturningCount().Render(sqlite.New())
+	if err == nil || !strings.Contains(err.Error(), "RETURNING count") {
+		t.Errorf("SQLite Render() error = %v, want RETURNING count unsupported", err)
+	}
+}
+
+func TestOnConflictWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{
+			instance.F("email"):    instance.P("email"),
+			instance.F("username"): instance.P("username"),
+		}).
+		OnConflict(instance.F("email")).
+		Where(instance.C(instance.F("age"), astql.GE, instance.P("min_age"))).
+		DoUpdate().
+		Set(instance.F("username"), instance.P("new_username")).
+		Where(instance.C(instance.F("username"), astql.NE, instance.P("locked_username"))).
+		Build().
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `INSERT INTO "users" ("email", "username") VALUES (:email, :username) ON CONFLICT ("email") WHERE "age" >= :min_age DO UPDATE SET "username" = :new_username WHERE "username" != :locked_username`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	wantParams := []string{"email", "username", "min_age", "new_username", "locked_username"}
+	if !reflect.DeepEqual(result.RequiredParams, wantParams) {
+		t.Errorf("RequiredParams = %v, want %v", result.RequiredParams, wantParams)
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{
+			instance.F("email"):    instance.P("email"),
+			instance.F("username"): instance.P("username"),
+		}).
+		OnConflict(instance.F("email")).
+		DoUpdate().
+		Set(instance.F("username"), astql.Excluded(instance.F("username"))).
+		Build().
+		Render(sqlite.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `INSERT INTO "users" ("email", "username") VALUES (:email, :username) ON CONFLICT ("email") DO UPDATE SET "username" = EXCLUDED."username"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	_, err = astql.Update(instance.T("users")).
+		Set(instance.F("username"), astql.Excluded(instance.F("username"))).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "only valid in ON CONFLICT DO UPDATE") {
+		t.Errorf("Build() error = %v, want EXCLUDED outside ON CONFLICT error", err)
+	}
+}
+
+func TestValuesTable(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	labels := astql.ValuesTable("v", []string{"id", "title"}, [][]types.Param{
+		{instance.P("id1"), instance.P("title1")},
+		{instance.P("id2"), instance.P("title2")},
+	})
+	result, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u"), instance.WithTable(instance.F("title"), "v")).
+		InnerJoin(labels, astql.CF(instance.WithTable(instance.F("id"), "u"), astql.EQ, instance.WithTable(instance.F("id"), "v"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT u."username", v."title" FROM "users" u INNER JOIN (VALUES (:id1, :title1), (:id2, :title2)) AS v("id", "title") ON u."id" = v."id"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a row with the wrong number of values")
+		}
+	}()
+	astql.ValuesTable("v", []string{"id", "title"}, [][]types.Param{{instance.P("id1")}})
+}
@@ -0,0 +1,8 @@
+package astql
+
+// Clone returns an independent copy of the builder for branching a base query.
+// The AST is deep-copied, so adding conditions, fields, or joins to the clone
+// never affects the original. A builder error is carried over to the clone.
+func (b *Builder) Clone() *Builder {
+	return &Builder{ast: b.ast.Clone(), err: b.err}
+}
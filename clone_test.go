@@ -0,0 +1,134 @@
+package astql_test
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/internal/types"
+	"github.com/zoobzio/astql/postgres"
+)
+
+func TestBuilder_Clone_IndependentWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	base := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("username")).
+		Where(instance.And(
+			instance.C(instance.F("age"), astql.GE, instance.P("min_age")),
+			instance.NotNull(instance.F("email")),
+		)).
+		OrderBy(instance.F("id"), astql.ASC)
+
+	before, err := base.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	branch := base.Clone().
+		Fields(instance.F("email")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+		OrderBy(instance.F("username"), astql.DESC).
+		Limit(5)
+
+	// The clone's WHERE is (base group AND username); editing the copied base
+	// group in place must not reach the original either.
+	inner := branch.GetAST().WhereClause.(types.ConditionGroup).Conditions[0].(types.ConditionGroup)
+	inner.Conditions[0] = instance.C(instance.F("age"), astql.LT, instance.P("max_age"))
+
+	after, err := base.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if after.SQL != before.SQL {
+		t.Errorf("original changed after mutating the clone:\nbefore: %s\nafter:  %s", before.SQL, after.SQL)
+	}
+
+	result, err := branch.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT "email" FROM "users" WHERE (("age" < :max_age AND "email" IS NOT NULL) AND "username" = :name) ORDER BY "id" ASC, "username" DESC LIMIT 5`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestBuilder_Clone_DeepCopiesSubqueriesAndMaps(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	sub := astql.Sub(astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("title"), astql.EQ, instance.P("title"))))
+	base := astql.Select(instance.T("users")).
+		Where(astql.CSub(instance.F("id"), astql.IN, sub))
+
+	clone := base.Clone()
+	clone.GetAST().WhereClause.(types.SubqueryCondition).Subquery.AST.Fields[0] = instance.F("id")
+	if base.GetAST().WhereClause.(types.SubqueryCondition).Subquery.AST.Fields[0].Name != "user_id" {
+		t.Error("subquery AST is shared with the clone")
+	}
+
+	update := astql.Update(instance.T("users")).
+		Set(instance.F("username"), instance.P("name")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id")))
+	updateClone := update.Clone().Set(instance.F("email"), instance.P("email"))
+	if len(update.GetAST().Updates) != 1 || len(updateClone.GetAST().Updates) != 2 {
+		t.Errorf("Updates map shared: original %d, clone %d", len(update.GetAST().Updates), len(updateClone.GetAST().Updates))
+	}
+}
+
+func TestBuilder_Clone_CarriesError(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	broken := astql.Delete(instance.T("users")).GroupBy(instance.F("id"))
+	if _, err := broken.Clone().Build(); err == nil {
+		t.Error("Expected the clone to keep the builder error")
+	}
+}
+
+func TestAST_Clone_TenantFilterMiddleware(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	recent := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("title"), astql.NE, instance.P("title")))
+	ast, err := astql.Select(instance.T("users")).
+		Fields(instance.F("id")).
+		Where(astql.CSub(instance.F("id"), astql.IN, astql.Sub(recent))).
+		Limit(10).
+		ForUpdate().
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	before, err := postgres.New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	// What a tenant-scoping middleware does to its own copy.
+	scoped := ast.Clone()
+	tenant := instance.C(instance.F("id"), astql.EQ, instance.P("tenant_id"))
+	scoped.WhereClause = instance.And(scoped.WhereClause, tenant)
+	scoped.WhereClause.(types.ConditionGroup).Conditions[0].(types.SubqueryCondition).Subquery.AST.WhereClause = tenant
+	scoped.Fields = append(scoped.Fields, instance.F("email"))
+	scoped.Fields[0] = instance.F("username")
+	*scoped.Limit.Static = 1
+
+	after, err := postgres.New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if after.SQL != before.SQL {
+		t.Errorf("original AST changed:\nbefore: %s\nafter:  %s", before.SQL, after.SQL)
+	}
+
+	result, err := postgres.New().Render(scoped)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT "username", "email" FROM "users" WHERE ("id" IN (SELECT "user_id" FROM "posts" WHERE "id" = :sq1_tenant_id) AND "id" = :tenant_id) LIMIT 1 FOR UPDATE`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
@@ -0,0 +1,127 @@
+package astql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Equal reports whether two ASTs are structurally identical. Conditions are
+// compared by their concrete type and contents, and maps are compared by
+// entry regardless of iteration order.
+func Equal(a, b *types.AST) bool {
+	return Diff(a, b) == ""
+}
+
+// Diff describes the first structural difference between two ASTs, or
+// returns "" when they are equal. The description starts with the path to
+// the differing value, for example:
+//
+//	WhereClause.Conditions[1].Operator: "=" != ">"
+//
+// Map entries are addressed by key (Updates[email], Windows["w"]), and maps
+// keyed by Field match keys by content, not pointer identity.
+func Diff(a, b *types.AST) string {
+	return diffValues(reflect.ValueOf(a), reflect.ValueOf(b), "")
+}
+
+func diffValues(a, b reflect.Value, path string) string {
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() == b.IsNil() {
+				return ""
+			}
+			return fmt.Sprintf("%s: %s != %s", label(path), nilOrSet(a), nilOrSet(b))
+		}
+		if a.Kind() == reflect.Interface && a.Elem().Type() != b.Elem().Type() {
+			return fmt.Sprintf("%s: %s != %s", label(path), a.Elem().Type().Name(), b.Elem().Type().Name())
+		}
+		return diffValues(a.Elem(), b.Elem(), path)
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return fmt.Sprintf("%s: length %d != %d", label(path), a.Len(), b.Len())
+		}
+		for i := 0; i < a.Len(); i++ {
+			if d := diffValues(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i)); d != "" {
+				return d
+			}
+		}
+		return ""
+	case reflect.Map:
+		return diffMaps(a, b, path)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			name := a.Type().Field(i).Name
+			if path != "" {
+				name = path + "." + name
+			}
+			if d := diffValues(a.Field(i), b.Field(i), name); d != "" {
+				return d
+			}
+		}
+		return ""
+	default:
+		if a.Interface() != b.Interface() {
+			return fmt.Sprintf("%s: %#v != %#v", label(path), a.Interface(), b.Interface())
+		}
+		return ""
+	}
+}
+
+// diffMaps compares map entries in key order. Keys are matched by
+// content, so struct keys holding pointers still pair up.
+func diffMaps(a, b reflect.Value, path string) string {
+	if a.Len() != b.Len() {
+		return fmt.Sprintf("%s: length %d != %d", label(path), a.Len(), b.Len())
+	}
+	keys := a.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return mapKeyLabel(keys[i]) < mapKeyLabel(keys[j]) })
+
+	bKeys := b.MapKeys()
+	for _, key := range keys {
+		entry := fmt.Sprintf("%s[%s]", path, mapKeyLabel(key))
+		match := -1
+		for i, candidate := range bKeys {
+			if diffValues(key, candidate, "") == "" {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			return fmt.Sprintf("%s: missing in second AST", entry)
+		}
+		if d := diffValues(a.MapIndex(key), b.MapIndex(bKeys[match]), entry); d != "" {
+			return d
+		}
+	}
+	return ""
+}
+
+// mapKeyLabel formats a map key for a diff path: fields by their
+// qualified name, strings quoted.
+func mapKeyLabel(key reflect.Value) string {
+	if f, ok := key.Interface().(types.Field); ok {
+		if f.Table != "" {
+			return f.Table + "." + f.Name
+		}
+		return f.Name
+	}
+	return fmt.Sprintf("%q", key.Interface())
+}
+
+func nilOrSet(v reflect.Value) string {
+	if v.IsNil() {
+		return "nil"
+	}
+	return "set"
+}
+
+func label(path string) string {
+	if path == "" {
+		return "AST"
+	}
+	return path
+}
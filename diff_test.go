@@ -0,0 +1,97 @@
+package astql_test
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func buildAST(t *testing.T, b *astql.Builder) *types.AST {
+	t.Helper()
+	ast, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return ast
+}
+
+func TestEqual_SameShape(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	build := func() *types.AST {
+		return buildAST(t, astql.Update(instance.T("users")).
+			Set(instance.F("username"), instance.P("name")).
+			Set(instance.F("email"), instance.P("email")).
+			Set(instance.F("age"), instance.P("age")).
+			Where(instance.And(
+				instance.C(instance.F("id"), astql.EQ, instance.P("id")),
+				instance.NotNull(instance.F("email")),
+			)))
+	}
+
+	a, b := build(), build()
+	if !astql.Equal(a, b) {
+		t.Errorf("Equal() = false, diff: %s", astql.Diff(a, b))
+	}
+	if d := astql.Diff(a, a.Clone()); d != "" {
+		t.Errorf("Diff() against a clone = %q, want empty", d)
+	}
+	if !astql.Equal(nil, nil) || astql.Equal(a, nil) {
+		t.Error("Equal() mishandles nil ASTs")
+	}
+}
+
+func TestDiff_Operator(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	build := func(op types.Operator) *types.AST {
+		return buildAST(t, astql.Select(instance.T("users")).
+			Where(instance.And(
+				instance.C(instance.F("id"), astql.EQ, instance.P("id")),
+				instance.C(instance.F("age"), op, instance.P("age")),
+			)))
+	}
+
+	a, b := build(astql.EQ), build(astql.GT)
+	if astql.Equal(a, b) {
+		t.Fatal("Equal() = true for different operators")
+	}
+	expected := `WhereClause.Conditions[1].Operator: "=" != ">"`
+	if d := astql.Diff(a, b); d != expected {
+		t.Errorf("Diff() = %q, want %q", d, expected)
+	}
+}
+
+func TestDiff_NestedSubquery(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	build := func(field string) *types.AST {
+		sub := astql.Sub(astql.Select(instance.T("posts")).Fields(instance.F(field)))
+		return buildAST(t, astql.Select(instance.T("users")).
+			Where(astql.CSub(instance.F("id"), astql.IN, sub)))
+	}
+
+	expected := `WhereClause.Subquery.AST.Fields[0].Name: "user_id" != "id"`
+	if d := astql.Diff(build("user_id"), build("id")); d != expected {
+		t.Errorf("Diff() = %q, want %q", d, expected)
+	}
+}
+
+func TestDiff_ConditionTypeAndMaps(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	single := buildAST(t, astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))))
+	grouped := buildAST(t, astql.Select(instance.T("users")).
+		Where(instance.Or(instance.C(instance.F("id"), astql.EQ, instance.P("id")))))
+	if d := astql.Diff(single, grouped); d != "WhereClause: Condition != ConditionGroup" {
+		t.Errorf("Diff() = %q, want condition type difference", d)
+	}
+
+	a := buildAST(t, astql.Update(instance.T("users")).Set(instance.F("email"), instance.P("email")))
+	b := buildAST(t, astql.Update(instance.T("users")).Set(instance.F("email"), instance.P("new_email")))
+	if d := astql.Diff(a, b); d != `Updates[email].Name: "email" != "new_email"` {
+		t.Errorf("Diff() = %q, want the Updates entry", d)
+	}
+}
@@ -0,0 +1,472 @@
+package astql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// conditionKinds maps the JSON discriminator of each ConditionItem
+// implementation to its concrete type.
+var conditionKinds = map[string]reflect.Type{
+	"condition":        reflect.TypeOf(types.Condition{}),
+	"group":            reflect.TypeOf(types.ConditionGroup{}),
+	"aggregate":        reflect.TypeOf(types.AggregateCondition{}),
+	"between":          reflect.TypeOf(types.BetweenCondition{}),
+	"field_comparison": reflect.TypeOf(types.FieldComparison{}),
+	"subquery":         reflect.TypeOf(types.SubqueryCondition{}),
+	"tuple":            reflect.TypeOf(types.TupleCondition{}),
+	"in_list":          reflect.TypeOf(types.InListCondition{}),
+	"comparison":       reflect.TypeOf(types.Comparison{}),
+}
+
+// valueKinds maps the JSON discriminator of each Value implementation to
+// its concrete type.
+var valueKinds = map[string]reflect.Type{
+	"param":    reflect.TypeOf(types.Param{}),
+	"field":    reflect.TypeOf(types.Field{}),
+	"subquery": reflect.TypeOf(types.Subquery{}),
+}
+
+var conditionItemType = reflect.TypeOf((*types.ConditionItem)(nil)).Elem()
+
+var valueType = reflect.TypeOf((*types.Value)(nil)).Elem()
+
+var paramType = reflect.TypeOf(types.Param{})
+
+var fieldType = reflect.TypeOf(types.Field{})
+
+var tableType = reflect.TypeOf(types.Table{})
+
+// validatorType matches the closed-set string types (JoinType, CastType,
+// Operator, ...), which DecodeAST checks against their known values.
+var validatorType = reflect.TypeOf((*interface{ Validate() error })(nil)).Elem()
+
+// identifierFields lists, per struct type, the string fields that reach the
+// SQL as identifiers, aliases, or parameter names. DecodeAST holds them to the
+// same rules the builder applies, since nothing else checks them before
+// rendering. Table aliases are checked separately in checkIdentifiers.
+var identifierFields = map[reflect.Type][]string{
+	reflect.TypeOf(types.AST{}):                {"TargetAlias"},
+	tableType:                                  {"Name"},
+	reflect.TypeOf(types.ValuesTable{}):        {"Columns"},
+	reflect.TypeOf(types.Join{}):               {"Columns"},
+	fieldType:                                  {"Name", "Table"},
+	paramType:                                  {"Name"},
+	reflect.TypeOf(types.FieldExpression{}):    {"Alias"},
+	reflect.TypeOf(types.CaseExpression{}):     {"Alias"},
+	reflect.TypeOf(types.CoalesceExpression{}): {"Alias"},
+	reflect.TypeOf(types.NullIfExpression{}):   {"Alias"},
+	reflect.TypeOf(types.MathExpression{}):     {"Alias"},
+	reflect.TypeOf(types.StringExpression{}):   {"Alias"},
+	reflect.TypeOf(types.DateExpression{}):     {"Alias"},
+	reflect.TypeOf(types.AggregateCondition{}): {"Alias"},
+	reflect.TypeOf(types.JSONObjectPair{}):     {"Key"},
+	reflect.TypeOf(types.WindowExpression{}):   {"WindowRef"},
+}
+
+// interfaceKinds returns the discriminator table for an interface type
+// and the noun used for it in error messages.
+func interfaceKinds(t reflect.Type) (map[string]reflect.Type, string) {
+	if t == valueType {
+		return valueKinds, "value"
+	}
+	return conditionKinds, "condition"
+}
+
+// kindOf returns the discriminator for a concrete type in kinds.
+func kindOf(kinds map[string]reflect.Type, t reflect.Type) (string, bool) {
+	for name, kind := range kinds {
+		if kind == t {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// EncodeAST serializes an AST to JSON so a query definition can be stored
+// and rebuilt later with DecodeAST. Struct fields keep their Go names and
+// zero-valued fields are omitted. Condition items carry a "type"
+// discriminator ("condition", "group", "between", ...), and maps keyed by
+// Field are written as arrays of {"Key", "Value"} pairs.
+func EncodeAST(ast *types.AST) ([]byte, error) {
+	if ast == nil {
+		return nil, fmt.Errorf("cannot encode nil AST")
+	}
+	v, err := encodeValue(reflect.ValueOf(ast).Elem(), false)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// DecodeAST rebuilds an AST from the output of EncodeAST. Unknown fields
+// and condition types are rejected, as are table names, aliases, field names,
+// and parameter names that the builder would refuse, and values outside the
+// closed sets of join types, operators, cast types, and the like. The decoded
+// tree is not checked against a schema, so only decode data your application
+// encoded; rendering still runs the usual AST validation.
+func DecodeAST(data []byte) (*types.AST, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid AST JSON: %w", err)
+	}
+	ast := &types.AST{}
+	if err := decodeValue(raw, reflect.ValueOf(ast).Elem(), "AST"); err != nil {
+		return nil, err
+	}
+	return ast, nil
+}
+
+// encodeValue converts v into plain JSON values. With anonymize set, every
+// named Param is written as {"Name": "?"} so only the query shape remains.
+func encodeValue(v reflect.Value, anonymize bool) (any, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem(), anonymize)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		elem := v.Elem()
+		kinds, noun := interfaceKinds(v.Type())
+		kind, ok := kindOf(kinds, elem.Type())
+		if !ok {
+			return nil, fmt.Errorf("cannot encode %s of type %s", noun, elem.Type())
+		}
+		if anonymize && elem.Type() == paramType && elem.FieldByName("Name").String() != "" {
+			elem = reflect.ValueOf(types.Param{Name: "?"})
+		}
+		obj, err := encodeStruct(elem, anonymize)
+		if err != nil {
+			return nil, err
+		}
+		obj["type"] = kind
+		return obj, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			elem, err := encodeValue(v.Index(i), anonymize)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = elem
+		}
+		return out, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Key().Kind() == reflect.String {
+			out := make(map[string]any, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				elem, err := encodeValue(iter.Value(), anonymize)
+				if err != nil {
+					return nil, err
+				}
+				out[iter.Key().String()] = elem
+			}
+			return out, nil
+		}
+		return encodeEntries(v, anonymize)
+	case reflect.Struct:
+		if anonymize && v.Type() == paramType && v.FieldByName("Name").String() != "" {
+			v = reflect.ValueOf(types.Param{Name: "?"})
+		}
+		return encodeStruct(v, anonymize)
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("cannot encode value of kind %s", v.Kind())
+	}
+}
+
+// encodeStruct converts a struct into an object of its non-zero fields.
+func encodeStruct(v reflect.Value, anonymize bool) (map[string]any, error) {
+	out := make(map[string]any)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		elem, err := encodeValue(field, anonymize)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.Type().Field(i).Name, err)
+		}
+		out[v.Type().Field(i).Name] = elem
+	}
+	return out, nil
+}
+
+// encodeEntries converts a map with struct keys into key/value pairs,
+// sorted by encoded key so the output is deterministic.
+func encodeEntries(v reflect.Value, anonymize bool) (any, error) {
+	type entry struct {
+		sortKey string
+		pair    map[string]any
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := encodeValue(iter.Key(), anonymize)
+		if err != nil {
+			return nil, err
+		}
+		value, err := encodeValue(iter.Value(), anonymize)
+		if err != nil {
+			return nil, err
+		}
+		sortKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{string(sortKey), map[string]any{"Key": key, "Value": value}})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].sortKey < entries[j].sortKey })
+
+	out := make([]any, len(entries))
+	for i, e := range entries {
+		out[i] = e.pair
+	}
+	return out, nil
+}
+
+// decodeValue fills v from a decoded JSON value. path names the position
+// in the tree for error messages.
+func decodeValue(raw any, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(raw, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Interface:
+		kinds, noun := interfaceKinds(v.Type())
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected %s object", path, noun)
+		}
+		kind, _ := obj["type"].(string)
+		t, ok := kinds[kind]
+		if !ok {
+			return fmt.Errorf("%s: unknown %s type %q", path, noun, kind)
+		}
+		elem := reflect.New(t).Elem()
+		if err := decodeStruct(obj, elem, path, "type"); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		items, ok := raw.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, out.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+	case reflect.Map:
+		return decodeMap(raw, v, path)
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		return decodeStruct(obj, v, path, "")
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := decodeNumber(raw, path)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(n.String(), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := decodeNumber(raw, path)
+		if err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(n.String(), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := decodeNumber(raw, path)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(n.String(), v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+		v.SetString(s)
+		if s != "" && v.Type().Implements(validatorType) {
+			if err := v.Interface().(interface{ Validate() error }).Validate(); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: cannot decode value of kind %s", path, v.Kind())
+	}
+}
+
+// decodeStruct fills the struct v from obj, rejecting unknown fields.
+// skip names a key that is consumed by the caller, such as the discriminator.
+func decodeStruct(obj map[string]any, v reflect.Value, path, skip string) error {
+	for key, raw := range obj {
+		if key == skip {
+			continue
+		}
+		sf, ok := v.Type().FieldByName(key)
+		if !ok || len(sf.Index) != 1 {
+			return fmt.Errorf("%s: unknown field %q", path, key)
+		}
+		if err := decodeValue(raw, v.FieldByIndex(sf.Index), path+"."+key); err != nil {
+			return err
+		}
+	}
+	return checkIdentifiers(v, path)
+}
+
+// checkIdentifiers rejects a decoded struct whose identifier fields would
+// not pass the builder's validation. Empty values are left to AST validation.
+func checkIdentifiers(v reflect.Value, path string) error {
+	for _, name := range identifierFields[v.Type()] {
+		field := v.FieldByName(name)
+		values := []string{field.String()}
+		if field.Kind() == reflect.Slice {
+			values = field.Interface().([]string)
+		}
+		for _, s := range values {
+			if s != "" && !isValidSQLIdentifier(s) {
+				return fmt.Errorf("%s.%s: invalid identifier %q", path, name, s)
+			}
+		}
+	}
+	if v.Type() == tableType {
+		// Named tables take the single-letter aliases of T(); derived
+		// tables and VALUES sources are named by any identifier.
+		alias := v.FieldByName("Alias").String()
+		valid := isValidSQLIdentifier
+		if v.FieldByName("Name").String() != "" {
+			valid = isValidTableAlias
+		}
+		if alias != "" && !valid(alias) {
+			return fmt.Errorf("%s.Alias: invalid table alias %q", path, alias)
+		}
+	}
+	if v.Type() == fieldType {
+		if err := v.Interface().(types.Field).Validate(); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// decodeMap fills the map v from an object (string keys) or from the
+// key/value pairs written by encodeEntries.
+func decodeMap(raw any, v reflect.Value, path string) error {
+	t := v.Type()
+	out := reflect.MakeMap(t)
+	if t.Key().Kind() == reflect.String {
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for key, item := range obj {
+			if !isValidSQLIdentifier(key) {
+				return fmt.Errorf("%s: invalid identifier %q", path, key)
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(item, elem, fmt.Sprintf("%s[%q]", path, key)); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+		v.Set(out)
+		return nil
+	}
+
+	items, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("%s: expected array of entries", path)
+	}
+	for i, item := range items {
+		entryPath := fmt.Sprintf("%s[%d]", path, i)
+		pair, ok := item.(map[string]any)
+		if !ok || len(pair) != 2 || pair["Key"] == nil {
+			return fmt.Errorf("%s: expected {\"Key\", \"Value\"} entry", entryPath)
+		}
+		key := reflect.New(t.Key()).Elem()
+		if err := decodeValue(pair["Key"], key, entryPath+".Key"); err != nil {
+			return err
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := decodeValue(pair["Value"], elem, entryPath+".Value"); err != nil {
+			return err
+		}
+		out.SetMapIndex(key, elem)
+	}
+	v.Set(out)
+	return nil
+}
+
+func decodeNumber(raw any, path string) (json.Number, error) {
+	n, ok := raw.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("%s: expected number", path)
+	}
+	return n, nil
+}
@@ -0,0 +1,263 @@
+package astql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/internal/types"
+	"github.com/zoobzio/astql/postgres"
+)
+
+// roundTrip encodes and decodes the builder's AST and checks that both
+// render to identical SQL and parameters.
+func roundTrip(t *testing.T, builder *astql.Builder) {
+	t.Helper()
+
+	ast, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	data, err := astql.EncodeAST(ast)
+	if err != nil {
+		t.Fatalf("EncodeAST failed: %v", err)
+	}
+	decoded, err := astql.DecodeAST(data)
+	if err != nil {
+		t.Fatalf("DecodeAST failed: %v\n%s", err, data)
+	}
+
+	want, err := postgres.New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	got, err := postgres.New().Render(decoded)
+	if err != nil {
+		t.Fatalf("Render of decoded AST failed: %v\n%s", err, data)
+	}
+	if got.SQL != want.SQL {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", want.SQL, got.SQL)
+	}
+	if strings.Join(got.RequiredParams, ",") != strings.Join(want.RequiredParams, ",") {
+		t.Errorf("RequiredParams = %v, want %v", got.RequiredParams, want.RequiredParams)
+	}
+
+	again, err := astql.EncodeAST(decoded)
+	if err != nil {
+		t.Fatalf("EncodeAST of decoded AST failed: %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("re-encoded JSON differs:\n%s\n%s", data, again)
+	}
+}
+
+func TestEncodeAST_Select(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	active := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("published"), astql.EQ, instance.P("published")))
+
+	query := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		SelectExpr(astql.RowNumber().
+			PartitionBy(instance.WithTable(instance.F("active"), "u")).
+			OrderBy(instance.WithTable(instance.F("created_at"), "u"), astql.DESC).
+			As("rn")).
+		LeftJoin(
+			instance.T("posts", "p"),
+			astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+		).
+		Where(instance.And(
+			instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("min_age")),
+			instance.Or(
+				instance.C(instance.WithTable(instance.F("email"), "u"), astql.IsNull, instance.P("unused")),
+				astql.Between(instance.WithTable(instance.F("age"), "u"), instance.P("low"), instance.P("high")),
+			),
+			astql.CSub(instance.WithTable(instance.F("id"), "u"), astql.IN, astql.Sub(active)),
+		)).
+		OrderBy(instance.WithTable(instance.F("username"), "u"), astql.ASC).
+		Limit(20)
+
+	roundTrip(t, query)
+}
+
+func TestEncodeAST_GroupByHaving(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		SelectExpr(astql.CountField(instance.F("id"))).
+		GroupBy(instance.F("user_id")).
+		HavingAgg(astql.HavingCount(astql.GT, instance.P("min_posts")))
+
+	roundTrip(t, query)
+}
+
+func TestEncodeAST_InsertUpsert(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{
+			instance.F("username"): instance.P("username"),
+			instance.F("email"):    instance.P("email"),
+			instance.F("active"):   instance.Bool(true),
+		}).
+		OnConflict(instance.F("email")).
+		DoUpdate().
+		Set(instance.F("username"), instance.P("new_username")).
+		Build().
+		Returning(instance.F("id"))
+
+	roundTrip(t, query)
+}
+
+func TestEncodeAST_Update(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Update(instance.T("users")).
+		Set(instance.F("email"), instance.P("email")).
+		Set(instance.F("age"), instance.P("age")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id")))
+
+	roundTrip(t, query)
+}
+
+func TestEncodeAST_Comparison(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	oldest := astql.Select(instance.T("users")).
+		SelectExpr(astql.Max(instance.F("age"))).
+		Where(instance.C(instance.F("active"), astql.EQ, instance.P("active")))
+
+	query := astql.Select(instance.T("users")).
+		Where(instance.And(
+			astql.CV(instance.F("age"), astql.GE, instance.P("min_age")),
+			astql.CV(instance.F("id"), astql.NE, instance.F("age")),
+			astql.CV(instance.F("age"), astql.LT, astql.Sub(oldest)),
+			astql.CV(instance.F("active"), astql.EQ, instance.Bool(true)),
+		))
+
+	roundTrip(t, query)
+}
+
+func TestEncodeAST_Discriminator(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	ast, err := astql.Select(instance.T("users")).
+		Where(instance.Or(
+			instance.C(instance.F("id"), astql.EQ, instance.P("id")),
+			astql.Between(instance.F("age"), instance.P("low"), instance.P("high")),
+		)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	data, err := astql.EncodeAST(ast)
+	if err != nil {
+		t.Fatalf("EncodeAST failed: %v", err)
+	}
+	for _, kind := range []string{`"type":"group"`, `"type":"condition"`, `"type":"between"`} {
+		if !strings.Contains(string(data), kind) {
+			t.Errorf("encoded AST missing %s: %s", kind, data)
+		}
+	}
+}
+
+func TestDecodeAST_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"invalid json", `{`, "invalid AST JSON"},
+		{"unknown field", `{"Operation":"SELECT","Bogus":1}`, `unknown field "Bogus"`},
+		{"unknown condition type", `{"WhereClause":{"type":"raw"}}`, `unknown condition type "raw"`},
+		{"wrong value type", `{"Distinct":"yes"}`, "AST.Distinct: expected boolean"},
+		{"nested path", `{"WhereClause":{"type":"group","Conditions":[{"type":"condition","Field":{"Nme":"id"}}]}}`, `AST.WhereClause.Conditions[0].Field: unknown field "Nme"`},
+		{"table name", `{"Operation":"SELECT","Target":{"Name":"users; DROP TABLE users"}}`, `AST.Target.Name: invalid identifier`},
+		{"table alias", `{"Operation":"SELECT","Target":{"Name":"users","Alias":"u--"}}`, `AST.Target.Alias: invalid table alias`},
+		{"target alias", `{"Operation":"SELECT","TargetAlias":"d) x"}`, `AST.TargetAlias: invalid identifier`},
+		{"join alias", `{"Joins":[{"Table":{"Alias":"p\"x"}}]}`, `AST.Joins[0].Table.Alias: invalid table alias`},
+		{"join columns", `{"Joins":[{"Columns":["a b"]}]}`, `AST.Joins[0].Columns: invalid identifier`},
+		{"field name", `{"Fields":[{"Name":"id\"; --"}]}`, `AST.Fields[0].Name: invalid identifier`},
+		{"field table", `{"Fields":[{"Name":"id","Table":"u.x"}]}`, `AST.Fields[0].Table: invalid identifier`},
+		{"json key", `{"Fields":[{"Name":"data","JSONKey":"a'b"}]}`, `AST.Fields[0]: invalid JSON key`},
+		{"json path", `{"Fields":[{"Name":"data","JSONPath":"a,b}"}]}`, `AST.Fields[0]: invalid JSON path key`},
+		{"param name", `{"WhereClause":{"type":"condition","Field":{"Name":"id"},"Value":{"Name":"p OR 1=1"}}}`, `AST.WhereClause.Value.Name: invalid identifier`},
+		{"expression alias", `{"FieldExpressions":[{"Alias":"n\" FROM x"}]}`, `AST.FieldExpressions[0].Alias: invalid identifier`},
+		{"window name", `{"Windows":{"w AS ()":{}}}`, `AST.Windows: invalid identifier`},
+		{"named table alias", `{"Operation":"SELECT","Target":{"Name":"users","Alias":"users2"}}`, `AST.Target.Alias: invalid table alias`},
+		{"join type", `{"Operation":"SELECT","Target":{"Name":"users"},"Joins":[{"Type":"INNER JOIN posts ON 1=1; DROP TABLE users; --","Table":{"Name":"posts"}}]}`, `AST.Joins[0].Type: unknown join type`},
+		{"cast type", `{"FieldExpressions":[{"Cast":{"Field":{"Name":"age"},"CastType":"TEXT); DROP TABLE users; --"}}]}`, `AST.FieldExpressions[0].Cast.CastType: unknown cast type`},
+		{"param cast", `{"WhereClause":{"type":"condition","Field":{"Name":"id"},"Operator":"=","Value":{"Name":"id","Cast":"UUID; DROP TABLE users"}}}`, `AST.WhereClause.Value.Cast: unknown cast type`},
+		{"operator", `{"WhereClause":{"type":"condition","Field":{"Name":"id"},"Operator":"= 1 OR 1 =","Value":{"Name":"id"}}}`, `AST.WhereClause.Operator: unknown operator`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := astql.DecodeAST([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeAST() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeAST_ComplexQueryEqual(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	authors := astql.Select(instance.T("posts")).
+		Fields(instance.F("user_id")).
+		Where(instance.C(instance.F("published"), astql.EQ, instance.P("published")))
+
+	query := astql.Select(instance.T("users", "u")).
+		Fields(
+			instance.WithTable(instance.F("username"), "u"),
+			instance.WithTable(instance.F("title"), "p"),
+		).
+		SelectExpr(astql.RowNumber().OverWindow("w").As("rn")).
+		SelectExpr(astql.SumOver(instance.WithTable(instance.F("id"), "c")).
+			PartitionBy(instance.WithTable(instance.F("id"), "u")).
+			As("comment_total")).
+		DefineWindow("w", astql.Window().
+			PartitionBy(instance.WithTable(instance.F("id"), "u")).
+			OrderBy(instance.WithTable(instance.F("created_at"), "u"), astql.DESC).
+			Build()).
+		InnerJoin(
+			instance.T("posts", "p"),
+			astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+		).
+		LeftJoin(
+			instance.T("comments", "c"),
+			instance.And(
+				astql.CF(instance.WithTable(instance.F("id"), "p"), "=", instance.WithTable(instance.F("post_id"), "c")),
+				instance.C(instance.WithTable(instance.F("body"), "c"), astql.NE, instance.P("empty")),
+			),
+		).
+		Where(instance.Or(
+			astql.CSub(instance.WithTable(instance.F("id"), "u"), astql.IN, astql.Sub(authors)),
+			instance.CIn(instance.WithTable(instance.F("id"), "u"), instance.P("a"), instance.P("b")),
+		)).
+		OrderBy(instance.WithTable(instance.F("username"), "u"), astql.ASC).
+		LimitParam(instance.P("page_size")).
+		Offset(40)
+
+	roundTrip(t, query)
+
+	ast, err := query.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	data, err := astql.EncodeAST(ast)
+	if err != nil {
+		t.Fatalf("EncodeAST failed: %v", err)
+	}
+	decoded, err := astql.DecodeAST(data)
+	if err != nil {
+		t.Fatalf("DecodeAST failed: %v", err)
+	}
+	if d := astql.Diff(ast, decoded); d != "" {
+		t.Errorf("decoded AST differs: %s", d)
+	}
+}
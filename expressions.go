@@ -147,6 +147,21 @@ func CF(left types.Field, op types.Operator, right types.Field) types.FieldCompa
 	}
 }
 
+// CV creates a comparison whose right-hand side is any Value: a param or
+// literal, another field, or a scalar subquery built with Sub.
+// Example: CV(price, GT, Sub(avgPrice)) -> "price" > (SELECT AVG("price") ...)
+func CV(field types.Field, op types.Operator, value types.Value) types.Comparison {
+	cond := types.Comparison{
+		Field:    field,
+		Operator: op,
+		Value:    value,
+	}
+	if err := cond.Validate(); err != nil {
+		panic(err)
+	}
+	return cond
+}
+
 // CSub creates a subquery condition with a field.
 func CSub(field types.Field, op types.Operator, subquery types.Subquery) types.SubqueryCondition {
 	// Validate operator is appropriate for subqueries
@@ -164,6 +179,58 @@ func CSub(field types.Field, op types.Operator, subquery types.Subquery) types.S
 	}
 }
 
+// CTuple creates a row value comparison against params or a subquery.
+// valuesOrSub must be a []types.Param (one per field) or a types.Subquery.
+// Example: CTuple(fields, EQ, []Param{p1, p2}) -> ("a", "b") = (:p1, :p2)
+// Example: CTuple(fields, IN, sub) -> ("a", "b") IN (SELECT ...)
+func CTuple(fields []types.Field, op types.Operator, valuesOrSub any) types.TupleCondition {
+	cond := types.TupleCondition{
+		Fields:   fields,
+		Operator: op,
+	}
+
+	switch v := valuesOrSub.(type) {
+	case []types.Param:
+		cond.Values = v
+	case types.Subquery:
+		cond.Subquery = &v
+	default:
+		panic(fmt.Errorf("CTuple requires []Param or Subquery, got %T", valuesOrSub))
+	}
+
+	if err := cond.Validate(); err != nil {
+		panic(err)
+	}
+
+	return cond
+}
+
+// CAny creates a quantified subquery comparison: field op ANY (subquery).
+// Example: CAny(status, EQ, sub) -> "status" = ANY (SELECT ...)
+func CAny(field types.Field, op types.Operator, subquery types.Subquery) types.SubqueryCondition {
+	return quantified(field, op, types.QuantAny, subquery)
+}
+
+// CAll creates a quantified subquery comparison: field op ALL (subquery).
+// Example: CAll(price, GT, sub) -> "price" > ALL (SELECT ...)
+func CAll(field types.Field, op types.Operator, subquery types.Subquery) types.SubqueryCondition {
+	return quantified(field, op, types.QuantAll, subquery)
+}
+
+// quantified builds an ANY/ALL subquery condition, panicking on non-comparison operators.
+func quantified(field types.Field, op types.Operator, quant types.Quantifier, subquery types.Subquery) types.SubqueryCondition {
+	if !op.IsComparison() {
+		panic(fmt.Errorf("operator %s cannot be used with %s - use =, !=, >, >=, <, or <=", op, quant))
+	}
+
+	return types.SubqueryCondition{
+		Field:      &field,
+		Operator:   op,
+		Quantifier: quant,
+		Subquery:   subquery,
+	}
+}
+
 // CSubExists creates an EXISTS/NOT EXISTS subquery condition.
 func CSubExists(op types.Operator, subquery types.Subquery) types.SubqueryCondition {
 	// Validate operator
@@ -190,6 +257,26 @@ func Sub(builder *Builder) types.Subquery {
 	return types.Subquery{AST: ast}
 }
 
+// ValuesTable creates an inline VALUES source for use as a SELECT target or
+// join table. Each row holds one param per column.
+// Example: ValuesTable("t", []string{"id", "name"}, rows) ->
+// (VALUES (:id1, :name1), (:id2, :name2)) AS t("id", "name")
+func ValuesTable(alias string, columns []string, rows [][]types.Param) types.Table {
+	if !isValidSQLIdentifier(alias) {
+		panic(fmt.Errorf("invalid VALUES table alias '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", alias))
+	}
+	for _, col := range columns {
+		if !isValidSQLIdentifier(col) {
+			panic(fmt.Errorf("invalid VALUES table column '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", col))
+		}
+	}
+	values := &types.ValuesTable{Columns: columns, Rows: rows}
+	if err := values.Validate(); err != nil {
+		panic(err)
+	}
+	return types.Table{Alias: alias, Values: values}
+}
+
 // Case creates a new CASE expression builder.
 func Case() *CaseBuilder {
 	return &CaseBuilder{
@@ -234,6 +321,85 @@ func (cb *CaseBuilder) Build() types.FieldExpression {
 	}
 }
 
+// JSONObject creates a new JSON object expression builder.
+// Example: JSONObject().Key("id", id).Key("name", name).As("user").Build()
+// -> json_build_object('id', "id", 'name', "name") AS "user" (PostgreSQL).
+func JSONObject() *JSONObjectBuilder {
+	return &JSONObjectBuilder{
+		expr: &types.JSONObjectExpression{},
+	}
+}
+
+// JSONObjectBuilder provides fluent API for building JSON object expressions.
+type JSONObjectBuilder struct {
+	expr  *types.JSONObjectExpression
+	alias string
+}
+
+// Key adds a key whose value is a field.
+func (jb *JSONObjectBuilder) Key(key string, field types.Field) *JSONObjectBuilder {
+	jb.checkKey(key)
+	jb.expr.Pairs = append(jb.expr.Pairs, types.JSONObjectPair{Key: key, Field: &field})
+	return jb
+}
+
+// KeyParam adds a key whose value is a parameter.
+func (jb *JSONObjectBuilder) KeyParam(key string, param types.Param) *JSONObjectBuilder {
+	jb.checkKey(key)
+	jb.expr.Pairs = append(jb.expr.Pairs, types.JSONObjectPair{Key: key, Param: &param})
+	return jb
+}
+
+// checkKey panics if the key is not a safe identifier, since keys are rendered as string literals.
+func (*JSONObjectBuilder) checkKey(key string) {
+	if !isValidSQLIdentifier(key) {
+		panic(fmt.Errorf("invalid JSON object key '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", key))
+	}
+}
+
+// As adds an alias to the JSON object expression.
+func (jb *JSONObjectBuilder) As(alias string) *JSONObjectBuilder {
+	if !isValidSQLIdentifier(alias) {
+		panic(fmt.Errorf("invalid alias '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", alias))
+	}
+	jb.alias = alias
+	return jb
+}
+
+// Build returns the JSONObjectExpression wrapped in a FieldExpression.
+func (jb *JSONObjectBuilder) Build() types.FieldExpression {
+	if len(jb.expr.Pairs) == 0 {
+		panic("JSON object requires at least one key")
+	}
+	return types.FieldExpression{
+		JSONObject: jb.expr,
+		Alias:      jb.alias,
+	}
+}
+
+// Func calls a custom scalar function registered with the renderer's RegisterFunction.
+// Each argument must be a types.Field or a types.Param; rendering fails if the
+// function is not registered or the argument count does not match.
+// Example: Func("my_fn", field, param) -> my_fn("field", :param)
+func Func(name string, args ...any) types.FieldExpression {
+	if !types.IsFunctionName(name) {
+		panic(fmt.Errorf("invalid function name '%s': must be alphanumeric/underscore identifiers separated by dots", name))
+	}
+
+	expr := &types.FuncExpression{Name: name, Args: make([]types.FuncArg, 0, len(args))}
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case types.Field:
+			expr.Args = append(expr.Args, types.FuncArg{Field: &v})
+		case types.Param:
+			expr.Args = append(expr.Args, types.FuncArg{Param: &v})
+		default:
+			panic(fmt.Errorf("argument %d of %s must be a Field or Param, got %T", i+1, name, arg))
+		}
+	}
+	return types.FieldExpression{Func: expr}
+}
+
 // Coalesce creates a COALESCE expression that returns the first non-null value.
 func Coalesce(values ...types.Param) types.FieldExpression {
 	if len(values) < 2 {
@@ -244,6 +410,17 @@ func Coalesce(values ...types.Param) types.FieldExpression {
 	}
 }
 
+// Array creates an ARRAY constructor from individual params, e.g. ARRAY[:a, :b].
+// Use it when the number of elements is known at build time. PostgreSQL only.
+func Array(values ...types.Param) types.FieldExpression {
+	if len(values) == 0 {
+		panic("ARRAY requires at least 1 value")
+	}
+	return types.FieldExpression{
+		Array: &types.ArrayExpression{Values: values},
+	}
+}
+
 // NullIf creates a NULLIF expression that returns NULL if two values are equal.
 func NullIf(value1, value2 types.Param) types.FieldExpression {
 	return types.FieldExpression{
@@ -320,13 +497,30 @@ func Sqrt(field types.Field) types.FieldExpression {
 }
 
 // Example: Cast(field, CastText) -> CAST("field" AS TEXT).
-func Cast(field types.Field, castType types.CastType) types.FieldExpression {
-	return types.FieldExpression{
-		Cast: &types.CastExpression{
-			Field:    field,
-			CastType: castType,
-		},
+// Example: Cast(field, Numeric(10, 2)) -> CAST("field" AS NUMERIC(10,2)).
+func Cast(field types.Field, target types.CastTarget) types.FieldExpression {
+	spec := target.CastSpec()
+	cast := types.CastExpression{
+		Field:     field,
+		CastType:  spec.Type,
+		Precision: spec.Precision,
+		Scale:     spec.Scale,
+		Length:    spec.Length,
+	}
+	if err := cast.Validate(); err != nil {
+		panic(err)
 	}
+	return types.FieldExpression{Cast: &cast}
+}
+
+// Numeric returns a NUMERIC(precision, scale) cast target.
+func Numeric(precision, scale int) types.CastSpec {
+	return types.CastSpec{Type: types.CastNumeric, Precision: precision, Scale: scale}
+}
+
+// Varchar returns a VARCHAR(length) cast target.
+func Varchar(length int) types.CastSpec {
+	return types.CastSpec{Type: types.CastVarchar, Length: length}
 }
 
 // BinaryExpr creates a binary expression for field <op> param patterns.
@@ -342,6 +536,51 @@ func BinaryExpr(field types.Field, op types.Operator, param types.Param) types.F
 	}
 }
 
+// Arith combines two expressions with an arithmetic operator.
+// Operands may be aggregates, window functions, or other arithmetic; use Expr to wrap a plain field.
+// Example: Arith(Sum(paid), ArithDiv, CountStar()) -> SUM("paid") / COUNT(*)
+func Arith(left types.FieldExpression, op types.ArithmeticOperator, right types.FieldExpression) types.FieldExpression {
+	return arith(types.ArithmeticOperand{Expr: arithOperand(left)}, op, types.ArithmeticOperand{Expr: arithOperand(right)})
+}
+
+// ArithParam combines an expression with a parameter.
+// Example: ArithParam(Sum(total), ArithMul, rate) -> SUM("total") * :rate
+func ArithParam(left types.FieldExpression, op types.ArithmeticOperator, right types.Param) types.FieldExpression {
+	return arith(types.ArithmeticOperand{Expr: arithOperand(left)}, op, types.ArithmeticOperand{Param: &right})
+}
+
+// Expr wraps a plain field as an expression, for use as an arithmetic operand.
+// Example: Arith(Expr(amount), ArithDiv, SumOver(amount).Build()) -> "amount" / SUM("amount") OVER ()
+func Expr(field types.Field) types.FieldExpression {
+	return types.FieldExpression{Field: field}
+}
+
+func arith(left types.ArithmeticOperand, op types.ArithmeticOperator, right types.ArithmeticOperand) types.FieldExpression {
+	expr := &types.ArithmeticExpression{Left: left, Right: right, Operator: op}
+	if err := expr.Validate(); err != nil {
+		panic(err)
+	}
+	return types.FieldExpression{Arithmetic: expr}
+}
+
+// arithOperand copies an operand expression and drops its alias,
+// which only applies to the outermost expression.
+func arithOperand(expr types.FieldExpression) *types.FieldExpression {
+	expr.Alias = ""
+	return &expr
+}
+
+// Grouping creates a GROUPING() expression for distinguishing ROLLUP/CUBE subtotal rows.
+// Example: Grouping(region, product) -> GROUPING("region", "product")
+func Grouping(fields ...types.Field) types.FieldExpression {
+	if len(fields) == 0 {
+		panic("GROUPING requires at least 1 field")
+	}
+	return types.FieldExpression{
+		Grouping: &types.GroupingExpression{Fields: fields},
+	}
+}
+
 // String functions
 
 // Upper creates an UPPER string expression.
@@ -434,6 +673,80 @@ func Replace(field types.Field, search types.Param, replacement types.Param) typ
 	}
 }
 
+// LPad creates an LPAD string expression, left-padding the field to length
+// characters with fill.
+// Example: LPad(field, length, fill) -> LPAD("field", :length, :fill)
+func LPad(field types.Field, length types.Param, fill types.Param) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringLPad,
+			Field:    field,
+			Args:     []types.Param{length, fill},
+		},
+	}
+}
+
+// RPad creates an RPAD string expression, right-padding the field to length
+// characters with fill.
+// Example: RPad(field, length, fill) -> RPAD("field", :length, :fill)
+func RPad(field types.Field, length types.Param, fill types.Param) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringRPad,
+			Field:    field,
+			Args:     []types.Param{length, fill},
+		},
+	}
+}
+
+// Reverse creates a REVERSE string expression.
+// Example: Reverse(field) -> REVERSE("field")
+func Reverse(field types.Field) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringReverse,
+			Field:    field,
+		},
+	}
+}
+
+// Initcap creates an INITCAP string expression, capitalizing each word.
+// Example: Initcap(field) -> INITCAP("field")
+func Initcap(field types.Field) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringInitcap,
+			Field:    field,
+		},
+	}
+}
+
+// StrPosition creates a substring index expression: the 1-based position of
+// needle within the field, or 0 when absent.
+// Example: StrPosition(field, needle) -> POSITION(:needle IN "field")
+func StrPosition(field types.Field, needle types.Param) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringPosition,
+			Field:    field,
+			Args:     []types.Param{needle},
+		},
+	}
+}
+
+// StrSplitPart creates a SPLIT_PART string expression: the n-th (1-based)
+// part of the field when split on delim.
+// Example: StrSplitPart(field, delim, n) -> SPLIT_PART("field", :delim, :n)
+func StrSplitPart(field types.Field, delim types.Param, n types.Param) types.FieldExpression {
+	return types.FieldExpression{
+		String: &types.StringExpression{
+			Function: types.StringSplitPart,
+			Field:    field,
+			Args:     []types.Param{delim, n},
+		},
+	}
+}
+
 // Concat creates a CONCAT string expression with multiple fields.
 // Example: Concat(field1, field2) -> CONCAT("field1", "field2")
 func Concat(fields ...types.Field) types.FieldExpression {
@@ -461,6 +774,16 @@ func Now() types.FieldExpression {
 	}
 }
 
+// NowTz creates a timezone-aware current timestamp expression.
+// Example: NowTz() -> now() (PostgreSQL), SYSDATETIMEOFFSET() (SQL Server)
+func NowTz() types.FieldExpression {
+	return types.FieldExpression{
+		Date: &types.DateExpression{
+			Function: types.DateNowTz,
+		},
+	}
+}
+
 // CurrentDate creates a CURRENT_DATE expression returning current date.
 // Example: CurrentDate() -> CURRENT_DATE
 func CurrentDate() types.FieldExpression {
@@ -515,6 +838,62 @@ func DateTrunc(part types.DatePart, field types.Field) types.FieldExpression {
 	}
 }
 
+// DateBin creates a DATE_BIN expression that buckets a timestamp into
+// fixed-width intervals counted from origin, for time-series rollups.
+// PostgreSQL 14+ only; other dialects return an UnsupportedFeatureError.
+// Example: DateBin(interval, field, origin) -> DATE_BIN(:interval, "field", :origin)
+func DateBin(interval types.Param, field types.Field, origin types.Param) types.FieldExpression {
+	return types.FieldExpression{
+		Date: &types.DateExpression{
+			Function: types.DateBin,
+			Field:    &field,
+			Interval: &interval,
+			Origin:   &origin,
+		},
+	}
+}
+
+// DateAdd adds amount units of part to a date/time field. Part must be an
+// interval unit: year, quarter, month, week, day, hour, minute, or second.
+// Example: DateAdd(field, n, PartDay) -> "field" + (:n * INTERVAL '1 day')
+func DateAdd(field types.Field, amount types.Param, part types.DatePart) types.FieldExpression {
+	return dateArith(types.DateAdd, field, amount, part)
+}
+
+// DateSub subtracts amount units of part from a date/time field.
+// Example: DateSub(field, n, PartDay) -> "field" - (:n * INTERVAL '1 day')
+func DateSub(field types.Field, amount types.Param, part types.DatePart) types.FieldExpression {
+	return dateArith(types.DateSub, field, amount, part)
+}
+
+func dateArith(fn types.DateFunc, field types.Field, amount types.Param, part types.DatePart) types.FieldExpression {
+	expr := types.DateExpression{
+		Function: fn,
+		Field:    &field,
+		Part:     part,
+		Amount:   &amount,
+	}
+	if err := expr.Validate(); err != nil {
+		panic(err)
+	}
+	return types.FieldExpression{Date: &expr}
+}
+
+// DateDiff counts the part units elapsed from start to end. Each side is
+// a field or a param.
+// Example: DateDiff(PartDay, end, start) -> TIMESTAMPDIFF(DAY, start, end) on MariaDB
+func DateDiff(part types.DatePart, end, start types.Value) types.FieldExpression {
+	expr := types.DateDiffExpression{
+		End:   end,
+		Start: start,
+		Part:  part,
+	}
+	if err := expr.Validate(); err != nil {
+		panic(err)
+	}
+	return types.FieldExpression{DateDiff: &expr}
+}
+
 // Window functions
 
 // WindowBuilder provides a fluent API for building window function expressions.
@@ -561,6 +940,42 @@ func (wsb *WindowSpecBuilder) OrderByNulls(field types.Field, direction types.Di
 
 // Rows sets the frame clause with ROWS BETWEEN.
 func (wsb *WindowSpecBuilder) Rows(start, end types.FrameBound) *WindowSpecBuilder {
+	return wsb.frame(types.FrameRows, start, end)
+}
+
+// Range sets the frame clause with RANGE BETWEEN, measured in ORDER BY values.
+func (wsb *WindowSpecBuilder) Range(start, end types.FrameBound) *WindowSpecBuilder {
+	return wsb.frame(types.FrameRange, start, end)
+}
+
+// Groups sets the frame clause with GROUPS BETWEEN, measured in peer groups (PostgreSQL, SQLite).
+func (wsb *WindowSpecBuilder) Groups(start, end types.FrameBound) *WindowSpecBuilder {
+	return wsb.frame(types.FrameGroups, start, end)
+}
+
+// StartOffset sets the offset for a FramePreceding/FrameFollowing start bound.
+// Example: Range(FramePreceding, FrameCurrentRow).StartOffset(days) -> RANGE BETWEEN :days PRECEDING AND CURRENT ROW
+func (wsb *WindowSpecBuilder) StartOffset(offset types.Param) *WindowSpecBuilder {
+	wsb.spec.FrameStartOffset = &offset
+	return wsb
+}
+
+// EndOffset sets the offset for a FramePreceding/FrameFollowing end bound.
+func (wsb *WindowSpecBuilder) EndOffset(offset types.Param) *WindowSpecBuilder {
+	wsb.spec.FrameEndOffset = &offset
+	return wsb
+}
+
+// Exclude sets the frame EXCLUDE option.
+// Example: Rows(FrameUnboundedPreceding, FrameCurrentRow).Exclude(ExcludeCurrentRow)
+// -> ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE CURRENT ROW
+func (wsb *WindowSpecBuilder) Exclude(exclude types.FrameExclude) *WindowSpecBuilder {
+	wsb.spec.FrameExclude = exclude
+	return wsb
+}
+
+func (wsb *WindowSpecBuilder) frame(unit types.FrameUnit, start, end types.FrameBound) *WindowSpecBuilder {
+	wsb.spec.FrameUnit = unit
 	wsb.spec.FrameStart = start
 	wsb.spec.FrameEnd = end
 	return wsb
@@ -717,6 +1132,14 @@ func (wb *WindowBuilder) OverBuilder(builder *WindowSpecBuilder) *WindowBuilder
 	return wb
 }
 
+// OverWindow references a named window defined with Builder.DefineWindow,
+// replacing any inline specification.
+func (wb *WindowBuilder) OverWindow(name string) *WindowBuilder {
+	wb.expr.Window = types.WindowSpec{}
+	wb.expr.WindowRef = name
+	return wb
+}
+
 // PartitionBy adds PARTITION BY fields (convenience method).
 func (wb *WindowBuilder) PartitionBy(fields ...types.Field) *WindowBuilder {
 	wb.expr.Window.PartitionBy = fields
@@ -739,6 +1162,19 @@ func (wb *WindowBuilder) Frame(start, end types.FrameBound) *WindowBuilder {
 	return wb
 }
 
+// IgnoreNulls skips NULL values in LAG, LEAD, FIRST_VALUE and LAST_VALUE.
+// Dialects without IGNORE NULLS return an UnsupportedFeatureError at render time.
+func (wb *WindowBuilder) IgnoreNulls() *WindowBuilder {
+	wb.expr.NullTreatment = types.NullsIgnore
+	return wb
+}
+
+// RespectNulls makes the default NULL handling explicit.
+func (wb *WindowBuilder) RespectNulls() *WindowBuilder {
+	wb.expr.NullTreatment = types.NullsRespect
+	return wb
+}
+
 // As adds an alias to the window function and returns a FieldExpression.
 func (wb *WindowBuilder) As(alias string) types.FieldExpression {
 	if !isValidSQLIdentifier(alias) {
@@ -757,6 +1193,72 @@ func (wb *WindowBuilder) Build() types.FieldExpression {
 	}
 }
 
+// StringAgg creates a STRING_AGG aggregate joining the field's values with
+// sep. MariaDB and SQLite render it as GROUP_CONCAT.
+// Example: StringAgg(field, sep).OrderBy(field, ASC).As("names")
+// -> STRING_AGG("field", :sep ORDER BY "field" ASC) AS "names"
+func StringAgg(field types.Field, sep types.Param) *OrderedAggregateBuilder {
+	return &OrderedAggregateBuilder{
+		expr: &types.OrderedAggregate{
+			Function:  types.AggStringAgg,
+			Field:     field,
+			Separator: &sep,
+		},
+	}
+}
+
+// ArrayAgg creates an ARRAY_AGG aggregate collecting the field's values into
+// an array. Only PostgreSQL supports it; other dialects reject it at render time.
+// Example: ArrayAgg(field).Distinct().OrderBy(field, ASC).As("tags")
+// -> ARRAY_AGG(DISTINCT "field" ORDER BY "field" ASC) AS "tags"
+func ArrayAgg(field types.Field) *OrderedAggregateBuilder {
+	return &OrderedAggregateBuilder{
+		expr: &types.OrderedAggregate{
+			Function: types.AggArrayAgg,
+			Field:    field,
+		},
+	}
+}
+
+// OrderedAggregateBuilder provides fluent API for building aggregates with
+// their own ORDER BY.
+type OrderedAggregateBuilder struct {
+	expr *types.OrderedAggregate
+}
+
+// Distinct aggregates only distinct values (ARRAY_AGG only).
+func (ab *OrderedAggregateBuilder) Distinct() *OrderedAggregateBuilder {
+	ab.expr.Distinct = true
+	return ab
+}
+
+// OrderBy adds an ORDER BY key inside the aggregate call.
+func (ab *OrderedAggregateBuilder) OrderBy(field types.Field, direction types.Direction) *OrderedAggregateBuilder {
+	ab.expr.OrderBy = append(ab.expr.OrderBy, types.OrderBy{
+		Field:     field,
+		Direction: direction,
+	})
+	return ab
+}
+
+// As adds an alias to the aggregate and returns a FieldExpression.
+func (ab *OrderedAggregateBuilder) As(alias string) types.FieldExpression {
+	if !isValidSQLIdentifier(alias) {
+		panic(fmt.Errorf("invalid alias '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", alias))
+	}
+	return types.FieldExpression{
+		OrderedAgg: ab.expr,
+		Alias:      alias,
+	}
+}
+
+// Build returns the FieldExpression without an alias.
+func (ab *OrderedAggregateBuilder) Build() types.FieldExpression {
+	return types.FieldExpression{
+		OrderedAgg: ab.expr,
+	}
+}
+
 // As adds an alias to a field expression.
 func As(expr types.FieldExpression, alias string) types.FieldExpression {
 	if !isValidSQLIdentifier(alias) {
@@ -838,3 +1340,14 @@ func HavingMax(field types.Field, op types.Operator, value types.Param) types.Ag
 		Value:    value,
 	}
 }
+
+// HavingAlias ties an aggregate condition to a select-list alias.
+// MariaDB renders the alias; other dialects re-expand the aggregate.
+// Example: HavingAlias(HavingSum(amount, GT, param), "total") -> HAVING `total` > :param (MariaDB).
+func HavingAlias(cond types.AggregateCondition, alias string) types.AggregateCondition {
+	if !isValidSQLIdentifier(alias) {
+		panic(fmt.Errorf("invalid alias '%s': must be alphanumeric/underscore, start with letter/underscore, and contain no SQL keywords", alias))
+	}
+	cond.Alias = alias
+	return cond
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/astql/internal/types"
+	"github.com/zoobzio/astql/mariadb"
 	"github.com/zoobzio/astql/postgres"
 	"github.com/zoobzio/dbml"
 )
@@ -204,6 +205,46 @@ func TestCaseBuilder_As_SQLInjection(t *testing.T) {
 		As("group; DROP TABLE users--")
 }
 
+// Test JSON object builder.
+func TestJSONObject_Basic(t *testing.T) {
+	instance := createExpressionsTestInstance(t)
+
+	expr := astql.JSONObject().
+		Key("id", instance.F("id")).
+		Key("age", instance.F("age")).
+		KeyParam("source", instance.P("source")).
+		As("payload").
+		Build()
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(expr).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT json_build_object('id', "id", 'age', "age", 'source', :source) AS "payload" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if !contains(result.RequiredParams, "source") {
+		t.Errorf("Expected param 'source', got %v", result.RequiredParams)
+	}
+}
+
+// Test JSON object key with SQL injection attempt.
+func TestJSONObject_Key_SQLInjection(t *testing.T) {
+	instance := createExpressionsTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for SQL injection in JSON object key")
+		}
+	}()
+
+	astql.JSONObject().Key("id', 'x", instance.F("id"))
+}
+
 // Test COALESCE with valid alias.
 func TestCoalesce_As_Valid(t *testing.T) {
 	instance := createExpressionsTestInstance(t)
@@ -353,7 +394,7 @@ func TestSubquery_EXISTS(t *testing.T) {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	expected := `SELECT * FROM "users" u WHERE EXISTS (SELECT p."user_id" FROM "posts" p WHERE p."user_id" = :sq1_user_id)`
+	expected := `SELECT * FROM "users" u WHERE EXISTS (SELECT 1 FROM "posts" p WHERE p."user_id" = :sq1_user_id)`
 	if result.SQL != expected {
 		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
@@ -376,7 +417,7 @@ func TestSubquery_NOT_EXISTS(t *testing.T) {
 		t.Fatalf("Render failed: %v", err)
 	}
 
-	expected := `SELECT * FROM "users" WHERE NOT EXISTS (SELECT "user_id" FROM "posts")`
+	expected := `SELECT * FROM "users" WHERE NOT EXISTS (SELECT 1 FROM "posts")`
 	if result.SQL != expected {
 		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
 	}
@@ -615,6 +656,274 @@ func TestSubquery_CSubExists_WrongOperator(t *testing.T) {
 	astql.CSubExists(astql.IN, subquery)
 }
 
+// Test EXISTS subquery is reduced to SELECT 1 without a redundant LIMIT 1.
+func TestSubquery_EXISTS_StripsProjectionAndLimit(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Distinct().
+			Fields(instance.F("user_id"), instance.F("title")).
+			Where(instance.C(instance.F("published"), "=", instance.P("is_published"))).
+			OrderBy(instance.F("title"), astql.ASC).
+			Limit(1),
+	)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CSubExists(astql.EXISTS, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE EXISTS (SELECT 1 FROM "posts" WHERE "published" = :sq1_is_published)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test EXISTS keeps a LIMIT other than 1, since LIMIT 0 changes the result.
+func TestSubquery_EXISTS_KeepsOtherLimit(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Fields(instance.F("user_id")).
+			Limit(0),
+	)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CSubExists(astql.EXISTS, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE EXISTS (SELECT 1 FROM "posts" LIMIT 0)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test EXISTS keeps DISTINCT and the select list when OFFSET is kept, since
+// skipping distinct values differs from skipping rows.
+func TestSubquery_EXISTS_KeepsDistinctWithOffset(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Distinct().
+			Fields(instance.F("user_id")).
+			Offset(5),
+	)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CSubExists(astql.EXISTS, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE EXISTS (SELECT DISTINCT "user_id" FROM "posts" OFFSET 5)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test ANY quantified subquery comparison.
+func TestSubquery_CAny(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Fields(instance.F("user_id")).
+			Where(instance.C(instance.F("published"), "=", instance.P("is_published"))),
+	)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CAny(instance.F("id"), astql.EQ, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" = ANY (SELECT "user_id" FROM "posts" WHERE "published" = :sq1_is_published)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test ALL quantified subquery comparison.
+func TestSubquery_CAll(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Fields(instance.F("user_id")),
+	)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CAll(instance.F("id"), astql.GT, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" > ALL (SELECT "user_id" FROM "posts")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test CAny with a non-comparison operator (should panic).
+func TestSubquery_CAny_WrongOperator(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for using IN operator with CAny")
+		}
+	}()
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Fields(instance.F("user_id")),
+	)
+
+	// Should panic - CAny only accepts comparison operators
+	astql.CAny(instance.F("id"), astql.IN, subquery)
+}
+
+// Test tuple IN subquery.
+func TestSubquery_CTuple_IN(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	subquery := astql.Sub(
+		astql.Select(instance.T("posts")).
+			Fields(instance.F("user_id"), instance.F("title")).
+			Where(instance.C(instance.F("published"), "=", instance.P("is_published"))),
+	)
+
+	fields := append(instance.Fields(), instance.F("id"), instance.F("username"))
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CTuple(fields, astql.IN, subquery)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("id", "username") IN (SELECT "user_id", "title" FROM "posts" WHERE "published" = :sq1_is_published)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test tuple equality against params.
+func TestCTuple_Equality(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	fields := append(instance.Fields(), instance.F("id"), instance.F("username"))
+	values := append(instance.Params(), instance.P("id"), instance.P("name"))
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CTuple(fields, astql.EQ, values)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("id", "username") = (:id, :name)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("Expected 2 params, got %v", result.RequiredParams)
+	}
+}
+
+// Test CTuple with mismatched arity (should panic).
+func TestCTuple_ArityMismatch(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for tuple arity mismatch")
+		}
+	}()
+
+	fields := append(instance.Fields(), instance.F("id"), instance.F("username"))
+	astql.CTuple(fields, astql.EQ, append(instance.Params(), instance.P("id")))
+}
+
+// Test CV with each kind of right-hand value.
+func TestCV_Values(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	latest := astql.Select(instance.T("posts")).
+		SelectExpr(astql.Max(instance.F("user_id"))).
+		Where(instance.C(instance.F("published"), astql.EQ, instance.P("is_published")))
+
+	tests := []struct {
+		name       string
+		value      types.Value
+		expected   string
+		wantParams string
+	}{
+		{"param", instance.P("user_id"), `SELECT * FROM "posts" WHERE "id" = :user_id`, "user_id"},
+		{"field", instance.F("user_id"), `SELECT * FROM "posts" WHERE "id" = "user_id"`, ""},
+		{"subquery", astql.Sub(latest), `SELECT * FROM "posts" WHERE "id" = (SELECT MAX("user_id") FROM "posts" WHERE "published" = :sq1_is_published)`, "sq1_is_published"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := astql.Select(instance.T("posts")).
+				Where(astql.CV(instance.F("id"), astql.EQ, tt.value)).
+				Render(postgres.New())
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+			if got := strings.Join(result.RequiredParams, ","); got != tt.wantParams {
+				t.Errorf("RequiredParams = %v, want %q", result.RequiredParams, tt.wantParams)
+			}
+		})
+	}
+}
+
+// Test CV with a literal value renders inline with no params.
+func TestCV_Literal(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	result, err := astql.Select(instance.T("posts")).
+		Where(astql.CV(instance.F("published"), astql.EQ, instance.Bool(true))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "posts" WHERE "published" = TRUE`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 0 {
+		t.Errorf("Expected no params, got %v", result.RequiredParams)
+	}
+}
+
+// Test CV with a non-comparison operator (should panic).
+func TestCV_InvalidOperator(t *testing.T) {
+	instance := createSubqueryTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for non-comparison operator")
+		}
+	}()
+
+	astql.CV(instance.F("title"), astql.LIKE, instance.P("pattern"))
+}
+
 // Test Sub with invalid builder (should panic).
 func TestSubquery_Sub_InvalidBuilder(t *testing.T) {
 	instance := createSubqueryTestInstance(t)
@@ -922,6 +1231,28 @@ func TestWindowFunction_Ntile(t *testing.T) {
 	}
 }
 
+func TestWindowFunction_RankRequiresOrderBy(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	_, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.Rank().PartitionBy(instance.F("user_id")).As("rnk")).
+		Render(postgres.New())
+	if err == nil || !strings.Contains(err.Error(), "RANK requires ORDER BY") {
+		t.Errorf("error = %v, want RANK requires ORDER BY", err)
+	}
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.SumOver(instance.F("total")).PartitionBy(instance.F("user_id")).As("user_total")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT SUM("total") OVER (PARTITION BY "user_id") AS "user_total" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
 func TestWindowFunction_Lag(t *testing.T) {
 	instance := createWindowTestInstance(t)
 
@@ -1096,6 +1427,35 @@ func TestCountFilter(t *testing.T) {
 	}
 }
 
+func TestCountFilter_ParamSharedWithHaving(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	// COUNT(*) FILTER (WHERE total > :min) in SELECT, HAVING COUNT(*) > :min
+	filterExpr := types.FieldExpression{
+		Aggregate: types.AggCountField,
+		Filter:    instance.C(instance.F("total"), ">", instance.P("min")),
+	}
+
+	result, err := astql.Select(instance.T("orders")).
+		Fields(instance.F("user_id")).
+		SelectExpr(astql.As(filterExpr, "big_orders")).
+		GroupBy(instance.F("user_id")).
+		HavingAgg(astql.HavingCount(astql.GT, instance.P("min"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "user_id", COUNT(*) FILTER (WHERE "total" > :min) AS "big_orders" FROM "orders" GROUP BY "user_id" HAVING COUNT(*) > :min`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "min" {
+		t.Errorf("Expected RequiredParams [min], got %v", result.RequiredParams)
+	}
+}
+
 // =============================================================================
 // ILIKE Operator Tests
 // =============================================================================
@@ -1241,30 +1601,104 @@ func TestArrayContainedBy(t *testing.T) {
 	}
 }
 
-func TestNotLike(t *testing.T) {
+func TestArrayConstructor(t *testing.T) {
 	project := dbml.NewProject("test")
 	users := dbml.NewTable("users")
+	users.AddColumn(dbml.NewColumn("id", "bigint"))
 	users.AddColumn(dbml.NewColumn("username", "varchar"))
+	users.AddColumn(dbml.NewColumn("tags", "text[]"))
 	project.AddTable(users)
 	instance, _ := astql.NewFromDBML(project)
 
-	result, err := astql.Select(instance.T("users")).
-		Where(instance.C(instance.F("username"), "NOT LIKE", instance.P("pattern"))).
+	insert, err := astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{instance.F("username"): instance.P("username")}).
+		ValueArray(instance.F("tags"), instance.P("tag1"), instance.P("tag2")).
 		Render(postgres.New())
 	if err != nil {
 		t.Fatalf("Render failed: %v", err)
 	}
+	expected := `INSERT INTO "users" ("tags", "username") VALUES (ARRAY[:tag1, :tag2], :username)`
+	if insert.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, insert.SQL)
+	}
+	if len(insert.RequiredParams) != 3 {
+		t.Errorf("Expected 3 params, got %v", insert.RequiredParams)
+	}
 
-	if !strings.Contains(result.SQL, "NOT LIKE") {
-		t.Errorf("Expected NOT LIKE in SQL: %s", result.SQL)
+	update, err := astql.Update(instance.T("users")).
+		SetExpr(instance.F("tags"), astql.Array(instance.P("tag"))).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected = `UPDATE "users" SET "tags" = ARRAY[:tag] WHERE "id" = :id`
+	if update.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, update.SQL)
+	}
+
+	_, err = astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{instance.F("username"): instance.P("username")}).
+		ValueArray(instance.F("tags"), instance.P("tag1")).
+		Render(mariadb.New())
+	if err == nil || !strings.Contains(err.Error(), "ARRAY constructors") {
+		t.Errorf("Expected mariadb to reject ARRAY constructors, got %v", err)
 	}
 }
 
-func TestNotRegexMatch(t *testing.T) {
+func TestValueArray_Errors(t *testing.T) {
 	project := dbml.NewProject("test")
 	users := dbml.NewTable("users")
-	users.AddColumn(dbml.NewColumn("email", "varchar"))
-	project.AddTable(users)
+	users.AddColumn(dbml.NewColumn("username", "varchar"))
+	users.AddColumn(dbml.NewColumn("tags", "text[]"))
+	project.AddTable(users)
+	instance, _ := astql.NewFromDBML(project)
+
+	if _, err := astql.Insert(instance.T("users")).ValueArray(instance.F("tags"), instance.P("tag")).Build(); err == nil {
+		t.Error("Expected error for ValueArray without Values")
+	}
+	if _, err := astql.Update(instance.T("users")).ValueArray(instance.F("tags"), instance.P("tag")).Build(); err == nil {
+		t.Error("Expected error for ValueArray outside INSERT")
+	}
+	if _, err := astql.Insert(instance.T("users")).
+		Values(map[types.Field]types.Param{instance.F("username"): instance.P("username")}).
+		ValueArray(instance.F("tags")).
+		Build(); err == nil {
+		t.Error("Expected error for an empty ValueArray")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Array() with no values to panic")
+		}
+	}()
+	astql.Array()
+}
+
+func TestNotLike(t *testing.T) {
+	project := dbml.NewProject("test")
+	users := dbml.NewTable("users")
+	users.AddColumn(dbml.NewColumn("username", "varchar"))
+	project.AddTable(users)
+	instance, _ := astql.NewFromDBML(project)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("username"), "NOT LIKE", instance.P("pattern"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.Contains(result.SQL, "NOT LIKE") {
+		t.Errorf("Expected NOT LIKE in SQL: %s", result.SQL)
+	}
+}
+
+func TestNotRegexMatch(t *testing.T) {
+	project := dbml.NewProject("test")
+	users := dbml.NewTable("users")
+	users.AddColumn(dbml.NewColumn("email", "varchar"))
+	project.AddTable(users)
 	instance, _ := astql.NewFromDBML(project)
 
 	result, err := astql.Select(instance.T("users")).
@@ -1855,6 +2289,21 @@ func TestNow_Basic(t *testing.T) {
 	}
 }
 
+func TestNowTz_Basic(t *testing.T) {
+	instance := createDateTestInstance(t)
+
+	result, err := astql.Select(instance.T("events")).
+		SelectExpr(astql.As(astql.NowTz(), "current_time")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.Contains(result.SQL, "now()") {
+		t.Errorf("Expected now() in SQL: %s", result.SQL)
+	}
+}
+
 func TestCurrentDate_Basic(t *testing.T) {
 	instance := createDateTestInstance(t)
 
@@ -1960,6 +2409,28 @@ func TestDateTrunc_Day(t *testing.T) {
 	}
 }
 
+func TestDateBin(t *testing.T) {
+	instance := createDateTestInstance(t)
+	query := func() *astql.Builder {
+		return astql.Select(instance.T("events")).
+			SelectExpr(astql.As(astql.DateBin(instance.P("bucket"), instance.F("created_at"), instance.P("origin")), "bucket_start")).
+			SelectExpr(astql.As(astql.CountStar(), "total")).
+			GroupBy(instance.F("created_at"))
+	}
+
+	result, err := query().Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(result.SQL, `DATE_BIN(:bucket, "created_at", :origin) AS "bucket_start"`) {
+		t.Errorf("Expected DATE_BIN in SQL: %s", result.SQL)
+	}
+
+	if _, err := query().Render(mariadb.New()); err == nil || !strings.Contains(err.Error(), "DATE_BIN") {
+		t.Errorf("MariaDB Render() error = %v, want DATE_BIN unsupported", err)
+	}
+}
+
 // =============================================================================
 // HAVING Aggregate Tests
 // =============================================================================
@@ -2131,6 +2602,35 @@ func TestCoalesce_PanicOnSingleValue(t *testing.T) {
 	astql.Coalesce(instance.P("val1"))
 }
 
+func TestGrouping_WithRollup(t *testing.T) {
+	instance := createExpressionsTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Fields(instance.F("active"), instance.F("age")).
+		SelectExpr(astql.As(astql.Grouping(instance.F("active"), instance.F("age")), "grp")).
+		SelectExpr(astql.As(astql.CountField(instance.F("id")), "total")).
+		GroupByRollup(instance.F("active"), instance.F("age")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "active", "age", GROUPING("active", "age") AS "grp", COUNT("id") AS "total" FROM "users" GROUP BY ROLLUP("active", "age")`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestGrouping_PanicOnNoFields(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for Grouping with no fields")
+		}
+	}()
+
+	astql.Grouping()
+}
+
 func TestNullIf_Basic(t *testing.T) {
 	instance := createMathTestInstance(t)
 
@@ -2165,6 +2665,47 @@ func TestCast_ToNumeric(t *testing.T) {
 	}
 }
 
+func TestCast_WithTypeParams(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.Cast(instance.F("id"), astql.Numeric(10, 2)), "amount")).
+		SelectExpr(astql.As(astql.Cast(instance.F("name"), astql.Varchar(50)), "short")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT CAST("id" AS NUMERIC(10,2)) AS "amount", CAST("name" AS VARCHAR(50)) AS "short" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestCast_InvalidTypeParams(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	tests := []struct {
+		name   string
+		target astql.CastTarget
+	}{
+		{"scale exceeds precision", astql.Numeric(2, 4)},
+		{"negative length", astql.Varchar(-1)},
+		{"length on numeric", astql.CastSpec{Type: astql.CastNumeric, Length: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			astql.Cast(instance.F("id"), tt.target)
+		})
+	}
+}
+
 func TestCast_ToBoolean(t *testing.T) {
 	instance := createStringTestInstance(t)
 
@@ -2224,3 +2765,325 @@ func TestCast_ToText_FromNumeric(t *testing.T) {
 		t.Errorf("Expected CAST in SQL: %s", result.SQL)
 	}
 }
+
+func TestArith_AggregateOverAggregate(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.As(astql.Arith(astql.Sum(instance.F("total")), astql.ArithDiv, astql.CountStar()), "avg_total")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT SUM("total") / COUNT(*) AS "avg_total" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestArith_FieldOverWindowSum(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	pct := astql.Arith(
+		astql.Expr(instance.F("total")),
+		astql.ArithDiv,
+		astql.SumOver(instance.F("total")).As("ignored"),
+	)
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.As(pct, "pct")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "total" / SUM("total") OVER () AS "pct" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestArithParam_RequiredParams(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.As(astql.ArithParam(astql.Sum(instance.F("total")), astql.ArithMul, instance.P("rate")), "taxed")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT SUM("total") * :rate AS "taxed" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if !contains(result.RequiredParams, "rate") {
+		t.Errorf("Expected rate in params: %v", result.RequiredParams)
+	}
+}
+
+func TestArith_InvalidOperatorPanics(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for invalid arithmetic operator")
+		}
+	}()
+	astql.Arith(astql.Expr(instance.F("total")), astql.ArithmeticOperator("^"), astql.CountStar())
+}
+
+func TestDefineWindow_ReusedAcrossColumns(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	spec := astql.Window().
+		PartitionBy(instance.F("user_id")).
+		OrderBy(instance.F("created_at"), astql.ASC).
+		Build()
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.RowNumber().OverWindow("w").As("rn")).
+		SelectExpr(astql.SumOver(instance.F("total")).OverWindow("w").As("running_total")).
+		DefineWindow("w", spec).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT ROW_NUMBER() OVER "w" AS "rn", SUM("total") OVER "w" AS "running_total" FROM "orders" WINDOW "w" AS (PARTITION BY "user_id" ORDER BY "created_at" ASC)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestDefineWindow_Undefined(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	_, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.RowNumber().OverWindow("w").As("rn")).
+		Render(postgres.New())
+	if err == nil {
+		t.Error("Expected error referencing an undefined window")
+	}
+}
+
+func TestDefineWindow_Duplicate(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	_, err := astql.Select(instance.T("orders")).
+		DefineWindow("w", astql.Window().Build()).
+		DefineWindow("w", astql.Window().Build()).
+		Build()
+	if err == nil {
+		t.Error("Expected error defining a window twice")
+	}
+}
+
+func TestWindowSpec_RangeWithOffset(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	spec := astql.Window().
+		OrderBy(instance.F("created_at"), astql.ASC).
+		Range(astql.FramePreceding, astql.FrameCurrentRow).
+		StartOffset(instance.P("span")).
+		Build()
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.AvgOver(instance.F("total")).Over(spec).As("moving_avg")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT AVG("total") OVER (ORDER BY "created_at" ASC RANGE BETWEEN :span PRECEDING AND CURRENT ROW) AS "moving_avg" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestWindowSpec_OffsetBoundWithoutParam(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	_, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.SumOver(instance.F("total")).
+			OverBuilder(astql.Window().Rows(astql.FramePreceding, astql.FrameCurrentRow)).
+			As("recent")).
+		Render(postgres.New())
+	if err == nil {
+		t.Error("Expected error for PRECEDING bound without an offset")
+	}
+}
+
+func TestWindowSpec_Exclude(t *testing.T) {
+	instance := createWindowTestInstance(t)
+
+	spec := astql.Window().
+		PartitionBy(instance.F("user_id")).
+		OrderBy(instance.F("created_at"), astql.ASC).
+		Rows(astql.FrameUnboundedPreceding, astql.FrameUnboundedFollowing).
+		Exclude(astql.ExcludeCurrentRow).
+		Build()
+
+	result, err := astql.Select(instance.T("orders")).
+		SelectExpr(astql.AvgOver(instance.F("total")).Over(spec).As("others_avg")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT AVG("total") OVER (PARTITION BY "user_id" ORDER BY "created_at" ASC ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING EXCLUDE CURRENT ROW) AS "others_avg" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestStringFormatting(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.LPad(instance.F("name"), instance.P("width"), instance.P("fill")), "padded")).
+		SelectExpr(astql.As(astql.RPad(instance.F("name"), instance.P("width"), instance.P("fill")), "right_padded")).
+		SelectExpr(astql.As(astql.Reverse(instance.F("name")), "reversed")).
+		SelectExpr(astql.As(astql.Initcap(instance.F("name")), "title")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT LPAD("name", :width, :fill) AS "padded", RPAD("name", :width, :fill) AS "right_padded", REVERSE("name") AS "reversed", INITCAP("name") AS "title" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("Expected 2 params, got %d: %v", len(result.RequiredParams), result.RequiredParams)
+	}
+}
+
+func TestStrPosition(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.StrPosition(instance.F("name"), instance.P("needle")), "at")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT POSITION(:needle IN "name") AS "at" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestStrSplitPart(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.StrSplitPart(instance.F("name"), instance.P("delim"), instance.P("part")), "first")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT SPLIT_PART("name", :delim, :part) AS "first" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestStringAgg(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.StringAgg(instance.F("name"), instance.P("sep")).
+			OrderBy(instance.F("name"), astql.DESC).
+			As("names")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT STRING_AGG("name", :sep ORDER BY "name" DESC) AS "names" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestArrayAgg(t *testing.T) {
+	instance := createStringTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.ArrayAgg(instance.F("name")).
+			Distinct().
+			OrderBy(instance.F("name"), astql.ASC).
+			As("names")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT ARRAY_AGG(DISTINCT "name" ORDER BY "name" ASC) AS "names" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestDateAdd(t *testing.T) {
+	instance := createDateTestInstance(t)
+
+	result, err := astql.Select(instance.T("events")).
+		SelectExpr(astql.As(astql.DateAdd(instance.F("created_at"), instance.P("days"), astql.PartDay), "due")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "created_at" + (:days * INTERVAL '1 day') AS "due" FROM "events"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test DateSub with a part that cannot be added (should panic).
+func TestDateSub_InvalidPart(t *testing.T) {
+	instance := createDateTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for EPOCH date arithmetic")
+		}
+	}()
+
+	astql.DateSub(instance.F("created_at"), instance.P("n"), astql.PartEpoch)
+}
+
+func TestDateDiff(t *testing.T) {
+	instance := createDateTestInstance(t)
+
+	result, err := astql.Select(instance.T("events")).
+		SelectExpr(astql.As(astql.DateDiff(astql.PartHour, instance.F("updated_at"), instance.F("created_at")), "hours")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT TRUNC(EXTRACT(EPOCH FROM ("updated_at" - "created_at")) / 3600) AS "hours" FROM "events"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test DateDiff with a subquery operand (should panic).
+func TestDateDiff_InvalidOperand(t *testing.T) {
+	instance := createDateTestInstance(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for subquery operand")
+		}
+	}()
+
+	latest := astql.Sub(astql.Select(instance.T("events")).SelectExpr(astql.Max(instance.F("created_at"))))
+	astql.DateDiff(astql.PartDay, latest, instance.F("created_at"))
+}
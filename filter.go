@@ -0,0 +1,150 @@
+package astql
+
+import (
+	"fmt"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Filter is a named, reusable WHERE condition.
+// Define it once and apply it to any number of builders with Builder.Apply.
+type Filter struct {
+	name      string
+	condition types.ConditionItem
+	params    []string
+}
+
+// NewFilter creates a named filter from a condition.
+// The filter records the parameters its condition references.
+func NewFilter(name string, condition types.ConditionItem) Filter {
+	if condition == nil {
+		panic(fmt.Errorf("filter '%s' requires a condition", name))
+	}
+	return Filter{
+		name:      name,
+		condition: condition,
+		params:    conditionParams(condition, nil),
+	}
+}
+
+// Name returns the filter name.
+func (f Filter) Name() string {
+	return f.name
+}
+
+// Condition returns the wrapped condition.
+func (f Filter) Condition() types.ConditionItem {
+	return f.condition
+}
+
+// Params returns the names of the parameters the filter requires, in order of first use.
+// Parameters inside subqueries are excluded because they are prefixed at render time.
+func (f Filter) Params() []string {
+	return append([]string(nil), f.params...)
+}
+
+// Apply adds each filter's condition to the WHERE clause, combined with AND.
+func (b *Builder) Apply(filters ...Filter) *Builder {
+	if b.err != nil {
+		return b
+	}
+	for _, f := range filters {
+		if f.condition == nil {
+			b.err = fmt.Errorf("cannot apply empty filter")
+			return b
+		}
+		b.Where(f.condition)
+	}
+	return b
+}
+
+// InjectCondition returns a copy of ast with cond ANDed into its WHERE
+// clause, for scoping queries you did not build yourself, such as adding a
+// tenant filter in middleware. A missing WHERE clause becomes cond, and an
+// existing one is wrapped in an AND group with cond. Subqueries at any depth
+// that select from the same table as ast receive cond as well, so a nested
+// lookup cannot reach rows outside the scope. INSERT statements have no
+// WHERE clause and only their subqueries are scoped. The input AST is not
+// modified; a nil AST returns nil.
+//
+// Write cond with unqualified fields so it applies to each query's own
+// target. Parameters injected into subqueries render with the usual
+// subquery prefix (sq1_tenant_id), so bind them under each rendered name.
+func InjectCondition(ast *types.AST, cond types.ConditionItem) *types.AST {
+	if ast == nil {
+		return nil
+	}
+	scoped := ast.Clone()
+	if cond == nil {
+		return scoped
+	}
+	if scoped.Target.Name != "" {
+		injectSubqueries(scoped, scoped.Target.Name, cond)
+	}
+	if scoped.Operation != types.OpInsert {
+		scoped.WhereClause = addCondition(scoped.WhereClause, cond)
+	}
+	return scoped
+}
+
+// injectSubqueries ANDs cond into every subquery of ast that targets table.
+// Each level is scoped before cond is added to it, so subqueries inside cond
+// itself are left alone.
+func injectSubqueries(ast *types.AST, table string, cond types.ConditionItem) {
+	for _, sub := range ast.Subqueries() {
+		injectSubqueries(sub, table, cond)
+		if sub.TargetSubquery == nil && sub.Target.Name == table {
+			sub.WhereClause = addCondition(sub.WhereClause, cond)
+		}
+	}
+}
+
+// addCondition combines an existing WHERE clause with cond using AND.
+func addCondition(where, cond types.ConditionItem) types.ConditionItem {
+	if where == nil {
+		return cond
+	}
+	return and(where, cond)
+}
+
+// conditionParams appends the parameter names referenced by a condition, skipping duplicates.
+func conditionParams(cond types.ConditionItem, params []string) []string {
+	add := func(p types.Param) {
+		if p.Name == "" {
+			return
+		}
+		for _, existing := range params {
+			if existing == p.Name {
+				return
+			}
+		}
+		params = append(params, p.Name)
+	}
+
+	switch c := cond.(type) {
+	case types.Condition:
+		add(c.Value)
+	case types.Comparison:
+		if p, ok := c.Value.(types.Param); ok {
+			add(p)
+		}
+	case types.BetweenCondition:
+		add(c.Low)
+		add(c.High)
+	case types.AggregateCondition:
+		add(c.Value)
+	case types.TupleCondition:
+		for _, v := range c.Values {
+			add(v)
+		}
+	case types.InListCondition:
+		for _, v := range c.Values {
+			add(v)
+		}
+	case types.ConditionGroup:
+		for _, sub := range c.Conditions {
+			params = conditionParams(sub, params)
+		}
+	}
+	return params
+}
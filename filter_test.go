@@ -0,0 +1,240 @@
+package astql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/postgres"
+)
+
+func TestFilter_AppliedToTwoQueries(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	adults := astql.NewFilter("adults", instance.And(
+		instance.C(instance.F("age"), astql.GE, instance.P("min_age")),
+		instance.NotNull(instance.F("email")),
+	))
+
+	list, err := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("username")).
+		Apply(adults).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	count, err := astql.Count(instance.T("users")).
+		Apply(adults).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	fragment := `WHERE ("age" >= :min_age AND "email" IS NOT NULL)`
+	if !strings.HasSuffix(list.SQL, fragment) {
+		t.Errorf("SELECT SQL = %q, want suffix %q", list.SQL, fragment)
+	}
+	if !strings.HasSuffix(count.SQL, fragment) {
+		t.Errorf("COUNT SQL = %q, want suffix %q", count.SQL, fragment)
+	}
+
+	params := adults.Params()
+	if len(params) != 1 || params[0] != "min_age" {
+		t.Errorf("Params() = %v, want [min_age]", params)
+	}
+	if !contains(list.RequiredParams, "min_age") || !contains(count.RequiredParams, "min_age") {
+		t.Errorf("RequiredParams missing min_age: %v / %v", list.RequiredParams, count.RequiredParams)
+	}
+}
+
+func TestFilter_MergesWithWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	adults := astql.NewFilter("adults", instance.C(instance.F("age"), astql.GE, instance.P("min_age")))
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+		Apply(adults).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("username" = :name AND "age" >= :min_age)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("Expected 2 params, got %v", result.RequiredParams)
+	}
+}
+
+func TestFilter_ApplyEmpty(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	_, err := astql.Select(instance.T("users")).Apply(astql.Filter{}).Build()
+	if err == nil {
+		t.Error("Expected error applying an empty filter")
+	}
+}
+
+func TestInjectCondition(t *testing.T) {
+	instance := createRenderTestInstance(t)
+	scope := instance.C(instance.F("active"), astql.EQ, instance.P("active"))
+
+	tests := []struct {
+		name     string
+		builder  *astql.Builder
+		expected string
+	}{
+		{
+			name:     "no where",
+			builder:  astql.Select(instance.T("users")),
+			expected: `SELECT * FROM "users" WHERE "active" = :active`,
+		},
+		{
+			name: "simple where",
+			builder: astql.Select(instance.T("users")).
+				Where(instance.C(instance.F("age"), astql.GE, instance.P("min_age"))),
+			expected: `SELECT * FROM "users" WHERE ("age" >= :min_age AND "active" = :active)`,
+		},
+		{
+			name: "grouped where",
+			builder: astql.Select(instance.T("users")).
+				Where(instance.Or(
+					instance.C(instance.F("age"), astql.GE, instance.P("min_age")),
+					instance.Null(instance.F("email")),
+				)),
+			expected: `SELECT * FROM "users" WHERE (("age" >= :min_age OR "email" IS NULL) AND "active" = :active)`,
+		},
+		{
+			name: "same-table subquery",
+			builder: astql.Select(instance.T("users")).
+				Where(astql.CSub(instance.F("id"), astql.IN, astql.Sub(
+					astql.Select(instance.T("users")).Fields(instance.F("id")),
+				))),
+			expected: `SELECT * FROM "users" WHERE ("id" IN (SELECT "id" FROM "users" WHERE "active" = :sq1_active) AND "active" = :active)`,
+		},
+		{
+			name: "other-table subquery",
+			builder: astql.Select(instance.T("users")).
+				Where(astql.CSub(instance.F("id"), astql.IN, astql.Sub(
+					astql.Select(instance.T("posts")).Fields(instance.F("user_id")),
+				))),
+			expected: `SELECT * FROM "users" WHERE ("id" IN (SELECT "user_id" FROM "posts") AND "active" = :active)`,
+		},
+		{
+			name:     "delete",
+			builder:  astql.Delete(instance.T("users")).Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))),
+			expected: `DELETE FROM "users" WHERE ("id" = :id AND "active" = :active)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			before := ast.Clone()
+
+			result, err := postgres.New().Render(astql.InjectCondition(ast, scope))
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+			if d := astql.Diff(before, ast); d != "" {
+				t.Errorf("InjectCondition modified its input: %s", d)
+			}
+		})
+	}
+}
+
+func TestInjectCondition_Nil(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	if astql.InjectCondition(nil, instance.C(instance.F("active"), astql.EQ, instance.P("active"))) != nil {
+		t.Error("InjectCondition(nil) should return nil")
+	}
+}
+
+func TestAndWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+		AndWhere(instance.C(instance.F("age"), astql.GE, instance.P("min_age"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("username" = :name AND "age" >= :min_age)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestWhere_TwiceCombinesWithAnd(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+		Where(instance.C(instance.F("age"), astql.GE, instance.P("min_age"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("username" = :name AND "age" >= :min_age)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestOrWhere(t *testing.T) {
+	instance := createBuilderTestInstance(t)
+
+	tests := []struct {
+		name     string
+		builder  *astql.Builder
+		expected string
+	}{
+		{
+			name: "after where",
+			builder: astql.Select(instance.T("users")).
+				Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+				OrWhere(instance.C(instance.F("email"), astql.EQ, instance.P("email"))),
+			expected: `SELECT * FROM "users" WHERE ("username" = :name OR "email" = :email)`,
+		},
+		{
+			name: "first call",
+			builder: astql.Select(instance.T("users")).
+				OrWhere(instance.C(instance.F("email"), astql.EQ, instance.P("email"))),
+			expected: `SELECT * FROM "users" WHERE "email" = :email`,
+		},
+		{
+			name: "wraps earlier conditions",
+			builder: astql.Select(instance.T("users")).
+				Where(instance.C(instance.F("username"), astql.EQ, instance.P("name"))).
+				Where(instance.C(instance.F("age"), astql.GE, instance.P("min_age"))).
+				OrWhere(instance.C(instance.F("email"), astql.EQ, instance.P("email"))),
+			expected: `SELECT * FROM "users" WHERE (("username" = :name AND "age" >= :min_age) OR "email" = :email)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.builder.Render(postgres.New())
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+		})
+	}
+}
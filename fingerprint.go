@@ -0,0 +1,39 @@
+package astql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Fingerprint returns a stable SHA-256 hex digest of an AST's structure,
+// suitable as a prepared-statement cache key. It hashes the same canonical
+// form EncodeAST writes, with these normalizations:
+//
+//   - Parameter names are ignored: :id and :user_id hash identically, as
+//     do two uses of one parameter and uses of two different ones.
+//   - Literals (TRUE, FALSE) are kept, since they render into the SQL.
+//   - Maps are hashed by sorted entry, so insertion order does not matter.
+//
+// Every other field counts, including operators, fields, tables, aliases,
+// join types and conditions, clause order, and LIMIT/OFFSET values. The
+// result is "" for a nil AST or one holding a condition type that
+// EncodeAST cannot encode.
+func Fingerprint(ast *types.AST) string {
+	if ast == nil {
+		return ""
+	}
+	v, err := encodeValue(reflect.ValueOf(ast).Elem(), true)
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
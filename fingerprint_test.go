@@ -0,0 +1,104 @@
+package astql_test
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func fingerprint(t *testing.T, builder *astql.Builder) string {
+	t.Helper()
+	ast, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return astql.Fingerprint(ast)
+}
+
+func TestFingerprint_IgnoresParamNames(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	a := fingerprint(t, astql.Select(instance.T("users")).
+		Where(instance.And(
+			instance.C(instance.F("age"), astql.GE, instance.P("min_age")),
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+		)).
+		Limit(10))
+	b := fingerprint(t, astql.Select(instance.T("users")).
+		Where(instance.And(
+			instance.C(instance.F("age"), astql.GE, instance.P("p1")),
+			instance.C(instance.F("username"), astql.EQ, instance.P("p2")),
+		)).
+		Limit(10))
+
+	if len(a) != 64 {
+		t.Fatalf("Fingerprint = %q, want 64 hex characters", a)
+	}
+	if a != b {
+		t.Errorf("param-name-only difference changed the fingerprint: %s != %s", a, b)
+	}
+
+	c := fingerprint(t, astql.Update(instance.T("users")).
+		Set(instance.F("email"), instance.P("email")).
+		Set(instance.F("age"), instance.P("age")))
+	d := fingerprint(t, astql.Update(instance.T("users")).
+		Set(instance.F("age"), instance.P("new_age")).
+		Set(instance.F("email"), instance.P("new_email")))
+	if c != d {
+		t.Errorf("SET order or param names changed the fingerprint: %s != %s", c, d)
+	}
+}
+
+func TestFingerprint_DistinguishesShape(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	base := func() *astql.Builder {
+		return astql.Select(instance.T("users", "u")).
+			InnerJoin(instance.T("posts", "p"),
+				astql.CF(instance.WithTable(instance.F("id"), "u"), astql.EQ, instance.WithTable(instance.F("user_id"), "p")))
+	}
+	reference := fingerprint(t, base().Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("age"))))
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+	}{
+		{"operator", base().Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GT, instance.P("age")))},
+		{"field", base().Where(instance.C(instance.WithTable(instance.F("id"), "u"), astql.GE, instance.P("age")))},
+		{"join type", astql.Select(instance.T("users", "u")).
+			LeftJoin(instance.T("posts", "p"),
+				astql.CF(instance.WithTable(instance.F("id"), "u"), astql.EQ, instance.WithTable(instance.F("user_id"), "p"))).
+			Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("age")))},
+		{"limit", base().Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("age"))).Limit(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fingerprint(t, tt.builder); got == reference {
+				t.Errorf("%s difference did not change the fingerprint", tt.name)
+			}
+		})
+	}
+}
+
+func TestFingerprint_KeepsLiterals(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	active := fingerprint(t, astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("active"), astql.EQ, instance.Bool(true))))
+	inactive := fingerprint(t, astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("active"), astql.EQ, instance.Bool(false))))
+	param := fingerprint(t, astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("active"), astql.EQ, instance.P("active"))))
+
+	if active == inactive || active == param {
+		t.Error("literal values must change the fingerprint")
+	}
+}
+
+func TestFingerprint_Nil(t *testing.T) {
+	if got := astql.Fingerprint((*types.AST)(nil)); got != "" {
+		t.Errorf("Fingerprint(nil) = %q, want empty", got)
+	}
+}
@@ -224,6 +224,33 @@ func (a *ASTQL) P(name string) types.Param {
 	return p
 }
 
+// Bool creates a boolean literal value for INSERT and UPDATE.
+// It renders inline as TRUE/FALSE on PostgreSQL and 1/0 on MariaDB, SQLite, and SQL Server,
+// and adds nothing to RequiredParams.
+func (*ASTQL) Bool(v bool) types.Param {
+	if v {
+		return types.Param{Literal: types.LiteralTrue}
+	}
+	return types.Param{Literal: types.LiteralFalse}
+}
+
+// TypedP creates a parameter with a type hint. PostgreSQL renders it as
+// :name::type, which resolves operator and function overloads such as
+// comparing :id to a uuid column; other dialects render the plain placeholder.
+func (a *ASTQL) TypedP(name string, castType types.CastType) types.Param {
+	p := a.P(name)
+	p.Cast = castType
+	return p
+}
+
+// Default creates the DEFAULT keyword as an INSERT or UPDATE value, so a
+// column takes its declared default. In a multi-row insert each row can
+// default different columns while the column list stays the same. It adds
+// nothing to RequiredParams. SQLite has no DEFAULT value keyword and rejects it.
+func (*ASTQL) Default() types.Param {
+	return types.Param{Literal: types.LiteralDefault}
+}
+
 // TryC creates a validated condition, returning an error if invalid.
 func (a *ASTQL) TryC(field types.Field, op types.Operator, param types.Param) (types.Condition, error) {
 	// Validate field exists
@@ -246,6 +273,50 @@ func (a *ASTQL) C(field types.Field, op types.Operator, param types.Param) types
 	return c
 }
 
+// TryCIn creates field IN (:a, :b, ...) with one placeholder per param, for
+// drivers that cannot expand a slice bound to a single parameter.
+// An empty param list is an error.
+func (a *ASTQL) TryCIn(field types.Field, params ...types.Param) (types.InListCondition, error) {
+	return a.tryInList(field, types.IN, params)
+}
+
+// CIn creates a validated field IN (:a, :b, ...) condition.
+func (a *ASTQL) CIn(field types.Field, params ...types.Param) types.InListCondition {
+	c, err := a.TryCIn(field, params...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// TryCNotIn creates field NOT IN (:a, :b, ...), returning an error if invalid.
+func (a *ASTQL) TryCNotIn(field types.Field, params ...types.Param) (types.InListCondition, error) {
+	return a.tryInList(field, types.NotIn, params)
+}
+
+// CNotIn creates a validated field NOT IN (:a, :b, ...) condition.
+func (a *ASTQL) CNotIn(field types.Field, params ...types.Param) types.InListCondition {
+	c, err := a.TryCNotIn(field, params...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (a *ASTQL) tryInList(field types.Field, op types.Operator, params []types.Param) (types.InListCondition, error) {
+	if err := a.validateField(field.Name); err != nil {
+		return types.InListCondition{}, err
+	}
+	if len(params) == 0 {
+		return types.InListCondition{}, fmt.Errorf("%s on %s requires at least one param", op, field.Name)
+	}
+	return types.InListCondition{
+		Field:    field,
+		Operator: op,
+		Values:   append([]types.Param(nil), params...),
+	}, nil
+}
+
 // TryAggC creates a validated aggregate condition for HAVING clauses.
 // Use nil for field to create COUNT(*).
 func (a *ASTQL) TryAggC(aggFunc types.AggregateFunc, field *types.Field, op types.Operator, param types.Param) (types.AggregateCondition, error) {
@@ -353,6 +424,51 @@ func (a *ASTQL) Or(conditions ...types.ConditionItem) types.ConditionGroup {
 	return g
 }
 
+// TryAnyOf combines a slice of conditions with OR, returning an error if it is empty.
+// A single condition is returned unchanged rather than wrapped in a group.
+func (*ASTQL) TryAnyOf(conditions []types.ConditionItem) (types.ConditionItem, error) {
+	return combineConditions(types.OR, conditions)
+}
+
+// AnyOf combines a slice of conditions with OR.
+func (a *ASTQL) AnyOf(conditions []types.ConditionItem) types.ConditionItem {
+	c, err := a.TryAnyOf(conditions)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// TryAllOf combines a slice of conditions with AND, returning an error if it is empty.
+// A single condition is returned unchanged rather than wrapped in a group.
+func (*ASTQL) TryAllOf(conditions []types.ConditionItem) (types.ConditionItem, error) {
+	return combineConditions(types.AND, conditions)
+}
+
+// AllOf combines a slice of conditions with AND.
+func (a *ASTQL) AllOf(conditions []types.ConditionItem) types.ConditionItem {
+	c, err := a.TryAllOf(conditions)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// combineConditions groups conditions under logic. The slice is copied so
+// the caller can keep appending to it without changing the group.
+func combineConditions(logic types.LogicOperator, conditions []types.ConditionItem) (types.ConditionItem, error) {
+	switch len(conditions) {
+	case 0:
+		return nil, fmt.Errorf("%s requires at least one condition", logic)
+	case 1:
+		return conditions[0], nil
+	}
+	return types.ConditionGroup{
+		Logic:      logic,
+		Conditions: append([]types.ConditionItem(nil), conditions...),
+	}, nil
+}
+
 // WithTable creates a new Field with a table/alias prefix, validated against the schema.
 func (a *ASTQL) WithTable(field types.Field, tableOrAlias string) types.Field {
 	if err := a.validateTableOrAlias(tableOrAlias); err != nil {
@@ -432,6 +548,11 @@ func (*ASTQL) OpCount() types.Operation {
 	return types.OpCount
 }
 
+// OpTruncate returns the Truncate operation constant.
+func (*ASTQL) OpTruncate() types.Operation {
+	return types.OpTruncate
+}
+
 // Direction constants.
 
 // ASC returns the ascending sort direction constant.
@@ -592,6 +713,57 @@ func (a *ASTQL) JSONBText(field types.Field, key types.Param) types.Field {
 	}
 }
 
+// TryJSONText creates a field extracting a literal key as text, returning an error if invalid.
+// Example: TryJSONText(data, "status") -> "data"->>'status'
+func (a *ASTQL) TryJSONText(field types.Field, key string) (types.Field, error) {
+	if !types.IsJSONKey(key) {
+		return types.Field{}, fmt.Errorf("invalid JSON key '%s': must be alphanumeric/underscore", key)
+	}
+	return types.Field{
+		Name:    field.Name,
+		Table:   field.Table,
+		JSONKey: key,
+	}, nil
+}
+
+// JSONText creates a field extracting a literal key as text.
+// Example: JSONText(data, "status") -> "data"->>'status'
+func (a *ASTQL) JSONText(field types.Field, key string) types.Field {
+	f, err := a.TryJSONText(field, key)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// TryJSONPath creates a field extracting a nested literal path as text, returning an error if invalid.
+// Example: TryJSONPath(data, "address", "city") -> "data"#>>'{address,city}'
+func (a *ASTQL) TryJSONPath(field types.Field, keys ...string) (types.Field, error) {
+	if len(keys) == 0 {
+		return types.Field{}, fmt.Errorf("JSON path requires at least one key")
+	}
+	for _, key := range keys {
+		if !types.IsJSONKey(key) {
+			return types.Field{}, fmt.Errorf("invalid JSON path key '%s': must be alphanumeric/underscore", key)
+		}
+	}
+	return types.Field{
+		Name:     field.Name,
+		Table:    field.Table,
+		JSONPath: strings.Join(keys, ","),
+	}, nil
+}
+
+// JSONPath creates a field extracting a nested literal path as text.
+// Example: JSONPath(data, "address", "city") -> "data"#>>'{address,city}'
+func (a *ASTQL) JSONPath(field types.Field, keys ...string) types.Field {
+	f, err := a.TryJSONPath(field, keys...)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
 // JSONBPath creates a field with JSONB path access (->).
 // The key is parameterized for safety - renders as: field->:key_param
 // Example: JSONBPath(metadata, P("tags_key")) -> "metadata"->:tags_key
@@ -6,6 +6,7 @@ import (
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/astql/internal/types"
 	"github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/astql/sqlite"
 	"github.com/zoobzio/dbml"
 )
 
@@ -274,6 +275,140 @@ func TestTryOr_NoConditions(t *testing.T) {
 	}
 }
 
+func TestAnyOf(t *testing.T) {
+	instance := createTestInstance(t)
+
+	var conds []types.ConditionItem
+	for _, name := range []string{"id", "username", "email"} {
+		conds = append(conds, instance.C(instance.F(name), "=", instance.P(name)))
+	}
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.AnyOf(conds)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" WHERE ("id" = :id OR "username" = :username OR "email" = :email)`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	// The group keeps its own copy of the slice.
+	group := instance.AnyOf(conds).(types.ConditionGroup)
+	conds[0] = instance.C(instance.F("active"), "=", instance.P("active"))
+	if group.Conditions[0].(types.Condition).Field.Name != "id" {
+		t.Errorf("AnyOf group changed when the input slice was modified")
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	instance := createTestInstance(t)
+
+	conds := []types.ConditionItem{
+		instance.C(instance.F("id"), "=", instance.P("id")),
+		instance.C(instance.F("active"), "=", instance.P("active")),
+	}
+
+	group, ok := instance.AllOf(conds).(types.ConditionGroup)
+	if !ok {
+		t.Fatal("Expected AllOf to return a ConditionGroup")
+	}
+	if group.Logic != "AND" {
+		t.Errorf("Expected AND logic, got '%s'", group.Logic)
+	}
+	if len(group.Conditions) != 2 {
+		t.Errorf("Expected 2 conditions, got %d", len(group.Conditions))
+	}
+}
+
+func TestAnyOfAllOf_SingleCondition(t *testing.T) {
+	instance := createTestInstance(t)
+	cond := instance.C(instance.F("id"), "=", instance.P("id"))
+
+	for name, combine := range map[string]func([]types.ConditionItem) types.ConditionItem{
+		"AnyOf": instance.AnyOf,
+		"AllOf": instance.AllOf,
+	} {
+		got, ok := combine([]types.ConditionItem{cond}).(types.Condition)
+		if !ok || got != cond {
+			t.Errorf("%s with one condition = %#v, want the condition unchanged", name, got)
+		}
+	}
+}
+
+func TestAnyOfAllOf_Empty(t *testing.T) {
+	instance := createTestInstance(t)
+
+	if _, err := instance.TryAnyOf(nil); err == nil {
+		t.Error("Expected error for AnyOf with no conditions")
+	}
+	if _, err := instance.TryAllOf([]types.ConditionItem{}); err == nil {
+		t.Error("Expected error for AllOf with no conditions")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected AnyOf to panic with no conditions")
+		}
+	}()
+	instance.AnyOf(nil)
+}
+
+func TestCIn(t *testing.T) {
+	instance := createTestInstance(t)
+	ids := []types.Param{instance.P("a"), instance.P("b"), instance.P("c")}
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		expected string
+	}{
+		{"postgres", postgres.New(), `SELECT * FROM "users" WHERE "id" IN (:a, :b, :c)`},
+		{"sqlite", sqlite.New(), `SELECT * FROM "users" WHERE "id" IN (:a, :b, :c)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := astql.Select(instance.T("users")).
+				Where(instance.CIn(instance.F("id"), ids...)).
+				Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+			if len(result.RequiredParams) != 3 || result.RequiredParams[2] != "c" {
+				t.Errorf("RequiredParams = %v, want [a b c]", result.RequiredParams)
+			}
+		})
+	}
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.CNotIn(instance.F("id"), instance.P("a"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := `SELECT * FROM "users" WHERE "id" NOT IN (:a)`; result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestTryCIn_Errors(t *testing.T) {
+	instance := createTestInstance(t)
+
+	if _, err := instance.TryCIn(instance.F("id")); err == nil {
+		t.Error("Expected error for CIn with no params")
+	}
+	if _, err := instance.TryCNotIn(instance.F("id")); err == nil {
+		t.Error("Expected error for CNotIn with no params")
+	}
+	if _, err := instance.TryCIn(types.Field{Name: "missing"}, instance.P("a")); err == nil {
+		t.Error("Expected error for an unknown field")
+	}
+}
+
 func TestTryC_InvalidField(t *testing.T) {
 	instance := createTestInstance(t)
 
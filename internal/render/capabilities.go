@@ -21,4 +21,13 @@ type Capabilities struct {
 	ArrayOperators      bool            // @>, <@, &&
 	InArray             bool            // IN (:array_param)
 	RowLocking          RowLockingLevel // FOR UPDATE/SHARE support
+	FullOuterJoin       bool            // FULL OUTER JOIN
+	RightJoin           bool            // RIGHT JOIN
+	SkipLocked          bool            // FOR UPDATE ... SKIP LOCKED
+	WithTies            bool            // FETCH FIRST n ROWS WITH TIES
+
+	// GroupConcatTruncates is a caveat, not a feature: the dialect's string
+	// aggregate silently cuts its result at a server limit
+	// (group_concat_max_len on MariaDB). SupportedBy ignores it.
+	GroupConcatTruncates bool
 }
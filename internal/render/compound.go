@@ -0,0 +1,51 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// CompoundOrderField resolves a field in the ORDER BY of a set operation to
+// the output column it sorts by. The result of UNION, INTERSECT, and EXCEPT
+// has no table scope, so a table or alias qualifier is dropped. The name must
+// match a selected field or expression alias of the base query, which names
+// the output columns; a base query selecting * is not checked.
+func CompoundOrderField(query *types.CompoundQuery, field types.Field) (types.Field, error) {
+	field.Table = ""
+	base := query.Base
+	if base == nil || (len(base.Fields) == 0 && len(base.FieldExpressions) == 0) {
+		return field, nil
+	}
+	for _, selected := range base.Fields {
+		if selected.Name == field.Name {
+			return field, nil
+		}
+	}
+	for i := range base.FieldExpressions {
+		if base.FieldExpressions[i].Alias == field.Name {
+			return field, nil
+		}
+	}
+	return types.Field{}, fmt.Errorf("ORDER BY %s of a set operation must name an output column of the first query", field.Name)
+}
+
+// CompoundOperandParens returns the text written before and after each
+// SELECT of a set operation: parentheses, or nothing when bare is set. Bare
+// operands cannot carry their own ORDER BY, LIMIT, or OFFSET, since those
+// would bind to the whole compound query.
+func CompoundOperandParens(query *types.CompoundQuery, bare bool) (string, string, error) {
+	if !bare {
+		return "(", ")", nil
+	}
+	asts := []*types.AST{query.Base}
+	for _, operand := range query.Operands {
+		asts = append(asts, operand.AST)
+	}
+	for i, ast := range asts {
+		if len(ast.Ordering) > 0 || ast.Limit != nil || ast.Offset != nil {
+			return "", "", fmt.Errorf("compound query %d has ORDER BY, LIMIT, or OFFSET, which requires parenthesized operands", i)
+		}
+	}
+	return "", "", nil
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func TestCompoundOrderField(t *testing.T) {
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Fields:           []types.Field{{Name: "name", Table: "u"}},
+			FieldExpressions: []types.FieldExpression{{Aggregate: types.AggCountField, Alias: "total"}},
+		},
+	}
+
+	got, err := CompoundOrderField(query, types.Field{Name: "name", Table: "u"})
+	if err != nil {
+		t.Fatalf("CompoundOrderField() error = %v", err)
+	}
+	if got.Table != "" || got.Name != "name" {
+		t.Errorf("CompoundOrderField() = %+v, want unqualified name", got)
+	}
+
+	if _, err := CompoundOrderField(query, types.Field{Name: "total"}); err != nil {
+		t.Errorf("CompoundOrderField() for an alias error = %v", err)
+	}
+	if _, err := CompoundOrderField(query, types.Field{Name: "email"}); err == nil {
+		t.Error("expected error for a column the base query does not select")
+	}
+
+	star := &types.CompoundQuery{Base: &types.AST{}}
+	if _, err := CompoundOrderField(star, types.Field{Name: "email", Table: "u"}); err != nil {
+		t.Errorf("CompoundOrderField() with SELECT * error = %v", err)
+	}
+}
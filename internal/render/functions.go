@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Functions records the custom scalar functions a renderer accepts and the
+// number of arguments each takes. The zero value is empty and ready to use,
+// and it is safe for concurrent use.
+type Functions struct {
+	mu     sync.RWMutex
+	counts map[string]int
+}
+
+// Register adds or replaces a custom function.
+func (f *Functions) Register(name string, argCount int) error {
+	if !types.IsFunctionName(name) {
+		return fmt.Errorf("invalid function name '%s': must be alphanumeric/underscore identifiers separated by dots", name)
+	}
+	if argCount < 0 {
+		return fmt.Errorf("function '%s' cannot take %d arguments", name, argCount)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.counts == nil {
+		f.counts = make(map[string]int)
+	}
+	f.counts[name] = argCount
+	return nil
+}
+
+// Check validates a custom function call against the registered functions.
+func (f *Functions) Check(dialect string, expr types.FuncExpression) error {
+	if err := expr.Validate(); err != nil {
+		return err
+	}
+	f.mu.RLock()
+	argCount, ok := f.counts[expr.Name]
+	f.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("function '%s' is not registered with the %s renderer", expr.Name, dialect)
+	}
+	if len(expr.Args) != argCount {
+		return fmt.Errorf("function '%s' takes %d arguments, got %d", expr.Name, argCount, len(expr.Args))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func TestFunctions_RegisterAndCheck(t *testing.T) {
+	var funcs Functions
+	if err := funcs.Register("pg_catalog.similarity", 2); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	field := types.Field{Name: "name"}
+	param := types.Param{Name: "term"}
+	call := types.FuncExpression{
+		Name: "pg_catalog.similarity",
+		Args: []types.FuncArg{{Field: &field}, {Param: &param}},
+	}
+	if err := funcs.Check("postgres", call); err != nil {
+		t.Errorf("Check failed: %v", err)
+	}
+
+	call.Args = call.Args[:1]
+	if err := funcs.Check("postgres", call); err == nil {
+		t.Error("Expected error for wrong argument count")
+	}
+	if err := funcs.Check("postgres", types.FuncExpression{Name: "unknown"}); err == nil {
+		t.Error("Expected error for unregistered function")
+	}
+}
+
+func TestFunctions_RegisterRejectsInvalid(t *testing.T) {
+	var funcs Functions
+	for _, name := range []string{"", "fn()", "a..b", "x; DROP", "1fn"} {
+		if err := funcs.Register(name, 1); err == nil {
+			t.Errorf("Register(%q) expected error", name)
+		}
+	}
+	if err := funcs.Register("fn", -1); err == nil {
+		t.Error("Expected error for negative argument count")
+	}
+}
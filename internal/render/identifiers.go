@@ -0,0 +1,149 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+var (
+	fieldType = reflect.TypeOf(types.Field{})
+	tableType = reflect.TypeOf(types.Table{})
+)
+
+// LowercaseIdentifiers returns a copy of ast with every table and column
+// name lowercased, for matching a lowercase schema however the names were
+// typed. Names the user chose for the query itself are kept as written:
+// expression aliases, derived table aliases, and window names. A field
+// whose name or table qualifier refers to one of those aliases is left
+// unchanged too. The input AST is not modified.
+func LowercaseIdentifiers(ast *types.AST) *types.AST {
+	if ast == nil {
+		return nil
+	}
+	l := newLowercaser(reflect.ValueOf(ast))
+	return l.copy(reflect.ValueOf(ast)).Interface().(*types.AST)
+}
+
+// LowercaseCompoundIdentifiers applies LowercaseIdentifiers to every query
+// of a set operation and to its ORDER BY fields.
+func LowercaseCompoundIdentifiers(query *types.CompoundQuery) *types.CompoundQuery {
+	if query == nil {
+		return nil
+	}
+	l := newLowercaser(reflect.ValueOf(query))
+	return l.copy(reflect.ValueOf(query)).Interface().(*types.CompoundQuery)
+}
+
+// lowercaser copies a tree while lowercasing table and column names.
+// keep holds the user-chosen aliases that must keep their case.
+type lowercaser struct {
+	keep map[string]bool
+}
+
+func newLowercaser(root reflect.Value) *lowercaser {
+	l := &lowercaser{keep: make(map[string]bool)}
+	l.collectAliases(root)
+	return l
+}
+
+// collectAliases records every expression alias and derived table alias in the tree.
+func (l *lowercaser) collectAliases(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			l.collectAliases(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			l.collectAliases(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			l.collectAliases(iter.Value())
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case fieldType:
+			return
+		case tableType:
+			if alias := v.FieldByName("Alias").String(); alias != "" {
+				l.keep[alias] = true
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Name
+			if (name == "Alias" || name == "TargetAlias") && v.Field(i).Kind() == reflect.String {
+				if alias := v.Field(i).String(); alias != "" {
+					l.keep[alias] = true
+				}
+				continue
+			}
+			l.collectAliases(v.Field(i))
+		}
+	}
+}
+
+// copy deep-copies v like AST.Clone, rewriting Field and Table values on the way.
+func (l *lowercaser) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(l.copy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(l.copy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(l.copy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(l.copy(iter.Key()), l.copy(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			c.Field(i).Set(l.copy(v.Field(i)))
+		}
+		switch v.Type() {
+		case fieldType:
+			l.lower(c.FieldByName("Name"))
+			l.lower(c.FieldByName("Table"))
+		case tableType:
+			l.lower(c.FieldByName("Name"))
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// lower lowercases a name unless it is one of the kept aliases.
+func (l *lowercaser) lower(v reflect.Value) {
+	if name := v.String(); !l.keep[name] {
+		v.SetString(strings.ToLower(name))
+	}
+}
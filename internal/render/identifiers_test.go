@@ -0,0 +1,75 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+func TestLowercaseIdentifiers(t *testing.T) {
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "Users", Alias: "u"},
+		Fields:    []types.Field{{Name: "UserName", Table: "u"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggCountField, Field: types.Field{Name: "ID"}, Alias: "PostCount"},
+		},
+		Updates:  map[types.Field]types.Param{{Name: "Email"}: {Name: "Email"}},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "PostCount"}, Direction: types.DESC}},
+		WhereClause: types.SubqueryCondition{
+			Field:    &types.Field{Name: "ID"},
+			Operator: types.IN,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "Posts"},
+				Fields:    []types.Field{{Name: "UserID", Table: "Posts"}},
+			}},
+		},
+	}
+
+	got := LowercaseIdentifiers(ast)
+
+	if got.Target.Name != "users" || got.Target.Alias != "u" {
+		t.Errorf("Target = %+v, want users u", got.Target)
+	}
+	if got.Fields[0].Name != "username" {
+		t.Errorf("Fields[0].Name = %q, want username", got.Fields[0].Name)
+	}
+	if expr := got.FieldExpressions[0]; expr.Field.Name != "id" || expr.Alias != "PostCount" {
+		t.Errorf("FieldExpressions[0] = %q AS %q, want id AS PostCount", expr.Field.Name, expr.Alias)
+	}
+	if got.Ordering[0].Field.Name != "PostCount" {
+		t.Errorf("ORDER BY alias = %q, want PostCount kept", got.Ordering[0].Field.Name)
+	}
+	if p, ok := got.Updates[types.Field{Name: "email"}]; !ok || p.Name != "Email" {
+		t.Errorf("Updates = %v, want email key with param name unchanged", got.Updates)
+	}
+	sub := got.WhereClause.(types.SubqueryCondition).Subquery.AST
+	if sub.Target.Name != "posts" || sub.Fields[0].Name != "userid" || sub.Fields[0].Table != "posts" {
+		t.Errorf("subquery = %+v, want lowercased names", sub)
+	}
+
+	if ast.Target.Name != "Users" || ast.Fields[0].Name != "UserName" {
+		t.Error("LowercaseIdentifiers modified its input")
+	}
+}
+
+func TestLowercaseIdentifiers_DerivedAlias(t *testing.T) {
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		TargetSubquery: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "Orders"},
+		},
+		TargetAlias: "Recent",
+		Fields:      []types.Field{{Name: "Total", Table: "Recent"}},
+	}
+
+	got := LowercaseIdentifiers(ast)
+	if got.TargetAlias != "Recent" || got.Fields[0].Table != "Recent" {
+		t.Errorf("derived alias = %q / %q, want Recent kept", got.TargetAlias, got.Fields[0].Table)
+	}
+	if got.Fields[0].Name != "total" || got.TargetSubquery.Target.Name != "orders" {
+		t.Error("expected column and table names to be lowercased")
+	}
+}
@@ -0,0 +1,101 @@
+package render
+
+import "strings"
+
+// KeywordCase selects how SQL keywords are cased in rendered output.
+type KeywordCase int
+
+const (
+	KeywordUpper    KeywordCase = iota // SELECT ... FROM (default)
+	KeywordLower                       // select ... from
+	KeywordPreserve                    // Keywords as emitted by the renderer
+)
+
+// keywords lists the SQL keywords the renderers emit. Function names are
+// deliberately absent; they keep the casing the dialect renders.
+var keywords = map[string]bool{
+	"ALL": true, "ANALYZE": true, "AND": true, "ANY": true, "APPLY": true, "AS": true, "ASC": true,
+	"BETWEEN": true, "BIGINT": true, "BOOLEAN": true, "BY": true, "BYTEA": true,
+	"CASE": true, "CAST": true, "CONFLICT": true, "CROSS": true, "CUBE": true, "CURRENT": true,
+	"DATE": true, "DECIMAL": true, "DELETE": true, "DELETED": true, "DESC": true,
+	"DISTINCT": true, "DO": true, "DOUBLE": true, "DUPLICATE": true,
+	"ELSE": true, "END": true, "EXCEPT": true, "EXCLUDED": true, "EXISTS": true, "EXPLAIN": true,
+	"FALSE": true, "FETCH": true, "FILTER": true, "FIRST": true, "FLOAT": true,
+	"FOLLOWING": true, "FOR": true, "FORMAT": true, "FROM": true, "FULL": true,
+	"GLOB": true, "GROUP": true, "HAVING": true,
+	"ILIKE": true, "IN": true, "INNER": true, "INSERT": true, "INSERTED": true, "INT": true,
+	"INTEGER": true, "INTERSECT": true, "INTERVAL": true, "INTO": true, "IS": true,
+	"JOIN": true, "JSON": true, "JSONB": true, "KEY": true,
+	"LAST": true, "LATERAL": true, "LEFT": true, "LIKE": true, "LIMIT": true, "LOCK": true,
+	"MODE": true, "NATURAL": true, "NEXT": true, "NO": true, "NOT": true, "NOTHING": true,
+	"NULL": true, "NULLS": true, "NUMERIC": true, "NVARCHAR": true,
+	"OFFSET": true, "ON": true, "ONLY": true, "OR": true, "ORDER": true, "OUTER": true,
+	"OUTPUT": true, "OVER": true,
+	"PARTITION": true, "PLAN": true, "PRECEDING": true, "PRECISION": true, "QUERY": true,
+	"REAL": true, "REGEXP": true, "RETURNING": true, "RIGHT": true, "RLIKE": true,
+	"ROLLUP": true, "ROW": true, "ROWS": true,
+	"SELECT": true, "SET": true, "SETS": true, "SHARE": true, "SIGNED": true, "SMALLINT": true,
+	"TEXT": true, "THEN": true, "TIME": true, "TIMESTAMP": true, "TIMESTAMPTZ": true, "TRUE": true,
+	"UNBOUNDED": true, "UNION": true, "UNSIGNED": true, "UPDATE": true, "USING": true, "UUID": true,
+	"VALUES": true, "VARBINARY": true, "WHEN": true, "WHERE": true, "WITH": true,
+}
+
+// ApplyKeywordCase re-cases the SQL keywords in rendered SQL. Quoted identifiers
+// ("x", `x`, [x]), string literals, and :named parameters are copied unchanged.
+func ApplyKeywordCase(sql string, c KeywordCase) string {
+	if c == KeywordPreserve {
+		return sql
+	}
+
+	var out strings.Builder
+	out.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == '"' || ch == '`' || ch == '\'' || ch == '[':
+			closer := ch
+			if ch == '[' {
+				closer = ']'
+			}
+			end := strings.IndexByte(sql[i+1:], closer)
+			if end < 0 {
+				out.WriteString(sql[i:])
+				return out.String()
+			}
+			out.WriteString(sql[i : i+end+2])
+			i += end + 2
+		case ch == ':' || ch == '@' || ch == '$':
+			// Parameter placeholder: copy the sigil and the name verbatim.
+			j := i + 1
+			for j < len(sql) && isWordByte(sql[j]) {
+				j++
+			}
+			out.WriteString(sql[i:j])
+			i = j
+		case isWordByte(ch):
+			j := i + 1
+			for j < len(sql) && isWordByte(sql[j]) {
+				j++
+			}
+			word := sql[i:j]
+			if keywords[strings.ToUpper(word)] {
+				if c == KeywordLower {
+					word = strings.ToLower(word)
+				} else {
+					word = strings.ToUpper(word)
+				}
+			}
+			out.WriteString(word)
+			i = j
+		default:
+			out.WriteByte(ch)
+			i++
+		}
+	}
+	return out.String()
+}
+
+func isWordByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
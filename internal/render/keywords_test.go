@@ -0,0 +1,51 @@
+package render
+
+import "testing"
+
+func TestApplyKeywordCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		c        KeywordCase
+		expected string
+	}{
+		{
+			name:     "lower keeps identifiers and params",
+			sql:      `SELECT "Select", "userId" FROM "Users" WHERE "From" = :MinAge`,
+			c:        KeywordLower,
+			expected: `select "Select", "userId" from "Users" where "From" = :MinAge`,
+		},
+		{
+			name:     "lower keeps function names",
+			sql:      `SELECT COUNT(*) AS "n" FROM "t" ORDER BY "n" DESC NULLS LAST`,
+			c:        KeywordLower,
+			expected: `select COUNT(*) as "n" from "t" order by "n" desc nulls last`,
+		},
+		{
+			name:     "lower keeps string literals and other quoting",
+			sql:      "SELECT `Order`, [Group] FROM t WHERE x = 'AND' AND y IS NULL",
+			c:        KeywordLower,
+			expected: "select `Order`, [Group] from t where x = 'AND' and y is null",
+		},
+		{
+			name:     "upper",
+			sql:      `select "id" from "users" where "id" = @Id`,
+			c:        KeywordUpper,
+			expected: `SELECT "id" FROM "users" WHERE "id" = @Id`,
+		},
+		{
+			name:     "preserve",
+			sql:      `Select "id" From "users"`,
+			c:        KeywordPreserve,
+			expected: `Select "id" From "users"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyKeywordCase(tt.sql, tt.c); got != tt.expected {
+				t.Errorf("ApplyKeywordCase() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
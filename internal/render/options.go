@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Options configures optional renderer behavior shared by all dialects.
+// The zero value matches the default New() renderer.
+type Options struct {
+	// StripInSubqueryDistinct removes a redundant DISTINCT from IN/NOT IN
+	// subqueries. Opt-in because it changes the generated SQL.
+	StripInSubqueryDistinct bool
+
+	// StrictEmptyIn returns an error for an IN/NOT IN value list with no
+	// values instead of rendering an always-false (IN) or always-true
+	// (NOT IN) guard predicate.
+	StrictEmptyIn bool
+
+	// Placeholder selects the parameter placeholder style. The default
+	// PlaceholderColon renders :name; positional styles also fill
+	// QueryResult.PositionalParams.
+	Placeholder PlaceholderStyle
+
+	// LowercaseIdentifiers lowercases table and column names before they
+	// are quoted, so F("UserName") renders as "username". Expression and
+	// derived table aliases keep the case they were written in.
+	LowercaseIdentifiers bool
+
+	// BareCompoundOperands renders the SELECTs of UNION, INTERSECT, and
+	// EXCEPT without surrounding parentheses. SQLite always renders them
+	// bare; the other dialects parenthesize them by default.
+	BareCompoundOperands bool
+}
+
+// RenderOptions configures a single render call.
+// The zero value matches Render.
+type RenderOptions struct {
+	// KeywordCase controls the casing of SQL keywords. Identifiers,
+	// string literals, and parameter names are never changed.
+	KeywordCase KeywordCase
+
+	// Explain prefixes the statement with the dialect's EXPLAIN form for
+	// query-plan inspection. Parameters are unaffected.
+	Explain ExplainMode
+
+	// Pretty breaks the statement onto multiple lines, one major clause per
+	// line with joins indented. Parameters and semantics are unchanged.
+	Pretty bool
+}
+
+// ExplainMode selects whether and how a rendered statement is wrapped in EXPLAIN.
+type ExplainMode int
+
+const (
+	ExplainNone    ExplainMode = iota // Render the statement as-is (default)
+	ExplainPlan                       // Show the estimated plan without running the statement
+	ExplainAnalyze                    // Run the statement and report the actual plan
+)
+
+// ValuesRows renders placeholders as the rows of a one-column VALUES table:
+// VALUES (:a), (:b).
+func ValuesRows(placeholders []string) string {
+	return "VALUES (" + strings.Join(placeholders, "), (") + ")"
+}
+
+// EmptyInGuard returns the predicate rendered for an IN/NOT IN value list
+// with no values: 1 = 0 for IN and 1 = 1 for NOT IN. It returns an error
+// when strict is set.
+func EmptyInGuard(cond types.InListCondition, strict bool) (string, error) {
+	if strict {
+		return "", fmt.Errorf("%s on %s requires at least one value", cond.Operator, cond.Field.Name)
+	}
+	if cond.Operator == types.NotIn {
+		return "1 = 1", nil
+	}
+	return "1 = 0", nil
+}
@@ -0,0 +1,22 @@
+package render
+
+import "github.com/zoobzio/astql/internal/types"
+
+// PlaceholderStyle selects how parameters are written into rendered SQL.
+type PlaceholderStyle = types.PlaceholderStyle
+
+// Re-export placeholder style constants for renderer options.
+const (
+	PlaceholderColon    = types.PlaceholderColon
+	PlaceholderQuestion = types.PlaceholderQuestion
+	PlaceholderDollar   = types.PlaceholderDollar
+	PlaceholderAtP      = types.PlaceholderAtP
+)
+
+// Params records the parameters referenced during a render.
+type Params = types.Params
+
+// NewParams creates an empty parameter tracker for the given style.
+func NewParams(style PlaceholderStyle) *Params {
+	return types.NewParams(style)
+}
@@ -0,0 +1,40 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams_Placeholder(t *testing.T) {
+	uses := []string{"a", "b", "a", "c"}
+
+	tests := []struct {
+		name         string
+		style        PlaceholderStyle
+		placeholders []string
+		positional   []string
+	}{
+		{"colon", PlaceholderColon, []string{":a", ":b", ":a", ":c"}, nil},
+		{"question", PlaceholderQuestion, []string{"?", "?", "?", "?"}, []string{"a", "b", "a", "c"}},
+		{"dollar", PlaceholderDollar, []string{"$1", "$2", "$1", "$3"}, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParams(tt.style)
+			var got []string
+			for _, name := range uses {
+				got = append(got, p.Placeholder(name))
+			}
+			if !reflect.DeepEqual(got, tt.placeholders) {
+				t.Errorf("placeholders = %v, want %v", got, tt.placeholders)
+			}
+			if !reflect.DeepEqual(p.Positional(), tt.positional) {
+				t.Errorf("Positional() = %v, want %v", p.Positional(), tt.positional)
+			}
+			if want := []string{"a", "b", "c"}; !reflect.DeepEqual(p.Names(), want) {
+				t.Errorf("Names() = %v, want %v", p.Names(), want)
+			}
+		})
+	}
+}
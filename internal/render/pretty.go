@@ -0,0 +1,111 @@
+package render
+
+import "strings"
+
+// prettyIndent is written before each JOIN so joins read as part of FROM.
+const prettyIndent = "  "
+
+// lookahead is how much SQL after a word clauseBreak needs to see.
+const lookahead = len(" OUTER JOIN ")
+
+// joinModifiers are the words that can open a JOIN clause.
+var joinModifiers = map[string]bool{
+	"INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true, "NATURAL": true,
+}
+
+// ApplyPretty breaks rendered SQL onto multiple lines. Each top-level FROM,
+// WHERE, GROUP BY, HAVING, ORDER BY, LIMIT, and OFFSET starts a new line,
+// and each JOIN starts an indented line. Text inside parentheses, quoted
+// identifiers, and string literals is copied unchanged, so subqueries and
+// window specifications stay on one line. Keywords match in any case.
+// Parameters are not affected.
+func ApplyPretty(sql string) string {
+	var out strings.Builder
+	out.Grow(len(sql) + len(sql)/8)
+
+	depth := 0
+	prev := ""
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == '"' || ch == '`' || ch == '\'' || ch == '[':
+			closer := ch
+			if ch == '[' {
+				closer = ']'
+			}
+			end := strings.IndexByte(sql[i+1:], closer)
+			if end < 0 {
+				out.WriteString(sql[i:])
+				return out.String()
+			}
+			out.WriteString(sql[i : i+end+2])
+			i += end + 2
+			prev = ""
+		case ch == '(':
+			depth++
+			out.WriteByte(ch)
+			i++
+			prev = ""
+		case ch == ')':
+			depth--
+			out.WriteByte(ch)
+			i++
+			prev = ""
+		case ch == ':' || ch == '@' || ch == '$':
+			j := i + 1
+			for j < len(sql) && isWordByte(sql[j]) {
+				j++
+			}
+			out.WriteString(sql[i:j])
+			i = j
+			prev = ""
+		case isWordByte(ch):
+			j := i + 1
+			for j < len(sql) && isWordByte(sql[j]) {
+				j++
+			}
+			word := sql[i:j]
+			upper := strings.ToUpper(word)
+			if depth == 0 && strings.HasSuffix(out.String(), " ") {
+				if indent, ok := clauseBreak(upper, prev, strings.ToUpper(sql[j:min(j+lookahead, len(sql))])); ok {
+					trimmed := strings.TrimSuffix(out.String(), " ")
+					out.Reset()
+					out.WriteString(trimmed)
+					out.WriteString("\n")
+					out.WriteString(indent)
+				}
+			}
+			out.WriteString(word)
+			i = j
+			prev = upper
+		default:
+			out.WriteByte(ch)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// clauseBreak reports whether word opens a clause that belongs on its own
+// line, and the indentation for that line. prev is the preceding word and
+// rest the start of the SQL following word, both upper-cased.
+func clauseBreak(word, prev, rest string) (string, bool) {
+	switch word {
+	case "FROM":
+		// DELETE FROM and IS DISTINCT FROM are not clause starts.
+		return "", prev != "DELETE" && prev != "DISTINCT"
+	case "WHERE", "HAVING", "LIMIT", "OFFSET":
+		return "", true
+	case "GROUP", "ORDER":
+		return "", strings.HasPrefix(rest, " BY ")
+	case "JOIN":
+		return prettyIndent, !joinModifiers[prev] && prev != "OUTER"
+	}
+	if joinModifiers[word] && !joinModifiers[prev] {
+		next := strings.TrimPrefix(rest, " ")
+		if strings.HasPrefix(next, "JOIN ") || strings.HasPrefix(next, "OUTER JOIN ") {
+			return prettyIndent, true
+		}
+	}
+	return "", false
+}
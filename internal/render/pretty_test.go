@@ -0,0 +1,53 @@
+package render
+
+import "testing"
+
+func TestApplyPretty(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			name:     "major clauses",
+			sql:      `SELECT "id" FROM "t" WHERE "a" = :a ORDER BY "id" DESC LIMIT 5 OFFSET 10`,
+			expected: "SELECT \"id\"\nFROM \"t\"\nWHERE \"a\" = :a\nORDER BY \"id\" DESC\nLIMIT 5\nOFFSET 10",
+		},
+		{
+			name:     "joins are indented",
+			sql:      `SELECT * FROM "a" FULL OUTER JOIN "b" ON "a"."id" = "b"."id" CROSS JOIN LATERAL (SELECT 1) x JOIN "c" ON true`,
+			expected: "SELECT *\nFROM \"a\"\n  FULL OUTER JOIN \"b\" ON \"a\".\"id\" = \"b\".\"id\"\n  CROSS JOIN LATERAL (SELECT 1) x\n  JOIN \"c\" ON true",
+		},
+		{
+			name:     "subqueries and windows stay inline",
+			sql:      `SELECT ROW_NUMBER() OVER (ORDER BY "id") FROM "t" WHERE "id" IN (SELECT "id" FROM "u" WHERE "x" = :x)`,
+			expected: "SELECT ROW_NUMBER() OVER (ORDER BY \"id\")\nFROM \"t\"\nWHERE \"id\" IN (SELECT \"id\" FROM \"u\" WHERE \"x\" = :x)",
+		},
+		{
+			name:     "non-clause FROM and quoted text",
+			sql:      `DELETE FROM "t" WHERE "a" IS DISTINCT FROM :a AND "b" = 'x FROM y' AND "LEFT JOIN" = 1`,
+			expected: "DELETE FROM \"t\"\nWHERE \"a\" IS DISTINCT FROM :a AND \"b\" = 'x FROM y' AND \"LEFT JOIN\" = 1",
+		},
+		{
+			name:     "function named like a join modifier",
+			sql:      `SELECT LEFT("name", 3) FROM "t"`,
+			expected: "SELECT LEFT(\"name\", 3)\nFROM \"t\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyPretty(tt.sql); got != tt.expected {
+				t.Errorf("ApplyPretty() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyPretty_LowerCaseKeywords(t *testing.T) {
+	got := ApplyPretty(`select "id" from "t" left outer join "u" on true where "a" = :a group by "id"`)
+	expected := "select \"id\"\nfrom \"t\"\n  left outer join \"u\" on true\nwhere \"a\" = :a\ngroup by \"id\""
+	if got != expected {
+		t.Errorf("ApplyPretty() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Renderer defines the interface for SQL dialect-specific rendering.
+// Implementations convert an AST to dialect-specific SQL with named parameters.
+type Renderer interface {
+	// Render converts an AST to a QueryResult with dialect-specific SQL.
+	Render(ast *types.AST) (*types.QueryResult, error)
+
+	// RenderWithOptions renders an AST with per-call options such as keyword casing.
+	RenderWithOptions(ast *types.AST, opts RenderOptions) (*types.QueryResult, error)
+
+	// RenderCompound converts a CompoundQuery (UNION, INTERSECT, EXCEPT) to SQL.
+	RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error)
+
+	// Capabilities returns the SQL features supported by this dialect.
+	Capabilities() Capabilities
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() Renderer)
+)
+
+// Register makes a dialect renderer available by name.
+// Dialect packages call it from init. It panics if the name is empty,
+// the factory is nil, or the name is already registered.
+func Register(name string, factory func() Renderer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("render: Register requires a name")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("render: Register factory for %q is nil", name))
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("render: Register called twice for %q", name))
+	}
+	registry[name] = factory
+}
+
+// Lookup returns a new renderer for a registered dialect name.
+func Lookup(name string) (Renderer, bool) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// Names returns the registered dialect names in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
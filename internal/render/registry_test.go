@@ -0,0 +1,59 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/internal/types"
+)
+
+type stubRenderer struct{}
+
+func (stubRenderer) Render(*types.AST) (*types.QueryResult, error) {
+	return &types.QueryResult{SQL: "stub"}, nil
+}
+
+func (stubRenderer) RenderWithOptions(*types.AST, RenderOptions) (*types.QueryResult, error) {
+	return &types.QueryResult{SQL: "stub"}, nil
+}
+
+func (stubRenderer) RenderCompound(*types.CompoundQuery) (*types.QueryResult, error) {
+	return &types.QueryResult{SQL: "stub"}, nil
+}
+
+func (stubRenderer) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func TestRegister_Lookup(t *testing.T) {
+	Register("stub", func() Renderer { return stubRenderer{} })
+
+	r, ok := Lookup("stub")
+	if !ok || r == nil {
+		t.Fatal("Lookup(stub) failed")
+	}
+
+	found := false
+	for _, name := range Names() {
+		if name == "stub" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Names() = %v, want to contain stub", Names())
+	}
+
+	if _, ok := Lookup("missing"); ok {
+		t.Error("Lookup(missing) should fail")
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	Register("stub-dup", func() Renderer { return stubRenderer{} })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic registering a name twice")
+		}
+	}()
+	Register("stub-dup", func() Renderer { return stubRenderer{} })
+}
@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -10,13 +12,24 @@ import (
 type Operation string
 
 const (
-	OpSelect Operation = "SELECT"
-	OpInsert Operation = "INSERT"
-	OpUpdate Operation = "UPDATE"
-	OpDelete Operation = "DELETE"
-	OpCount  Operation = "COUNT"
+	OpSelect   Operation = "SELECT"
+	OpInsert   Operation = "INSERT"
+	OpUpdate   Operation = "UPDATE"
+	OpDelete   Operation = "DELETE"
+	OpCount    Operation = "COUNT"
+	OpTruncate Operation = "TRUNCATE"
 )
 
+// Validate checks that the operation is one of the known values.
+func (op Operation) Validate() error {
+	switch op {
+	case OpSelect, OpInsert, OpUpdate, OpDelete, OpCount, OpTruncate:
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", string(op))
+	}
+}
+
 // Direction represents sort direction.
 type Direction string
 
@@ -25,6 +38,16 @@ const (
 	DESC Direction = "DESC"
 )
 
+// Validate checks that the sort direction is one of the known values.
+func (d Direction) Validate() error {
+	switch d {
+	case ASC, DESC:
+		return nil
+	default:
+		return fmt.Errorf("unknown sort direction %q", string(d))
+	}
+}
+
 // NullsOrdering represents NULL ordering in ORDER BY.
 type NullsOrdering string
 
@@ -33,6 +56,16 @@ const (
 	NullsLast  NullsOrdering = "NULLS LAST"
 )
 
+// Validate checks that the NULLS ordering is one of the known values.
+func (n NullsOrdering) Validate() error {
+	switch n {
+	case NullsFirst, NullsLast:
+		return nil
+	default:
+		return fmt.Errorf("unknown NULLS ordering %q", string(n))
+	}
+}
+
 // OrderBy represents an ORDER BY clause.
 type OrderBy struct {
 	Field     Field
@@ -51,6 +84,18 @@ type PaginationValue struct {
 	Param  *Param // Parameterized value (takes precedence if set)
 }
 
+// validate checks that a static pagination value is non-negative.
+// Parameterized values are bound at execution and cannot be checked here.
+func (p *PaginationValue) validate(clause string) error {
+	if p == nil || p.Param != nil || p.Static == nil {
+		return nil
+	}
+	if *p.Static < 0 {
+		return fmt.Errorf("%s must be non-negative, got %d", clause, *p.Static)
+	}
+	return nil
+}
+
 // LockMode represents row-level locking modes (PostgreSQL).
 type LockMode string
 
@@ -61,6 +106,16 @@ const (
 	LockForKeyShare    LockMode = "FOR KEY SHARE"
 )
 
+// Validate checks that the lock mode is one of the known values.
+func (m LockMode) Validate() error {
+	switch m {
+	case LockForUpdate, LockForNoKeyUpdate, LockForShare, LockForKeyShare:
+		return nil
+	default:
+		return fmt.Errorf("unknown lock mode %q", string(m))
+	}
+}
+
 // JoinType represents the type of SQL join.
 type JoinType string
 
@@ -69,14 +124,66 @@ const (
 	LeftJoin      JoinType = "LEFT JOIN"
 	RightJoin     JoinType = "RIGHT JOIN"
 	FullOuterJoin JoinType = "FULL OUTER JOIN"
+	FullJoin      JoinType = FullOuterJoin // Alias for FullOuterJoin
 	CrossJoin     JoinType = "CROSS JOIN"
+	NaturalJoin   JoinType = "NATURAL JOIN"
 )
 
+// Validate checks that the join type is one of the known values.
+func (jt JoinType) Validate() error {
+	switch jt {
+	case InnerJoin, LeftJoin, RightJoin, FullOuterJoin, CrossJoin, NaturalJoin:
+		return nil
+	default:
+		return fmt.Errorf("unknown join type %q", string(jt))
+	}
+}
+
+// RequiresOn reports whether the join type takes an ON clause.
+// CROSS JOIN and NATURAL JOIN never do.
+func (jt JoinType) RequiresOn() bool {
+	return jt != CrossJoin && jt != NaturalJoin
+}
+
 // Join represents a SQL JOIN clause.
+// On and Using are mutually exclusive; Using renders JOIN ... USING (col, ...).
+// When Subquery is set the join targets a derived table named by Table.Alias,
+// optionally LATERAL and with a column alias list: LATERAL (SELECT ...) AS t(a, b).
 type Join struct {
-	On    ConditionItem
-	Table Table
-	Type  JoinType
+	On       ConditionItem
+	Subquery *Subquery
+	Table    Table
+	Type     JoinType
+	Using    []Field
+	Columns  []string
+	Lateral  bool
+}
+
+// GroupByMode represents the grouping form used by a GROUP BY clause.
+type GroupByMode string
+
+const (
+	GroupPlain  GroupByMode = ""              // GROUP BY a, b
+	GroupRollup GroupByMode = "ROLLUP"        // GROUP BY ROLLUP(a, b)
+	GroupCube   GroupByMode = "CUBE"          // GROUP BY CUBE(a, b)
+	GroupSets   GroupByMode = "GROUPING SETS" // GROUP BY GROUPING SETS((a), (b), ())
+)
+
+// Validate checks that the GROUP BY mode is one of the known values.
+func (m GroupByMode) Validate() error {
+	switch m {
+	case GroupPlain, GroupRollup, GroupCube, GroupSets:
+		return nil
+	default:
+		return fmt.Errorf("unknown GROUP BY mode %q", string(m))
+	}
+}
+
+// CoalescedGroup is a GROUP BY entry rendered as COALESCE(field, :sentinel),
+// so NULL values of a nullable column fall into a named bucket.
+type CoalescedGroup struct {
+	Field    Field
+	Sentinel Param
 }
 
 // ConflictAction represents what to do on conflict.
@@ -87,11 +194,26 @@ const (
 	DoUpdate  ConflictAction = "DO UPDATE"
 )
 
+// Validate checks that the conflict action is one of the known values.
+func (a ConflictAction) Validate() error {
+	switch a {
+	case DoNothing, DoUpdate:
+		return nil
+	default:
+		return fmt.Errorf("unknown conflict action %q", string(a))
+	}
+}
+
 // ConflictClause represents PostgreSQL's ON CONFLICT clause.
+// ConflictPredicate is the partial-index predicate written after the
+// conflict target, and DoUpdateWhere limits which conflicting rows a
+// DO UPDATE action changes.
 type ConflictClause struct {
-	Updates map[Field]Param
-	Action  ConflictAction
-	Columns []Field
+	Updates           map[Field]Param
+	ConflictPredicate ConditionItem
+	DoUpdateWhere     ConditionItem
+	Action            ConflictAction
+	Columns           []Field
 }
 
 // AggregateFunc represents SQL aggregate functions.
@@ -105,23 +227,252 @@ const (
 	// Note: COUNT is already an operation, but can also be an aggregate on fields.
 	AggCountField    AggregateFunc = "COUNT"
 	AggCountDistinct AggregateFunc = "COUNT_DISTINCT"
+	// Ordered aggregates, carried by an OrderedAggregate expression.
+	AggStringAgg AggregateFunc = "STRING_AGG"
+	AggArrayAgg  AggregateFunc = "ARRAY_AGG"
 )
 
+// Validate checks that the aggregate function is one of the known values.
+func (f AggregateFunc) Validate() error {
+	switch f {
+	case AggSum, AggAvg, AggMin, AggMax, AggCountField, AggCountDistinct, AggStringAgg, AggArrayAgg:
+		return nil
+	default:
+		return fmt.Errorf("unknown aggregate function %q", string(f))
+	}
+}
+
 // FieldExpression represents a field with optional aggregate function or SQL expression.
 type FieldExpression struct {
+	Field      Field
+	Aggregate  AggregateFunc
+	Filter     ConditionItem         // For FILTER clause on aggregates
+	Case       *CaseExpression       // For CASE expressions in SELECT
+	Coalesce   *CoalesceExpression   // For COALESCE expressions
+	NullIf     *NullIfExpression     // For NULLIF expressions
+	Math       *MathExpression       // For math functions
+	String     *StringExpression     // For string functions
+	Date       *DateExpression       // For date/time functions
+	DateDiff   *DateDiffExpression   // For the number of date parts between two dates
+	Cast       *CastExpression       // For type casting
+	Window     *WindowExpression     // For window functions
+	Binary     *BinaryExpression     // For field <op> param expressions (e.g., vector distance)
+	Grouping   *GroupingExpression   // For GROUPING() with ROLLUP/CUBE/GROUPING SETS
+	JSONObject *JSONObjectExpression // For JSON object construction
+	Array      *ArrayExpression      // For ARRAY[...] constructors (PostgreSQL)
+	Arithmetic *ArithmeticExpression // For arithmetic between expressions
+	Func       *FuncExpression       // For custom functions registered with the renderer
+	OrderedAgg *OrderedAggregate     // For STRING_AGG and other aggregates with their own ORDER BY
+	Alias      string
+}
+
+// ArithmeticOperator is an operator combining two numeric expressions.
+type ArithmeticOperator string
+
+const (
+	ArithAdd ArithmeticOperator = "+"
+	ArithSub ArithmeticOperator = "-"
+	ArithMul ArithmeticOperator = "*"
+	ArithDiv ArithmeticOperator = "/"
+	ArithMod ArithmeticOperator = "%"
+)
+
+// Validate checks that the arithmetic operator is one of the known values.
+func (op ArithmeticOperator) Validate() error {
+	switch op {
+	case ArithAdd, ArithSub, ArithMul, ArithDiv, ArithMod:
+		return nil
+	default:
+		return fmt.Errorf("unknown arithmetic operator %q", string(op))
+	}
+}
+
+// ArithmeticOperand is one side of an arithmetic expression.
+// Exactly one of Expr or Param is set.
+type ArithmeticOperand struct {
+	Expr  *FieldExpression
+	Param *Param
+}
+
+// ArithmeticExpression combines two operands with an arithmetic operator.
+// Operands may be fields, aggregates, window functions, or nested arithmetic,
+// e.g. SUM("paid") / COUNT(*) or "amount" / SUM("amount") OVER ().
+type ArithmeticExpression struct {
+	Left     ArithmeticOperand
+	Right    ArithmeticOperand
+	Operator ArithmeticOperator
+}
+
+// Validate checks the operator and that each operand has exactly one value.
+// Operand aliases are rejected because they cannot be rendered inside an expression.
+func (e ArithmeticExpression) Validate() error {
+	switch e.Operator {
+	case ArithAdd, ArithSub, ArithMul, ArithDiv, ArithMod:
+	default:
+		return fmt.Errorf("invalid arithmetic operator '%s'", e.Operator)
+	}
+	for _, operand := range []ArithmeticOperand{e.Left, e.Right} {
+		if (operand.Expr == nil) == (operand.Param == nil) {
+			return fmt.Errorf("arithmetic operand requires exactly one expression or param")
+		}
+		if operand.Expr != nil && operand.Expr.Alias != "" {
+			return fmt.Errorf("arithmetic operand cannot have an alias '%s'", operand.Expr.Alias)
+		}
+	}
+	return nil
+}
+
+// JSONObjectPair is one key/value entry of a JSON object expression.
+// Exactly one of Field or Param is set.
+type JSONObjectPair struct {
+	Field *Field
+	Param *Param
+	Key   string
+}
+
+// JSONObjectExpression builds a JSON object from key/value pairs,
+// e.g. json_build_object('id', "id", 'name', :name).
+type JSONObjectExpression struct {
+	Pairs []JSONObjectPair
+}
+
+// Validate checks that the object has pairs, each key is a safe identifier
+// (keys are rendered as string literals), and each pair has exactly one value.
+func (e JSONObjectExpression) Validate() error {
+	if len(e.Pairs) == 0 {
+		return fmt.Errorf("JSON object requires at least one key")
+	}
+	for _, pair := range e.Pairs {
+		if !IsJSONKey(pair.Key) {
+			return fmt.Errorf("invalid JSON object key '%s': must be alphanumeric/underscore", pair.Key)
+		}
+		if (pair.Field == nil) == (pair.Param == nil) {
+			return fmt.Errorf("JSON object key '%s' requires exactly one field or param value", pair.Key)
+		}
+	}
+	return nil
+}
+
+// ArrayExpression builds an array from individual params, e.g. ARRAY[:a, :b].
+type ArrayExpression struct {
+	Values []Param
+}
+
+// Validate checks that the array has at least one element and that every
+// literal element is a known literal.
+func (e ArrayExpression) Validate() error {
+	if len(e.Values) == 0 {
+		return fmt.Errorf("ARRAY constructor requires at least one value")
+	}
+	for i, value := range e.Values {
+		if !value.IsLiteral() {
+			continue
+		}
+		if value.Name != "" {
+			return fmt.Errorf("ARRAY element %d cannot be both a parameter and a literal", i+1)
+		}
+		if value.IsDefault() {
+			return fmt.Errorf("ARRAY element %d cannot be DEFAULT", i+1)
+		}
+		if err := value.Literal.Validate(); err != nil {
+			return fmt.Errorf("ARRAY element %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// isIdentifier reports whether s is a letter or underscore followed by
+// letters, digits, and underscores.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, ch := range s {
+		switch {
+		case ch == '_', ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		case ch >= '0' && ch <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FuncArg is one argument of a custom function call.
+// Exactly one of Field or Param is set.
+type FuncArg struct {
+	Field *Field
+	Param *Param
+}
+
+// OrderedAggregate is an aggregate that carries its own ordering,
+// e.g. STRING_AGG("name", :sep ORDER BY "name" ASC) or
+// ARRAY_AGG(DISTINCT "tag" ORDER BY "tag" ASC).
+type OrderedAggregate struct {
+	Separator *Param // STRING_AGG only; optional where the dialect has a default
+	Function  AggregateFunc
 	Field     Field
-	Aggregate AggregateFunc
-	Filter    ConditionItem       // For FILTER clause on aggregates
-	Case      *CaseExpression     // For CASE expressions in SELECT
-	Coalesce  *CoalesceExpression // For COALESCE expressions
-	NullIf    *NullIfExpression   // For NULLIF expressions
-	Math      *MathExpression     // For math functions
-	String    *StringExpression   // For string functions
-	Date      *DateExpression     // For date/time functions
-	Cast      *CastExpression     // For type casting
-	Window    *WindowExpression   // For window functions
-	Binary    *BinaryExpression   // For field <op> param expressions (e.g., vector distance)
-	Alias     string
+	OrderBy   []OrderBy
+	Distinct  bool // ARRAY_AGG only
+}
+
+// Validate checks the aggregate function, that it has a field, and that
+// the separator and DISTINCT are used only where they apply.
+func (e OrderedAggregate) Validate() error {
+	switch e.Function {
+	case AggStringAgg:
+		if e.Distinct {
+			return fmt.Errorf("%s does not support DISTINCT", e.Function)
+		}
+	case AggArrayAgg:
+		if e.Separator != nil {
+			return fmt.Errorf("%s does not take a separator", e.Function)
+		}
+	default:
+		return fmt.Errorf("invalid ordered aggregate '%s'", e.Function)
+	}
+	if e.Field.Name == "" {
+		return fmt.Errorf("%s requires a field", e.Function)
+	}
+	return nil
+}
+
+// FuncExpression calls a custom scalar function, e.g. my_fn("a", :b).
+// The function must be registered with the renderer, which checks the argument count.
+type FuncExpression struct {
+	Name string
+	Args []FuncArg
+}
+
+// Validate checks that the name is a safe, optionally schema-qualified
+// identifier and that each argument has exactly one value.
+func (e FuncExpression) Validate() error {
+	if !IsFunctionName(e.Name) {
+		return fmt.Errorf("invalid function name '%s': must be alphanumeric/underscore identifiers separated by dots", e.Name)
+	}
+	for i, arg := range e.Args {
+		if (arg.Field == nil) == (arg.Param == nil) {
+			return fmt.Errorf("argument %d of %s requires exactly one field or param", i+1, e.Name)
+		}
+	}
+	return nil
+}
+
+// IsFunctionName reports whether name is safe to render unquoted as a function
+// name: one or more identifiers separated by dots, such as my_fn or ext.my_fn.
+func IsFunctionName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !isIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupingExpression represents a GROUPING(col, ...) call, which reports
+// whether each column is aggregated away in the current subtotal row.
+type GroupingExpression struct {
+	Fields []Field
 }
 
 // BinaryExpression represents a binary operation between a field and a parameter.
@@ -170,6 +521,16 @@ const (
 	MathSqrt  MathFunc = "SQRT"
 )
 
+// Validate checks that the math function is one of the known values.
+func (f MathFunc) Validate() error {
+	switch f {
+	case MathRound, MathFloor, MathCeil, MathAbs, MathPower, MathSqrt:
+		return nil
+	default:
+		return fmt.Errorf("unknown math function %q", string(f))
+	}
+}
+
 // MathExpression represents a math function call.
 type MathExpression struct {
 	Function  MathFunc
@@ -192,13 +553,31 @@ const (
 	StringSubstring StringFunc = "SUBSTRING"
 	StringReplace   StringFunc = "REPLACE"
 	StringConcat    StringFunc = "CONCAT"
+	StringLPad      StringFunc = "LPAD"
+	StringRPad      StringFunc = "RPAD"
+	StringReverse   StringFunc = "REVERSE"
+	StringInitcap   StringFunc = "INITCAP"
+	StringPosition  StringFunc = "POSITION"
+	StringSplitPart StringFunc = "SPLIT_PART"
 )
 
+// Validate checks that the string function is one of the known values.
+func (f StringFunc) Validate() error {
+	switch f {
+	case StringUpper, StringLower, StringTrim, StringLTrim, StringRTrim, StringLength,
+		StringSubstring, StringReplace, StringConcat, StringLPad, StringRPad, StringReverse,
+		StringInitcap, StringPosition, StringSplitPart:
+		return nil
+	default:
+		return fmt.Errorf("unknown string function %q", string(f))
+	}
+}
+
 // StringExpression represents a string function call.
 type StringExpression struct {
 	Function StringFunc
 	Field    Field   // Primary field/column
-	Args     []Param // Additional arguments (for SUBSTRING, REPLACE, CONCAT)
+	Args     []Param // Additional arguments (for SUBSTRING, REPLACE, LPAD, RPAD, POSITION)
 	Fields   []Field // Additional fields (for CONCAT with multiple fields)
 	Alias    string
 }
@@ -208,13 +587,28 @@ type DateFunc string
 
 const (
 	DateNow              DateFunc = "NOW"
+	DateNowTz            DateFunc = "NOW_TZ"
 	DateCurrentDate      DateFunc = "CURRENT_DATE"
 	DateCurrentTime      DateFunc = "CURRENT_TIME"
 	DateCurrentTimestamp DateFunc = "CURRENT_TIMESTAMP"
 	DateExtract          DateFunc = "EXTRACT"
 	DateTrunc            DateFunc = "DATE_TRUNC"
+	DateBin              DateFunc = "DATE_BIN"
+	DateAdd              DateFunc = "DATE_ADD"
+	DateSub              DateFunc = "DATE_SUB"
 )
 
+// Validate checks that the date function is one of the known values.
+func (f DateFunc) Validate() error {
+	switch f {
+	case DateNow, DateNowTz, DateCurrentDate, DateCurrentTime, DateCurrentTimestamp, DateExtract,
+		DateTrunc, DateBin, DateAdd, DateSub:
+		return nil
+	default:
+		return fmt.Errorf("unknown date function %q", string(f))
+	}
+}
+
 // DatePart represents date/time parts for EXTRACT and DATE_TRUNC.
 type DatePart string
 
@@ -232,14 +626,78 @@ const (
 	PartEpoch     DatePart = "EPOCH"
 )
 
+// Validate checks that the date part is one of the known values.
+func (p DatePart) Validate() error {
+	switch p {
+	case PartYear, PartMonth, PartDay, PartHour, PartMinute, PartSecond, PartWeek, PartQuarter,
+		PartDayOfWeek, PartDayOfYear, PartEpoch:
+		return nil
+	default:
+		return fmt.Errorf("unknown date part %q", string(p))
+	}
+}
+
 // DateExpression represents a date/time function call.
 type DateExpression struct {
 	Function DateFunc
 	Field    *Field   // Optional - not needed for NOW, CURRENT_DATE, etc.
 	Part     DatePart // For EXTRACT and DATE_TRUNC
+	Interval *Param   // Bucket width for DATE_BIN, e.g. '15 minutes'
+	Origin   *Param   // Timestamp DATE_BIN buckets are aligned to
+	Amount   *Param   // Number of Parts to add for DATE_ADD and DATE_SUB
 	Alias    string
 }
 
+// IsIntervalUnit reports whether the part can be added to a date, as in
+// DATE_ADD and DATE_SUB. Derived parts like DOW and EPOCH cannot.
+func (p DatePart) IsIntervalUnit() bool {
+	switch p {
+	case PartYear, PartQuarter, PartMonth, PartWeek, PartDay, PartHour, PartMinute, PartSecond:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the operands of DATE_ADD and DATE_SUB. Other date
+// functions are checked by the dialects that render them.
+func (e DateExpression) Validate() error {
+	if e.Function != DateAdd && e.Function != DateSub {
+		return nil
+	}
+	if e.Field == nil || e.Amount == nil {
+		return fmt.Errorf("%s requires a field and an amount", e.Function)
+	}
+	if !e.Part.IsIntervalUnit() {
+		return fmt.Errorf("%s cannot add %s units", e.Function, e.Part)
+	}
+	return nil
+}
+
+// DateDiffExpression counts the Part units elapsed from Start to End.
+// Each side is a Field or a Param.
+type DateDiffExpression struct {
+	End   Value
+	Start Value
+	Part  DatePart
+}
+
+// Validate checks that both sides are fields or params and that Part is
+// a unit dates can be counted in.
+func (e DateDiffExpression) Validate() error {
+	for _, side := range []Value{e.End, e.Start} {
+		switch side.(type) {
+		case Field, Param:
+		default:
+			return fmt.Errorf("DATE_DIFF operands must be fields or params, got %T", side)
+		}
+	}
+	if !e.Part.IsIntervalUnit() {
+		return fmt.Errorf("DATE_DIFF cannot count %s units", e.Part)
+	}
+	return nil
+}
+
 // CastType represents allowed PostgreSQL data types for casting.
 type CastType string
 
@@ -261,12 +719,86 @@ const (
 	CastJSON            CastType = "JSON"
 	CastJSONB           CastType = "JSONB"
 	CastBytea           CastType = "BYTEA"
+	CastVarchar         CastType = "VARCHAR"
 )
 
+// Validate checks that the cast type is one of the known values.
+func (t CastType) Validate() error {
+	switch t {
+	case CastText, CastInteger, CastBigint, CastSmallint, CastNumeric, CastReal,
+		CastDoublePrecision, CastBoolean, CastDate, CastTime, CastTimestamp, CastTimestampTZ,
+		CastInterval, CastUUID, CastJSON, CastJSONB, CastBytea, CastVarchar:
+		return nil
+	default:
+		return fmt.Errorf("unknown cast type %q", string(t))
+	}
+}
+
+// CastSpec is a cast target with optional type parameters:
+// NUMERIC(Precision, Scale) or VARCHAR(Length). Zero means unset.
+type CastSpec struct {
+	Type      CastType
+	Precision int
+	Scale     int
+	Length    int
+}
+
+// CastTarget is a cast target: a bare CastType or a parameterized CastSpec.
+type CastTarget interface {
+	CastSpec() CastSpec
+}
+
+// CastSpec returns the type as an unparameterized cast target.
+func (t CastType) CastSpec() CastSpec {
+	return CastSpec{Type: t}
+}
+
+// CastSpec returns the spec itself.
+func (s CastSpec) CastSpec() CastSpec {
+	return s
+}
+
 // CastExpression represents a type cast.
 type CastExpression struct {
-	Field    Field
-	CastType CastType
+	Field     Field
+	CastType  CastType
+	Precision int // NUMERIC precision; 0 when unset
+	Scale     int // NUMERIC scale; requires Precision
+	Length    int // VARCHAR length; 0 when unset
+}
+
+// Validate checks the cast type and that type parameters match it.
+func (e CastExpression) Validate() error {
+	if err := e.CastType.Validate(); err != nil {
+		return err
+	}
+	if e.Precision < 0 || e.Scale < 0 || e.Length < 0 {
+		return fmt.Errorf("cast type parameters cannot be negative")
+	}
+	if (e.Precision > 0 || e.Scale > 0) && e.CastType != CastNumeric {
+		return fmt.Errorf("precision and scale require a NUMERIC cast, got %s", e.CastType)
+	}
+	if e.Scale > e.Precision {
+		return fmt.Errorf("cast scale %d exceeds precision %d", e.Scale, e.Precision)
+	}
+	if e.Length > 0 && e.CastType != CastVarchar {
+		return fmt.Errorf("length requires a VARCHAR cast, got %s", e.CastType)
+	}
+	return nil
+}
+
+// TypeParams renders the parenthesized type parameters, e.g. "(10,2)" or "(50)",
+// or "" when none are set.
+func (e CastExpression) TypeParams() string {
+	switch {
+	case e.Precision > 0 && e.Scale > 0:
+		return fmt.Sprintf("(%d,%d)", e.Precision, e.Scale)
+	case e.Precision > 0:
+		return fmt.Sprintf("(%d)", e.Precision)
+	case e.Length > 0:
+		return fmt.Sprintf("(%d)", e.Length)
+	}
+	return ""
 }
 
 // WindowFunc represents window function types.
@@ -283,6 +815,54 @@ const (
 	WinLastValue  WindowFunc = "LAST_VALUE"
 )
 
+// Validate checks that the window function is one of the known values.
+func (f WindowFunc) Validate() error {
+	switch f {
+	case WinRowNumber, WinRank, WinDenseRank, WinNtile, WinLag, WinLead, WinFirstValue,
+		WinLastValue:
+		return nil
+	default:
+		return fmt.Errorf("unknown window function %q", string(f))
+	}
+}
+
+// RequiresOrderBy reports whether the function is a ranking function, whose
+// result is only meaningful over an ordered window.
+func (f WindowFunc) RequiresOrderBy() bool {
+	switch f {
+	case WinRowNumber, WinRank, WinDenseRank, WinNtile:
+		return true
+	}
+	return false
+}
+
+// NullTreatment controls whether value window functions skip NULLs.
+type NullTreatment string
+
+const (
+	NullsRespect NullTreatment = "RESPECT NULLS"
+	NullsIgnore  NullTreatment = "IGNORE NULLS"
+)
+
+// Validate checks that the null treatment is one of the known values.
+func (n NullTreatment) Validate() error {
+	switch n {
+	case NullsRespect, NullsIgnore:
+		return nil
+	default:
+		return fmt.Errorf("unknown null treatment %q", string(n))
+	}
+}
+
+// AcceptsNullTreatment reports whether the function takes IGNORE/RESPECT NULLS.
+func (f WindowFunc) AcceptsNullTreatment() bool {
+	switch f {
+	case WinLag, WinLead, WinFirstValue, WinLastValue:
+		return true
+	}
+	return false
+}
+
 // FrameBound represents window frame boundaries.
 type FrameBound string
 
@@ -290,25 +870,166 @@ const (
 	FrameUnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
 	FrameCurrentRow         FrameBound = "CURRENT ROW"
 	FrameUnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+	FramePreceding          FrameBound = "PRECEDING" // <offset> PRECEDING; requires an offset param
+	FrameFollowing          FrameBound = "FOLLOWING" // <offset> FOLLOWING; requires an offset param
+)
+
+// Validate checks that the frame bound is one of the known values.
+func (b FrameBound) Validate() error {
+	switch b {
+	case FrameUnboundedPreceding, FrameCurrentRow, FrameUnboundedFollowing, FramePreceding,
+		FrameFollowing:
+		return nil
+	default:
+		return fmt.Errorf("unknown frame bound %q", string(b))
+	}
+}
+
+// FrameUnit represents the unit a window frame is measured in.
+type FrameUnit string
+
+const (
+	FrameRows   FrameUnit = "ROWS"
+	FrameRange  FrameUnit = "RANGE"
+	FrameGroups FrameUnit = "GROUPS"
 )
 
+// Validate checks that the frame unit is one of the known values.
+func (u FrameUnit) Validate() error {
+	switch u {
+	case FrameRows, FrameRange, FrameGroups:
+		return nil
+	default:
+		return fmt.Errorf("unknown frame unit %q", string(u))
+	}
+}
+
+// FrameExclude represents the EXCLUDE option of a window frame.
+type FrameExclude string
+
+const (
+	ExcludeNoOthers   FrameExclude = "NO OTHERS" // Default: exclude nothing
+	ExcludeCurrentRow FrameExclude = "CURRENT ROW"
+	ExcludeTies       FrameExclude = "TIES"
+	ExcludeGroup      FrameExclude = "GROUP"
+)
+
+// Validate checks that the frame exclusion is one of the known values.
+func (e FrameExclude) Validate() error {
+	switch e {
+	case ExcludeNoOthers, ExcludeCurrentRow, ExcludeTies, ExcludeGroup:
+		return nil
+	default:
+		return fmt.Errorf("unknown frame exclusion %q", string(e))
+	}
+}
+
 // WindowSpec represents a window specification.
 type WindowSpec struct {
-	FrameStart  FrameBound
-	FrameEnd    FrameBound
-	PartitionBy []Field
-	OrderBy     []OrderBy
+	FrameStartOffset *Param // Offset for a FramePreceding/FrameFollowing start
+	FrameEndOffset   *Param // Offset for a FramePreceding/FrameFollowing end
+	FrameUnit        FrameUnit
+	FrameExclude     FrameExclude // EXCLUDE option rendered after the frame bounds
+	FrameStart       FrameBound
+	FrameEnd         FrameBound
+	PartitionBy      []Field
+	OrderBy          []OrderBy
+}
+
+// IsEmpty reports whether the spec has no partitioning, ordering or frame.
+func (s WindowSpec) IsEmpty() bool {
+	return len(s.PartitionBy) == 0 && len(s.OrderBy) == 0 && s.FrameStart == "" && s.FrameEnd == ""
+}
+
+// Unit returns the frame unit, defaulting to ROWS.
+func (s WindowSpec) Unit() FrameUnit {
+	if s.FrameUnit == "" {
+		return FrameRows
+	}
+	return s.FrameUnit
+}
+
+// ExcludesRows reports whether the frame has an EXCLUDE option other than NO OTHERS.
+func (s WindowSpec) ExcludesRows() bool {
+	return s.FrameExclude != "" && s.FrameExclude != ExcludeNoOthers
+}
+
+// HasFrameOffset reports whether either frame bound uses an offset.
+func (s WindowSpec) HasFrameOffset() bool {
+	return s.FrameStartOffset != nil || s.FrameEndOffset != nil
+}
+
+// Validate checks the frame unit and that offset bounds and offset params
+// are paired. RANGE with an offset needs exactly one ORDER BY column, since the
+// offset is measured in that column's values.
+func (s WindowSpec) Validate() error {
+	switch s.FrameUnit {
+	case "", FrameRows, FrameRange, FrameGroups:
+	default:
+		return fmt.Errorf("invalid frame unit '%s'", s.FrameUnit)
+	}
+	switch s.FrameExclude {
+	case "", ExcludeNoOthers, ExcludeCurrentRow, ExcludeTies, ExcludeGroup:
+	default:
+		return fmt.Errorf("invalid frame exclusion '%s'", s.FrameExclude)
+	}
+	if s.FrameStart == "" && (s.FrameEnd != "" || s.FrameUnit != "" || s.HasFrameOffset() || s.FrameExclude != "") {
+		return fmt.Errorf("window frame requires a start bound")
+	}
+	if err := validateFrameBound(s.FrameStart, s.FrameStartOffset); err != nil {
+		return err
+	}
+	if err := validateFrameBound(s.FrameEnd, s.FrameEndOffset); err != nil {
+		return err
+	}
+	if s.Unit() == FrameRange && s.HasFrameOffset() && len(s.OrderBy) != 1 {
+		return fmt.Errorf("RANGE frame with an offset requires exactly one ORDER BY column, got %d", len(s.OrderBy))
+	}
+	return nil
+}
+
+func validateFrameBound(bound FrameBound, offset *Param) error {
+	isOffset := bound == FramePreceding || bound == FrameFollowing
+	if isOffset && offset == nil {
+		return fmt.Errorf("frame bound %s requires an offset", bound)
+	}
+	if !isOffset && offset != nil {
+		return fmt.Errorf("frame offset requires a PRECEDING or FOLLOWING bound")
+	}
+	return nil
 }
 
 // WindowExpression represents a window function call.
 type WindowExpression struct {
 	Field      *Field
+	WindowRef  string // Named window from AST.Windows; mutually exclusive with Window
 	NtileParam *Param
 	LagOffset  *Param
 	LagDefault *Param
 	Function   WindowFunc
 	Aggregate  AggregateFunc
 	Window     WindowSpec
+	// NullTreatment applies to LAG, LEAD, FIRST_VALUE and LAST_VALUE only.
+	NullTreatment NullTreatment
+}
+
+// Validate checks the null treatment against the window function.
+func (w WindowExpression) Validate() error {
+	switch w.NullTreatment {
+	case "":
+		return nil
+	case NullsRespect, NullsIgnore:
+	default:
+		return fmt.Errorf("invalid null treatment '%s'", w.NullTreatment)
+	}
+	if !w.Function.AcceptsNullTreatment() {
+		name := string(w.Function)
+		if name == "" {
+			name = string(w.Aggregate)
+		}
+		return fmt.Errorf("%s does not accept %s", name, w.NullTreatment)
+	}
+	return nil
 }
 
 // SetOperation represents SQL set operations.
@@ -323,6 +1044,16 @@ const (
 	SetExceptAll    SetOperation = "EXCEPT ALL"
 )
 
+// Validate checks that the set operation is one of the known values.
+func (op SetOperation) Validate() error {
+	switch op {
+	case SetUnion, SetUnionAll, SetIntersect, SetIntersectAll, SetExcept, SetExceptAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown set operation %q", string(op))
+	}
+}
+
 // SetOperand represents one operand in a set operation.
 type SetOperand struct {
 	AST       *AST
@@ -345,11 +1076,32 @@ type FieldComparison struct {
 	RightField Field
 }
 
+// Quantifier represents an ANY/ALL quantified subquery comparison.
+type Quantifier string
+
+const (
+	QuantNone Quantifier = ""
+	QuantAny  Quantifier = "ANY"
+	QuantAll  Quantifier = "ALL"
+)
+
+// Validate checks that the quantifier is one of the known values.
+func (q Quantifier) Validate() error {
+	switch q {
+	case QuantNone, QuantAny, QuantAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown quantifier %q", string(q))
+	}
+}
+
 // SubqueryCondition represents a condition that uses a subquery.
+// With a Quantifier, Operator is a scalar comparison: field op ANY|ALL (subquery).
 type SubqueryCondition struct {
-	Subquery Subquery
-	Field    *Field
-	Operator Operator
+	Subquery   Subquery
+	Field      *Field
+	Operator   Operator
+	Quantifier Quantifier
 }
 
 // Subquery represents a nested query.
@@ -357,6 +1109,141 @@ type Subquery struct {
 	AST *AST
 }
 
+// ExistsSubquery returns a shallow copy of ast trimmed for an EXISTS test.
+// EXISTS only checks whether a row is produced, so a LIMIT 1 without OFFSET is
+// dropped, and ORDER BY, DISTINCT, and DISTINCT ON are dropped once no LIMIT/OFFSET
+// remains; with one kept they still decide which rows are skipped or counted.
+// selectOne reports whether the select list can be replaced with a constant; it is
+// false when ORDER BY or DISTINCT is kept or a HAVING condition refers to a
+// select-list alias.
+func ExistsSubquery(ast *AST) (trimmed *AST, selectOne bool) {
+	if ast == nil {
+		return nil, false
+	}
+
+	cp := *ast
+	if cp.Offset == nil && cp.Limit != nil && cp.Limit.Static != nil && *cp.Limit.Static == 1 {
+		cp.Limit = nil
+	}
+	if cp.Limit == nil && cp.Offset == nil {
+		cp.Ordering = nil
+		cp.Distinct = false
+		cp.DistinctOn = nil
+	}
+
+	selectOne = len(cp.Ordering) == 0 && !cp.Distinct
+	for _, cond := range cp.Having {
+		if agg, ok := cond.(AggregateCondition); ok && agg.Alias != "" {
+			selectOne = false
+		}
+	}
+
+	return &cp, selectOne
+}
+
+// StripDistinct returns a shallow copy of ast without DISTINCT, for IN/NOT IN
+// subqueries where duplicates do not affect the result. DISTINCT is kept when
+// LIMIT or OFFSET is set, since they apply after deduplication.
+func StripDistinct(ast *AST) *AST {
+	if ast == nil || !ast.Distinct || ast.Limit != nil || ast.Offset != nil {
+		return ast
+	}
+	cp := *ast
+	cp.Distinct = false
+	return &cp
+}
+
+// TupleCondition compares a row value against a row of params or a subquery.
+// Examples: ("a", "b") = (:p1, :p2), ("a", "b") IN (SELECT "x", "y" FROM ...)
+// Exactly one of Values or Subquery must be set.
+type TupleCondition struct {
+	Subquery *Subquery
+	Fields   []Field
+	Values   []Param
+	Operator Operator
+}
+
+// Validate checks the tuple shape and operator.
+func (c TupleCondition) Validate() error {
+	if len(c.Fields) < 2 {
+		return fmt.Errorf("tuple condition requires at least two fields")
+	}
+	switch {
+	case c.Subquery != nil && len(c.Values) > 0:
+		return fmt.Errorf("tuple condition cannot have both values and a subquery")
+	case c.Subquery != nil:
+		if c.Subquery.AST == nil {
+			return fmt.Errorf("tuple subquery requires an AST")
+		}
+		if c.Operator != IN && c.Operator != NotIn && !c.Operator.IsComparison() {
+			return fmt.Errorf("operator %s cannot be used with a tuple subquery", c.Operator)
+		}
+	case len(c.Values) > 0:
+		if len(c.Values) != len(c.Fields) {
+			return fmt.Errorf("tuple has %d fields but %d values", len(c.Fields), len(c.Values))
+		}
+		if !c.Operator.IsComparison() {
+			return fmt.Errorf("operator %s cannot be used with tuple values", c.Operator)
+		}
+	default:
+		return fmt.Errorf("tuple condition requires values or a subquery")
+	}
+	return nil
+}
+
+// Expand rewrites a tuple comparison against values into per-column
+// conditions, for dialects without row value comparisons. Equality becomes
+// an AND of column equalities, inequality an OR of column inequalities, and
+// an ordering comparison the lexicographic chain
+// (a > :a OR (a = :a AND b > :b) ...), where only the last column keeps an
+// inclusive >= or <=. ok is false for subquery tuples.
+func (c TupleCondition) Expand() (expanded ConditionItem, ok bool) {
+	if c.Subquery != nil || len(c.Values) != len(c.Fields) {
+		return nil, false
+	}
+	column := func(i int, op Operator) ConditionItem {
+		return Condition{Field: c.Fields[i], Operator: op, Value: c.Values[i]}
+	}
+
+	switch c.Operator {
+	case EQ, NE:
+		logic := AND
+		if c.Operator == NE {
+			logic = OR
+		}
+		group := ConditionGroup{Logic: logic}
+		for i := range c.Fields {
+			group.Conditions = append(group.Conditions, column(i, c.Operator))
+		}
+		return group, true
+	case GT, GE, LT, LE:
+		strict := GT
+		if c.Operator == LT || c.Operator == LE {
+			strict = LT
+		}
+		chain := ConditionGroup{Logic: OR}
+		for i := range c.Fields {
+			op := strict
+			if i == len(c.Fields)-1 {
+				op = c.Operator
+			}
+			if i == 0 {
+				chain.Conditions = append(chain.Conditions, column(0, op))
+				continue
+			}
+			term := ConditionGroup{Logic: AND}
+			for j := 0; j < i; j++ {
+				term.Conditions = append(term.Conditions, column(j, EQ))
+			}
+			term.Conditions = append(term.Conditions, column(i, op))
+			chain.Conditions = append(chain.Conditions, term)
+		}
+		return chain, true
+	default:
+		return nil, false
+	}
+}
+
 // Constants for query complexity limits to prevent DoS attacks.
 const (
 	MaxSubqueryDepth   = 3   // Prevent DoS via deep nesting
@@ -367,9 +1254,33 @@ const (
 	MaxSetOperations   = 5   // Maximum number of UNION/INTERSECT/EXCEPT operations
 )
 
+// InListCondition compares a field against an explicit list of params.
+// Example: "id" IN (:a, :b, :c)
+// An empty list has no valid SQL form; renderers emit an always-false (IN)
+// or always-true (NOT IN) guard instead, or an error in strict mode.
+// With ValuesTable set the list renders as an inline VALUES table,
+// "id" IN (VALUES (:a), (:b)), which planners execute as a hashed semi-join
+// instead of a long chain of comparisons. The result is the same.
+type InListCondition struct {
+	Field       Field
+	Operator    Operator // IN or NOT IN
+	Values      []Param
+	ValuesTable bool
+}
+
+// Validate checks the operator.
+func (c InListCondition) Validate() error {
+	if c.Operator != IN && c.Operator != NotIn {
+		return fmt.Errorf("operator %s cannot be used with a value list", c.Operator)
+	}
+	return nil
+}
+
 // Implement ConditionItem interface for new condition types.
 func (FieldComparison) IsConditionItem()   {}
 func (SubqueryCondition) IsConditionItem() {}
+func (TupleCondition) IsConditionItem()    {}
+func (InListCondition) IsConditionItem()   {}
 
 // AST represents the abstract syntax tree for PostgreSQL queries.
 // This is exported from the internal package so the base package can use it,
@@ -379,30 +1290,379 @@ func (SubqueryCondition) IsConditionItem() {}
 type AST struct {
 	WhereClause       ConditionItem
 	Lock              *LockMode
+	LockOf            []Table // FOR UPDATE OF: restricts the lock to these FROM/JOIN tables
 	OnConflict        *ConflictClause
 	Limit             *PaginationValue
 	Offset            *PaginationValue
 	Updates           map[Field]Param
 	UpdateExpressions map[Field]FieldExpression
+	UpdateRows        []RowUpdate // SET (a, b) = (SELECT ...) assignments
 	Target            Table
+	TargetSubquery    *AST   // Derived table FROM target: (SELECT ...) AS TargetAlias
+	TargetAlias       string // Required when TargetSubquery is set
 	Operation         Operation
 	Values            []map[Field]Param
+	ValueSubqueries   []map[Field]Subquery        // Scalar subquery cells, indexed like Values
+	ValueArrays       []map[Field]ArrayExpression // ARRAY[...] cells, indexed like Values
 	Ordering          []OrderBy
 	Joins             []Join
 	GroupBy           []Field
+	GroupByMode       GroupByMode
+	GroupByAll        bool // Also group by every projected Field; see GroupByFields
+	GroupingSets      [][]Field
+	CoalescedGroupBy  []CoalescedGroup // GROUP BY COALESCE(field, :sentinel), after GroupBy
 	Having            []ConditionItem
+	Windows           map[string]WindowSpec // Named windows: WINDOW name AS (...)
 	FieldExpressions  []FieldExpression
 	Returning         []Field
 	DistinctOn        []Field
 	Fields            []Field
 	Distinct          bool
+	SkipLocked        bool // SKIP LOCKED: skip rows locked by other transactions
+	WithTies          bool // FETCH FIRST n ROWS WITH TIES: also return rows tied with the last
+	ReturningCount    bool // UPDATE/DELETE answers with the number of affected rows
+	RestartIdentity   bool // TRUNCATE ... RESTART IDENTITY: reset owned sequences
+	Cascade           bool // TRUNCATE ... CASCADE: also truncate referencing tables
+}
+
+// RowUpdate assigns several columns from the single row a subquery
+// returns: SET (a, b) = (SELECT x, y FROM ...).
+type RowUpdate struct {
+	Subquery Subquery
+	Fields   []Field
+}
+
+// validateLockOf checks that every OF table of a row lock names the target
+// or a joined table by its alias (or its name when unaliased).
+func (ast *AST) validateLockOf() error {
+	if len(ast.LockOf) == 0 {
+		return nil
+	}
+	if ast.Lock == nil {
+		return fmt.Errorf("lock OF tables require a row lock mode")
+	}
+
+	present := make(map[string]bool, len(ast.Joins)+1)
+	if ast.TargetSubquery == nil {
+		present[lockRef(ast.Target)] = true
+	}
+	for _, join := range ast.Joins {
+		if join.Subquery == nil {
+			present[lockRef(join.Table)] = true
+		}
+	}
+	for _, table := range ast.LockOf {
+		if !present[lockRef(table)] {
+			return fmt.Errorf("lock OF table '%s' is not in FROM or JOIN", lockRef(table))
+		}
+	}
+	return nil
+}
+
+// lockRef returns the name a table is referenced by in a locking clause.
+func lockRef(t Table) string {
+	if t.Alias != "" {
+		return t.Alias
+	}
+	return t.Name
+}
+
+// Tables returns the distinct table names the AST references, target first,
+// followed by joined tables and tables inside derived tables and subqueries.
+func (ast *AST) Tables() []string {
+	var names []string
+	ast.collectTables(&names, make(map[string]bool))
+	return names
+}
+
+// Tables returns the distinct table names referenced by every query in the compound.
+func (q *CompoundQuery) Tables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	q.Base.collectTables(&names, seen)
+	for _, operand := range q.Operands {
+		operand.AST.collectTables(&names, seen)
+	}
+	return names
+}
+
+func (ast *AST) collectTables(names *[]string, seen map[string]bool) {
+	add := func(table Table) {
+		if table.Values == nil && table.Name != "" && !seen[table.Name] {
+			seen[table.Name] = true
+			*names = append(*names, table.Name)
+		}
+	}
+
+	if ast.TargetSubquery == nil {
+		add(ast.Target)
+	}
+	for _, join := range ast.Joins {
+		if join.Subquery == nil {
+			add(join.Table)
+		}
+	}
+	for _, sub := range ast.Subqueries() {
+		sub.collectTables(names, seen)
+	}
+}
+
+// Subqueries returns the ASTs nested directly in ast: the derived FROM
+// target, join subqueries, subqueries in WHERE, HAVING, and join
+// conditions and in the FILTER and CASE WHEN conditions of select
+// expressions, scalar subquery cells of INSERT values, and UPDATE row
+// assignments. Deeper levels are reached by calling Subqueries on each result.
+func (ast *AST) Subqueries() []*AST {
+	var subs []*AST
+	if ast.TargetSubquery != nil {
+		subs = append(subs, ast.TargetSubquery)
+	}
+	for _, join := range ast.Joins {
+		if join.Subquery != nil && join.Subquery.AST != nil {
+			subs = append(subs, join.Subquery.AST)
+		}
+	}
+
+	conds := append([]ConditionItem{ast.WhereClause}, ast.Having...)
+	for _, join := range ast.Joins {
+		conds = append(conds, join.On)
+	}
+	for _, expr := range ast.FieldExpressions {
+		conds = append(conds, expr.Filter)
+		if expr.Case != nil {
+			for _, when := range expr.Case.WhenClauses {
+				conds = append(conds, when.Condition)
+			}
+		}
+	}
+	for _, cond := range conds {
+		for _, sub := range subqueriesOf(cond) {
+			if sub != nil {
+				subs = append(subs, sub)
+			}
+		}
+	}
+
+	for _, cells := range ast.ValueSubqueries {
+		for _, sub := range cells {
+			if sub.AST != nil {
+				subs = append(subs, sub.AST)
+			}
+		}
+	}
+
+	for _, row := range ast.UpdateRows {
+		if row.Subquery.AST != nil {
+			subs = append(subs, row.Subquery.AST)
+		}
+	}
+	return subs
+}
+
+// subqueriesOf returns the subquery ASTs referenced by a condition tree.
+func subqueriesOf(cond ConditionItem) []*AST {
+	switch c := cond.(type) {
+	case SubqueryCondition:
+		return []*AST{c.Subquery.AST}
+	case Comparison:
+		if sub, ok := c.Value.(Subquery); ok {
+			return []*AST{sub.AST}
+		}
+	case TupleCondition:
+		if c.Subquery != nil {
+			return []*AST{c.Subquery.AST}
+		}
+	case ConditionGroup:
+		var subs []*AST
+		for _, sub := range c.Conditions {
+			subs = append(subs, subqueriesOf(sub)...)
+		}
+		return subs
+	}
+	return nil
+}
+
+// InsertColumns returns the columns of the first value set, including
+// subquery cells, sorted by name as the INSERT column list renders them.
+func (ast *AST) InsertColumns() []Field {
+	if len(ast.Values) == 0 {
+		return nil
+	}
+	keys := ast.valueSetFields(0)
+	fields := make([]Field, 0, len(keys))
+	for field := range keys {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+// ValueSubquery returns the scalar subquery cell for a field of value set i, if any.
+func (ast *AST) ValueSubquery(i int, field Field) (Subquery, bool) {
+	if i >= len(ast.ValueSubqueries) {
+		return Subquery{}, false
+	}
+	sub, ok := ast.ValueSubqueries[i][field]
+	return sub, ok
+}
+
+// valueSetFields returns the fields of value set i across params and subquery cells.
+func (ast *AST) valueSetFields(i int) map[Field]bool {
+	fields := make(map[Field]bool, len(ast.Values[i]))
+	for field := range ast.Values[i] {
+		fields[field] = true
+	}
+	if i < len(ast.ValueSubqueries) {
+		for field := range ast.ValueSubqueries[i] {
+			fields[field] = true
+		}
+	}
+	if i < len(ast.ValueArrays) {
+		for field := range ast.ValueArrays[i] {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
+// ValueArray returns the ARRAY constructor cell for a field of value set i, if any.
+func (ast *AST) ValueArray(i int, field Field) (ArrayExpression, bool) {
+	if i >= len(ast.ValueArrays) {
+		return ArrayExpression{}, false
+	}
+	array, ok := ast.ValueArrays[i][field]
+	return array, ok
+}
+
+// validateValueArrays checks that ARRAY cells belong to a value set, do not
+// also have a param or subquery, and are valid constructors.
+func (ast *AST) validateValueArrays() error {
+	if len(ast.ValueArrays) > len(ast.Values) {
+		return fmt.Errorf("value arrays reference %d value sets but only %d exist", len(ast.ValueArrays), len(ast.Values))
+	}
+	for i, cells := range ast.ValueArrays {
+		for field, array := range cells {
+			if _, ok := ast.Values[i][field]; ok {
+				return fmt.Errorf("value set %d has both a param and an array for '%s'", i, field.Name)
+			}
+			if _, ok := ast.ValueSubquery(i, field); ok {
+				return fmt.Errorf("value set %d has both a subquery and an array for '%s'", i, field.Name)
+			}
+			if err := array.Validate(); err != nil {
+				return fmt.Errorf("value array for '%s': %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateValueSubqueries checks that subquery cells belong to a value set,
+// do not also have a param, and are single-column SELECTs.
+func (ast *AST) validateValueSubqueries() error {
+	if len(ast.ValueSubqueries) > len(ast.Values) {
+		return fmt.Errorf("value subqueries reference %d value sets but only %d exist", len(ast.ValueSubqueries), len(ast.Values))
+	}
+	for i, cells := range ast.ValueSubqueries {
+		for field, sub := range cells {
+			if _, ok := ast.Values[i][field]; ok {
+				return fmt.Errorf("value set %d has both a param and a subquery for '%s'", i, field.Name)
+			}
+			if sub.AST == nil || sub.AST.Operation != OpSelect {
+				return fmt.Errorf("value subquery for '%s' must be a SELECT", field.Name)
+			}
+			if len(sub.AST.Fields)+len(sub.AST.FieldExpressions) != 1 {
+				return fmt.Errorf("value subquery for '%s' must select exactly one column", field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateUpdateRows checks that each row assignment names columns not
+// set elsewhere and is a SELECT projecting one column per assigned field.
+func (ast *AST) validateUpdateRows() error {
+	seen := make(map[string]bool)
+	for i, row := range ast.UpdateRows {
+		if len(row.Fields) == 0 {
+			return fmt.Errorf("row update %d requires at least one field", i+1)
+		}
+		for _, field := range row.Fields {
+			_, inSet := ast.Updates[field]
+			_, inExpr := ast.UpdateExpressions[field]
+			if inSet || inExpr || seen[field.Name] {
+				return fmt.Errorf("field %s is assigned more than once", field.Name)
+			}
+			seen[field.Name] = true
+		}
+		sub := row.Subquery.AST
+		if sub == nil || sub.Operation != OpSelect {
+			return fmt.Errorf("row update %d must assign from a SELECT", i+1)
+		}
+		if n := len(sub.Fields) + len(sub.FieldExpressions); n != len(row.Fields) {
+			return fmt.Errorf("row update %d assigns %d fields but its subquery selects %d columns", i+1, len(row.Fields), n)
+		}
+	}
+	return nil
+}
+
+// validateLiterals checks every literal value in a field-to-param map.
+func validateLiterals(values map[Field]Param) error {
+	for field, param := range values {
+		if param.IsExcluded() {
+			return fmt.Errorf("value for %s: EXCLUDED references are only valid in ON CONFLICT DO UPDATE", field.Name)
+		}
+		if !param.IsLiteral() {
+			continue
+		}
+		if param.Name != "" {
+			return fmt.Errorf("value for %s cannot be both a parameter and a literal", field.Name)
+		}
+		if err := param.Literal.Validate(); err != nil {
+			return fmt.Errorf("value for %s: %w", field.Name, err)
+		}
+	}
+	return nil
 }
 
 // Validate performs basic validation on the AST.
 func (ast *AST) Validate() error {
-	if ast.Target.Name == "" {
+	if err := validateFields(reflect.ValueOf(ast)); err != nil {
+		return err
+	}
+	if ast.TargetSubquery != nil {
+		if ast.Operation != OpSelect && ast.Operation != OpCount {
+			return fmt.Errorf("derived table target can only be used with SELECT or COUNT queries")
+		}
+		if ast.TargetAlias == "" {
+			return fmt.Errorf("derived table target requires an alias")
+		}
+	} else if ast.Target.Values != nil {
+		if ast.Operation != OpSelect && ast.Operation != OpCount {
+			return fmt.Errorf("VALUES table target can only be used with SELECT or COUNT queries")
+		}
+		if err := ast.Target.validateValues(); err != nil {
+			return err
+		}
+	} else if ast.Target.Name == "" {
 		return fmt.Errorf("target table is required")
 	}
+	for _, join := range ast.Joins {
+		if join.Table.Values == nil {
+			continue
+		}
+		if join.Subquery != nil {
+			return fmt.Errorf("join cannot have both a subquery and a VALUES table")
+		}
+		if err := join.Table.validateValues(); err != nil {
+			return err
+		}
+	}
+
+	if (ast.RestartIdentity || ast.Cascade) && ast.Operation != OpTruncate {
+		return fmt.Errorf("RESTART IDENTITY and CASCADE can only be used with TRUNCATE")
+	}
 
 	// Check complexity limits
 	if len(ast.Joins) > MaxJoinCount {
@@ -414,17 +1674,87 @@ func (ast *AST) Validate() error {
 		return fmt.Errorf("too many fields: %d (max %d)", totalFields, MaxFieldCount)
 	}
 
-	// Count window functions
+	// Count window functions and resolve named window references
 	windowCount := 0
 	for i := range ast.FieldExpressions {
-		if ast.FieldExpressions[i].Window != nil {
-			windowCount++
+		win := ast.FieldExpressions[i].Window
+		if win == nil {
+			continue
+		}
+		windowCount++
+		if err := win.Validate(); err != nil {
+			return err
+		}
+		if err := win.Window.Validate(); err != nil {
+			return err
+		}
+		if win.WindowRef != "" {
+			if !win.Window.IsEmpty() {
+				return fmt.Errorf("window function cannot use both named window '%s' and an inline window", win.WindowRef)
+			}
+			if _, ok := ast.Windows[win.WindowRef]; !ok {
+				return fmt.Errorf("undefined window '%s'", win.WindowRef)
+			}
+		}
+		if win.Function.RequiresOrderBy() {
+			spec := win.Window
+			if win.WindowRef != "" {
+				spec = ast.Windows[win.WindowRef]
+			}
+			if len(spec.OrderBy) == 0 {
+				return fmt.Errorf("%s requires ORDER BY in its window", win.Function)
+			}
+		}
+	}
+	if len(ast.Windows) > 0 && ast.Operation != OpSelect {
+		return fmt.Errorf("named windows can only be used with SELECT queries")
+	}
+	for name, spec := range ast.Windows {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("window '%s': %w", name, err)
 		}
 	}
 	if windowCount > MaxWindowFunctions {
 		return fmt.Errorf("too many window functions: %d (max %d)", windowCount, MaxWindowFunctions)
 	}
 
+	for i := range ast.Joins {
+		if err := ast.Joins[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := ast.validateLockOf(); err != nil {
+		return err
+	}
+	if ast.SkipLocked && ast.Lock == nil {
+		return fmt.Errorf("SKIP LOCKED requires a row lock mode")
+	}
+	if err := ast.Limit.validate("LIMIT"); err != nil {
+		return err
+	}
+	if err := ast.Offset.validate("OFFSET"); err != nil {
+		return err
+	}
+	if ast.ReturningCount {
+		if ast.Operation != OpUpdate && ast.Operation != OpDelete {
+			return fmt.Errorf("RETURNING count can only be used with UPDATE or DELETE queries")
+		}
+		if len(ast.Returning) > 0 {
+			return fmt.Errorf("RETURNING count cannot be combined with RETURNING fields")
+		}
+	}
+	if ast.WithTies {
+		switch {
+		case ast.Operation != OpSelect:
+			return fmt.Errorf("WITH TIES can only be used with SELECT queries")
+		case ast.Limit == nil:
+			return fmt.Errorf("WITH TIES requires LIMIT")
+		case len(ast.Ordering) == 0:
+			return fmt.Errorf("WITH TIES requires ORDER BY")
+		}
+	}
+
 	// Validate condition depth
 	if ast.WhereClause != nil {
 		if err := validateConditionDepth(ast.WhereClause, 0); err != nil {
@@ -440,19 +1770,28 @@ func (ast *AST) Validate() error {
 		if len(ast.Values) == 0 {
 			return fmt.Errorf("INSERT requires at least one value set")
 		}
+		if err := ast.validateValueSubqueries(); err != nil {
+			return err
+		}
+		if err := ast.validateValueArrays(); err != nil {
+			return err
+		}
+		for _, valueSet := range ast.Values {
+			if err := validateLiterals(valueSet); err != nil {
+				return err
+			}
+		}
 		// Ensure all value sets have the same fields
 		if len(ast.Values) > 1 {
-			firstKeys := make(map[Field]bool)
-			for k := range ast.Values[0] {
-				firstKeys[k] = true
-			}
-			for i, valueSet := range ast.Values[1:] {
+			firstKeys := ast.valueSetFields(0)
+			for i := 1; i < len(ast.Values); i++ {
+				valueSet := ast.valueSetFields(i)
 				if len(valueSet) != len(firstKeys) {
-					return fmt.Errorf("value set %d has different number of fields", i+1)
+					return fmt.Errorf("value set %d has different number of fields", i)
 				}
 				for k := range valueSet {
 					if !firstKeys[k] {
-						return fmt.Errorf("value set %d has different fields", i+1)
+						return fmt.Errorf("value set %d has different fields", i)
 					}
 				}
 			}
@@ -461,10 +1800,34 @@ func (ast *AST) Validate() error {
 		if ast.OnConflict != nil && len(ast.OnConflict.Columns) == 0 {
 			return fmt.Errorf("ON CONFLICT requires at least one column")
 		}
+		if ast.OnConflict != nil && ast.OnConflict.DoUpdateWhere != nil && ast.OnConflict.Action != DoUpdate {
+			return fmt.Errorf("ON CONFLICT WHERE on the action requires DO UPDATE")
+		}
+		if ast.OnConflict != nil {
+			for field, param := range ast.OnConflict.Updates {
+				if param.IsExcluded() && (param.Name != "" || param.IsLiteral()) {
+					return fmt.Errorf("ON CONFLICT value for %s cannot be both an EXCLUDED reference and a parameter", field.Name)
+				}
+			}
+			for _, cond := range []ConditionItem{ast.OnConflict.ConflictPredicate, ast.OnConflict.DoUpdateWhere} {
+				if cond == nil {
+					continue
+				}
+				if err := validateConditionDepth(cond, 0); err != nil {
+					return err
+				}
+			}
+		}
 	case OpUpdate:
-		if len(ast.Updates) == 0 && len(ast.UpdateExpressions) == 0 {
+		if len(ast.Updates) == 0 && len(ast.UpdateExpressions) == 0 && len(ast.UpdateRows) == 0 {
 			return fmt.Errorf("UPDATE requires at least one field to update")
 		}
+		if err := ast.validateUpdateRows(); err != nil {
+			return err
+		}
+		if err := validateLiterals(ast.Updates); err != nil {
+			return err
+		}
 		// Detect duplicate fields across Updates and UpdateExpressions
 		if len(ast.Updates) > 0 && len(ast.UpdateExpressions) > 0 {
 			var duplicates []string
@@ -479,23 +1842,32 @@ func (ast *AST) Validate() error {
 			}
 		}
 		// UPDATE can have RETURNING but not SELECT features
-		if ast.Distinct || len(ast.Joins) > 0 || len(ast.GroupBy) > 0 {
+		if ast.Distinct || len(ast.Joins) > 0 || ast.HasGroupBy() {
 			return fmt.Errorf("UPDATE cannot have SELECT features like DISTINCT, JOIN, or GROUP BY")
 		}
 	case OpDelete:
 		// DELETE can have RETURNING but not SELECT features
-		if ast.Distinct || len(ast.Joins) > 0 || len(ast.GroupBy) > 0 {
+		if ast.Distinct || len(ast.Joins) > 0 || ast.HasGroupBy() {
 			return fmt.Errorf("DELETE cannot have SELECT features like DISTINCT, JOIN, or GROUP BY")
 		}
 	case OpCount:
 		// COUNT can have JOINs and WHERE but no fields
 		// COUNT can have JOINs
+	case OpTruncate:
+		if ast.WhereClause != nil || len(ast.Joins) > 0 || len(ast.Fields) > 0 || len(ast.FieldExpressions) > 0 ||
+			len(ast.Returning) > 0 || len(ast.Ordering) > 0 || ast.Limit != nil || ast.Offset != nil || ast.Lock != nil {
+			return fmt.Errorf("TRUNCATE cannot have WHERE, JOIN, RETURNING, or SELECT features; use DELETE to remove selected rows")
+		}
 	default:
 		return fmt.Errorf("unsupported operation: %s", ast.Operation)
 	}
 
+	if err := ast.validateGrouping(); err != nil {
+		return err
+	}
+
 	// HAVING requires GROUP BY
-	if len(ast.Having) > 0 && len(ast.GroupBy) == 0 {
+	if len(ast.Having) > 0 && !ast.HasGroupBy() {
 		return fmt.Errorf("HAVING requires GROUP BY")
 	}
 
@@ -507,6 +1879,102 @@ func (ast *AST) Validate() error {
 	return nil
 }
 
+// validate checks the join type and that the join has exactly the clause
+// its type calls for.
+func (j *Join) validate() error {
+	if err := j.Type.Validate(); err != nil {
+		return err
+	}
+	if j.Subquery != nil {
+		if j.Subquery.AST == nil {
+			return fmt.Errorf("derived table join requires a subquery")
+		}
+		if j.Table.Alias == "" {
+			return fmt.Errorf("derived table join requires an alias")
+		}
+	} else {
+		if j.Lateral {
+			return fmt.Errorf("LATERAL requires a derived table")
+		}
+		if len(j.Columns) > 0 {
+			return fmt.Errorf("column alias list requires a derived table")
+		}
+	}
+
+	hasOn := j.On != nil
+	hasUsing := len(j.Using) > 0
+	switch {
+	case hasOn && hasUsing:
+		return fmt.Errorf("%s cannot have both ON and USING clauses", j.Type)
+	case !j.Type.RequiresOn() && hasOn:
+		return fmt.Errorf("%s cannot have ON clause", j.Type)
+	case !j.Type.RequiresOn() && hasUsing:
+		return fmt.Errorf("%s cannot have USING clause", j.Type)
+	case j.Type.RequiresOn() && !hasOn && !hasUsing:
+		return fmt.Errorf("%s requires ON or USING clause", j.Type)
+	}
+	return nil
+}
+
+// HasGroupBy reports whether the AST has a GROUP BY clause in any form.
+func (ast *AST) HasGroupBy() bool {
+	return len(ast.GroupByFields()) > 0 || len(ast.GroupingSets) > 0 || len(ast.CoalescedGroupBy) > 0
+}
+
+// GroupByFields returns the plain GROUP BY fields. With GroupByAll set, every
+// projected Field not already listed is appended in select-list order.
+// Field expressions (aggregates, window functions, CASE, ...) are never added.
+func (ast *AST) GroupByFields() []Field {
+	if !ast.GroupByAll {
+		return ast.GroupBy
+	}
+	fields := append([]Field(nil), ast.GroupBy...)
+	for _, field := range ast.Fields {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// validateGrouping checks that the GROUP BY mode matches the populated fields.
+func (ast *AST) validateGrouping() error {
+	for _, group := range ast.CoalescedGroupBy {
+		if group.Sentinel.Name == "" {
+			return fmt.Errorf("coalesced GROUP BY on %s requires a sentinel parameter", group.Field.Name)
+		}
+	}
+	if ast.GroupByAll && ast.GroupByMode != GroupPlain {
+		return fmt.Errorf("GroupByAll cannot be combined with GROUP BY %s", ast.GroupByMode)
+	}
+	if len(ast.CoalescedGroupBy) > 0 && ast.GroupByMode != GroupPlain {
+		return fmt.Errorf("coalesced GROUP BY fields cannot be used with GROUP BY %s", ast.GroupByMode)
+	}
+	switch ast.GroupByMode {
+	case GroupPlain:
+		if len(ast.GroupingSets) > 0 {
+			return fmt.Errorf("grouping sets require GROUP BY GROUPING SETS mode")
+		}
+	case GroupRollup, GroupCube:
+		if len(ast.GroupBy) == 0 {
+			return fmt.Errorf("GROUP BY %s requires at least one field", ast.GroupByMode)
+		}
+		if len(ast.GroupingSets) > 0 {
+			return fmt.Errorf("grouping sets cannot be used with GROUP BY %s", ast.GroupByMode)
+		}
+	case GroupSets:
+		if len(ast.GroupingSets) == 0 {
+			return fmt.Errorf("GROUP BY GROUPING SETS requires at least one grouping set")
+		}
+		if len(ast.GroupBy) > 0 {
+			return fmt.Errorf("GROUP BY GROUPING SETS cannot be combined with plain GROUP BY fields")
+		}
+	default:
+		return fmt.Errorf("unsupported GROUP BY mode: %s", ast.GroupByMode)
+	}
+	return nil
+}
+
 // validateConditionDepth checks the nesting depth of condition groups.
 func validateConditionDepth(cond ConditionItem, depth int) error {
 	if depth > MaxConditionDepth {
@@ -520,7 +1988,7 @@ func validateConditionDepth(cond ConditionItem, depth int) error {
 				return err
 			}
 		}
-	case Condition, FieldComparison, SubqueryCondition, AggregateCondition, BetweenCondition:
+	case Condition, FieldComparison, Comparison, SubqueryCondition, TupleCondition, InListCondition, AggregateCondition, BetweenCondition:
 		// Leaf nodes, no further depth
 	}
 
@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 // Condition represents a simple condition.
 // Values are always parameters, never literals.
 // This is exported from the internal package so providers can use it,
@@ -23,6 +25,16 @@ const (
 	OR  LogicOperator = "OR"
 )
 
+// Validate checks that the logic operator is one of the known values.
+func (op LogicOperator) Validate() error {
+	switch op {
+	case AND, OR:
+		return nil
+	default:
+		return fmt.Errorf("unknown logic operator %q", string(op))
+	}
+}
+
 // ConditionGroup represents grouped conditions with AND/OR logic.
 type ConditionGroup struct {
 	Logic      LogicOperator
@@ -44,6 +56,10 @@ type AggregateCondition struct {
 	Field    *Field        // nil for COUNT(*), otherwise the field to aggregate
 	Operator Operator
 	Value    Param
+	// Alias names the select-list expression this aggregate corresponds to.
+	// Dialects that allow aliases in HAVING (MariaDB) render the alias;
+	// the others re-expand the aggregate.
+	Alias string
 }
 
 // Example: field BETWEEN :low AND :high.
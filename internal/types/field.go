@@ -1,5 +1,11 @@
 package types
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // Field represents a validated field reference.
 // This is exported from the internal package so providers can use it,
 // but external users cannot import this package.
@@ -8,6 +14,88 @@ type Field struct {
 	Table        string // Optional table/alias prefix
 	JSONBTextKey *Param // Param for JSONB text extraction (->>), renders as field->>:param
 	JSONBPathKey *Param // Param for JSONB path access (->), renders as field->:param
+	JSONKey      string // Validated literal key for text extraction, renders as field->>'key'
+	JSONPath     string // Validated comma-separated literal path, renders as field#>>'{a,b}'
+}
+
+// HasJSONAccess reports whether the field extracts from a JSON/JSONB value.
+func (f Field) HasJSONAccess() bool {
+	return f.JSONBTextKey != nil || f.JSONBPathKey != nil || f.JSONKey != "" || f.JSONPath != ""
+}
+
+// Validate checks that the literal JSON key and every element of the literal
+// JSON path are safe to render inside a string literal.
+func (f Field) Validate() error {
+	if f.JSONKey != "" && !IsJSONKey(f.JSONKey) {
+		return fmt.Errorf("invalid JSON key '%s': must be alphanumeric/underscore", f.JSONKey)
+	}
+	if f.JSONPath != "" {
+		for _, key := range strings.Split(f.JSONPath, ",") {
+			if !IsJSONKey(key) {
+				return fmt.Errorf("invalid JSON path key '%s': must be alphanumeric/underscore", key)
+			}
+		}
+	}
+	return nil
+}
+
+// IsJSONKey reports whether s is safe to render inside a string literal as a
+// JSON key or path element. Digits may lead so that array indexes can be used
+// in paths.
+func IsJSONKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if !((ch >= 'a' && ch <= 'z') || //nolint:staticcheck // readability over De Morgan
+			(ch >= 'A' && ch <= 'Z') ||
+			(ch >= '0' && ch <= '9') ||
+			ch == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+var fieldType = reflect.TypeOf(Field{})
+
+// validateFields runs Field.Validate on every field in the tree under v, so
+// literal JSON keys are checked however the AST was built.
+func validateFields(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return validateFields(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateFields(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := validateFields(iter.Key()); err != nil {
+				return err
+			}
+			if err := validateFields(iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == fieldType {
+			return v.Interface().(Field).Validate()
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if err := validateFields(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // TableValidator is a function that validates table names and aliases.
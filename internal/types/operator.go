@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 // Operator represents query comparison operators.
 type Operator string
 
@@ -24,6 +26,10 @@ const (
 	EXISTS    Operator = "EXISTS"
 	NotExists Operator = "NOT EXISTS"
 
+	// NULL-safe comparison operators: NULL compares equal to NULL.
+	IsDistinctFrom    Operator = "IS DISTINCT FROM"
+	IsNotDistinctFrom Operator = "IS NOT DISTINCT FROM"
+
 	// Regex operators (PostgreSQL).
 	RegexMatch     Operator = "~"
 	RegexIMatch    Operator = "~*"
@@ -41,3 +47,25 @@ const (
 	VectorCosineDistance Operator = "<=>" // Cosine distance
 	VectorL1Distance     Operator = "<+>" // L1/Manhattan distance
 )
+
+// Validate checks that the operator is one of the known values.
+func (op Operator) Validate() error {
+	switch op {
+	case EQ, NE, GT, GE, LT, LE, IN, NotIn, LIKE, NotLike, ILIKE, NotILike, IsNull, IsNotNull,
+		EXISTS, NotExists, IsDistinctFrom, IsNotDistinctFrom, RegexMatch, RegexIMatch,
+		NotRegexMatch, NotRegexIMatch, ArrayContains, ArrayContainedBy, ArrayOverlap,
+		VectorL2Distance, VectorInnerProduct, VectorCosineDistance, VectorL1Distance:
+		return nil
+	default:
+		return fmt.Errorf("unknown operator %q", string(op))
+	}
+}
+
+// IsComparison reports whether the operator is a basic scalar comparison (=, !=, >, >=, <, <=).
+func (op Operator) IsComparison() bool {
+	switch op {
+	case EQ, NE, GT, GE, LT, LE:
+		return true
+	}
+	return false
+}
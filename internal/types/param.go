@@ -1,11 +1,63 @@
 package types
 
+import "fmt"
+
 // Param represents a parameter reference in a query.
-// All parameters are named parameters.
+// All parameters are named parameters, except literal values (Literal set,
+// Name empty), which each dialect renders inline instead of a placeholder.
 // This is exported from the internal package so providers can use it,
 // but external users cannot import this package.
 type Param struct {
-	Name string
+	Excluded *ExcludedRef
+	Name     string
+	Literal  Literal
+	// Cast is an optional type hint for the placeholder. PostgreSQL renders
+	// it as :name::type; other dialects infer parameter types and ignore it.
+	Cast CastType
+}
+
+// ExcludedRef refers to the value a conflicting INSERT proposed for a
+// column, EXCLUDED.col in PostgreSQL and SQLite or VALUES(col) in MariaDB.
+// It is valid only as an ON CONFLICT DO UPDATE assignment.
+type ExcludedRef struct {
+	Field Field
+}
+
+// Literal is a fixed value rendered inline with dialect-appropriate syntax.
+// Only a closed set of values exists, so no user input reaches the SQL.
+// LiteralDefault is the DEFAULT keyword, valid only as an INSERT or UPDATE value.
+type Literal string
+
+const (
+	LiteralTrue    Literal = "TRUE"
+	LiteralFalse   Literal = "FALSE"
+	LiteralOne     Literal = "1"
+	LiteralDefault Literal = "DEFAULT"
+)
+
+// Validate checks that the literal is one of the known values.
+func (l Literal) Validate() error {
+	switch l {
+	case LiteralTrue, LiteralFalse, LiteralOne, LiteralDefault:
+		return nil
+	default:
+		return fmt.Errorf("unknown literal %q", string(l))
+	}
+}
+
+// IsLiteral reports whether the param is a literal value rather than a placeholder.
+func (p Param) IsLiteral() bool {
+	return p.Literal != ""
+}
+
+// IsDefault reports whether the param is the DEFAULT keyword.
+func (p Param) IsDefault() bool {
+	return p.Literal == LiteralDefault
+}
+
+// IsExcluded reports whether the param references the proposed insert value.
+func (p Param) IsExcluded() bool {
+	return p.Excluded != nil
 }
 
 // GetName returns the parameter name.
@@ -0,0 +1,67 @@
+package types
+
+import "strconv"
+
+// PlaceholderStyle selects how parameters are written into rendered SQL.
+type PlaceholderStyle int
+
+const (
+	PlaceholderColon    PlaceholderStyle = iota // :name, for sqlx named queries (default)
+	PlaceholderQuestion                         // ?, one per occurrence (database/sql with MySQL, SQLite)
+	PlaceholderDollar                           // $1, $2, ... reused for repeated names (pq, pgx)
+	PlaceholderAtP                              // @p1, @p2, ... reused for repeated names (SQL Server drivers)
+)
+
+// Params records the parameters referenced during a render and emits their
+// placeholders in the configured style.
+type Params struct {
+	style      PlaceholderStyle
+	names      []string
+	index      map[string]int
+	positional []string
+}
+
+// NewParams creates an empty parameter tracker for the given style.
+func NewParams(style PlaceholderStyle) *Params {
+	return &Params{style: style, index: make(map[string]int)}
+}
+
+// Placeholder records a use of the named parameter and returns its placeholder.
+// Placeholders must be requested in the order they appear in the SQL for the
+// question style, which binds by position.
+func (p *Params) Placeholder(name string) string {
+	n, seen := p.index[name]
+	if !seen {
+		p.names = append(p.names, name)
+		n = len(p.names)
+		p.index[name] = n
+	}
+
+	switch p.style {
+	case PlaceholderQuestion:
+		p.positional = append(p.positional, name)
+		return "?"
+	case PlaceholderDollar, PlaceholderAtP:
+		if !seen {
+			p.positional = append(p.positional, name)
+		}
+		if p.style == PlaceholderAtP {
+			return "@p" + strconv.Itoa(n)
+		}
+		return "$" + strconv.Itoa(n)
+	default:
+		return ":" + name
+	}
+}
+
+// Names returns the unique parameter names in order of first use.
+func (p *Params) Names() []string {
+	return p.names
+}
+
+// Positional returns the parameter names in bind order for positional styles:
+// one entry per placeholder for the question style, one per index for the
+// dollar and @p styles. It returns nil for the colon style.
+func (p *Params) Positional() []string {
+	return p.positional
+}
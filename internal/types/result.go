@@ -1,7 +1,124 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 // QueryResult contains the rendered SQL and required parameters.
 type QueryResult struct {
 	SQL            string
 	RequiredParams []string
+	// PositionalParams lists parameter names in bind order when the renderer
+	// uses a positional placeholder style ($N or ?); nil for named placeholders.
+	PositionalParams []string
+	// Operation is the statement kind that was rendered (OpSelect for compound queries).
+	Operation Operation
+	// Tables lists the table names the query reads or writes, target first,
+	// including joined tables and tables referenced by subqueries. Each name appears once.
+	Tables []string
+}
+
+// BindOrdered returns the argument values in bind order for positional
+// placeholders, looking each parameter up by name. A parameter used by several
+// ? placeholders appears once per use. For named placeholders the values follow
+// RequiredParams. It returns an error listing every missing name.
+func (r *QueryResult) BindOrdered(values map[string]any) ([]any, error) {
+	names := r.PositionalParams
+	if names == nil {
+		names = r.RequiredParams
+	}
+
+	args := make([]any, 0, len(names))
+	var missing []string
+	reported := make(map[string]bool)
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			if !reported[name] {
+				missing = append(missing, name)
+				reported[name] = true
+			}
+			continue
+		}
+		args = append(args, value)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing values for parameters: %s", strings.Join(missing, ", "))
+	}
+	return args, nil
+}
+
+// Rebind rewrites the named :param placeholders of SQL into the given style and
+// returns the parameter names in bind order. Positional styles number a repeated
+// name once ($N, @pN) or repeat it per occurrence (?). Quoted identifiers and
+// string literals are left untouched. A result that was already rendered with a
+// positional style no longer carries the names in its SQL, so it returns an error;
+// render with named placeholders to rebind.
+func (r *QueryResult) Rebind(style PlaceholderStyle) (string, []string, error) {
+	if r.PositionalParams != nil {
+		return "", nil, fmt.Errorf("cannot rebind SQL rendered with positional placeholders; render with named placeholders")
+	}
+	if style == PlaceholderColon {
+		return r.SQL, r.RequiredParams, nil
+	}
+
+	params := NewParams(style)
+	sql := r.SQL
+	var out strings.Builder
+	out.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == '"' || ch == '`' || ch == '\'' || ch == '[':
+			closer := ch
+			if ch == '[' {
+				closer = ']'
+			}
+			end := strings.IndexByte(sql[i+1:], closer)
+			if end < 0 {
+				out.WriteString(sql[i:])
+				i = len(sql)
+				continue
+			}
+			out.WriteString(sql[i : i+end+2])
+			i += end + 2
+		case ch == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			// PostgreSQL cast, not a placeholder
+			out.WriteString("::")
+			i += 2
+		case ch == ':' && i+1 < len(sql) && isParamByte(sql[i+1]):
+			j := i + 1
+			for j < len(sql) && isParamByte(sql[j]) {
+				j++
+			}
+			out.WriteString(params.Placeholder(sql[i+1 : j]))
+			i = j
+		default:
+			out.WriteByte(ch)
+			i++
+		}
+	}
+	return out.String(), params.Positional(), nil
+}
+
+// ForPgx returns the SQL with $N placeholders and the parameter names in
+// argument order, as expected by pgx and lib/pq. A repeated parameter reuses
+// its number and appears once in the names. Like Rebind, it requires a result
+// rendered with named placeholders.
+func (r *QueryResult) ForPgx() (string, []string, error) {
+	return r.Rebind(PlaceholderDollar)
+}
+
+// ForMySQL returns the SQL with ? placeholders and the parameter names in
+// argument order, as expected by go-sql-driver/mysql and SQLite drivers. A
+// repeated parameter appears once per occurrence in the names. Like Rebind, it
+// requires a result rendered with named placeholders.
+func (r *QueryResult) ForMySQL() (string, []string, error) {
+	return r.Rebind(PlaceholderQuestion)
+}
+
+func isParamByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
 }
@@ -1,11 +1,49 @@
 package types
 
+import "fmt"
+
 // Table represents a validated table reference.
 // This is exported from the internal package so providers can use it,
 // but external users cannot import this package.
 type Table struct {
-	Name  string
-	Alias string
+	Values *ValuesTable // Inline VALUES rows in place of a named table
+	Name   string
+	Alias  string
+}
+
+// ValuesTable is an inline list of rows used as a FROM or JOIN source:
+// (VALUES (:a, :b), (:c, :d)) AS alias("col1", "col2"). The alias lives on
+// the enclosing Table.
+type ValuesTable struct {
+	Columns []string
+	Rows    [][]Param
+}
+
+// Validate checks that the table has columns and rows, that every row has
+// one value per column, and that every literal is known.
+func (v ValuesTable) Validate() error {
+	if len(v.Columns) == 0 {
+		return fmt.Errorf("VALUES table requires at least one column")
+	}
+	if len(v.Rows) == 0 {
+		return fmt.Errorf("VALUES table requires at least one row")
+	}
+	for i, row := range v.Rows {
+		if len(row) != len(v.Columns) {
+			return fmt.Errorf("VALUES table row %d has %d values, want %d", i, len(row), len(v.Columns))
+		}
+		for _, value := range row {
+			if value.IsExcluded() || value.IsDefault() {
+				return fmt.Errorf("VALUES table row %d: values must be parameters or TRUE, FALSE, or 1", i)
+			}
+			if value.IsLiteral() {
+				if err := value.Literal.Validate(); err != nil {
+					return fmt.Errorf("VALUES table row %d: %w", i, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // GetName returns the table name.
@@ -17,3 +55,11 @@ func (t Table) GetName() string {
 func (t Table) GetAlias() string {
 	return t.Alias
 }
+
+// validateValues checks a VALUES table source and its required alias.
+func (t Table) validateValues() error {
+	if t.Alias == "" {
+		return fmt.Errorf("VALUES table requires an alias")
+	}
+	return t.Values.Validate()
+}
@@ -2,6 +2,9 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -192,6 +195,70 @@ func TestSubqueryCondition_IsConditionItem(_ *testing.T) {
 	item.IsConditionItem() // Should not panic
 }
 
+func TestTupleCondition_IsConditionItem(_ *testing.T) {
+	var item ConditionItem = TupleCondition{
+		Fields:   []Field{{Name: "a"}, {Name: "b"}},
+		Operator: EQ,
+		Values:   []Param{{Name: "a"}, {Name: "b"}},
+	}
+	item.IsConditionItem() // Should not panic
+}
+
+func TestTupleCondition_Validate(t *testing.T) {
+	sub := &Subquery{AST: &AST{Operation: OpSelect, Target: Table{Name: "users"}}}
+	fields := []Field{{Name: "a"}, {Name: "b"}}
+
+	tests := []struct {
+		name    string
+		cond    TupleCondition
+		wantErr bool
+	}{
+		{"values", TupleCondition{Fields: fields, Operator: EQ, Values: []Param{{Name: "a"}, {Name: "b"}}}, false},
+		{"subquery", TupleCondition{Fields: fields, Operator: IN, Subquery: sub}, false},
+		{"single field", TupleCondition{Fields: fields[:1], Operator: EQ, Values: []Param{{Name: "a"}}}, true},
+		{"arity mismatch", TupleCondition{Fields: fields, Operator: EQ, Values: []Param{{Name: "a"}}}, true},
+		{"both", TupleCondition{Fields: fields, Operator: EQ, Values: []Param{{Name: "a"}, {Name: "b"}}, Subquery: sub}, true},
+		{"neither", TupleCondition{Fields: fields, Operator: EQ}, true},
+		{"IN with values", TupleCondition{Fields: fields, Operator: IN, Values: []Param{{Name: "a"}, {Name: "b"}}}, true},
+		{"LIKE with subquery", TupleCondition{Fields: fields, Operator: LIKE, Subquery: sub}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cond.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTupleCondition_Expand(t *testing.T) {
+	fields := []Field{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	values := []Param{{Name: "pa"}, {Name: "pb"}, {Name: "pc"}}
+	col := func(i int, op Operator) Condition {
+		return Condition{Field: fields[i], Operator: op, Value: values[i]}
+	}
+
+	expanded, ok := TupleCondition{Fields: fields, Operator: GE, Values: values}.Expand()
+	if !ok {
+		t.Fatal("Expand() ok = false, want true")
+	}
+	want := ConditionGroup{Logic: OR, Conditions: []ConditionItem{
+		col(0, GT),
+		ConditionGroup{Logic: AND, Conditions: []ConditionItem{col(0, EQ), col(1, GT)}},
+		ConditionGroup{Logic: AND, Conditions: []ConditionItem{col(0, EQ), col(1, EQ), col(2, GE)}},
+	}}
+	if !reflect.DeepEqual(expanded, want) {
+		t.Errorf("Expand() = %+v, want %+v", expanded, want)
+	}
+
+	sub := &Subquery{AST: &AST{Operation: OpSelect, Target: Table{Name: "users"}}}
+	if _, ok := (TupleCondition{Fields: fields[:2], Operator: IN, Subquery: sub}).Expand(); ok {
+		t.Error("Expand() ok = true for a subquery tuple, want false")
+	}
+}
+
 // =============================================================================
 // AST.Validate() Tests
 // =============================================================================
@@ -377,6 +444,41 @@ func TestAST_Validate_Update_Valid(t *testing.T) {
 	}
 }
 
+func TestAST_Validate_Update_Rows(t *testing.T) {
+	sub := &AST{
+		Operation: OpSelect,
+		Target:    Table{Name: "plans"},
+		Fields:    []Field{{Name: "name"}, {Name: "seats"}},
+	}
+	tests := []struct {
+		name    string
+		row     RowUpdate
+		updates map[Field]Param
+		wantErr bool
+	}{
+		{"valid", RowUpdate{Fields: []Field{{Name: "plan"}, {Name: "seats"}}, Subquery: Subquery{AST: sub}}, nil, false},
+		{"no fields", RowUpdate{Subquery: Subquery{AST: sub}}, nil, true},
+		{"arity mismatch", RowUpdate{Fields: []Field{{Name: "plan"}}, Subquery: Subquery{AST: sub}}, nil, true},
+		{"not a select", RowUpdate{Fields: []Field{{Name: "plan"}}, Subquery: Subquery{AST: &AST{Operation: OpDelete, Target: Table{Name: "plans"}}}}, nil, true},
+		{"assigned twice", RowUpdate{Fields: []Field{{Name: "plan"}, {Name: "seats"}}, Subquery: Subquery{AST: sub}}, map[Field]Param{{Name: "seats"}: {Name: "seats"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &AST{
+				Operation:  OpUpdate,
+				Target:     Table{Name: "accounts"},
+				Updates:    tt.updates,
+				UpdateRows: []RowUpdate{tt.row},
+			}
+			err := ast.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAST_Validate_Update_WithSelectFeatures(t *testing.T) {
 	ast := &AST{
 		Operation: OpUpdate,
@@ -451,6 +553,48 @@ func TestAST_Validate_HavingWithoutGroupBy(t *testing.T) {
 	}
 }
 
+func TestJoinType_RequiresOn(t *testing.T) {
+	for _, jt := range []JoinType{InnerJoin, LeftJoin, RightJoin, FullJoin} {
+		if !jt.RequiresOn() {
+			t.Errorf("%s.RequiresOn() = false, want true", jt)
+		}
+	}
+	for _, jt := range []JoinType{CrossJoin, NaturalJoin} {
+		if jt.RequiresOn() {
+			t.Errorf("%s.RequiresOn() = true, want false", jt)
+		}
+	}
+}
+
+func TestAST_Validate_JoinDerivedTable(t *testing.T) {
+	sub := &Subquery{AST: &AST{Operation: OpSelect, Target: Table{Name: "orders"}}}
+	tests := []struct {
+		name    string
+		join    Join
+		wantErr bool
+	}{
+		{"lateral derived table", Join{Type: CrossJoin, Table: Table{Alias: "x"}, Subquery: sub, Lateral: true, Columns: []string{"a"}}, false},
+		{"missing alias", Join{Type: CrossJoin, Subquery: sub}, true},
+		{"lateral without subquery", Join{Type: CrossJoin, Table: Table{Name: "orders"}, Lateral: true}, true},
+		{"columns without subquery", Join{Type: CrossJoin, Table: Table{Name: "orders"}, Columns: []string{"a"}}, true},
+		{"cross join", Join{Type: CrossJoin, Table: Table{Name: "orders"}}, false},
+		{"cross join with on", Join{Type: CrossJoin, Table: Table{Name: "orders"}, On: Condition{}}, true},
+		{"cross join with using", Join{Type: CrossJoin, Table: Table{Name: "orders"}, Using: []Field{{Name: "id"}}}, true},
+		{"on and using", Join{Type: InnerJoin, Table: Table{Name: "orders"}, On: Condition{}, Using: []Field{{Name: "id"}}}, true},
+		{"neither on nor using", Join{Type: InnerJoin, Table: Table{Name: "orders"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &AST{Operation: OpSelect, Target: Table{Name: "users"}, Joins: []Join{tt.join}}
+			err := ast.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAST_Validate_DistinctAndDistinctOn(t *testing.T) {
 	ast := &AST{
 		Operation:  OpSelect,
@@ -545,3 +689,412 @@ func buildNestedConditionGroup(depth int) ConditionItem {
 		Conditions: []ConditionItem{buildNestedConditionGroup(depth - 1)},
 	}
 }
+
+func TestAST_Validate_LockOf(t *testing.T) {
+	lock := LockForUpdate
+	base := func(lockOf ...Table) *AST {
+		return &AST{
+			Operation: OpSelect,
+			Target:    Table{Name: "users", Alias: "u"},
+			Joins: []Join{{
+				Type:  InnerJoin,
+				Table: Table{Name: "orders", Alias: "o"},
+				On:    FieldComparison{LeftField: Field{Name: "id", Table: "u"}, Operator: EQ, RightField: Field{Name: "user_id", Table: "o"}},
+			}},
+			Lock:   &lock,
+			LockOf: lockOf,
+		}
+	}
+
+	if err := base(Table{Name: "orders", Alias: "o"}).Validate(); err != nil {
+		t.Errorf("Expected joined alias to be valid, got %v", err)
+	}
+	if err := base(Table{Name: "payments", Alias: "p"}).Validate(); err == nil {
+		t.Error("Expected error for table not in FROM or JOIN")
+	}
+	// Aliased tables must be locked by alias.
+	if err := base(Table{Name: "users"}).Validate(); err == nil {
+		t.Error("Expected error for aliased table referenced by name")
+	}
+
+	ast := base(Table{Name: "orders", Alias: "o"})
+	ast.Lock = nil
+	if err := ast.Validate(); err == nil {
+		t.Error("Expected error for OF tables without a lock mode")
+	}
+}
+
+func TestAST_Validate_WindowRef(t *testing.T) {
+	build := func(expr WindowExpression, windows map[string]WindowSpec) *AST {
+		return &AST{
+			Operation:        OpSelect,
+			Target:           Table{Name: "orders"},
+			FieldExpressions: []FieldExpression{{Window: &expr, Alias: "rn"}},
+			Windows:          windows,
+		}
+	}
+	windows := map[string]WindowSpec{"w": {
+		PartitionBy: []Field{{Name: "user_id"}},
+		OrderBy:     []OrderBy{{Field: Field{Name: "id"}, Direction: ASC}},
+	}}
+
+	if err := build(WindowExpression{Function: WinRowNumber, WindowRef: "w"}, windows).Validate(); err != nil {
+		t.Errorf("Expected valid named window reference, got %v", err)
+	}
+	if err := build(WindowExpression{Function: WinRowNumber, WindowRef: "missing"}, windows).Validate(); err == nil {
+		t.Error("Expected error for undefined window")
+	}
+	both := WindowExpression{
+		Function:  WinRowNumber,
+		WindowRef: "w",
+		Window:    WindowSpec{OrderBy: []OrderBy{{Field: Field{Name: "id"}, Direction: ASC}}},
+	}
+	if err := build(both, windows).Validate(); err == nil {
+		t.Error("Expected error for named and inline window together")
+	}
+}
+
+func TestAST_Validate_ValueSubqueries(t *testing.T) {
+	sub := Subquery{AST: &AST{Operation: OpSelect, Target: Table{Name: "t"}, Fields: []Field{{Name: "n"}}}}
+	build := func(cells map[Field]Subquery) *AST {
+		return &AST{
+			Operation:       OpInsert,
+			Target:          Table{Name: "t"},
+			Values:          []map[Field]Param{{{Name: "a"}: {Name: "a"}}},
+			ValueSubqueries: []map[Field]Subquery{cells},
+		}
+	}
+
+	if err := build(map[Field]Subquery{{Name: "b"}: sub}).Validate(); err != nil {
+		t.Errorf("Expected valid subquery cell, got %v", err)
+	}
+	if err := build(map[Field]Subquery{{Name: "a"}: sub}).Validate(); err == nil {
+		t.Error("Expected error for a column with both a param and a subquery")
+	}
+	wide := Subquery{AST: &AST{Operation: OpSelect, Target: Table{Name: "t"}, Fields: []Field{{Name: "n"}, {Name: "m"}}}}
+	if err := build(map[Field]Subquery{{Name: "b"}: wide}).Validate(); err == nil {
+		t.Error("Expected error for a multi-column subquery cell")
+	}
+
+	// A second row must supply the subquery column too.
+	ast := build(map[Field]Subquery{{Name: "b"}: sub})
+	ast.Values = append(ast.Values, map[Field]Param{{Name: "a"}: {Name: "a2"}})
+	if err := ast.Validate(); err == nil {
+		t.Error("Expected error for value sets with different fields")
+	}
+}
+
+func TestAST_Validate_Truncate(t *testing.T) {
+	if err := (&AST{Operation: OpTruncate, Target: Table{Name: "t"}, Cascade: true}).Validate(); err != nil {
+		t.Errorf("Expected valid TRUNCATE, got %v", err)
+	}
+	withWhere := &AST{
+		Operation:   OpTruncate,
+		Target:      Table{Name: "t"},
+		WhereClause: Condition{Field: Field{Name: "id"}, Operator: EQ, Value: Param{Name: "id"}},
+	}
+	if err := withWhere.Validate(); err == nil {
+		t.Error("Expected error for TRUNCATE with WHERE")
+	}
+	if err := (&AST{Operation: OpDelete, Target: Table{Name: "t"}, RestartIdentity: true}).Validate(); err == nil {
+		t.Error("Expected error for RESTART IDENTITY on DELETE")
+	}
+}
+
+func TestAST_Validate_ValueArrays(t *testing.T) {
+	build := func(cells map[Field]ArrayExpression) *AST {
+		return &AST{
+			Operation:   OpInsert,
+			Target:      Table{Name: "t"},
+			Values:      []map[Field]Param{{{Name: "a"}: {Name: "a"}}},
+			ValueArrays: []map[Field]ArrayExpression{cells},
+		}
+	}
+	tags := ArrayExpression{Values: []Param{{Name: "x"}, {Name: "y"}}}
+
+	if err := build(map[Field]ArrayExpression{{Name: "b"}: tags}).Validate(); err != nil {
+		t.Errorf("Expected valid array cell, got %v", err)
+	}
+	if err := build(map[Field]ArrayExpression{{Name: "a"}: tags}).Validate(); err == nil {
+		t.Error("Expected error for a column with both a param and an array")
+	}
+	if err := build(map[Field]ArrayExpression{{Name: "b"}: {}}).Validate(); err == nil {
+		t.Error("Expected error for an empty array cell")
+	}
+	if err := build(map[Field]ArrayExpression{{Name: "b"}: {Values: []Param{{Literal: "1; DROP TABLE t"}}}}).Validate(); err == nil {
+		t.Error("Expected error for an unknown literal element")
+	}
+}
+
+func TestAST_Validate_Literals(t *testing.T) {
+	build := func(value Param) *AST {
+		return &AST{
+			Operation: OpInsert,
+			Target:    Table{Name: "t"},
+			Values:    []map[Field]Param{{{Name: "a"}: value}},
+		}
+	}
+
+	if err := build(Param{Literal: LiteralTrue}).Validate(); err != nil {
+		t.Errorf("Expected valid boolean literal, got %v", err)
+	}
+	if err := build(Param{Literal: "1; DROP TABLE t"}).Validate(); err == nil {
+		t.Error("Expected error for an unknown literal")
+	}
+	if err := build(Param{Name: "a", Literal: LiteralFalse}).Validate(); err == nil {
+		t.Error("Expected error for a value that is both a parameter and a literal")
+	}
+
+	update := &AST{
+		Operation: OpUpdate,
+		Target:    Table{Name: "t"},
+		Updates:   map[Field]Param{{Name: "a"}: {Literal: "NOW()"}},
+	}
+	if err := update.Validate(); err == nil {
+		t.Error("Expected error for an unknown literal in UPDATE")
+	}
+}
+
+func TestWindowExpression_Validate_NullTreatment(t *testing.T) {
+	valid := WindowExpression{Function: WinLead, NullTreatment: NullsIgnore}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid null treatment, got %v", err)
+	}
+	if err := (WindowExpression{Function: WinLag, NullTreatment: "SKIP NULLS"}).Validate(); err == nil {
+		t.Error("Expected error for an unknown null treatment")
+	}
+	if err := (WindowExpression{Aggregate: AggSum, NullTreatment: NullsRespect}).Validate(); err == nil {
+		t.Error("Expected error for null treatment on an aggregate")
+	}
+}
+
+func TestQueryResult_Rebind(t *testing.T) {
+	result := &QueryResult{
+		SQL:            `SELECT "a:b", 'x :skip' FROM "t" WHERE "x" = :lo OR "y" = :hi OR "z"::text = :lo`,
+		RequiredParams: []string{"lo", "hi"},
+	}
+
+	tests := []struct {
+		style PlaceholderStyle
+		sql   string
+		names string
+	}{
+		{PlaceholderColon, result.SQL, "[lo hi]"},
+		{PlaceholderDollar, `SELECT "a:b", 'x :skip' FROM "t" WHERE "x" = $1 OR "y" = $2 OR "z"::text = $1`, "[lo hi]"},
+		{PlaceholderAtP, `SELECT "a:b", 'x :skip' FROM "t" WHERE "x" = @p1 OR "y" = @p2 OR "z"::text = @p1`, "[lo hi]"},
+		{PlaceholderQuestion, `SELECT "a:b", 'x :skip' FROM "t" WHERE "x" = ? OR "y" = ? OR "z"::text = ?`, "[lo hi lo]"},
+	}
+
+	for _, tt := range tests {
+		sql, names, err := result.Rebind(tt.style)
+		if err != nil {
+			t.Fatalf("style %d: Rebind() error = %v", tt.style, err)
+		}
+		if sql != tt.sql {
+			t.Errorf("style %d: SQL = %q, want %q", tt.style, sql, tt.sql)
+		}
+		if got := fmt.Sprint(names); got != tt.names {
+			t.Errorf("style %d: names = %s, want %s", tt.style, got, tt.names)
+		}
+	}
+}
+
+func TestQueryResult_RebindAlreadyPositional(t *testing.T) {
+	result := &QueryResult{SQL: `SELECT 1 WHERE "x" = $1`, RequiredParams: []string{"x"}, PositionalParams: []string{"x"}}
+
+	for _, style := range []PlaceholderStyle{PlaceholderQuestion, PlaceholderDollar} {
+		if _, _, err := result.Rebind(style); err == nil || !strings.Contains(err.Error(), "positional placeholders") {
+			t.Errorf("style %d: Rebind() error = %v, want positional placeholders error", style, err)
+		}
+	}
+	if _, _, err := result.ForMySQL(); err == nil {
+		t.Error("ForMySQL() on a $N result should fail")
+	}
+}
+
+func TestAST_Validate_RankingRequiresOrderBy(t *testing.T) {
+	build := func(expr WindowExpression, windows map[string]WindowSpec) *AST {
+		return &AST{
+			Operation:        OpSelect,
+			Target:           Table{Name: "orders"},
+			FieldExpressions: []FieldExpression{{Window: &expr, Alias: "w"}},
+			Windows:          windows,
+		}
+	}
+	partitioned := WindowSpec{PartitionBy: []Field{{Name: "user_id"}}}
+	ntile := Param{Name: "buckets"}
+
+	for _, fn := range []WindowFunc{WinRowNumber, WinRank, WinDenseRank, WinNtile} {
+		expr := WindowExpression{Function: fn, NtileParam: &ntile, Window: partitioned}
+		err := build(expr, nil).Validate()
+		if err == nil || err.Error() != string(fn)+" requires ORDER BY in its window" {
+			t.Errorf("%s without ORDER BY: error = %v", fn, err)
+		}
+	}
+
+	named := WindowExpression{Function: WinRank, WindowRef: "w"}
+	if err := build(named, map[string]WindowSpec{"w": partitioned}).Validate(); err == nil {
+		t.Error("Expected error for a ranking function over a named window without ORDER BY")
+	}
+
+	total := Field{Name: "total"}
+	sum := WindowExpression{Aggregate: AggSum, Field: &total, Window: partitioned}
+	if err := build(sum, nil).Validate(); err != nil {
+		t.Errorf("Expected SUM OVER without ORDER BY to be valid, got %v", err)
+	}
+	first := WindowExpression{Function: WinFirstValue, Field: &total}
+	if err := build(first, nil).Validate(); err != nil {
+		t.Errorf("Expected FIRST_VALUE without ORDER BY to be valid, got %v", err)
+	}
+}
+
+func TestAST_Clone(t *testing.T) {
+	limit := 10
+	lock := LockForUpdate
+	original := &AST{
+		Operation: OpInsert,
+		Target:    Table{Name: "users"},
+		Values:    []map[Field]Param{{{Name: "email"}: {Name: "email"}}},
+		Limit:     &PaginationValue{Static: &limit},
+		Lock:      &lock,
+		OnConflict: &ConflictClause{
+			Action:  DoUpdate,
+			Columns: []Field{{Name: "email"}},
+			Updates: map[Field]Param{{Name: "name"}: {Name: "name"}},
+		},
+	}
+
+	clone := original.Clone()
+	clone.Values[0][Field{Name: "name"}] = Param{Name: "name"}
+	*clone.Limit.Static = 5
+	*clone.Lock = LockForShare
+	clone.OnConflict.Columns[0].Name = "id"
+	clone.OnConflict.Updates[Field{Name: "age"}] = Param{Name: "age"}
+
+	if len(original.Values[0]) != 1 {
+		t.Errorf("Values map shared: %v", original.Values[0])
+	}
+	if *original.Limit.Static != 10 {
+		t.Errorf("Limit shared: got %d", *original.Limit.Static)
+	}
+	if *original.Lock != LockForUpdate {
+		t.Errorf("Lock shared: got %s", *original.Lock)
+	}
+	if original.OnConflict.Columns[0].Name != "email" || len(original.OnConflict.Updates) != 1 {
+		t.Errorf("OnConflict shared: %+v", original.OnConflict)
+	}
+
+	if (*AST)(nil).Clone() != nil {
+		t.Error("Clone of a nil AST should be nil")
+	}
+}
+
+func TestAST_Validate_NegativePagination(t *testing.T) {
+	negative, zero := -1, 0
+	param := Param{Name: "page_size"}
+
+	tests := []struct {
+		name    string
+		limit   *PaginationValue
+		offset  *PaginationValue
+		wantErr string
+	}{
+		{"negative limit", &PaginationValue{Static: &negative}, nil, "LIMIT must be non-negative, got -1"},
+		{"negative offset", nil, &PaginationValue{Static: &negative}, "OFFSET must be non-negative, got -1"},
+		{"zero", &PaginationValue{Static: &zero}, &PaginationValue{Static: &zero}, ""},
+		{"param", &PaginationValue{Param: &param}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &AST{Operation: OpSelect, Target: Table{Name: "users"}, Limit: tt.limit, Offset: tt.offset}
+			err := ast.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAST_GroupByFields(t *testing.T) {
+	ast := &AST{
+		Operation: OpSelect,
+		Fields:    []Field{{Name: "a"}, {Name: "b"}, {Name: "a", Table: "t"}},
+		GroupBy:   []Field{{Name: "b"}},
+	}
+	if got := ast.GroupByFields(); len(got) != 1 || got[0].Name != "b" {
+		t.Errorf("GroupByFields() without GroupByAll = %v, want only b", got)
+	}
+
+	ast.GroupByAll = true
+	var names []string
+	for _, f := range ast.GroupByFields() {
+		names = append(names, f.Table+"."+f.Name)
+	}
+	if got := fmt.Sprint(names); got != "[.b .a t.a]" {
+		t.Errorf("GroupByFields() = %s, want [.b .a t.a]", got)
+	}
+	if len(ast.GroupBy) != 1 {
+		t.Errorf("GroupByFields() modified GroupBy: %v", ast.GroupBy)
+	}
+
+	ast.GroupByMode = GroupRollup
+	if err := ast.validateGrouping(); err == nil {
+		t.Error("Expected error for GroupByAll with ROLLUP")
+	}
+}
+
+func TestAST_Validate_ValuesTable(t *testing.T) {
+	two := [][]Param{{{Name: "id1"}, {Name: "name1"}}, {{Name: "id2"}, {Name: "name2"}}}
+	short := [][]Param{{{Name: "id1"}, {Name: "name1"}}, {{Name: "id2"}}}
+
+	tests := []struct {
+		name    string
+		op      Operation
+		table   Table
+		wantErr string
+	}{
+		{"two rows", OpSelect, Table{Alias: "t", Values: &ValuesTable{Columns: []string{"id", "name"}, Rows: two}}, ""},
+		{"row arity", OpSelect, Table{Alias: "t", Values: &ValuesTable{Columns: []string{"id", "name"}, Rows: short}}, "VALUES table row 1 has 1 values, want 2"},
+		{"no rows", OpSelect, Table{Alias: "t", Values: &ValuesTable{Columns: []string{"id"}}}, "VALUES table requires at least one row"},
+		{"no alias", OpSelect, Table{Values: &ValuesTable{Columns: []string{"id", "name"}, Rows: two}}, "VALUES table requires an alias"},
+		{"delete", OpDelete, Table{Alias: "t", Values: &ValuesTable{Columns: []string{"id", "name"}, Rows: two}}, "VALUES table target can only be used with SELECT or COUNT queries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &AST{Operation: tt.op, Target: tt.table}
+			err := ast.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsJSONKey_SharedByFieldsAndObjects(t *testing.T) {
+	for _, key := range []string{"status", "0", "items_2"} {
+		if err := (Field{Name: "data", JSONKey: key, JSONPath: key + ",name"}).Validate(); err != nil {
+			t.Errorf("Field key %q: unexpected error %v", key, err)
+		}
+		obj := JSONObjectExpression{Pairs: []JSONObjectPair{{Key: key, Field: &Field{Name: "id"}}}}
+		if err := obj.Validate(); err != nil {
+			t.Errorf("JSON object key %q: unexpected error %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "a'b", "a b"} {
+		if IsJSONKey(key) {
+			t.Errorf("IsJSONKey(%q) = true, want false", key)
+		}
+	}
+}
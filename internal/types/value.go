@@ -0,0 +1,55 @@
+package types
+
+import "fmt"
+
+// Value is the right-hand side of a Comparison: a Param (a placeholder or
+// one of the fixed literals), another Field, or a scalar Subquery. The set
+// is closed, so renderers can switch on the concrete type.
+type Value interface {
+	isValue()
+}
+
+func (Param) isValue()    {}
+func (Field) isValue()    {}
+func (Subquery) isValue() {}
+
+// Comparison compares a field with any Value: field op :param,
+// field op other_field, or field op (SELECT ...).
+type Comparison struct {
+	Value    Value
+	Field    Field
+	Operator Operator
+}
+
+// IsConditionItem implements ConditionItem.
+func (Comparison) IsConditionItem() {}
+
+// Validate checks the operator and the right-hand side.
+func (c Comparison) Validate() error {
+	if !c.Operator.IsComparison() {
+		return fmt.Errorf("operator %s cannot be used in a comparison - use =, !=, >, >=, <, or <=", c.Operator)
+	}
+	switch v := c.Value.(type) {
+	case Param:
+		if v.IsExcluded() || v.IsDefault() {
+			return fmt.Errorf("comparison value must be a parameter or TRUE, FALSE, or 1")
+		}
+		if v.IsLiteral() {
+			return v.Literal.Validate()
+		}
+		if v.Name == "" {
+			return fmt.Errorf("comparison parameter requires a name")
+		}
+	case Field:
+		if v.Name == "" {
+			return fmt.Errorf("comparison field requires a name")
+		}
+	case Subquery:
+		if v.AST == nil {
+			return fmt.Errorf("comparison subquery requires a query")
+		}
+	case nil:
+		return fmt.Errorf("comparison requires a value")
+	}
+	return nil
+}
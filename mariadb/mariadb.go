@@ -19,6 +19,7 @@ type renderContext struct {
 	paramCallback func(types.Param) string
 	paramPrefix   string
 	depth         int
+	selectOne     bool // Render the select list as 1 (EXISTS ignores it)
 }
 
 // newRenderContext creates a new render context.
@@ -47,22 +48,52 @@ func (ctx *renderContext) withSubquery() (*renderContext, error) {
 
 // addParam adds a parameter with proper namespacing.
 func (ctx *renderContext) addParam(param types.Param) string {
-	if ctx.paramPrefix != "" {
+	if ctx.paramPrefix != "" && !param.IsLiteral() {
 		param = types.Param{Name: ctx.paramPrefix + param.Name}
 	}
 	return ctx.paramCallback(param)
 }
 
 // Renderer implements the MariaDB dialect renderer.
-type Renderer struct{}
+type Renderer struct {
+	opts  Options
+	funcs render.Functions
+}
+
+func init() {
+	render.Register("mariadb", func() render.Renderer { return New() })
+}
+
+// Options configures optional rendering behavior.
+type Options = render.Options
+
+// RenderOptions configures a single render call.
+type RenderOptions = render.RenderOptions
 
 // New creates a new MariaDB renderer.
 func New() *Renderer {
 	return &Renderer{}
 }
 
+// NewWithOptions creates a new MariaDB renderer with the given options.
+func NewWithOptions(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// RegisterFunction allows a custom scalar function, such as a stored function or
+// an extension function, to be called through astql.Func with exactly argCount
+// arguments. The name may be schema-qualified (ext.my_fn) and renders unquoted.
+func (r *Renderer) RegisterFunction(name string, argCount int) error {
+	return r.funcs.Register(name, argCount)
+}
+
 // Render converts an AST to a QueryResult with MariaDB SQL.
 func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
+	return r.RenderWithOptions(ast, RenderOptions{})
+}
+
+// RenderWithOptions converts an AST to a QueryResult, applying per-call options.
+func (r *Renderer) RenderWithOptions(ast *types.AST, opts RenderOptions) (*types.QueryResult, error) {
 	// Validate unsupported features
 	if err := r.validateAST(ast); err != nil {
 		return nil, err
@@ -71,18 +102,26 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 	if err := ast.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid AST: %w", err)
 	}
+	if r.opts.LowercaseIdentifiers {
+		ast = render.LowercaseIdentifiers(ast)
+	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
 
 	addParam := func(param types.Param) string {
-		placeholder := ":" + param.Name
-		if !usedParams[param.Name] {
-			params = append(params, param.Name)
-			usedParams[param.Name] = true
+		if param.IsLiteral() {
+			return r.renderLiteral(param.Literal)
 		}
-		return placeholder
+		return params.Placeholder(param.Name)
+	}
+
+	if opts.Explain != render.ExplainNone {
+		prefix, err := r.explainPrefix(opts.Explain)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(prefix)
 	}
 
 	ctx := newRenderContext(addParam)
@@ -108,18 +147,44 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 		if err := r.renderCount(ast, &sql, addParam); err != nil {
 			return nil, err
 		}
+	case types.OpTruncate:
+		r.renderTruncate(ast, &sql)
 	default:
 		return nil, fmt.Errorf("unsupported operation: %s", ast.Operation)
 	}
 
+	text := sql.String()
+	if opts.Pretty {
+		text = render.ApplyPretty(text)
+	}
+
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              render.ApplyKeywordCase(text, opts.KeywordCase),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        ast.Operation,
+		Tables:           ast.Tables(),
 	}, nil
 }
 
+// explainPrefix returns the EXPLAIN prefix for the mode, with JSON output for plan diffing.
+// MariaDB spells the executing form ANALYZE rather than EXPLAIN ANALYZE.
+func (r *Renderer) explainPrefix(mode render.ExplainMode) (string, error) {
+	switch mode {
+	case render.ExplainPlan:
+		return "EXPLAIN FORMAT=JSON ", nil
+	case render.ExplainAnalyze:
+		return "ANALYZE FORMAT=JSON ", nil
+	}
+	return "", fmt.Errorf("unknown explain mode: %d", mode)
+}
+
 // RenderCompound converts a CompoundQuery to a QueryResult with SQL and parameters.
 func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error) {
+	if r.opts.LowercaseIdentifiers {
+		query = render.LowercaseCompoundIdentifiers(query)
+	}
+
 	// Validate each AST in the compound query
 	if err := r.validateAST(query.Base); err != nil {
 		return nil, err
@@ -131,42 +196,44 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
+
+	lparen, rparen, err := render.CompoundOperandParens(query, r.opts.BareCompoundOperands)
+	if err != nil {
+		return nil, err
+	}
 
 	queryIndex := 0
 
 	makeParamCallback := func(prefix string) func(types.Param) string {
 		return func(param types.Param) string {
-			name := prefix + param.Name
-			placeholder := ":" + name
-			if !usedParams[name] {
-				params = append(params, name)
-				usedParams[name] = true
+			if param.IsLiteral() {
+				return r.renderLiteral(param.Literal)
 			}
-			return placeholder
+			return params.Placeholder(prefix + param.Name)
 		}
 	}
 
-	// MySQL supports parentheses around SELECT in compound queries
-	sql.WriteString("(")
+	// Operands are parenthesized unless Options.BareCompoundOperands is set
+	sql.WriteString(lparen)
 	baseCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
 	if err := r.renderSelect(query.Base, &sql, baseCtx); err != nil {
 		return nil, err
 	}
-	sql.WriteString(")")
+	sql.WriteString(rparen)
 	queryIndex++
 
 	for _, operand := range query.Operands {
 		sql.WriteString(" ")
 		sql.WriteString(string(operand.Operation))
-		sql.WriteString(" (")
+		sql.WriteString(" ")
+		sql.WriteString(lparen)
 
 		opCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
 		if err := r.renderSelect(operand.AST, &sql, opCtx); err != nil {
 			return nil, err
 		}
-		sql.WriteString(")")
+		sql.WriteString(rparen)
 		queryIndex++
 	}
 
@@ -178,15 +245,19 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 		var orderParts []string
 		for i := range query.Ordering {
 			order := &query.Ordering[i]
+			field, err := render.CompoundOrderField(query, order.Field)
+			if err != nil {
+				return nil, err
+			}
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
-					r.renderField(order.Field),
+					r.renderField(field),
 					r.renderOperator(order.Operator),
 					finalCtx.addParam(order.Param),
 					order.Direction)
 			} else {
-				part = fmt.Sprintf("%s %s", r.renderField(order.Field), order.Direction)
+				part = fmt.Sprintf("%s %s", r.renderField(field), order.Direction)
 			}
 			orderParts = append(orderParts, part)
 		}
@@ -204,125 +275,236 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              sql.String(),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        types.OpSelect,
+		Tables:           query.Tables(),
 	}, nil
 }
 
-// validateAST checks for MySQL-unsupported features.
+// Validate reports every MariaDB incompatibility in ast at once, in the
+// order Render would meet them, or nil when the AST renders on MariaDB.
+// Render stops at the first of these errors; Validate is the dry run that
+// lists them all. Structural problems are left to ast.Validate.
+func (r *Renderer) Validate(ast *types.AST) []error {
+	var errs []error
+	r.collectAST(ast, &errs)
+	return errs
+}
+
+// validateAST returns the first MariaDB-unsupported feature in ast.
 func (r *Renderer) validateAST(ast *types.AST) error {
+	if errs := r.Validate(ast); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// collectAST appends an error for each MariaDB-unsupported feature in ast.
+// Conditions, expressions, and window specs report their first problem.
+func (r *Renderer) collectAST(ast *types.AST, errs *[]error) {
+	if ast.ReturningCount {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "RETURNING count",
+			"MariaDB cannot run UPDATE or DELETE inside a CTE; read the driver's rows-affected count instead"))
+	}
+
+	if ast.WithTies {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "FETCH FIRST ... WITH TIES",
+			"filter on RANK() in a derived table instead"))
+	}
+
+	if ast.Cascade {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "TRUNCATE CASCADE",
+			"truncate or delete from the referencing tables first"))
+	}
+
+	if len(ast.ValueArrays) > 0 {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "ARRAY constructors",
+			"pass the array as a single JSON param instead"))
+	}
+
 	if len(ast.DistinctOn) > 0 {
-		return render.NewUnsupportedFeatureError("mariadb", "DISTINCT ON",
-			"use GROUP BY with aggregates instead")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "DISTINCT ON",
+			"use GROUP BY with aggregates instead"))
 	}
 
 	if ast.Lock != nil {
-		// MySQL supports FOR UPDATE but with different syntax for some options
-		// For now, support basic FOR UPDATE/FOR SHARE
+		// MariaDB supports FOR UPDATE and shared locks (LOCK IN SHARE MODE)
 		if *ast.Lock != types.LockForUpdate && *ast.Lock != types.LockForShare {
-			return render.NewUnsupportedFeatureError("mariadb", "FOR NO KEY UPDATE/FOR KEY SHARE",
-				"use FOR UPDATE or FOR SHARE instead")
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "FOR NO KEY UPDATE/FOR KEY SHARE",
+				"use FOR UPDATE or FOR SHARE instead"))
+		}
+		if len(ast.LockOf) > 0 {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "FOR UPDATE OF",
+				"MariaDB locks rows of every table in the query; lock in a separate query per table instead"))
+		}
+	}
+
+	switch ast.GroupByMode {
+	case types.GroupCube, types.GroupSets:
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", fmt.Sprintf("GROUP BY %s", ast.GroupByMode),
+			"use GROUP BY ... WITH ROLLUP or UNION ALL of separately grouped queries instead"))
+	case types.GroupRollup:
+		if len(ast.Ordering) > 0 {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "WITH ROLLUP combined with ORDER BY",
+				"wrap the rollup query in a derived table and order the outer query"))
 		}
 	}
 
+	if ast.TargetSubquery != nil {
+		r.collectAST(ast.TargetSubquery, errs)
+	}
+
 	// Check for JSONB fields in all field locations
 	for _, field := range ast.Fields {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.FieldExpressions {
 		if err := r.validateFieldExpression(&ast.FieldExpressions[i]); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.GroupBy {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
+		}
+	}
+
+	for _, group := range ast.CoalescedGroupBy {
+		if err := r.checkJSONBField(group.Field); err != nil {
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.Ordering {
 		if err := r.checkJSONBField(ast.Ordering[i].Field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.Returning {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.Updates {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.UpdateExpressions {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 		exprCopy := ast.UpdateExpressions[field]
 		if err := r.validateFieldExpression(&exprCopy); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, valueSet := range ast.Values {
 		for field := range valueSet {
 			if err := r.checkJSONBField(field); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
 	}
 
+	for _, spec := range ast.Windows {
+		if err := r.validateWindowSpec(spec); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+
+	for _, cells := range ast.ValueSubqueries {
+		for _, sub := range cells {
+			r.collectAST(sub.AST, errs)
+		}
+	}
+
+	if len(ast.UpdateRows) > 0 {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "multi-column SET from a subquery",
+			"assign each column its own scalar subquery, or use a multi-table UPDATE joined to the subquery"))
+	}
+
 	if ast.OnConflict != nil {
 		for _, field := range ast.OnConflict.Columns {
 			if err := r.checkJSONBField(field); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
 		for field := range ast.OnConflict.Updates {
 			if err := r.checkJSONBField(field); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
+		if ast.OnConflict.ConflictPredicate != nil || ast.OnConflict.DoUpdateWhere != nil {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "ON CONFLICT WHERE predicates",
+				"ON DUPLICATE KEY UPDATE cannot filter conflicting rows; guard the update values with IF() instead"))
+		}
 	}
 
 	// Check for unsupported operators and JSONB in conditions
 	if ast.WhereClause != nil {
 		if err := r.validateCondition(ast.WhereClause); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, join := range ast.Joins {
+		if join.Lateral {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "LATERAL derived tables",
+				"use a correlated subquery in the SELECT list instead"))
+		}
+		if len(join.Columns) > 0 {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "derived table column alias lists",
+				"alias the columns inside the derived table's SELECT list instead"))
+		}
+		if join.Type == types.FullOuterJoin && !r.Capabilities().FullOuterJoin {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mariadb", "FULL OUTER JOIN",
+				"emulate with LEFT JOIN ... UNION ... RIGHT JOIN"))
+		}
 		if join.On != nil {
 			if err := r.validateCondition(join.On); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
+		if join.Subquery != nil && join.Subquery.AST != nil {
+			r.collectAST(join.Subquery.AST, errs)
+		}
 	}
 
 	for _, having := range ast.Having {
 		if err := r.validateCondition(having); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
-	return nil
 }
 
-// validateFieldExpression validates a field expression for JSONB fields.
+// validateFieldExpression validates a field expression for JSONB fields
+// and MariaDB-unsupported functions.
 func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
+	if err := r.validateFeatures(*expr); err != nil {
+		return err
+	}
 	if err := r.checkJSONBField(expr.Field); err != nil {
 		return err
 	}
 
+	if expr.Filter != nil {
+		if err := r.validateCondition(expr.Filter); err != nil {
+			return err
+		}
+	}
+
 	if expr.Binary != nil {
 		if err := r.checkJSONBField(expr.Binary.Field); err != nil {
 			return err
@@ -352,6 +534,16 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.DateDiff != nil {
+		for _, side := range []types.Value{expr.DateDiff.End, expr.DateDiff.Start} {
+			if f, ok := side.(types.Field); ok {
+				if err := r.checkJSONBField(f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	if expr.Cast != nil {
 		if err := r.checkJSONBField(expr.Cast.Field); err != nil {
 			return err
@@ -359,6 +551,9 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 	}
 
 	if expr.Window != nil {
+		if err := r.validateWindowSpec(expr.Window.Window); err != nil {
+			return err
+		}
 		if expr.Window.Field != nil {
 			if err := r.checkJSONBField(*expr.Window.Field); err != nil {
 				return err
@@ -376,9 +571,99 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.OrderedAgg != nil {
+		if err := r.checkJSONBField(expr.OrderedAgg.Field); err != nil {
+			return err
+		}
+		for i := range expr.OrderedAgg.OrderBy {
+			if err := r.checkJSONBField(expr.OrderedAgg.OrderBy[i].Field); err != nil {
+				return err
+			}
+		}
+	}
+
+	if expr.Arithmetic != nil {
+		for _, operand := range []types.ArithmeticOperand{expr.Arithmetic.Left, expr.Arithmetic.Right} {
+			if operand.Expr != nil {
+				if err := r.validateFieldExpression(operand.Expr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateWindowSpec checks a window frame for units the dialect cannot render.
+func (r *Renderer) validateWindowSpec(spec types.WindowSpec) error {
+	if spec.Unit() == types.FrameGroups {
+		return render.NewUnsupportedFeatureError("mariadb", "GROUPS window frames",
+			"use a ROWS or RANGE frame instead")
+	}
+	if spec.ExcludesRows() {
+		return render.NewUnsupportedFeatureError("mariadb", "window frame EXCLUDE",
+			"filter the excluded rows in an outer query instead")
+	}
+	return nil
+}
+
+// validateFeatures returns the first MariaDB-unsupported function or clause
+// expr uses at its own level; nested arithmetic operands are reached through
+// validateFieldExpression. renderFieldExpression calls it too, so Validate
+// and Render reject the same expressions.
+func (r *Renderer) validateFeatures(expr types.FieldExpression) error {
+	switch {
+	case expr.String != nil:
+		if expr.String.Function == types.StringInitcap {
+			return render.NewUnsupportedFeatureError("mariadb", "INITCAP",
+				"capitalize in application code, or combine UPPER(LEFT(s, 1)) with LOWER(SUBSTRING(s, 2)) for single words")
+		}
+	case expr.Date != nil:
+		return r.validateDateExpression(*expr.Date)
+	case expr.Window != nil:
+		return r.validateNullTreatment(*expr.Window)
+	case expr.Grouping != nil:
+		return render.NewUnsupportedFeatureError("mariadb", "GROUPING() function",
+			"GROUPING() requires ROLLUP, CUBE or GROUPING SETS support")
+	case expr.Array != nil:
+		return render.NewUnsupportedFeatureError("mariadb", "ARRAY constructors",
+			"pass the array as a single JSON param instead")
+	case expr.OrderedAgg != nil:
+		if expr.OrderedAgg.Function == types.AggArrayAgg && !r.Capabilities().ArrayOperators {
+			return render.NewUnsupportedFeatureError("mariadb", "ARRAY_AGG",
+				"use JSON_ARRAYAGG for a JSON array, or GROUP_CONCAT for a delimited string")
+		}
+	}
+	return nil
+}
+
+// validateDateExpression rejects DATE_BIN and DATE_TRUNC precisions MariaDB
+// has no truncation for.
+func (r *Renderer) validateDateExpression(expr types.DateExpression) error {
+	switch expr.Function {
+	case types.DateTrunc:
+		switch expr.Part {
+		case types.PartDay, types.PartMonth, types.PartYear:
+		default:
+			return render.NewUnsupportedFeatureError("mariadb", fmt.Sprintf("DATE_TRUNC with %s precision", expr.Part),
+				"use DATE_FORMAT() with appropriate format string")
+		}
+	case types.DateBin:
+		return render.NewUnsupportedFeatureError("mariadb", "DATE_BIN",
+			"bucket on epoch seconds with FROM_UNIXTIME(FLOOR(UNIX_TIMESTAMP(ts) / :seconds) * :seconds) in a raw query")
+	}
 	return nil
 }
 
+// validateNullTreatment rejects IGNORE NULLS, which MariaDB does not accept.
+func (r *Renderer) validateNullTreatment(expr types.WindowExpression) error {
+	if expr.NullTreatment == "" || expr.NullTreatment == types.NullsRespect {
+		return nil
+	}
+	return render.NewUnsupportedFeatureError("mariadb", string(expr.NullTreatment)+" on "+string(expr.Function),
+		"use a subquery that filters NULLs, or carry values forward with a running COUNT partition")
+}
+
 // validateCondition recursively checks conditions for unsupported operators and JSONB fields.
 func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 	switch c := cond.(type) {
@@ -401,6 +686,18 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			return err
 		}
 		return r.validateOperator(c.Operator)
+	case types.Comparison:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
+		switch v := c.Value.(type) {
+		case types.Field:
+			return r.checkJSONBField(v)
+		case types.Subquery:
+			if v.AST != nil {
+				return r.validateAST(v.AST)
+			}
+		}
 	case types.SubqueryCondition:
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
@@ -415,6 +712,20 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 				return err
 			}
 		}
+	case types.TupleCondition:
+		for _, field := range c.Fields {
+			if err := r.checkJSONBField(field); err != nil {
+				return err
+			}
+		}
+		if err := r.validateOperator(c.Operator); err != nil {
+			return err
+		}
+		if c.Subquery != nil && c.Subquery.AST != nil {
+			if err := r.validateAST(c.Subquery.AST); err != nil {
+				return err
+			}
+		}
 	case types.AggregateCondition:
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
@@ -422,6 +733,10 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			}
 		}
 		return r.validateOperator(c.Operator)
+	case types.InListCondition:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
 	case types.BetweenCondition:
 		if err := r.checkJSONBField(c.Field); err != nil {
 			return err
@@ -453,7 +768,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		sql.WriteString("DISTINCT ")
 	}
 
-	if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
+	if ctx.selectOne {
+		sql.WriteString("1")
+	} else if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
 		sql.WriteString("*")
 	} else {
 		var selections []string
@@ -477,18 +794,13 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
@@ -499,13 +811,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		}
 	}
 
-	if len(ast.GroupBy) > 0 {
+	if ast.HasGroupBy() {
 		sql.WriteString(" GROUP BY ")
-		var groupFields []string
-		for _, field := range ast.GroupBy {
-			groupFields = append(groupFields, r.renderField(field))
-		}
-		sql.WriteString(strings.Join(groupFields, ", "))
+		sql.WriteString(r.renderGroupBy(ast, ctx))
 	}
 
 	if len(ast.Having) > 0 {
@@ -520,6 +828,11 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		}
 	}
 
+	if len(ast.Windows) > 0 {
+		sql.WriteString(" WINDOW ")
+		sql.WriteString(r.renderWindowDefinitions(ast.Windows, ctx))
+	}
+
 	if len(ast.Ordering) > 0 {
 		sql.WriteString(" ORDER BY ")
 		var orderParts []string
@@ -560,13 +873,52 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		case types.LockForUpdate:
 			sql.WriteString(" FOR UPDATE")
 		case types.LockForShare:
-			sql.WriteString(" FOR SHARE")
+			// MariaDB spells shared locks as LOCK IN SHARE MODE
+			sql.WriteString(" LOCK IN SHARE MODE")
+		}
+		if ast.SkipLocked {
+			sql.WriteString(" SKIP LOCKED")
 		}
 	}
 
 	return nil
 }
 
+// renderGroupBy renders the GROUP BY list. MariaDB only supports the
+// trailing WITH ROLLUP form; CUBE and GROUPING SETS are rejected in validateAST.
+func (r *Renderer) renderGroupBy(ast *types.AST, ctx *renderContext) string {
+	grouped := ast.GroupByFields()
+	groupFields := make([]string, 0, len(grouped)+len(ast.CoalescedGroupBy))
+	for _, field := range grouped {
+		groupFields = append(groupFields, r.renderField(field))
+	}
+	for _, group := range ast.CoalescedGroupBy {
+		groupFields = append(groupFields, fmt.Sprintf("COALESCE(%s, %s)", r.renderField(group.Field), ctx.addParam(group.Sentinel)))
+	}
+	list := strings.Join(groupFields, ", ")
+	if ast.GroupByMode == types.GroupRollup {
+		return list + " WITH ROLLUP"
+	}
+	return list
+}
+
+// renderLiteral renders a literal value inline. MariaDB booleans are TINYINT(1).
+func (r *Renderer) renderLiteral(lit types.Literal) string {
+	switch lit {
+	case types.LiteralTrue:
+		return "1"
+	case types.LiteralFalse:
+		return "0"
+	case types.LiteralOne:
+		return "1"
+	case types.LiteralDefault:
+		return "DEFAULT"
+	default:
+		// Unreachable: AST validation rejects unknown literals.
+		return "NULL"
+	}
+}
+
 func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
 	sql.WriteString("INSERT INTO ")
 	sql.WriteString(r.renderTable(ast.Target))
@@ -575,15 +927,9 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		return fmt.Errorf("INSERT requires at least one value set")
 	}
 
-	fields := make([]string, 0, len(ast.Values[0]))
-	fieldObjs := make([]types.Field, 0, len(ast.Values[0]))
-	for field := range ast.Values[0] {
-		fieldObjs = append(fieldObjs, field)
-	}
-
-	sort.Slice(fieldObjs, func(i, j int) bool {
-		return fieldObjs[i].Name < fieldObjs[j].Name
-	})
+	// Columns sorted by name for deterministic output, including subquery cells
+	fieldObjs := ast.InsertColumns()
+	fields := make([]string, 0, len(fieldObjs))
 
 	for _, field := range fieldObjs {
 		fields = append(fields, r.quoteIdentifier(field.Name))
@@ -594,11 +940,19 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 	sql.WriteString(") VALUES ")
 
 	valueSets := make([]string, 0, len(ast.Values))
-	for _, valueSet := range ast.Values {
+	ctx := newRenderContext(addParam)
+	for i, valueSet := range ast.Values {
 		var values []string
 		for _, field := range fieldObjs {
-			param := valueSet[field]
-			values = append(values, addParam(param))
+			if sub, ok := ast.ValueSubquery(i, field); ok {
+				var subSQL strings.Builder
+				if err := r.renderSubquery(sub, &subSQL, ctx); err != nil {
+					return err
+				}
+				values = append(values, "("+subSQL.String()+")")
+				continue
+			}
+			values = append(values, addParam(valueSet[field]))
 		}
 		valueSets = append(valueSets, "("+strings.Join(values, ", ")+")")
 	}
@@ -632,7 +986,13 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 			var updates []string
 			for _, field := range conflictUpdateFields {
 				param := ast.OnConflict.Updates[field]
-				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), addParam(param)))
+				var value string
+				if param.IsExcluded() {
+					value = "VALUES(" + r.quoteIdentifier(param.Excluded.Field.Name) + ")"
+				} else {
+					value = addParam(param)
+				}
+				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), value))
 			}
 			sql.WriteString(strings.Join(updates, ", "))
 		}
@@ -737,27 +1097,28 @@ func (r *Renderer) renderDelete(ast *types.AST, sql *strings.Builder, addParam f
 	return nil
 }
 
+// renderTruncate renders TRUNCATE TABLE. It always resets the table's
+// AUTO_INCREMENT counter, so RestartIdentity adds nothing to the SQL.
+func (r *Renderer) renderTruncate(ast *types.AST, sql *strings.Builder) {
+	sql.WriteString("TRUNCATE TABLE ")
+	sql.WriteString(r.quoteIdentifier(ast.Target.Name))
+}
+
 func (r *Renderer) renderCount(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
+	ctx := newRenderContext(addParam)
 	sql.WriteString("SELECT " + countStarSQL + " FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			ctx := newRenderContext(addParam)
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
 	if ast.WhereClause != nil {
 		sql.WriteString(" WHERE ")
-		ctx := newRenderContext(addParam)
 		if err := r.renderCondition(ast.WhereClause, sql, ctx); err != nil {
 			return err
 		}
@@ -780,6 +1141,94 @@ func (r *Renderer) renderTable(table types.Table) string {
 	return quotedName
 }
 
+// renderFrom renders the FROM target: a table or a derived table with its alias.
+func (r *Renderer) renderFrom(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	if ast.Target.Values != nil {
+		sql.WriteString(r.renderValuesTable(ast.Target, ctx))
+		return nil
+	}
+	if ast.TargetSubquery == nil {
+		sql.WriteString(r.renderTable(ast.Target))
+		return nil
+	}
+	sql.WriteString("(")
+	if err := r.renderSubquery(types.Subquery{AST: ast.TargetSubquery}, sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(") AS ")
+	sql.WriteString(ast.TargetAlias)
+	return nil
+}
+
+// renderValuesTable renders an inline VALUES source with its alias. MariaDB has
+// no derived table column lists, so the rows become a UNION ALL of SELECTs
+// whose first branch names the columns.
+func (r *Renderer) renderValuesTable(table types.Table, ctx *renderContext) string {
+	selects := make([]string, 0, len(table.Values.Rows))
+	for i, row := range table.Values.Rows {
+		values := make([]string, 0, len(row))
+		for j, value := range row {
+			value := ctx.addParam(value)
+			if i == 0 {
+				value += " AS " + r.quoteIdentifier(table.Values.Columns[j])
+			}
+			values = append(values, value)
+		}
+		selects = append(selects, "SELECT "+strings.Join(values, ", "))
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ") AS " + table.Alias
+}
+
+// renderJoin renders a single JOIN clause against a table or derived table.
+func (r *Renderer) renderJoin(join types.Join, sql *strings.Builder, ctx *renderContext) error {
+	sql.WriteString(" ")
+	sql.WriteString(string(join.Type))
+	sql.WriteString(" ")
+	if join.Lateral {
+		sql.WriteString("LATERAL ")
+	}
+	if join.Subquery != nil {
+		sql.WriteString("(")
+		if err := r.renderSubquery(*join.Subquery, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(") AS ")
+		sql.WriteString(join.Table.Alias)
+		if len(join.Columns) > 0 {
+			columns := make([]string, 0, len(join.Columns))
+			for _, col := range join.Columns {
+				columns = append(columns, r.quoteIdentifier(col))
+			}
+			sql.WriteString("(" + strings.Join(columns, ", ") + ")")
+		}
+	} else if join.Table.Values != nil {
+		sql.WriteString(r.renderValuesTable(join.Table, ctx))
+	} else {
+		sql.WriteString(r.renderTable(join.Table))
+	}
+
+	// USING replaces ON; CROSS JOIN and NATURAL JOIN have neither
+	if len(join.Using) > 0 {
+		sql.WriteString(" USING ")
+		sql.WriteString(r.renderUsing(join.Using))
+	} else if join.Type.RequiresOn() {
+		sql.WriteString(" ON ")
+		if err := r.renderCondition(join.On, sql, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderUsing renders a JOIN USING column list with unqualified column names.
+func (r *Renderer) renderUsing(fields []types.Field) string {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, r.quoteIdentifier(field.Name))
+	}
+	return "(" + strings.Join(columns, ", ") + ")"
+}
+
 func (r *Renderer) renderField(field types.Field) string {
 	quotedName := r.quoteIdentifier(field.Name)
 	if field.Table != "" {
@@ -790,7 +1239,7 @@ func (r *Renderer) renderField(field types.Field) string {
 
 // checkJSONBField returns an error if the field uses JSONB access operators.
 func (r *Renderer) checkJSONBField(field types.Field) error {
-	if field.JSONBTextKey != nil || field.JSONBPathKey != nil {
+	if field.HasJSONAccess() {
 		return render.NewUnsupportedFeatureError("mariadb", "JSONB field access operators",
 			"use JSON_EXTRACT() or JSON_UNQUOTE(JSON_EXTRACT()) instead")
 	}
@@ -830,7 +1279,36 @@ func (r *Renderer) renderAggregateExpression(aggregate types.AggregateFunc, fiel
 	}
 }
 
+// renderFilteredAggregate renders AGG(x) FILTER (WHERE cond) as AGG(CASE WHEN cond THEN x END).
+// Rows failing the condition yield NULL, which every aggregate ignores.
+func (r *Renderer) renderFilteredAggregate(aggregate types.AggregateFunc, field types.Field, filter types.ConditionItem, ctx *renderContext) (string, error) {
+	var cond strings.Builder
+	if err := r.renderCondition(filter, &cond, ctx); err != nil {
+		return "", err
+	}
+
+	// COUNT(*) has no field; count a constant for matching rows instead
+	value := "1"
+	if field.Name != "" {
+		value = r.renderField(field)
+	}
+	caseExpr := fmt.Sprintf("CASE WHEN %s THEN %s END", cond.String(), value)
+
+	switch aggregate {
+	case types.AggCountField:
+		return fmt.Sprintf("COUNT(%s)", caseExpr), nil
+	case types.AggCountDistinct:
+		return fmt.Sprintf("COUNT(DISTINCT %s)", caseExpr), nil
+	default:
+		return fmt.Sprintf("%s(%s)", aggregate, caseExpr), nil
+	}
+}
+
 func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *renderContext) (string, error) {
+	if err := r.validateFeatures(expr); err != nil {
+		return "", err
+	}
+
 	var result string
 
 	switch {
@@ -870,8 +1348,17 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 			return "", err
 		}
 		result = dateStr
+	case expr.DateDiff != nil:
+		diffStr, err := r.renderDateDiff(*expr.DateDiff, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = diffStr
 	case expr.Cast != nil:
-		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.mapCastType(expr.Cast.CastType))
+		if err := expr.Cast.Validate(); err != nil {
+			return "", err
+		}
+		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.renderCastType(*expr.Cast))
 	case expr.Window != nil:
 		windowStr, err := r.renderWindowExpression(*expr.Window, ctx)
 		if err != nil {
@@ -891,12 +1378,43 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 		paramStr := ctx.addParam(expr.Binary.Param)
 		opStr := r.renderOperator(expr.Binary.Operator)
 		result = fmt.Sprintf("%s %s %s", r.renderField(expr.Binary.Field), opStr, paramStr)
+	case expr.Arithmetic != nil:
+		// Render arithmetic between expressions
+		arithStr, err := r.renderArithmetic(*expr.Arithmetic, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = arithStr
+	case expr.Func != nil:
+		// Render a registered custom function
+		funcStr, err := r.renderFunc(*expr.Func, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = funcStr
+	case expr.JSONObject != nil:
+		// Render JSON object construction
+		jsonStr, err := r.renderJSONObject(*expr.JSONObject, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = jsonStr
+	case expr.OrderedAgg != nil:
+		aggStr, err := r.renderOrderedAggregate(*expr.OrderedAgg, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = aggStr
 	case expr.Aggregate != "":
-		result = r.renderAggregateExpression(expr.Aggregate, expr.Field)
 		if expr.Filter != nil {
-			// MySQL doesn't support FILTER clause - would need to use CASE WHEN
-			return "", render.NewUnsupportedFeatureError("mariadb", "FILTER clause on aggregates",
-				"use CASE WHEN inside the aggregate instead")
+			// No native FILTER clause: rewrite as AGG(CASE WHEN cond THEN x END)
+			filtered, err := r.renderFilteredAggregate(expr.Aggregate, expr.Field, expr.Filter, ctx)
+			if err != nil {
+				return "", err
+			}
+			result = filtered
+		} else {
+			result = r.renderAggregateExpression(expr.Aggregate, expr.Field)
 		}
 	default:
 		result = r.renderField(expr.Field)
@@ -909,6 +1427,84 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 	return result, nil
 }
 
+// renderOrderedAggregate renders STRING_AGG as
+// GROUP_CONCAT(field ORDER BY ... SEPARATOR :sep). Without a separator
+// GROUP_CONCAT joins values with a comma.
+func (r *Renderer) renderOrderedAggregate(expr types.OrderedAggregate, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	sql.WriteString("GROUP_CONCAT(")
+	sql.WriteString(r.renderField(expr.Field))
+	if len(expr.OrderBy) > 0 {
+		var orderParts []string
+		for i := range expr.OrderBy {
+			order := &expr.OrderBy[i]
+			var part string
+			if order.Operator != "" {
+				part = fmt.Sprintf("%s %s %s %s",
+					r.renderField(order.Field),
+					r.renderOperator(order.Operator),
+					ctx.addParam(order.Param),
+					order.Direction)
+			} else {
+				part = fmt.Sprintf("%s %s", r.renderField(order.Field), order.Direction)
+			}
+			if order.Nulls != "" {
+				part += " " + string(order.Nulls)
+			}
+			orderParts = append(orderParts, part)
+		}
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(orderParts, ", "))
+	}
+	if expr.Separator != nil {
+		sql.WriteString(" SEPARATOR ")
+		sql.WriteString(ctx.addParam(*expr.Separator))
+	}
+	sql.WriteString(")")
+	return sql.String(), nil
+}
+
+// renderArithmetic renders left <op> right. Nested arithmetic operands are
+// parenthesized so the expression tree's grouping is preserved.
+func (r *Renderer) renderArithmetic(expr types.ArithmeticExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	left, err := r.renderArithmeticOperand(expr.Left, ctx)
+	if err != nil {
+		return "", err
+	}
+	right, err := r.renderArithmeticOperand(expr.Right, ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, expr.Operator, right), nil
+}
+
+func (r *Renderer) renderArithmeticOperand(operand types.ArithmeticOperand, ctx *renderContext) (string, error) {
+	if operand.Param != nil {
+		return ctx.addParam(*operand.Param), nil
+	}
+	result, err := r.renderFieldExpression(*operand.Expr, ctx)
+	if err != nil {
+		return "", err
+	}
+	if operand.Expr.Arithmetic != nil {
+		result = "(" + result + ")"
+	}
+	return result, nil
+}
+
+// renderCastType renders the MySQL cast target with its type parameters.
+// VARCHAR maps to CHAR, the only string type CAST accepts.
+func (r *Renderer) renderCastType(cast types.CastExpression) string {
+	return r.mapCastType(cast.CastType) + cast.TypeParams()
+}
+
 // mapCastType maps PostgreSQL cast types to MySQL equivalents.
 func (r *Renderer) mapCastType(castType types.CastType) string {
 	switch castType {
@@ -930,11 +1526,49 @@ func (r *Renderer) mapCastType(castType types.CastType) string {
 		return "UNSIGNED" // MySQL uses 0/1 for boolean
 	case types.CastBytea:
 		return "BINARY"
+	case types.CastVarchar:
+		return "CHAR"
 	default:
 		return "CHAR"
 	}
 }
 
+// renderJSONObject renders JSON_OBJECT('key', value, ...).
+// Keys are validated identifiers, so they are safe to emit as string literals.
+func (r *Renderer) renderJSONObject(expr types.JSONObjectExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Pairs)*2)
+	for _, pair := range expr.Pairs {
+		args = append(args, "'"+pair.Key+"'")
+		if pair.Field != nil {
+			args = append(args, r.renderField(*pair.Field))
+		} else {
+			args = append(args, ctx.addParam(*pair.Param))
+		}
+	}
+	return fmt.Sprintf("JSON_OBJECT(%s)", strings.Join(args, ", ")), nil
+}
+
+// renderFunc renders a call to a custom function registered with RegisterFunction.
+func (r *Renderer) renderFunc(expr types.FuncExpression, ctx *renderContext) (string, error) {
+	if err := r.funcs.Check("mariadb", expr); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Args))
+	for _, arg := range expr.Args {
+		if arg.Field != nil {
+			args = append(args, r.renderField(*arg.Field))
+		} else {
+			args = append(args, ctx.addParam(*arg.Param))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", expr.Name, strings.Join(args, ", ")), nil
+}
+
 func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builder, ctx *renderContext) error {
 	switch c := cond.(type) {
 	case types.Condition:
@@ -954,14 +1588,23 @@ func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builde
 		}
 		sql.WriteString(")")
 	case types.FieldComparison:
-		fmt.Fprintf(sql, "%s %s %s",
-			r.renderField(c.LeftField),
-			r.renderOperator(c.Operator),
-			r.renderField(c.RightField))
+		sql.WriteString(r.renderComparison(r.renderField(c.LeftField), c.Operator, r.renderField(c.RightField)))
+	case types.Comparison:
+		if err := r.renderValueComparison(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.SubqueryCondition:
 		if err := r.renderSubqueryCondition(c, sql, ctx); err != nil {
 			return err
 		}
+	case types.TupleCondition:
+		if err := r.renderTupleCondition(c, sql, ctx); err != nil {
+			return err
+		}
+	case types.InListCondition:
+		if err := r.renderInListCondition(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.AggregateCondition:
 		sql.WriteString(r.renderAggregateCondition(c, ctx.addParam))
 	case types.BetweenCondition:
@@ -974,7 +1617,6 @@ func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builde
 
 func (r *Renderer) renderSimpleCondition(cond types.Condition, addParam func(types.Param) string) string {
 	field := r.renderField(cond.Field)
-	op := r.renderOperator(cond.Operator)
 
 	switch cond.Operator {
 	case types.IsNull:
@@ -987,13 +1629,30 @@ func (r *Renderer) renderSimpleCondition(cond types.Condition, addParam func(typ
 	case types.NotIn:
 		return fmt.Sprintf("%s NOT IN (%s)", field, addParam(cond.Value))
 	default:
-		return fmt.Sprintf("%s %s %s", field, op, addParam(cond.Value))
+		return r.renderComparison(field, cond.Operator, addParam(cond.Value))
+	}
+}
+
+// renderComparison renders left op right. NULL-safe comparisons use the <=> operator.
+func (r *Renderer) renderComparison(left string, op types.Operator, right string) string {
+	switch op {
+	case types.IsNotDistinctFrom:
+		return fmt.Sprintf("%s <=> %s", left, right)
+	case types.IsDistinctFrom:
+		return fmt.Sprintf("NOT (%s <=> %s)", left, right)
+	default:
+		return fmt.Sprintf("%s %s %s", left, r.renderOperator(op), right)
 	}
 }
 
 func (r *Renderer) renderAggregateCondition(cond types.AggregateCondition, addParam func(types.Param) string) string {
 	var aggExpr string
 
+	// MariaDB allows HAVING to reference select-list aliases
+	if cond.Alias != "" {
+		return fmt.Sprintf("%s %s %s", r.quoteIdentifier(cond.Alias), r.renderOperator(cond.Operator), addParam(cond.Value))
+	}
+
 	switch cond.Func {
 	case types.AggCountField:
 		if cond.Field == nil {
@@ -1047,6 +1706,39 @@ func (r *Renderer) renderBetweenCondition(cond types.BetweenCondition, addParam
 	return fmt.Sprintf("%s %s %s AND %s", field, op, addParam(cond.Low), addParam(cond.High))
 }
 
+// renderValueComparison renders field op value for a param, field, or
+// scalar subquery on the right-hand side.
+func (r *Renderer) renderValueComparison(cond types.Comparison, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	var right strings.Builder
+	if err := r.renderValue(cond.Value, &right, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(r.renderComparison(r.renderField(cond.Field), cond.Operator, right.String()))
+	return nil
+}
+
+// renderValue renders the right-hand side of a Comparison.
+func (r *Renderer) renderValue(value types.Value, sql *strings.Builder, ctx *renderContext) error {
+	switch v := value.(type) {
+	case types.Param:
+		sql.WriteString(ctx.addParam(v))
+	case types.Field:
+		sql.WriteString(r.renderField(v))
+	case types.Subquery:
+		sql.WriteString("(")
+		if err := r.renderSubquery(v, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(")")
+	default:
+		return fmt.Errorf("unknown value type: %T", v)
+	}
+	return nil
+}
+
 func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *strings.Builder, ctx *renderContext) error {
 	switch cond.Operator {
 	case types.EXISTS, types.NotExists:
@@ -1058,12 +1750,26 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 		}
 		sql.WriteString(r.renderField(*cond.Field))
 		sql.WriteString(" ")
-		sql.WriteString(string(cond.Operator))
+		if cond.Quantifier != types.QuantNone {
+			// Quantified comparison: field op ANY|ALL (subquery)
+			if !cond.Operator.IsComparison() {
+				return fmt.Errorf("operator %s cannot be used with %s", cond.Operator, cond.Quantifier)
+			}
+			sql.WriteString(r.renderOperator(cond.Operator))
+			sql.WriteString(" ")
+			sql.WriteString(string(cond.Quantifier))
+		} else {
+			sql.WriteString(string(cond.Operator))
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("(")
-	if err := r.renderSubquery(cond.Subquery, sql, ctx); err != nil {
+	if cond.Operator == types.EXISTS || cond.Operator == types.NotExists {
+		if err := r.renderExistsSubquery(cond.Subquery, sql, ctx); err != nil {
+			return err
+		}
+	} else if err := r.renderSubquery(r.inSubquery(cond.Operator, cond.Subquery), sql, ctx); err != nil {
 		return err
 	}
 	sql.WriteString(")")
@@ -1071,6 +1777,92 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 	return nil
 }
 
+// renderInListCondition renders field IN (:a, :b). An empty list renders a
+// constant guard predicate unless StrictEmptyIn is set.
+func (r *Renderer) renderInListCondition(cond types.InListCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	if len(cond.Values) == 0 {
+		guard, err := render.EmptyInGuard(cond, r.opts.StrictEmptyIn)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(guard)
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		placeholders = append(placeholders, ctx.addParam(value))
+	}
+	if cond.ValuesTable {
+		fmt.Fprintf(sql, "%s %s (%s)", r.renderField(cond.Field), cond.Operator, render.ValuesRows(placeholders))
+		return nil
+	}
+	fmt.Fprintf(sql, "%s %s (%s)", r.renderField(cond.Field), cond.Operator, strings.Join(placeholders, ", "))
+	return nil
+}
+
+// renderTupleCondition renders a row value comparison: ("a", "b") op (:p1, :p2) or (subquery).
+func (r *Renderer) renderTupleCondition(cond types.TupleCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+
+	sql.WriteString("(")
+	for i, field := range cond.Fields {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(r.renderField(field))
+	}
+	sql.WriteString(") ")
+
+	if cond.Operator == types.IN || cond.Operator == types.NotIn {
+		sql.WriteString(string(cond.Operator))
+	} else {
+		sql.WriteString(r.renderOperator(cond.Operator))
+	}
+	sql.WriteString(" (")
+
+	if cond.Subquery != nil {
+		if err := r.renderSubquery(r.inSubquery(cond.Operator, *cond.Subquery), sql, ctx); err != nil {
+			return err
+		}
+	} else {
+		for i, value := range cond.Values {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(ctx.addParam(value))
+		}
+	}
+	sql.WriteString(")")
+
+	return nil
+}
+
+// renderExistsSubquery renders an EXISTS subquery as SELECT 1 without a redundant LIMIT 1.
+func (r *Renderer) renderExistsSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
+	subCtx, err := ctx.withSubquery()
+	if err != nil {
+		return err
+	}
+
+	ast, selectOne := types.ExistsSubquery(subquery.AST)
+	subCtx.selectOne = selectOne
+	return r.renderSelect(ast, sql, subCtx)
+}
+
+// inSubquery applies the StripInSubqueryDistinct option to IN/NOT IN subqueries.
+func (r *Renderer) inSubquery(op types.Operator, subquery types.Subquery) types.Subquery {
+	if !r.opts.StripInSubqueryDistinct || (op != types.IN && op != types.NotIn) {
+		return subquery
+	}
+	return types.Subquery{AST: types.StripDistinct(subquery.AST)}
+}
+
 func (r *Renderer) renderSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
 	subCtx, err := ctx.withSubquery()
 	if err != nil {
@@ -1226,6 +2018,44 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 			sql.WriteString(r.renderField(f))
 		}
 		sql.WriteString(")")
+	case types.StringLPad, types.StringRPad:
+		sql.WriteString(string(expr.Function))
+		sql.WriteString("(")
+		sql.WriteString(r.renderField(expr.Field))
+		for _, arg := range expr.Args {
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(arg))
+		}
+		sql.WriteString(")")
+	case types.StringReverse:
+		sql.WriteString("REVERSE(")
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringPosition:
+		sql.WriteString("POSITION(")
+		if len(expr.Args) >= 1 {
+			sql.WriteString(ctx.addParam(expr.Args[0]))
+			sql.WriteString(" IN ")
+		}
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringSplitPart:
+		// No SPLIT_PART: take everything before the n-th delimiter, then the
+		// last part of that. Past the final part this returns the last part
+		// instead of an empty string. The delimiter is added once per use so
+		// positional placeholders bind correctly.
+		if len(expr.Args) >= 2 {
+			delim, n := expr.Args[0], expr.Args[1]
+			sql.WriteString("SUBSTRING_INDEX(SUBSTRING_INDEX(")
+			sql.WriteString(r.renderField(expr.Field))
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(delim))
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(n))
+			sql.WriteString("), ")
+			sql.WriteString(ctx.addParam(delim))
+			sql.WriteString(", -1)")
+		}
 	default:
 		return "", fmt.Errorf("unsupported string function: %s", expr.Function)
 	}
@@ -1233,11 +2063,11 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 	return sql.String(), nil
 }
 
-func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderContext) (string, error) {
+func (r *Renderer) renderDateExpression(expr types.DateExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
 	switch expr.Function {
-	case types.DateNow:
+	case types.DateNow, types.DateNowTz:
 		sql.WriteString("NOW()")
 	case types.DateCurrentDate:
 		sql.WriteString("CURDATE()")
@@ -1259,7 +2089,7 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 			return "", fmt.Errorf("DATE_TRUNC requires a field")
 		}
 		// MySQL doesn't have DATE_TRUNC, need to use DATE_FORMAT or similar
-		// For simplicity, use DATE() for day truncation, otherwise error
+		// DATE() truncates to the day; other precisions are rejected by validateFeatures
 		switch expr.Part {
 		case types.PartDay:
 			sql.WriteString("DATE(")
@@ -1273,10 +2103,20 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 			sql.WriteString("DATE_FORMAT(")
 			sql.WriteString(r.renderField(*expr.Field))
 			sql.WriteString(", '%Y-01-01')")
-		default:
-			return "", render.NewUnsupportedFeatureError("mariadb", fmt.Sprintf("DATE_TRUNC with %s precision", expr.Part),
-				"use DATE_FORMAT() with appropriate format string")
 		}
+	case types.DateAdd, types.DateSub:
+		if err := expr.Validate(); err != nil {
+			return "", err
+		}
+		// DATE_ADD(ts, INTERVAL :n DAY) and DATE_SUB share the function name
+		sql.WriteString(string(expr.Function))
+		sql.WriteString("(")
+		sql.WriteString(r.renderField(*expr.Field))
+		sql.WriteString(", INTERVAL ")
+		sql.WriteString(ctx.addParam(*expr.Amount))
+		sql.WriteString(" ")
+		sql.WriteString(string(expr.Part))
+		sql.WriteString(")")
 	default:
 		return "", fmt.Errorf("unsupported date function: %s", expr.Function)
 	}
@@ -1284,6 +2124,22 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 	return sql.String(), nil
 }
 
+// renderDateDiff renders TIMESTAMPDIFF(unit, start, end), which counts
+// whole units like the other dialects' DATE_DIFF forms.
+func (r *Renderer) renderDateDiff(expr types.DateDiffExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	var start, end strings.Builder
+	if err := r.renderValue(expr.Start, &start, ctx); err != nil {
+		return "", err
+	}
+	if err := r.renderValue(expr.End, &end, ctx); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("TIMESTAMPDIFF(%s, %s, %s)", expr.Part, start.String(), end.String()), nil
+}
+
 func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
@@ -1337,22 +2193,65 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		}
 	}
 
+	if expr.NullTreatment != "" {
+		treatment, err := r.renderNullTreatment(expr)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(treatment)
+	}
+
+	// Render OVER clause, referencing a named window when set
+	if expr.WindowRef != "" {
+		sql.WriteString(" OVER ")
+		sql.WriteString(r.quoteIdentifier(expr.WindowRef))
+		return sql.String(), nil
+	}
 	sql.WriteString(" OVER (")
+	sql.WriteString(r.renderWindowSpec(expr.Window, ctx))
+	sql.WriteString(")")
+
+	return sql.String(), nil
+}
+
+// renderNullTreatment renders IGNORE NULLS or RESPECT NULLS after the argument list.
+// MariaDB accepts neither clause; RESPECT NULLS is its
+// default behavior and renders as nothing.
+func (r *Renderer) renderNullTreatment(expr types.WindowExpression) (string, error) {
+	return "", r.validateNullTreatment(expr)
+}
+
+// renderWindowDefinitions renders the named windows as name AS (...), sorted by name.
+func (r *Renderer) renderWindowDefinitions(windows map[string]types.WindowSpec, ctx *renderContext) string {
+	names := make([]string, 0, len(windows))
+	for name := range windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defs := make([]string, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, fmt.Sprintf("%s AS (%s)", r.quoteIdentifier(name), r.renderWindowSpec(windows[name], ctx)))
+	}
+	return strings.Join(defs, ", ")
+}
 
+// renderWindowSpec renders a window definition body: PARTITION BY, ORDER BY and frame.
+func (r *Renderer) renderWindowSpec(spec types.WindowSpec, ctx *renderContext) string {
 	var overParts []string
 
-	if len(expr.Window.PartitionBy) > 0 {
+	if len(spec.PartitionBy) > 0 {
 		var partitionFields []string
-		for _, field := range expr.Window.PartitionBy {
+		for _, field := range spec.PartitionBy {
 			partitionFields = append(partitionFields, r.renderField(field))
 		}
 		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionFields, ", "))
 	}
 
-	if len(expr.Window.OrderBy) > 0 {
+	if len(spec.OrderBy) > 0 {
 		var orderParts []string
-		for i := range expr.Window.OrderBy {
-			order := &expr.Window.OrderBy[i]
+		for i := range spec.OrderBy {
+			order := &spec.OrderBy[i]
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
@@ -1368,20 +2267,25 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
 	}
 
-	if expr.Window.FrameStart != "" {
-		framePart := "ROWS BETWEEN " + string(expr.Window.FrameStart) + " AND "
-		if expr.Window.FrameEnd != "" {
-			framePart += string(expr.Window.FrameEnd)
-		} else {
-			framePart += "CURRENT ROW"
+	if spec.FrameStart != "" {
+		end := string(types.FrameCurrentRow)
+		if spec.FrameEnd != "" {
+			end = r.renderFrameBound(spec.FrameEnd, spec.FrameEndOffset, ctx)
 		}
+		framePart := fmt.Sprintf("%s BETWEEN %s AND %s", spec.Unit(),
+			r.renderFrameBound(spec.FrameStart, spec.FrameStartOffset, ctx), end)
 		overParts = append(overParts, framePart)
 	}
 
-	sql.WriteString(strings.Join(overParts, " "))
-	sql.WriteString(")")
+	return strings.Join(overParts, " ")
+}
 
-	return sql.String(), nil
+// renderFrameBound renders a frame bound, prefixing offset bounds with their parameter.
+func (r *Renderer) renderFrameBound(bound types.FrameBound, offset *types.Param, ctx *renderContext) string {
+	if offset != nil {
+		return ctx.addParam(*offset) + " " + string(bound)
+	}
+	return string(bound)
 }
 
 func (r *Renderer) renderOperator(op types.Operator) string {
@@ -1433,5 +2337,11 @@ func (r *Renderer) Capabilities() render.Capabilities {
 		ArrayOperators:      false,
 		InArray:             true,
 		RowLocking:          render.RowLockingBasic,
+		FullOuterJoin:       false,
+		RightJoin:           true,
+		SkipLocked:          true, // MariaDB 10.6+
+		WithTies:            false,
+
+		GroupConcatTruncates: true, // Cut at group_concat_max_len
 	}
 }
@@ -1,6 +1,7 @@
 package mariadb
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -83,6 +84,37 @@ func TestRender_Insert(t *testing.T) {
 	}
 }
 
+func TestRender_InsertDefaultValues(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{
+				{Name: "name"}:  {Name: "name1"},
+				{Name: "email"}: {Name: "email1"},
+			},
+			{
+				{Name: "name"}:  {Name: "name2"},
+				{Name: "email"}: {Literal: types.LiteralDefault},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "INSERT INTO `users` (`email`, `name`) VALUES (:email1, :name1), (DEFAULT, :name2)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "email1,name1,name2" {
+		t.Errorf("RequiredParams = %v, want [email1 name1 name2]", result.RequiredParams)
+	}
+}
+
 func TestRender_Update(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -278,6 +310,34 @@ func TestRender_OnDuplicateKeyUpdate(t *testing.T) {
 	}
 }
 
+func TestRender_OnDuplicateKeyUpdateValuesRef(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Name: "email_val"}, {Name: "name"}: {Name: "name_val"}},
+		},
+		OnConflict: &types.ConflictClause{
+			Columns: []types.Field{{Name: "email"}},
+			Action:  types.DoUpdate,
+			Updates: map[types.Field]types.Param{
+				{Name: "name"}: {Excluded: &types.ExcludedRef{Field: types.Field{Name: "name"}}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "INSERT INTO `users` (`email`, `name`) VALUES (:email_val, :name_val) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_OnDuplicateKeyDoNothing(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -500,6 +560,45 @@ func TestRenderCompound_Union(t *testing.T) {
 	}
 }
 
+func TestRenderCompound_BareCompoundOperands(t *testing.T) {
+	r := NewWithOptions(Options{BareCompoundOperands: true})
+	limit := 5
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "users"},
+			Fields:    []types.Field{{Name: "id"}},
+		},
+		Operands: []types.SetOperand{
+			{
+				Operation: types.SetUnion,
+				AST: &types.AST{
+					Operation: types.OpSelect,
+					Target:    types.Table{Name: "admins"},
+					Fields:    []types.Field{{Name: "id"}},
+				},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "id"}, Direction: types.ASC}},
+	}
+
+	result, err := r.RenderCompound(query)
+	if err != nil {
+		t.Fatalf("RenderCompound() error = %v", err)
+	}
+
+	expected := "SELECT `id` FROM `users` UNION SELECT `id` FROM `admins` ORDER BY `id` ASC"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	// A per-operand LIMIT would bind to the whole compound when bare
+	query.Operands[0].AST.Limit = &types.PaginationValue{Static: &limit}
+	if _, err := r.RenderCompound(query); err == nil || !strings.Contains(err.Error(), "requires parenthesized operands") {
+		t.Errorf("RenderCompound() error = %v, want parenthesized operands error", err)
+	}
+}
+
 func TestRender_StringConcat(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -555,6 +654,32 @@ func TestRender_DateNow(t *testing.T) {
 	}
 }
 
+func TestRender_DateNowTz(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateNowTz,
+				},
+				Alias: "current_time",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT NOW() AS `current_time` FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_DateCurrentDate(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -662,6 +787,60 @@ func TestRender_ForUpdate(t *testing.T) {
 	}
 }
 
+func TestRender_ForUpdateOfUnsupported(t *testing.T) {
+	r := New()
+	lock := types.LockForUpdate
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Lock:      &lock,
+		LockOf:    []types.Table{{Name: "users"}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for FOR UPDATE OF, got nil")
+	}
+	if !strings.Contains(err.Error(), "FOR UPDATE OF") {
+		t.Errorf("error = %q, want to contain %q", err.Error(), "FOR UPDATE OF")
+	}
+}
+
+func TestRender_ForShareLockInShareMode(t *testing.T) {
+	r := New()
+	lock := types.LockForShare
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+		Lock:      &lock,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `id` FROM `users` LOCK IN SHARE MODE"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_RejectsForKeyShare(t *testing.T) {
+	r := New()
+	lock := types.LockForKeyShare
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Lock:      &lock,
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for FOR KEY SHARE, got nil")
+	}
+}
+
 func TestRender_ParameterizedLimit(t *testing.T) {
 	r := New()
 	pageSize := types.Param{Name: "page_size"}
@@ -1087,6 +1266,26 @@ func TestRender_Join(t *testing.T) {
 	}
 }
 
+func TestRender_NaturalJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Fields:    []types.Field{{Name: "name", Table: "u"}},
+		Joins:     []types.Join{{Type: types.NaturalJoin, Table: types.Table{Name: "profiles", Alias: "p"}}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT u.`name` FROM `users` u NATURAL JOIN `profiles` p"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_MathExpression(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -1297,6 +1496,44 @@ func TestRenderCompound_Except(t *testing.T) {
 	}
 }
 
+func TestRenderCompound_ExceptOrderByOutputColumn(t *testing.T) {
+	r := New()
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "users", Alias: "u"},
+			Fields:    []types.Field{{Name: "name", Table: "u"}},
+		},
+		Operands: []types.SetOperand{
+			{
+				Operation: types.SetExcept,
+				AST: &types.AST{
+					Operation: types.OpSelect,
+					Target:    types.Table{Name: "banned_users"},
+					Fields:    []types.Field{{Name: "name"}},
+				},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "name", Table: "u"}, Direction: types.ASC}},
+	}
+
+	result, err := r.RenderCompound(query)
+	if err != nil {
+		t.Fatalf("RenderCompound() error = %v", err)
+	}
+
+	// The qualifier is dropped: a set operation result has no table scope.
+	expected := "(SELECT u.`name` FROM `users` u) EXCEPT (SELECT `name` FROM `banned_users`) ORDER BY `name` ASC"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	query.Ordering[0].Field = types.Field{Name: "email"}
+	if _, err := r.RenderCompound(query); err == nil {
+		t.Error("expected error for ORDER BY a column the first query does not select")
+	}
+}
+
 func TestRenderCompound_WithOrderByLimit(t *testing.T) {
 	r := New()
 	limitVal := 10
@@ -2069,6 +2306,42 @@ func TestRender_HavingSum(t *testing.T) {
 	}
 }
 
+func TestRender_HavingAlias(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Aggregate: types.AggSum,
+				Field:     types.Field{Name: "total"},
+				Alias:     "order_total",
+			},
+		},
+		GroupBy: []types.Field{{Name: "user_id"}},
+		Having: []types.ConditionItem{
+			types.AggregateCondition{
+				Func:     types.AggSum,
+				Field:    &types.Field{Name: "total"},
+				Operator: types.GE,
+				Value:    types.Param{Name: "min_total"},
+				Alias:    "order_total",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `user_id`, SUM(`total`) AS `order_total` FROM `orders` GROUP BY `user_id` HAVING `order_total` >= :min_total"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_HavingAvg(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -2612,4 +2885,1003 @@ func TestCapabilities(t *testing.T) {
 	if caps.RowLocking != render.RowLockingBasic {
 		t.Errorf("RowLocking = %v, want RowLockingBasic", caps.RowLocking)
 	}
+	if caps.FullOuterJoin {
+		t.Error("FullOuterJoin should be false")
+	}
+	if !caps.RightJoin {
+		t.Error("RightJoin should be true")
+	}
+	if !caps.GroupConcatTruncates {
+		t.Error("GroupConcatTruncates should be true")
+	}
+}
+
+func TestRender_RejectsFullOuterJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Joins: []types.Join{
+			{
+				Type:  types.FullJoin,
+				Table: types.Table{Name: "profiles"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "users"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "profiles"},
+				},
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for FULL OUTER JOIN, got nil")
+	}
+	if !strings.Contains(err.Error(), "FULL OUTER JOIN") || !strings.Contains(err.Error(), "UNION") {
+		t.Errorf("error = %q, want FULL OUTER JOIN with a UNION hint", err.Error())
+	}
+}
+
+func TestRender_GroupByRollup(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupRollup,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `region`, `product` FROM `sales` GROUP BY `region`, `product` WITH ROLLUP"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_RejectsGroupByRollupWithOrderBy(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}},
+		GroupBy:     []types.Field{{Name: "region"}},
+		GroupByMode: types.GroupRollup,
+		Ordering:    []types.OrderBy{{Field: types.Field{Name: "region"}, Direction: types.ASC}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for WITH ROLLUP and ORDER BY, got nil")
+	}
+	if !strings.Contains(err.Error(), "WITH ROLLUP") {
+		t.Errorf("error = %q, want to contain 'WITH ROLLUP'", err.Error())
+	}
+}
+
+func TestRender_RejectsGroupByCubeAndGroupingSets(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"cube", &types.AST{
+			Operation:   types.OpSelect,
+			Target:      types.Table{Name: "sales"},
+			GroupBy:     []types.Field{{Name: "region"}},
+			GroupByMode: types.GroupCube,
+		}},
+		{"grouping sets", &types.AST{
+			Operation:    types.OpSelect,
+			Target:       types.Table{Name: "sales"},
+			GroupByMode:  types.GroupSets,
+			GroupingSets: [][]types.Field{{{Name: "region"}}, {}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Render(tt.ast)
+			if err == nil {
+				t.Fatal("expected unsupported feature error, got nil")
+			}
+			if !strings.Contains(err.Error(), string(tt.ast.GroupByMode)) {
+				t.Errorf("error = %q, want to contain %q", err.Error(), tt.ast.GroupByMode)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsGrouping(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{
+			{Grouping: &types.GroupingExpression{Fields: []types.Field{{Name: "region"}}}},
+		},
+		GroupBy: []types.Field{{Name: "region"}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for GROUPING(), got nil")
+	}
+	if !strings.Contains(err.Error(), "GROUPING()") {
+		t.Errorf("error = %q, want to contain 'GROUPING()'", err.Error())
+	}
+}
+
+func TestRender_RejectsLateralJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Joins: []types.Join{
+			{
+				Type:     types.CrossJoin,
+				Table:    types.Table{Alias: "x"},
+				Subquery: &types.Subquery{AST: &types.AST{Operation: types.OpSelect, Target: types.Table{Name: "orders"}}},
+				Lateral:  true,
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for LATERAL, got nil")
+	}
+	if !strings.Contains(err.Error(), "LATERAL") {
+		t.Errorf("error = %q, want to contain 'LATERAL'", err.Error())
+	}
+}
+
+func TestRender_AggregateFilterRewrittenAsCase(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Aggregate: types.AggCountDistinct,
+				Field:     types.Field{Name: "user_id"},
+				Filter:    types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "is_active"}},
+				Alias:     "engaged",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT COUNT(DISTINCT CASE WHEN `active` = :is_active THEN `user_id` END) AS `engaged` FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_TupleInSubquery(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.TupleCondition{
+			Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+			Operator: types.IN,
+			Subquery: &types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "members"},
+				Fields:    []types.Field{{Name: "user_id"}, {Name: "org_id"}},
+			}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT * FROM `users` WHERE (`id`, `org_id`) IN (SELECT `user_id`, `org_id` FROM `members`)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObject(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id", Field: &types.Field{Name: "id"}},
+						{Key: "name", Field: &types.Field{Name: "name"}},
+						{Key: "source", Param: &types.Param{Name: "source"}},
+					},
+				},
+				Alias: "payload",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT JSON_OBJECT('id', `id`, 'name', `name`, 'source', :source) AS `payload` FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObjectRejectsUnsafeKey(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id'); DROP TABLE users; --", Field: &types.Field{Name: "id"}},
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for unsafe JSON object key")
+	}
+}
+
+func TestRender_NotInSubqueryStripDistinct(t *testing.T) {
+	idField := types.Field{Name: "id"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.SubqueryCondition{
+			Field:    &idField,
+			Operator: types.NotIn,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "banned"},
+				Fields:    []types.Field{{Name: "user_id"}},
+				Distinct:  true,
+			}},
+		},
+	}
+
+	result, err := NewWithOptions(Options{StripInSubqueryDistinct: true}).Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT * FROM `users` WHERE `id` NOT IN (SELECT `user_id` FROM `banned`)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_NamedWindow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{Window: &types.WindowExpression{Function: types.WinRowNumber, WindowRef: "w"}, Alias: "rn"},
+			{Window: &types.WindowExpression{Aggregate: types.AggSum, Field: &types.Field{Name: "total"}, WindowRef: "w"}, Alias: "running"},
+		},
+		Windows: map[string]types.WindowSpec{
+			"w": {
+				PartitionBy: []types.Field{{Name: "user_id"}},
+				OrderBy:     []types.OrderBy{{Field: types.Field{Name: "created_at"}, Direction: types.ASC}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT ROW_NUMBER() OVER `w` AS `rn`, SUM(`total`) OVER `w` AS `running` FROM `orders` WINDOW `w` AS (PARTITION BY `user_id` ORDER BY `created_at` ASC)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_RejectsGroupsFrame(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggSum,
+					Field:     &types.Field{Name: "points"},
+					Window: types.WindowSpec{
+						OrderBy:    []types.OrderBy{{Field: types.Field{Name: "round"}, Direction: types.ASC}},
+						FrameUnit:  types.FrameGroups,
+						FrameStart: types.FrameUnboundedPreceding,
+						FrameEnd:   types.FrameCurrentRow,
+					},
+				},
+				Alias: "total",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for GROUPS frame, got nil")
+	}
+	if !strings.Contains(err.Error(), "GROUPS") {
+		t.Errorf("error = %q, want to contain %q", err.Error(), "GROUPS")
+	}
+}
+
+func TestRender_RejectsFrameExclude(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggSum,
+					Field:     &types.Field{Name: "points"},
+					Window: types.WindowSpec{
+						OrderBy:      []types.OrderBy{{Field: types.Field{Name: "round"}, Direction: types.ASC}},
+						FrameStart:   types.FrameUnboundedPreceding,
+						FrameEnd:     types.FrameCurrentRow,
+						FrameExclude: types.ExcludeGroup,
+					},
+				},
+				Alias: "total",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for frame EXCLUDE, got nil")
+	}
+	if !strings.Contains(err.Error(), "EXCLUDE") {
+		t.Errorf("error = %q, want to contain %q", err.Error(), "EXCLUDE")
+	}
+}
+
+func TestRender_NullSafeJoinCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "ledger", Alias: "a"},
+		Fields:    []types.Field{{Name: "id"}},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "bank", Alias: "b"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "ref", Table: "a"},
+					Operator:   types.IsNotDistinctFrom,
+					RightField: types.Field{Name: "ref", Table: "b"},
+				},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `id` FROM `ledger` a INNER JOIN `bank` b ON a.`ref` <=> b.`ref`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_IsDistinctFromParam(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "ledger"},
+		WhereClause: types.Condition{Field: types.Field{Name: "ref"}, Operator: types.IsDistinctFrom, Value: types.Param{Name: "ref"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT * FROM `ledger` WHERE NOT (`ref` <=> :ref)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_CastWithTypeParams(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "amount"}, CastType: types.CastNumeric, Precision: 10, Scale: 2},
+				Alias: "amt",
+			},
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "name"}, CastType: types.CastVarchar, Length: 50},
+				Alias: "short",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT CAST(`amount` AS DECIMAL(10,2)) AS `amt`, CAST(`name` AS CHAR(50)) AS `short` FROM `orders`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_QuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		Fields:    []types.Field{{Name: "id"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.OR,
+			Conditions: []types.ConditionItem{
+				types.Condition{Field: types.Field{Name: "starts_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+				types.Condition{Field: types.Field{Name: "kind"}, Operator: types.EQ, Value: types.Param{Name: "kind"}},
+				types.Condition{Field: types.Field{Name: "ends_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `id` FROM `events` WHERE (`starts_at` >= ? OR `kind` = ? OR `ends_at` >= ?)"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"since", "kind", "since"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("RequiredParams = %v, want 2 unique names", result.RequiredParams)
+	}
+}
+
+func TestRender_RejectsArrayConstructor(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{
+			name: "select",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "posts"},
+				FieldExpressions: []types.FieldExpression{{
+					Array: &types.ArrayExpression{Values: []types.Param{{Name: "a"}, {Name: "b"}}},
+				}},
+			},
+		},
+		{
+			name: "insert",
+			ast: &types.AST{
+				Operation: types.OpInsert,
+				Target:    types.Table{Name: "posts"},
+				Values:    []map[types.Field]types.Param{{{Name: "title"}: {Name: "title"}}},
+				ValueArrays: []map[types.Field]types.ArrayExpression{{
+					{Name: "tags"}: {Values: []types.Param{{Name: "tag"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Render(tt.ast)
+			if err == nil {
+				t.Fatal("expected error for ARRAY constructor, got nil")
+			}
+			if !strings.Contains(err.Error(), "ARRAY constructors") {
+				t.Errorf("error = %q, want to contain 'ARRAY constructors'", err.Error())
+			}
+		})
+	}
+}
+
+func TestRender_Truncate(t *testing.T) {
+	r := New()
+
+	result, err := r.Render(&types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, RestartIdentity: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "TRUNCATE TABLE `staging`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	_, err = r.Render(&types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, Cascade: true})
+	if err == nil || !strings.Contains(err.Error(), "TRUNCATE CASCADE") {
+		t.Errorf("error = %v, want TRUNCATE CASCADE unsupported", err)
+	}
+}
+
+func TestRender_WithTies(t *testing.T) {
+	limit := 3
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "score"}, Direction: types.DESC}},
+		Limit:     &types.PaginationValue{Static: &limit},
+		WithTies:  true,
+	}
+
+	_, err := New().Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || !strings.Contains(unsupported.Feature, "WITH TIES") {
+		t.Errorf("Render() error = %v, want WITH TIES UnsupportedFeatureError", err)
+	}
+	if New().Capabilities().WithTies {
+		t.Error("Capabilities().WithTies should be false")
+	}
+}
+
+func TestRender_RejectsOnConflictPredicate(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Name: "email_val"}},
+		},
+		OnConflict: &types.ConflictClause{
+			Columns:           []types.Field{{Name: "email"}},
+			ConflictPredicate: types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "active"}},
+			Action:            types.DoNothing,
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for ON CONFLICT predicate, got nil")
+	}
+	if !strings.Contains(err.Error(), "ON CONFLICT WHERE") {
+		t.Errorf("error = %q, want ON CONFLICT WHERE", err.Error())
+	}
+}
+
+func TestRender_StringPadReverseInitcap(t *testing.T) {
+	r := New()
+	padArgs := []types.Param{{Name: "width"}, {Name: "fill"}}
+	tests := []struct {
+		function types.StringFunc
+		args     []types.Param
+		want     string
+	}{
+		{types.StringLPad, padArgs, "SELECT LPAD(`name`, :width, :fill) AS `formatted` FROM `users`"},
+		{types.StringRPad, padArgs, "SELECT RPAD(`name`, :width, :fill) AS `formatted` FROM `users`"},
+		{types.StringReverse, nil, "SELECT REVERSE(`name`) AS `formatted` FROM `users`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						String: &types.StringExpression{
+							Function: tt.function,
+							Field:    types.Field{Name: "name"},
+							Args:     tt.args,
+						},
+						Alias: "formatted",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.want {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsInitcap(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{Function: types.StringInitcap, Field: types.Field{Name: "name"}},
+				Alias:  "title",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "INITCAP" {
+		t.Errorf("Render() error = %v, want INITCAP UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_ValuesTable(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target: types.Table{
+			Alias: "t",
+			Values: &types.ValuesTable{
+				Columns: []string{"id", "name"},
+				Rows: [][]types.Param{
+					{{Name: "id1"}, {Name: "name1"}},
+					{{Name: "id2"}, {Name: "name2"}},
+				},
+			},
+		},
+		Fields: []types.Field{{Name: "id", Table: "t"}, {Name: "name", Table: "t"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT t.`id`, t.`name` FROM (SELECT :id1 AS `id`, :name1 AS `name` UNION ALL SELECT :id2, :name2) AS t"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "id1,name1,id2,name2" {
+		t.Errorf("RequiredParams = %v, want [id1 name1 id2 name2]", result.RequiredParams)
+	}
+}
+
+func TestRender_StringPosition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringPosition,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "needle"}},
+				},
+				Alias: "at",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT POSITION(:needle IN `email`) AS `at` FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringSplitPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringSplitPart,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "delim"}, {Name: "part"}},
+				},
+				Alias: "domain",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT SUBSTRING_INDEX(SUBSTRING_INDEX(`email`, :delim, :part), :delim, -1) AS `domain` FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "delim,part" {
+		t.Errorf("RequiredParams = %v, want [delim part]", result.RequiredParams)
+	}
+}
+
+func TestRender_StringAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "team"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggStringAgg,
+					Field:     types.Field{Name: "name"},
+					Separator: &types.Param{Name: "sep"},
+					OrderBy:   []types.OrderBy{{Field: types.Field{Name: "name"}, Direction: types.ASC}},
+				},
+				Alias: "names",
+			},
+		},
+		GroupBy: []types.Field{{Name: "team"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT `team`, GROUP_CONCAT(`name` ORDER BY `name` ASC SEPARATOR :sep) AS `names` FROM `users` GROUP BY `team`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "sep" {
+		t.Errorf("RequiredParams = %v, want [sep]", result.RequiredParams)
+	}
+}
+
+func TestRender_RejectsArrayAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "posts"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{Function: types.AggArrayAgg, Field: types.Field{Name: "tag"}},
+				Alias:      "tags",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "ARRAY_AGG" {
+		t.Errorf("Render() error = %v, want ARRAY_AGG UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_DateAdd(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		function types.DateFunc
+		part     types.DatePart
+		expected string
+	}{
+		{"add days", types.DateAdd, types.PartDay, "SELECT DATE_ADD(`created_at`, INTERVAL :days DAY) AS `due` FROM `orders`"},
+		{"subtract days", types.DateSub, types.PartDay, "SELECT DATE_SUB(`created_at`, INTERVAL :days DAY) AS `due` FROM `orders`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						Date: &types.DateExpression{
+							Function: tt.function,
+							Field:    &types.Field{Name: "created_at"},
+							Part:     tt.part,
+							Amount:   &types.Param{Name: "days"},
+						},
+						Alias: "due",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "days" {
+				t.Errorf("RequiredParams = %v, want [days]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsUpdateSetRow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpUpdate,
+		Target:    types.Table{Name: "accounts"},
+		Updates:   map[types.Field]types.Param{{Name: "note"}: {Name: "note"}},
+		UpdateRows: []types.RowUpdate{{
+			Fields: []types.Field{{Name: "plan"}, {Name: "seats"}},
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "plans"},
+				Fields:      []types.Field{{Name: "name"}, {Name: "seats"}},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "plan_id"}},
+			}},
+		}},
+		WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "id"}},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "multi-column SET from a subquery" {
+		t.Errorf("error = %v, want multi-column SET unsupported", err)
+	}
+}
+
+func TestRender_DateDiff(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		part     types.DatePart
+		end      types.Value
+		expected string
+		param    string
+	}{
+		{"day", types.PartDay, types.Field{Name: "shipped_at"}, "SELECT TIMESTAMPDIFF(DAY, `created_at`, `shipped_at`) AS `elapsed` FROM `orders`", ""},
+		{"hour", types.PartHour, types.Param{Name: "now"}, "SELECT TIMESTAMPDIFF(HOUR, `created_at`, :now) AS `elapsed` FROM `orders`", "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						DateDiff: &types.DateDiffExpression{
+							End:   tt.end,
+							Start: types.Field{Name: "created_at"},
+							Part:  tt.part,
+						},
+						Alias: "elapsed",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.RequiredParams, ",") != tt.param {
+				t.Errorf("RequiredParams = %v, want [%s]", result.RequiredParams, tt.param)
+			}
+		})
+	}
+}
+
+func TestValidate_AgreesWithRender(t *testing.T) {
+	r := New()
+	users := types.Table{Name: "users"}
+	name := types.Field{Name: "name"}
+	age := types.Field{Name: "age"}
+	created := types.Field{Name: "created_at"}
+	selecting := func(expr types.FieldExpression) *types.AST {
+		return &types.AST{Operation: types.OpSelect, Target: users, FieldExpressions: []types.FieldExpression{expr}}
+	}
+
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"grouping", selecting(types.FieldExpression{Grouping: &types.GroupingExpression{Fields: []types.Field{name}}})},
+		{"with ties", &types.AST{Operation: types.OpSelect, Target: users, Ordering: []types.OrderBy{{Field: age, Direction: types.DESC}},
+			Limit: &types.PaginationValue{Param: &types.Param{Name: "n"}}, WithTies: true}},
+		{"array", selecting(types.FieldExpression{Array: &types.ArrayExpression{Values: []types.Param{{Name: "tag"}}}})},
+		{"initcap", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+		{"date bin", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateBin, Field: &created, Interval: &types.Param{Name: "width"}, Origin: &types.Param{Name: "origin"}}})},
+		{"date trunc week", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateTrunc, Field: &created, Part: types.PartWeek}})},
+		{"ignore nulls", selecting(types.FieldExpression{Window: &types.WindowExpression{
+			Function:      types.WinLag,
+			Field:         &age,
+			Window:        types.WindowSpec{OrderBy: []types.OrderBy{{Field: created, Direction: types.ASC}}},
+			NullTreatment: types.NullsIgnore,
+		}})},
+		{"array agg", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggArrayAgg, Field: name}})},
+		{"distinct on", &types.AST{Operation: types.OpSelect, Target: users, Fields: []types.Field{name}, DistinctOn: []types.Field{name}}},
+		{"nested in arithmetic", selecting(types.FieldExpression{Arithmetic: &types.ArithmeticExpression{
+			Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Date: &types.DateExpression{Function: types.DateBin, Field: &created, Interval: &types.Param{Name: "width"}, Origin: &types.Param{Name: "origin"}}}},
+			Operator: types.ArithAdd,
+			Right:    types.ArithmeticOperand{Param: &types.Param{Name: "n"}},
+		}})},
+		{"nested in subquery", &types.AST{
+			Operation: types.OpSelect,
+			Target:    users,
+			Fields:    []types.Field{name},
+			WhereClause: types.SubqueryCondition{
+				Field:    &name,
+				Operator: types.IN,
+				Subquery: types.Subquery{AST: selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := r.Validate(tt.ast)
+			if len(errs) == 0 {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			_, err := r.Render(tt.ast)
+			if err == nil || err.Error() != errs[0].Error() {
+				t.Errorf("Render() error = %v, want %v", err, errs[0])
+			}
+		})
+	}
+}
+
+func TestRender_SplitPartQuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{{
+			String: &types.StringExpression{
+				Function: types.StringSplitPart,
+				Field:    types.Field{Name: "email"},
+				Args:     []types.Param{{Name: "delim"}, {Name: "n"}},
+			},
+		}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT SUBSTRING_INDEX(SUBSTRING_INDEX(`email`, ?, ?), ?, -1) FROM `users`"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"delim", "n", "delim"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
 }
@@ -19,6 +19,8 @@ type renderContext struct {
 	paramCallback func(types.Param) string
 	paramPrefix   string
 	depth         int
+	selectOne     bool                        // Render the select list as 1 (EXISTS ignores it)
+	windows       map[string]types.WindowSpec // Named windows of the SELECT being rendered, expanded inline
 }
 
 // newRenderContext creates a new render context.
@@ -47,22 +49,81 @@ func (ctx *renderContext) withSubquery() (*renderContext, error) {
 
 // addParam adds a parameter with proper namespacing.
 func (ctx *renderContext) addParam(param types.Param) string {
-	if ctx.paramPrefix != "" {
+	if ctx.paramPrefix != "" && !param.IsLiteral() {
 		param = types.Param{Name: ctx.paramPrefix + param.Name}
 	}
 	return ctx.paramCallback(param)
 }
 
 // Renderer implements the SQL Server dialect renderer.
-type Renderer struct{}
+type Renderer struct {
+	opts           Options
+	funcs          render.Functions
+	defaultOrderBy *types.Field
+}
+
+func init() {
+	render.Register("mssql", func() render.Renderer { return New() })
+}
+
+// Options configures optional rendering behavior.
+type Options = render.Options
+
+// RenderOptions configures a single render call.
+type RenderOptions = render.RenderOptions
 
 // New creates a new SQL Server renderer.
 func New() *Renderer {
 	return &Renderer{}
 }
 
+// NewWithOptions creates a new SQL Server renderer with the given options.
+func NewWithOptions(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// WithDefaultOrderBy sets a fallback ORDER BY field, typically the primary
+// key, for paginated queries that have none. OFFSET/FETCH requires an ORDER
+// BY, so without a fallback such queries fail to render. The renderer is
+// changed in place and returned for chaining.
+func (r *Renderer) WithDefaultOrderBy(field types.Field) *Renderer {
+	r.defaultOrderBy = &field
+	return r
+}
+
+// offsetOrderFeature and offsetOrderHint describe a SELECT with OFFSET but no
+// ORDER BY, which collectAST reports and renderSelect would otherwise hit.
+const (
+	offsetOrderFeature = "OFFSET without ORDER BY"
+	offsetOrderHint    = "add ORDER BY clause when using OFFSET, or set WithDefaultOrderBy"
+)
+
+// writeDefaultOrderBy writes the fallback ORDER BY for an unordered
+// OFFSET/FETCH query, or returns the error Render reports without one.
+func (r *Renderer) writeDefaultOrderBy(sql *strings.Builder, feature, hint string) error {
+	if r.defaultOrderBy == nil {
+		return render.NewUnsupportedFeatureError("mssql", feature, hint)
+	}
+	sql.WriteString(" ORDER BY ")
+	sql.WriteString(r.renderField(*r.defaultOrderBy))
+	sql.WriteString(" ASC")
+	return nil
+}
+
+// RegisterFunction allows a custom scalar function, such as a stored function or
+// an extension function, to be called through astql.Func with exactly argCount
+// arguments. The name may be schema-qualified (ext.my_fn) and renders unquoted.
+func (r *Renderer) RegisterFunction(name string, argCount int) error {
+	return r.funcs.Register(name, argCount)
+}
+
 // Render converts an AST to a QueryResult with SQL Server SQL.
 func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
+	return r.RenderWithOptions(ast, RenderOptions{})
+}
+
+// RenderWithOptions converts an AST to a QueryResult, applying per-call options.
+func (r *Renderer) RenderWithOptions(ast *types.AST, opts RenderOptions) (*types.QueryResult, error) {
 	// Validate unsupported features
 	if err := r.validateAST(ast); err != nil {
 		return nil, err
@@ -71,18 +132,26 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 	if err := ast.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid AST: %w", err)
 	}
+	if r.opts.LowercaseIdentifiers {
+		ast = render.LowercaseIdentifiers(ast)
+	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
 
 	addParam := func(param types.Param) string {
-		placeholder := ":" + param.Name
-		if !usedParams[param.Name] {
-			params = append(params, param.Name)
-			usedParams[param.Name] = true
+		if param.IsLiteral() {
+			return r.renderLiteral(param.Literal)
 		}
-		return placeholder
+		return params.Placeholder(param.Name)
+	}
+
+	if opts.Explain != render.ExplainNone {
+		prefix, err := r.explainPrefix(opts.Explain)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(prefix)
 	}
 
 	ctx := newRenderContext(addParam)
@@ -108,18 +177,39 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 		if err := r.renderCount(ast, &sql, addParam); err != nil {
 			return nil, err
 		}
+	case types.OpTruncate:
+		r.renderTruncate(ast, &sql)
 	default:
 		return nil, fmt.Errorf("unsupported operation: %s", ast.Operation)
 	}
 
+	text := sql.String()
+	if opts.Pretty {
+		text = render.ApplyPretty(text)
+	}
+
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              render.ApplyKeywordCase(text, opts.KeywordCase),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        ast.Operation,
+		Tables:           ast.Tables(),
 	}, nil
 }
 
+// explainPrefix rejects EXPLAIN: SQL Server reports plans through session
+// settings rather than a statement prefix.
+func (r *Renderer) explainPrefix(_ render.ExplainMode) (string, error) {
+	return "", render.NewUnsupportedFeatureError("mssql", "EXPLAIN",
+		"run SET SHOWPLAN_XML ON (estimated) or SET STATISTICS XML ON (actual) in the session instead")
+}
+
 // RenderCompound converts a CompoundQuery to a QueryResult with SQL and parameters.
 func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error) {
+	if r.opts.LowercaseIdentifiers {
+		query = render.LowercaseCompoundIdentifiers(query)
+	}
+
 	// Validate each AST in the compound query
 	if err := r.validateAST(query.Base); err != nil {
 		return nil, err
@@ -131,42 +221,44 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
+
+	lparen, rparen, err := render.CompoundOperandParens(query, r.opts.BareCompoundOperands)
+	if err != nil {
+		return nil, err
+	}
 
 	queryIndex := 0
 
 	makeParamCallback := func(prefix string) func(types.Param) string {
 		return func(param types.Param) string {
-			name := prefix + param.Name
-			placeholder := ":" + name
-			if !usedParams[name] {
-				params = append(params, name)
-				usedParams[name] = true
+			if param.IsLiteral() {
+				return r.renderLiteral(param.Literal)
 			}
-			return placeholder
+			return params.Placeholder(prefix + param.Name)
 		}
 	}
 
-	// SQL Server supports parentheses around SELECT in compound queries
-	sql.WriteString("(")
+	// Operands are parenthesized unless Options.BareCompoundOperands is set
+	sql.WriteString(lparen)
 	baseCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
 	if err := r.renderSelect(query.Base, &sql, baseCtx); err != nil {
 		return nil, err
 	}
-	sql.WriteString(")")
+	sql.WriteString(rparen)
 	queryIndex++
 
 	for _, operand := range query.Operands {
 		sql.WriteString(" ")
 		sql.WriteString(string(operand.Operation))
-		sql.WriteString(" (")
+		sql.WriteString(" ")
+		sql.WriteString(lparen)
 
 		opCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
 		if err := r.renderSelect(operand.AST, &sql, opCtx); err != nil {
 			return nil, err
 		}
-		sql.WriteString(")")
+		sql.WriteString(rparen)
 		queryIndex++
 	}
 
@@ -178,15 +270,19 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 		var orderParts []string
 		for i := range query.Ordering {
 			order := &query.Ordering[i]
+			field, err := render.CompoundOrderField(query, order.Field)
+			if err != nil {
+				return nil, err
+			}
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
-					r.renderField(order.Field),
+					r.renderField(field),
 					r.renderOperator(order.Operator),
 					finalCtx.addParam(order.Param),
 					order.Direction)
 			} else {
-				part = fmt.Sprintf("%s %s", r.renderField(order.Field), order.Direction)
+				part = fmt.Sprintf("%s %s", r.renderField(field), order.Direction)
 			}
 			orderParts = append(orderParts, part)
 		}
@@ -197,8 +293,10 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	if query.Offset != nil || query.Limit != nil {
 		// OFFSET/FETCH requires ORDER BY
 		if len(query.Ordering) == 0 {
-			return nil, render.NewUnsupportedFeatureError("mssql", "LIMIT/OFFSET without ORDER BY",
-				"add ORDER BY clause when using LIMIT or OFFSET")
+			if err := r.writeDefaultOrderBy(&sql, "LIMIT/OFFSET without ORDER BY",
+				"add ORDER BY clause when using LIMIT or OFFSET, or set WithDefaultOrderBy"); err != nil {
+				return nil, err
+			}
 		}
 
 		sql.WriteString(" OFFSET ")
@@ -217,113 +315,212 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              sql.String(),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        types.OpSelect,
+		Tables:           query.Tables(),
 	}, nil
 }
 
-// validateAST checks for SQL Server-unsupported features.
+// Validate reports every SQL Server incompatibility in ast at once, in the
+// order Render would meet them, or nil when the AST renders on SQL Server.
+// Render stops at the first of these errors; Validate is the dry run that
+// lists them all. Structural problems are left to ast.Validate.
+func (r *Renderer) Validate(ast *types.AST) []error {
+	var errs []error
+	r.collectAST(ast, &errs)
+	return errs
+}
+
+// validateAST returns the first SQL Server-unsupported feature in ast.
 func (r *Renderer) validateAST(ast *types.AST) error {
+	if errs := r.Validate(ast); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// collectAST appends an error for each SQL Server-unsupported feature in ast.
+// Conditions, expressions, and window specs report their first problem.
+func (r *Renderer) collectAST(ast *types.AST, errs *[]error) {
+	if ast.ReturningCount {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "RETURNING count",
+			"read @@ROWCOUNT or the driver's rows-affected count instead"))
+	}
+
+	if ast.WithTies && ast.Offset != nil {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "WITH TIES with OFFSET",
+			"TOP (n) WITH TIES cannot skip rows; filter on RANK() in a derived table instead"))
+	}
+
+	if ast.Cascade {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "TRUNCATE CASCADE",
+			"truncate or delete from the referencing tables first"))
+	}
+
+	if len(ast.ValueArrays) > 0 {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "ARRAY constructors",
+			"pass the array as a single JSON param and use OPENJSON instead"))
+	}
+
 	if len(ast.DistinctOn) > 0 {
-		return render.NewUnsupportedFeatureError("mssql", "DISTINCT ON",
-			"use GROUP BY with aggregates or ROW_NUMBER() instead")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "DISTINCT ON",
+			"use GROUP BY with aggregates or ROW_NUMBER() instead"))
 	}
 
 	if ast.Lock != nil {
-		return render.NewUnsupportedFeatureError("mssql", "row-level locking syntax",
-			"use WITH (ROWLOCK, UPDLOCK) table hints instead")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "row-level locking syntax",
+			"use WITH (ROWLOCK, UPDLOCK) table hints instead"))
 	}
 
 	if ast.OnConflict != nil {
-		return render.NewUnsupportedFeatureError("mssql", "ON CONFLICT / upsert",
-			"use MERGE statement or separate INSERT/UPDATE with EXISTS check")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "ON CONFLICT / upsert",
+			"use MERGE statement or separate INSERT/UPDATE with EXISTS check"))
+	}
+
+	if ast.TargetSubquery != nil {
+		r.collectAST(ast.TargetSubquery, errs)
 	}
 
 	// Check for JSONB fields in all field locations
 	for _, field := range ast.Fields {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.FieldExpressions {
 		if err := r.validateFieldExpression(&ast.FieldExpressions[i]); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.GroupBy {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
+		}
+	}
+
+	for _, group := range ast.CoalescedGroupBy {
+		if err := r.checkJSONBField(group.Field); err != nil {
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.Ordering {
 		if err := r.checkJSONBField(ast.Ordering[i].Field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.Returning {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.Updates {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.UpdateExpressions {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 		exprCopy := ast.UpdateExpressions[field]
 		if err := r.validateFieldExpression(&exprCopy); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, valueSet := range ast.Values {
 		for field := range valueSet {
 			if err := r.checkJSONBField(field); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
 	}
 
+	for _, spec := range ast.Windows {
+		if err := r.validateWindowSpec(spec); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+
+	for _, cells := range ast.ValueSubqueries {
+		for _, sub := range cells {
+			r.collectAST(sub.AST, errs)
+		}
+	}
+
+	// OFFSET/FETCH requires ORDER BY; TOP (used for LIMIT WITH TIES) does not
+	if ast.Operation == types.OpSelect && ast.Offset != nil && len(ast.Ordering) == 0 &&
+		r.defaultOrderBy == nil && !(ast.Limit != nil && ast.WithTies) {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", offsetOrderFeature, offsetOrderHint))
+	}
+
+	if len(ast.UpdateRows) > 0 {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "multi-column SET from a subquery",
+			"use UPDATE ... FROM with a join to the subquery"))
+	}
+
 	// Check for unsupported operators and JSONB in conditions
 	if ast.WhereClause != nil {
 		if err := r.validateCondition(ast.WhereClause); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
-	for _, join := range ast.Joins {
+	for i, join := range ast.Joins {
+		if i > 0 && len(join.Using) > 0 {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "USING after another join",
+				"the column could come from any earlier table; use ON with qualified columns instead"))
+		}
+		if join.Lateral {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "LATERAL derived tables",
+				"use CROSS APPLY or OUTER APPLY instead"))
+		}
+		if join.Type == types.NaturalJoin {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("mssql", "NATURAL JOIN",
+				"use INNER JOIN with an explicit ON clause"))
+		}
 		if join.On != nil {
 			if err := r.validateCondition(join.On); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
+		if join.Subquery != nil && join.Subquery.AST != nil {
+			r.collectAST(join.Subquery.AST, errs)
+		}
 	}
 
 	for _, having := range ast.Having {
 		if err := r.validateCondition(having); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
-	return nil
 }
 
-// validateFieldExpression validates a field expression for JSONB fields.
+// validateFieldExpression validates a field expression for JSONB fields
+// and SQL Server-unsupported functions.
 func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
+	if err := r.validateFeatures(*expr); err != nil {
+		return err
+	}
 	if err := r.checkJSONBField(expr.Field); err != nil {
 		return err
 	}
 
+	if expr.Filter != nil {
+		if err := r.validateCondition(expr.Filter); err != nil {
+			return err
+		}
+	}
+
 	if expr.Binary != nil {
 		if err := r.checkJSONBField(expr.Binary.Field); err != nil {
 			return err
@@ -353,6 +550,16 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.DateDiff != nil {
+		for _, side := range []types.Value{expr.DateDiff.End, expr.DateDiff.Start} {
+			if f, ok := side.(types.Field); ok {
+				if err := r.checkJSONBField(f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	if expr.Cast != nil {
 		if err := r.checkJSONBField(expr.Cast.Field); err != nil {
 			return err
@@ -360,6 +567,9 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 	}
 
 	if expr.Window != nil {
+		if err := r.validateWindowSpec(expr.Window.Window); err != nil {
+			return err
+		}
 		if expr.Window.Field != nil {
 			if err := r.checkJSONBField(*expr.Window.Field); err != nil {
 				return err
@@ -377,6 +587,101 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.OrderedAgg != nil {
+		if err := r.checkJSONBField(expr.OrderedAgg.Field); err != nil {
+			return err
+		}
+		for i := range expr.OrderedAgg.OrderBy {
+			if err := r.checkJSONBField(expr.OrderedAgg.OrderBy[i].Field); err != nil {
+				return err
+			}
+		}
+	}
+
+	if expr.Arithmetic != nil {
+		for _, operand := range []types.ArithmeticOperand{expr.Arithmetic.Left, expr.Arithmetic.Right} {
+			if operand.Expr != nil {
+				if err := r.validateFieldExpression(operand.Expr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateWindowSpec checks a window frame for units the dialect cannot render.
+func (r *Renderer) validateWindowSpec(spec types.WindowSpec) error {
+	if spec.Unit() == types.FrameGroups {
+		return render.NewUnsupportedFeatureError("mssql", "GROUPS window frames",
+			"use a ROWS or RANGE frame instead")
+	}
+	if spec.ExcludesRows() {
+		return render.NewUnsupportedFeatureError("mssql", "window frame EXCLUDE",
+			"filter the excluded rows in an outer query instead")
+	}
+	if spec.Unit() == types.FrameRange && spec.HasFrameOffset() {
+		return render.NewUnsupportedFeatureError("mssql", "RANGE frames with an offset",
+			"SQL Server only allows UNBOUNDED and CURRENT ROW bounds in RANGE frames; use ROWS instead")
+	}
+	return nil
+}
+
+// validateFeatures returns the first SQL Server-unsupported function or clause
+// expr uses at its own level; nested arithmetic operands are reached through
+// validateFieldExpression. renderFieldExpression calls it too, so Validate
+// and Render reject the same expressions.
+func (r *Renderer) validateFeatures(expr types.FieldExpression) error {
+	switch {
+	case expr.String != nil:
+		switch expr.String.Function {
+		case types.StringInitcap:
+			return render.NewUnsupportedFeatureError("mssql", "INITCAP",
+				"capitalize in application code, or combine UPPER(LEFT(s, 1)) with LOWER(SUBSTRING(s, 2, LEN(s))) for single words")
+		case types.StringSplitPart:
+			return render.NewUnsupportedFeatureError("mssql", "SPLIT_PART",
+				"join CROSS APPLY STRING_SPLIT(s, delim, 1) and filter on its ordinal column (SQL Server 2022+)")
+		}
+	case expr.Date != nil:
+		return r.validateDateExpression(*expr.Date)
+	case expr.Array != nil:
+		return render.NewUnsupportedFeatureError("mssql", "ARRAY constructors",
+			"pass the array as a single JSON param and use OPENJSON instead")
+	case expr.JSONObject != nil:
+		return render.NewUnsupportedFeatureError("mssql", "JSON object construction",
+			"use FOR JSON PATH in a subquery instead")
+	case expr.OrderedAgg != nil:
+		if expr.OrderedAgg.Function == types.AggArrayAgg && !r.Capabilities().ArrayOperators {
+			return render.NewUnsupportedFeatureError("mssql", "ARRAY_AGG",
+				"use STRING_AGG for a delimited string, or FOR JSON PATH in a subquery for a JSON array")
+		}
+		if expr.OrderedAgg.Separator == nil {
+			return fmt.Errorf("%s requires a separator", expr.OrderedAgg.Function)
+		}
+	}
+	return nil
+}
+
+// validateDateExpression rejects DATE_BIN and the EXTRACT parts and
+// DATE_TRUNC precisions SQL Server has no equivalent for.
+func (r *Renderer) validateDateExpression(expr types.DateExpression) error {
+	switch expr.Function {
+	case types.DateExtract:
+		if r.datePartToMSSQL(expr.Part) == "" {
+			return render.NewUnsupportedFeatureError("mssql", fmt.Sprintf("EXTRACT %s", expr.Part),
+				"use DATEPART with appropriate part name")
+		}
+	case types.DateTrunc:
+		switch expr.Part {
+		case types.PartDay, types.PartMonth, types.PartYear:
+		default:
+			return render.NewUnsupportedFeatureError("mssql", fmt.Sprintf("DATE_TRUNC with %s precision", expr.Part),
+				"use DATEADD/DATEDIFF or DATEFROMPARTS for date truncation")
+		}
+	case types.DateBin:
+		return render.NewUnsupportedFeatureError("mssql", "DATE_BIN",
+			"use DATE_BUCKET (SQL Server 2022+) or DATEADD/DATEDIFF on a fixed origin")
+	}
 	return nil
 }
 
@@ -402,6 +707,18 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			return err
 		}
 		return r.validateOperator(c.Operator)
+	case types.Comparison:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
+		switch v := c.Value.(type) {
+		case types.Field:
+			return r.checkJSONBField(v)
+		case types.Subquery:
+			if v.AST != nil {
+				return r.validateAST(v.AST)
+			}
+		}
 	case types.SubqueryCondition:
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
@@ -416,6 +733,14 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 				return err
 			}
 		}
+	case types.TupleCondition:
+		// Value tuples are expanded into per-column conditions at render time.
+		expanded, ok := c.Expand()
+		if !ok {
+			return render.NewUnsupportedFeatureError("mssql", "row value (tuple) subquery comparisons",
+				"compare each column separately or use EXISTS with a correlated subquery")
+		}
+		return r.validateCondition(expanded)
 	case types.AggregateCondition:
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
@@ -423,6 +748,10 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			}
 		}
 		return r.validateOperator(c.Operator)
+	case types.InListCondition:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
 	case types.BetweenCondition:
 		if err := r.checkJSONBField(c.Field); err != nil {
 			return err
@@ -451,13 +780,28 @@ func (r *Renderer) validateOperator(op types.Operator) error {
 }
 
 func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	ctx.windows = ast.Windows
 	sql.WriteString("SELECT ")
 
 	if ast.Distinct {
 		sql.WriteString("DISTINCT ")
 	}
 
-	if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
+	// A limit without an offset renders as TOP, which needs no ORDER BY;
+	// OFFSET/FETCH is kept for offsets. WITH TIES only exists on TOP.
+	useTop := ast.Limit != nil && (ast.Offset == nil || ast.WithTies)
+	if useTop {
+		sql.WriteString("TOP (")
+		sql.WriteString(r.renderPaginationValue(ast.Limit, ctx))
+		sql.WriteString(") ")
+		if ast.WithTies {
+			sql.WriteString("WITH TIES ")
+		}
+	}
+
+	if ctx.selectOne {
+		sql.WriteString("1")
+	} else if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
 		sql.WriteString("*")
 	} else {
 		var selections []string
@@ -481,18 +825,13 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, fromRef(ast), sql, ctx); err != nil {
+			return err
 		}
 	}
 
@@ -503,13 +842,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		}
 	}
 
-	if len(ast.GroupBy) > 0 {
+	if ast.HasGroupBy() {
 		sql.WriteString(" GROUP BY ")
-		var groupFields []string
-		for _, field := range ast.GroupBy {
-			groupFields = append(groupFields, r.renderField(field))
-		}
-		sql.WriteString(strings.Join(groupFields, ", "))
+		sql.WriteString(r.renderGroupBy(ast, ctx))
 	}
 
 	if len(ast.Having) > 0 {
@@ -546,19 +881,16 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	// SQL Server uses OFFSET/FETCH instead of LIMIT/OFFSET
-	if ast.Offset != nil || ast.Limit != nil {
+	if !useTop && ast.Offset != nil {
 		// OFFSET/FETCH requires ORDER BY
 		if len(ast.Ordering) == 0 {
-			return render.NewUnsupportedFeatureError("mssql", "LIMIT/OFFSET without ORDER BY",
-				"add ORDER BY clause when using LIMIT or OFFSET")
+			if err := r.writeDefaultOrderBy(sql, offsetOrderFeature, offsetOrderHint); err != nil {
+				return err
+			}
 		}
 
 		sql.WriteString(" OFFSET ")
-		if ast.Offset != nil {
-			sql.WriteString(r.renderPaginationValue(ast.Offset, ctx))
-		} else {
-			sql.WriteString("0")
-		}
+		sql.WriteString(r.renderPaginationValue(ast.Offset, ctx))
 		sql.WriteString(" ROWS")
 
 		if ast.Limit != nil {
@@ -571,6 +903,63 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	return nil
 }
 
+// renderGroupBy renders the GROUP BY list, including ROLLUP, CUBE and GROUPING SETS.
+func (r *Renderer) renderGroupBy(ast *types.AST, ctx *renderContext) string {
+	renderList := func(fields []types.Field) string {
+		parts := make([]string, 0, len(fields))
+		for _, field := range fields {
+			parts = append(parts, r.renderField(field))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	switch ast.GroupByMode {
+	case types.GroupRollup, types.GroupCube:
+		return fmt.Sprintf("%s(%s)", ast.GroupByMode, renderList(ast.GroupBy))
+	case types.GroupSets:
+		sets := make([]string, 0, len(ast.GroupingSets))
+		for _, set := range ast.GroupingSets {
+			sets = append(sets, "("+renderList(set)+")")
+		}
+		return fmt.Sprintf("GROUPING SETS(%s)", strings.Join(sets, ", "))
+	default:
+		list := renderList(ast.GroupByFields())
+		if coalesced := r.renderCoalescedGroups(ast, ctx); coalesced != "" {
+			if list == "" {
+				return coalesced
+			}
+			return list + ", " + coalesced
+		}
+		return list
+	}
+}
+
+// renderCoalescedGroups renders COALESCE(field, :sentinel) GROUP BY entries.
+func (r *Renderer) renderCoalescedGroups(ast *types.AST, ctx *renderContext) string {
+	parts := make([]string, 0, len(ast.CoalescedGroupBy))
+	for _, group := range ast.CoalescedGroupBy {
+		parts = append(parts, fmt.Sprintf("COALESCE(%s, %s)", r.renderField(group.Field), ctx.addParam(group.Sentinel)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderLiteral renders a literal value inline. SQL Server BIT columns take 1 and 0.
+func (r *Renderer) renderLiteral(lit types.Literal) string {
+	switch lit {
+	case types.LiteralTrue:
+		return "1"
+	case types.LiteralFalse:
+		return "0"
+	case types.LiteralOne:
+		return "1"
+	case types.LiteralDefault:
+		return "DEFAULT"
+	default:
+		// Unreachable: AST validation rejects unknown literals.
+		return "NULL"
+	}
+}
+
 func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
 	sql.WriteString("INSERT INTO ")
 	sql.WriteString(r.renderTable(ast.Target))
@@ -579,15 +968,9 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		return fmt.Errorf("INSERT requires at least one value set")
 	}
 
-	fields := make([]string, 0, len(ast.Values[0]))
-	fieldObjs := make([]types.Field, 0, len(ast.Values[0]))
-	for field := range ast.Values[0] {
-		fieldObjs = append(fieldObjs, field)
-	}
-
-	sort.Slice(fieldObjs, func(i, j int) bool {
-		return fieldObjs[i].Name < fieldObjs[j].Name
-	})
+	// Columns sorted by name for deterministic output, including subquery cells
+	fieldObjs := ast.InsertColumns()
+	fields := make([]string, 0, len(fieldObjs))
 
 	for _, field := range fieldObjs {
 		fields = append(fields, r.quoteIdentifier(field.Name))
@@ -614,11 +997,19 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 	}
 
 	valueSets := make([]string, 0, len(ast.Values))
-	for _, valueSet := range ast.Values {
+	ctx := newRenderContext(addParam)
+	for i, valueSet := range ast.Values {
 		var values []string
 		for _, field := range fieldObjs {
-			param := valueSet[field]
-			values = append(values, addParam(param))
+			if sub, ok := ast.ValueSubquery(i, field); ok {
+				var subSQL strings.Builder
+				if err := r.renderSubquery(sub, &subSQL, ctx); err != nil {
+					return err
+				}
+				values = append(values, "("+subSQL.String()+")")
+				continue
+			}
+			values = append(values, addParam(valueSet[field]))
 		}
 		valueSets = append(valueSets, "("+strings.Join(values, ", ")+")")
 	}
@@ -713,27 +1104,28 @@ func (r *Renderer) renderDelete(ast *types.AST, sql *strings.Builder, addParam f
 	return nil
 }
 
+// renderTruncate renders TRUNCATE TABLE. It always resets the table's
+// identity seed, so RestartIdentity adds nothing to the SQL.
+func (r *Renderer) renderTruncate(ast *types.AST, sql *strings.Builder) {
+	sql.WriteString("TRUNCATE TABLE ")
+	sql.WriteString(r.quoteIdentifier(ast.Target.Name))
+}
+
 func (r *Renderer) renderCount(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
+	ctx := newRenderContext(addParam)
 	sql.WriteString("SELECT " + countStarSQL + " FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			ctx := newRenderContext(addParam)
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, fromRef(ast), sql, ctx); err != nil {
+			return err
 		}
 	}
 
 	if ast.WhereClause != nil {
 		sql.WriteString(" WHERE ")
-		ctx := newRenderContext(addParam)
 		if err := r.renderCondition(ast.WhereClause, sql, ctx); err != nil {
 			return err
 		}
@@ -756,6 +1148,115 @@ func (r *Renderer) renderTable(table types.Table) string {
 	return quotedName
 }
 
+// renderFrom renders the FROM target: a table or a derived table with its alias.
+func (r *Renderer) renderFrom(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	if ast.Target.Values != nil {
+		sql.WriteString(r.renderValuesTable(ast.Target, ctx))
+		return nil
+	}
+	if ast.TargetSubquery == nil {
+		sql.WriteString(r.renderTable(ast.Target))
+		return nil
+	}
+	sql.WriteString("(")
+	if err := r.renderSubquery(types.Subquery{AST: ast.TargetSubquery}, sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(") AS ")
+	sql.WriteString(ast.TargetAlias)
+	return nil
+}
+
+// renderValuesTable renders an inline VALUES source with its alias and
+// column list.
+func (r *Renderer) renderValuesTable(table types.Table, ctx *renderContext) string {
+	rows := make([]string, 0, len(table.Values.Rows))
+	for _, row := range table.Values.Rows {
+		values := make([]string, 0, len(row))
+		for _, value := range row {
+			values = append(values, ctx.addParam(value))
+		}
+		rows = append(rows, "("+strings.Join(values, ", ")+")")
+	}
+	columns := make([]string, 0, len(table.Values.Columns))
+	for _, col := range table.Values.Columns {
+		columns = append(columns, r.quoteIdentifier(col))
+	}
+	return "(VALUES " + strings.Join(rows, ", ") + ") AS " + table.Alias + "(" + strings.Join(columns, ", ") + ")"
+}
+
+// renderJoin renders a single JOIN clause against a table or derived table.
+// left names the FROM target, used to translate USING into ON.
+func (r *Renderer) renderJoin(join types.Join, left string, sql *strings.Builder, ctx *renderContext) error {
+	sql.WriteString(" ")
+	sql.WriteString(string(join.Type))
+	sql.WriteString(" ")
+	if join.Lateral {
+		sql.WriteString("LATERAL ")
+	}
+	if join.Subquery != nil {
+		sql.WriteString("(")
+		if err := r.renderSubquery(*join.Subquery, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(") AS ")
+		sql.WriteString(join.Table.Alias)
+		if len(join.Columns) > 0 {
+			columns := make([]string, 0, len(join.Columns))
+			for _, col := range join.Columns {
+				columns = append(columns, r.quoteIdentifier(col))
+			}
+			sql.WriteString("(" + strings.Join(columns, ", ") + ")")
+		}
+	} else if join.Table.Values != nil {
+		sql.WriteString(r.renderValuesTable(join.Table, ctx))
+	} else {
+		sql.WriteString(r.renderTable(join.Table))
+	}
+
+	// SQL Server has no USING; translate it to ON left.col = right.col.
+	// CROSS JOIN and NATURAL JOIN have neither.
+	if len(join.Using) > 0 {
+		sql.WriteString(" ON ")
+		sql.WriteString(r.renderUsingAsOn(join, left))
+	} else if join.Type.RequiresOn() {
+		sql.WriteString(" ON ")
+		if err := r.renderCondition(join.On, sql, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderUsingAsOn renders a USING column list as an equivalent ON condition,
+// comparing each column of the FROM target with the joined table. Validation
+// allows USING only on the first join, where the target is the sole source.
+func (r *Renderer) renderUsingAsOn(join types.Join, left string) string {
+	right := join.Table.Alias
+	if right == "" {
+		right = join.Table.Name
+	}
+	parts := make([]string, 0, len(join.Using))
+	for _, field := range join.Using {
+		parts = append(parts, fmt.Sprintf("%s = %s",
+			r.renderField(types.Field{Name: field.Name, Table: left}),
+			r.renderField(types.Field{Name: field.Name, Table: right})))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// fromRef returns the name the FROM item is referenced by: its alias, or its
+// table name when unaliased.
+func fromRef(ast *types.AST) string {
+	if ast.TargetSubquery != nil {
+		return ast.TargetAlias
+	}
+	if ast.Target.Alias != "" {
+		return ast.Target.Alias
+	}
+	return ast.Target.Name
+}
+
 func (r *Renderer) renderField(field types.Field) string {
 	quotedName := r.quoteIdentifier(field.Name)
 	if field.Table != "" {
@@ -766,7 +1267,7 @@ func (r *Renderer) renderField(field types.Field) string {
 
 // checkJSONBField returns an error if the field uses JSONB access operators.
 func (r *Renderer) checkJSONBField(field types.Field) error {
-	if field.JSONBTextKey != nil || field.JSONBPathKey != nil {
+	if field.HasJSONAccess() {
 		return render.NewUnsupportedFeatureError("mssql", "JSONB field access operators",
 			"use JSON_VALUE() or JSON_QUERY() instead")
 	}
@@ -806,7 +1307,36 @@ func (r *Renderer) renderAggregateExpression(aggregate types.AggregateFunc, fiel
 	}
 }
 
+// renderFilteredAggregate renders AGG(x) FILTER (WHERE cond) as AGG(CASE WHEN cond THEN x END).
+// Rows failing the condition yield NULL, which every aggregate ignores.
+func (r *Renderer) renderFilteredAggregate(aggregate types.AggregateFunc, field types.Field, filter types.ConditionItem, ctx *renderContext) (string, error) {
+	var cond strings.Builder
+	if err := r.renderCondition(filter, &cond, ctx); err != nil {
+		return "", err
+	}
+
+	// COUNT(*) has no field; count a constant for matching rows instead
+	value := "1"
+	if field.Name != "" {
+		value = r.renderField(field)
+	}
+	caseExpr := fmt.Sprintf("CASE WHEN %s THEN %s END", cond.String(), value)
+
+	switch aggregate {
+	case types.AggCountField:
+		return fmt.Sprintf("COUNT(%s)", caseExpr), nil
+	case types.AggCountDistinct:
+		return fmt.Sprintf("COUNT(DISTINCT %s)", caseExpr), nil
+	default:
+		return fmt.Sprintf("%s(%s)", aggregate, caseExpr), nil
+	}
+}
+
 func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *renderContext) (string, error) {
+	if err := r.validateFeatures(expr); err != nil {
+		return "", err
+	}
+
 	var result string
 
 	switch {
@@ -846,8 +1376,17 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 			return "", err
 		}
 		result = dateStr
+	case expr.DateDiff != nil:
+		diffStr, err := r.renderDateDiff(*expr.DateDiff, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = diffStr
 	case expr.Cast != nil:
-		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.mapCastType(expr.Cast.CastType))
+		if err := expr.Cast.Validate(); err != nil {
+			return "", err
+		}
+		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.renderCastType(*expr.Cast))
 	case expr.Window != nil:
 		windowStr, err := r.renderWindowExpression(*expr.Window, ctx)
 		if err != nil {
@@ -867,12 +1406,51 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 		paramStr := ctx.addParam(expr.Binary.Param)
 		opStr := r.renderOperator(expr.Binary.Operator)
 		result = fmt.Sprintf("%s %s %s", r.renderField(expr.Binary.Field), opStr, paramStr)
+	case expr.Grouping != nil:
+		// SQL Server's GROUPING() takes a single column; GROUPING_ID() is the
+		// multi-column equivalent with the same bitmask semantics.
+		groupFields := make([]string, 0, len(expr.Grouping.Fields))
+		for _, field := range expr.Grouping.Fields {
+			if err := r.checkJSONBField(field); err != nil {
+				return "", err
+			}
+			groupFields = append(groupFields, r.renderField(field))
+		}
+		fn := "GROUPING"
+		if len(groupFields) > 1 {
+			fn = "GROUPING_ID"
+		}
+		result = fmt.Sprintf("%s(%s)", fn, strings.Join(groupFields, ", "))
+	case expr.Arithmetic != nil:
+		// Render arithmetic between expressions
+		arithStr, err := r.renderArithmetic(*expr.Arithmetic, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = arithStr
+	case expr.Func != nil:
+		// Render a registered custom function
+		funcStr, err := r.renderFunc(*expr.Func, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = funcStr
+	case expr.OrderedAgg != nil:
+		aggStr, err := r.renderOrderedAggregate(*expr.OrderedAgg, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = aggStr
 	case expr.Aggregate != "":
-		result = r.renderAggregateExpression(expr.Aggregate, expr.Field)
 		if expr.Filter != nil {
-			// SQL Server doesn't support FILTER clause
-			return "", render.NewUnsupportedFeatureError("mssql", "FILTER clause on aggregates",
-				"use CASE WHEN inside the aggregate instead")
+			// No native FILTER clause: rewrite as AGG(CASE WHEN cond THEN x END)
+			filtered, err := r.renderFilteredAggregate(expr.Aggregate, expr.Field, expr.Filter, ctx)
+			if err != nil {
+				return "", err
+			}
+			result = filtered
+		} else {
+			result = r.renderAggregateExpression(expr.Aggregate, expr.Field)
 		}
 	default:
 		result = r.renderField(expr.Field)
@@ -885,6 +1463,85 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 	return result, nil
 }
 
+// renderOrderedAggregate renders STRING_AGG(field, :sep), with ordering in
+// a WITHIN GROUP clause since SQL Server takes no ORDER BY inside the call.
+func (r *Renderer) renderOrderedAggregate(expr types.OrderedAggregate, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	sql.WriteString("STRING_AGG(")
+	sql.WriteString(r.renderField(expr.Field))
+	sql.WriteString(", ")
+	sql.WriteString(ctx.addParam(*expr.Separator))
+	sql.WriteString(")")
+	if len(expr.OrderBy) > 0 {
+		var orderParts []string
+		for i := range expr.OrderBy {
+			order := &expr.OrderBy[i]
+			var part string
+			if order.Operator != "" {
+				part = fmt.Sprintf("%s %s %s %s",
+					r.renderField(order.Field),
+					r.renderOperator(order.Operator),
+					ctx.addParam(order.Param),
+					order.Direction)
+			} else {
+				part = fmt.Sprintf("%s %s", r.renderField(order.Field), order.Direction)
+			}
+			orderParts = append(orderParts, part)
+		}
+		sql.WriteString(" WITHIN GROUP (ORDER BY ")
+		sql.WriteString(strings.Join(orderParts, ", "))
+		sql.WriteString(")")
+	}
+	return sql.String(), nil
+}
+
+// renderArithmetic renders left <op> right. Nested arithmetic operands are
+// parenthesized so the expression tree's grouping is preserved.
+func (r *Renderer) renderArithmetic(expr types.ArithmeticExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	left, err := r.renderArithmeticOperand(expr.Left, ctx)
+	if err != nil {
+		return "", err
+	}
+	right, err := r.renderArithmeticOperand(expr.Right, ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, expr.Operator, right), nil
+}
+
+func (r *Renderer) renderArithmeticOperand(operand types.ArithmeticOperand, ctx *renderContext) (string, error) {
+	if operand.Param != nil {
+		return ctx.addParam(*operand.Param), nil
+	}
+	result, err := r.renderFieldExpression(*operand.Expr, ctx)
+	if err != nil {
+		return "", err
+	}
+	if operand.Expr.Arithmetic != nil {
+		result = "(" + result + ")"
+	}
+	return result, nil
+}
+
+// renderCastType renders the SQL Server cast target with its type parameters.
+// VARCHAR maps to NVARCHAR, with MAX when no length is given.
+func (r *Renderer) renderCastType(cast types.CastExpression) string {
+	if cast.CastType == types.CastVarchar {
+		if cast.Length > 0 {
+			return fmt.Sprintf("NVARCHAR(%d)", cast.Length)
+		}
+		return "NVARCHAR(MAX)"
+	}
+	return r.mapCastType(cast.CastType) + cast.TypeParams()
+}
+
 // mapCastType maps PostgreSQL cast types to SQL Server equivalents.
 func (r *Renderer) mapCastType(castType types.CastType) string {
 	switch castType {
@@ -911,6 +1568,23 @@ func (r *Renderer) mapCastType(castType types.CastType) string {
 	}
 }
 
+// renderFunc renders a call to a custom function registered with RegisterFunction.
+func (r *Renderer) renderFunc(expr types.FuncExpression, ctx *renderContext) (string, error) {
+	if err := r.funcs.Check("mssql", expr); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Args))
+	for _, arg := range expr.Args {
+		if arg.Field != nil {
+			args = append(args, r.renderField(*arg.Field))
+		} else {
+			args = append(args, ctx.addParam(*arg.Param))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", expr.Name, strings.Join(args, ", ")), nil
+}
+
 func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builder, ctx *renderContext) error {
 	switch c := cond.(type) {
 	case types.Condition:
@@ -930,27 +1604,69 @@ func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builde
 		}
 		sql.WriteString(")")
 	case types.FieldComparison:
-		fmt.Fprintf(sql, "%s %s %s",
-			r.renderField(c.LeftField),
-			r.renderOperator(c.Operator),
-			r.renderField(c.RightField))
+		sql.WriteString(r.renderComparison(r.renderField(c.LeftField), c.Operator, r.renderField(c.RightField)))
+	case types.Comparison:
+		if err := r.renderValueComparison(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.SubqueryCondition:
 		if err := r.renderSubqueryCondition(c, sql, ctx); err != nil {
 			return err
 		}
+	case types.InListCondition:
+		if err := r.renderInListCondition(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.AggregateCondition:
 		sql.WriteString(r.renderAggregateCondition(c, ctx.addParam))
 	case types.BetweenCondition:
 		sql.WriteString(r.renderBetweenCondition(c, ctx.addParam))
+	case types.TupleCondition:
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		expanded, ok := c.Expand()
+		if !ok {
+			return render.NewUnsupportedFeatureError("mssql", "row value (tuple) subquery comparisons",
+				"compare each column separately or use EXISTS with a correlated subquery")
+		}
+		return r.renderCondition(expanded, sql, ctx)
 	default:
 		return fmt.Errorf("unknown condition type: %T", c)
 	}
 	return nil
 }
 
+// renderInListCondition renders field IN (:a, :b). An empty list renders a
+// constant guard predicate unless StrictEmptyIn is set.
+func (r *Renderer) renderInListCondition(cond types.InListCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	if len(cond.Values) == 0 {
+		guard, err := render.EmptyInGuard(cond, r.opts.StrictEmptyIn)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(guard)
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		placeholders = append(placeholders, ctx.addParam(value))
+	}
+	if cond.ValuesTable {
+		// VALUES is only a derived table in SQL Server, so select from it
+		fmt.Fprintf(sql, "%s %s (SELECT [v] FROM (%s) AS [vals]([v]))", r.renderField(cond.Field), cond.Operator, render.ValuesRows(placeholders))
+		return nil
+	}
+	fmt.Fprintf(sql, "%s %s (%s)", r.renderField(cond.Field), cond.Operator, strings.Join(placeholders, ", "))
+	return nil
+}
+
 func (r *Renderer) renderSimpleCondition(cond types.Condition, addParam func(types.Param) string) string {
 	field := r.renderField(cond.Field)
-	op := r.renderOperator(cond.Operator)
 
 	switch cond.Operator {
 	case types.IsNull:
@@ -962,7 +1678,20 @@ func (r *Renderer) renderSimpleCondition(cond types.Condition, addParam func(typ
 	case types.NotIn:
 		return fmt.Sprintf("%s NOT IN (%s)", field, addParam(cond.Value))
 	default:
-		return fmt.Sprintf("%s %s %s", field, op, addParam(cond.Value))
+		return r.renderComparison(field, cond.Operator, addParam(cond.Value))
+	}
+}
+
+// renderComparison renders left op right. NULL-safe comparisons are emulated with
+// EXISTS over INTERSECT, which treats NULLs as equal and never yields UNKNOWN.
+func (r *Renderer) renderComparison(left string, op types.Operator, right string) string {
+	switch op {
+	case types.IsNotDistinctFrom:
+		return fmt.Sprintf("EXISTS (SELECT %s INTERSECT SELECT %s)", left, right)
+	case types.IsDistinctFrom:
+		return fmt.Sprintf("NOT EXISTS (SELECT %s INTERSECT SELECT %s)", left, right)
+	default:
+		return fmt.Sprintf("%s %s %s", left, r.renderOperator(op), right)
 	}
 }
 
@@ -1022,6 +1751,39 @@ func (r *Renderer) renderBetweenCondition(cond types.BetweenCondition, addParam
 	return fmt.Sprintf("%s %s %s AND %s", field, op, addParam(cond.Low), addParam(cond.High))
 }
 
+// renderValueComparison renders field op value for a param, field, or
+// scalar subquery on the right-hand side.
+func (r *Renderer) renderValueComparison(cond types.Comparison, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	var right strings.Builder
+	if err := r.renderValue(cond.Value, &right, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(r.renderComparison(r.renderField(cond.Field), cond.Operator, right.String()))
+	return nil
+}
+
+// renderValue renders the right-hand side of a Comparison.
+func (r *Renderer) renderValue(value types.Value, sql *strings.Builder, ctx *renderContext) error {
+	switch v := value.(type) {
+	case types.Param:
+		sql.WriteString(ctx.addParam(v))
+	case types.Field:
+		sql.WriteString(r.renderField(v))
+	case types.Subquery:
+		sql.WriteString("(")
+		if err := r.renderSubquery(v, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(")")
+	default:
+		return fmt.Errorf("unknown value type: %T", v)
+	}
+	return nil
+}
+
 func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *strings.Builder, ctx *renderContext) error {
 	switch cond.Operator {
 	case types.EXISTS, types.NotExists:
@@ -1033,12 +1795,26 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 		}
 		sql.WriteString(r.renderField(*cond.Field))
 		sql.WriteString(" ")
-		sql.WriteString(string(cond.Operator))
+		if cond.Quantifier != types.QuantNone {
+			// Quantified comparison: field op ANY|ALL (subquery)
+			if !cond.Operator.IsComparison() {
+				return fmt.Errorf("operator %s cannot be used with %s", cond.Operator, cond.Quantifier)
+			}
+			sql.WriteString(r.renderOperator(cond.Operator))
+			sql.WriteString(" ")
+			sql.WriteString(string(cond.Quantifier))
+		} else {
+			sql.WriteString(string(cond.Operator))
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("(")
-	if err := r.renderSubquery(cond.Subquery, sql, ctx); err != nil {
+	if cond.Operator == types.EXISTS || cond.Operator == types.NotExists {
+		if err := r.renderExistsSubquery(cond.Subquery, sql, ctx); err != nil {
+			return err
+		}
+	} else if err := r.renderSubquery(r.inSubquery(cond.Operator, cond.Subquery), sql, ctx); err != nil {
 		return err
 	}
 	sql.WriteString(")")
@@ -1046,6 +1822,26 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 	return nil
 }
 
+// renderExistsSubquery renders an EXISTS subquery as SELECT 1 without a redundant LIMIT 1.
+func (r *Renderer) renderExistsSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
+	subCtx, err := ctx.withSubquery()
+	if err != nil {
+		return err
+	}
+
+	ast, selectOne := types.ExistsSubquery(subquery.AST)
+	subCtx.selectOne = selectOne
+	return r.renderSelect(ast, sql, subCtx)
+}
+
+// inSubquery applies the StripInSubqueryDistinct option to IN/NOT IN subqueries.
+func (r *Renderer) inSubquery(op types.Operator, subquery types.Subquery) types.Subquery {
+	if !r.opts.StripInSubqueryDistinct || (op != types.IN && op != types.NotIn) {
+		return subquery
+	}
+	return types.Subquery{AST: types.StripDistinct(subquery.AST)}
+}
+
 func (r *Renderer) renderSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
 	subCtx, err := ctx.withSubquery()
 	if err != nil {
@@ -1203,6 +1999,51 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 			sql.WriteString(r.renderField(f))
 		}
 		sql.WriteString(")")
+	case types.StringLPad:
+		// No LPAD: prepend REPLICATE padding and keep the rightmost n characters.
+		// Matches LPAD exactly for single-character pad strings. Each use of a
+		// param is added in SQL order so positional placeholders bind correctly.
+		if len(expr.Args) >= 2 {
+			length, fill := expr.Args[0], expr.Args[1]
+			sql.WriteString("RIGHT(REPLICATE(")
+			sql.WriteString(ctx.addParam(fill))
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(length))
+			sql.WriteString(") + LEFT(")
+			sql.WriteString(r.renderField(expr.Field))
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(length))
+			sql.WriteString("), ")
+			sql.WriteString(ctx.addParam(length))
+			sql.WriteString(")")
+		}
+	case types.StringRPad:
+		// No RPAD: append REPLICATE padding and keep the leftmost n characters.
+		if len(expr.Args) >= 2 {
+			length, fill := expr.Args[0], expr.Args[1]
+			sql.WriteString("LEFT(")
+			sql.WriteString(r.renderField(expr.Field))
+			sql.WriteString(" + REPLICATE(")
+			sql.WriteString(ctx.addParam(fill))
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(length))
+			sql.WriteString("), ")
+			sql.WriteString(ctx.addParam(length))
+			sql.WriteString(")")
+		}
+	case types.StringReverse:
+		sql.WriteString("REVERSE(")
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringPosition:
+		// SQL Server uses CHARINDEX(needle, haystack)
+		sql.WriteString("CHARINDEX(")
+		if len(expr.Args) >= 1 {
+			sql.WriteString(ctx.addParam(expr.Args[0]))
+			sql.WriteString(", ")
+		}
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
 	default:
 		return "", fmt.Errorf("unsupported string function: %s", expr.Function)
 	}
@@ -1210,12 +2051,14 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 	return sql.String(), nil
 }
 
-func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderContext) (string, error) {
+func (r *Renderer) renderDateExpression(expr types.DateExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
 	switch expr.Function {
 	case types.DateNow:
 		sql.WriteString("GETDATE()")
+	case types.DateNowTz:
+		sql.WriteString("SYSDATETIMEOFFSET()")
 	case types.DateCurrentDate:
 		sql.WriteString("CAST(GETDATE() AS DATE)")
 	case types.DateCurrentTime:
@@ -1227,13 +2070,8 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 			return "", fmt.Errorf("EXTRACT requires a field")
 		}
 		// SQL Server uses DATEPART
-		part := r.datePartToMSSQL(expr.Part)
-		if part == "" {
-			return "", render.NewUnsupportedFeatureError("mssql", fmt.Sprintf("EXTRACT %s", expr.Part),
-				"use DATEPART with appropriate part name")
-		}
 		sql.WriteString("DATEPART(")
-		sql.WriteString(part)
+		sql.WriteString(r.datePartToMSSQL(expr.Part))
 		sql.WriteString(", ")
 		sql.WriteString(r.renderField(*expr.Field))
 		sql.WriteString(")")
@@ -1257,10 +2095,23 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 			sql.WriteString("DATEFROMPARTS(YEAR(")
 			sql.WriteString(r.renderField(*expr.Field))
 			sql.WriteString("), 1, 1)")
-		default:
-			return "", render.NewUnsupportedFeatureError("mssql", fmt.Sprintf("DATE_TRUNC with %s precision", expr.Part),
-				"use DATEADD/DATEDIFF or DATEFROMPARTS for date truncation")
 		}
+	case types.DateAdd, types.DateSub:
+		if err := expr.Validate(); err != nil {
+			return "", err
+		}
+		// SQL Server has only DATEADD; subtract by negating the amount
+		amount := ctx.addParam(*expr.Amount)
+		if expr.Function == types.DateSub {
+			amount = "-" + amount
+		}
+		sql.WriteString("DATEADD(")
+		sql.WriteString(r.datePartToMSSQL(expr.Part))
+		sql.WriteString(", ")
+		sql.WriteString(amount)
+		sql.WriteString(", ")
+		sql.WriteString(r.renderField(*expr.Field))
+		sql.WriteString(")")
 	default:
 		return "", fmt.Errorf("unsupported date function: %s", expr.Function)
 	}
@@ -1296,6 +2147,22 @@ func (r *Renderer) datePartToMSSQL(part types.DatePart) string {
 	}
 }
 
+// renderDateDiff renders DATEDIFF(unit, start, end). SQL Server counts the
+// unit boundaries crossed, so a diff from 23:59 to 00:01 is one day.
+func (r *Renderer) renderDateDiff(expr types.DateDiffExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	var start, end strings.Builder
+	if err := r.renderValue(expr.Start, &start, ctx); err != nil {
+		return "", err
+	}
+	if err := r.renderValue(expr.End, &end, ctx); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("DATEDIFF(%s, %s, %s)", r.datePartToMSSQL(expr.Part), start.String(), end.String()), nil
+}
+
 func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
@@ -1349,22 +2216,53 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		}
 	}
 
+	if expr.NullTreatment != "" {
+		treatment, err := r.renderNullTreatment(expr)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(treatment)
+	}
+
+	// Render OVER clause. Named windows are expanded inline from the query's
+	// WINDOW definitions.
+	spec := expr.Window
+	if expr.WindowRef != "" {
+		named, ok := ctx.windows[expr.WindowRef]
+		if !ok {
+			return "", fmt.Errorf("undefined window '%s'", expr.WindowRef)
+		}
+		spec = named
+	}
 	sql.WriteString(" OVER (")
+	sql.WriteString(r.renderWindowSpec(spec, ctx))
+	sql.WriteString(")")
+
+	return sql.String(), nil
+}
+
+// renderNullTreatment renders IGNORE NULLS or RESPECT NULLS after the argument list.
+// SQL Server 2022 supports both on LAG, LEAD, FIRST_VALUE and LAST_VALUE.
+func (r *Renderer) renderNullTreatment(expr types.WindowExpression) (string, error) {
+	return " " + string(expr.NullTreatment), nil
+}
 
+// renderWindowSpec renders a window definition body: PARTITION BY, ORDER BY and frame.
+func (r *Renderer) renderWindowSpec(spec types.WindowSpec, ctx *renderContext) string {
 	var overParts []string
 
-	if len(expr.Window.PartitionBy) > 0 {
+	if len(spec.PartitionBy) > 0 {
 		var partitionFields []string
-		for _, field := range expr.Window.PartitionBy {
+		for _, field := range spec.PartitionBy {
 			partitionFields = append(partitionFields, r.renderField(field))
 		}
 		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionFields, ", "))
 	}
 
-	if len(expr.Window.OrderBy) > 0 {
+	if len(spec.OrderBy) > 0 {
 		var orderParts []string
-		for i := range expr.Window.OrderBy {
-			order := &expr.Window.OrderBy[i]
+		for i := range spec.OrderBy {
+			order := &spec.OrderBy[i]
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
@@ -1380,20 +2278,25 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
 	}
 
-	if expr.Window.FrameStart != "" {
-		framePart := "ROWS BETWEEN " + string(expr.Window.FrameStart) + " AND "
-		if expr.Window.FrameEnd != "" {
-			framePart += string(expr.Window.FrameEnd)
-		} else {
-			framePart += "CURRENT ROW"
+	if spec.FrameStart != "" {
+		end := string(types.FrameCurrentRow)
+		if spec.FrameEnd != "" {
+			end = r.renderFrameBound(spec.FrameEnd, spec.FrameEndOffset, ctx)
 		}
+		framePart := fmt.Sprintf("%s BETWEEN %s AND %s", spec.Unit(),
+			r.renderFrameBound(spec.FrameStart, spec.FrameStartOffset, ctx), end)
 		overParts = append(overParts, framePart)
 	}
 
-	sql.WriteString(strings.Join(overParts, " "))
-	sql.WriteString(")")
+	return strings.Join(overParts, " ")
+}
 
-	return sql.String(), nil
+// renderFrameBound renders a frame bound, prefixing offset bounds with their parameter.
+func (r *Renderer) renderFrameBound(bound types.FrameBound, offset *types.Param, ctx *renderContext) string {
+	if offset != nil {
+		return ctx.addParam(*offset) + " " + string(bound)
+	}
+	return string(bound)
 }
 
 func (r *Renderer) renderOperator(op types.Operator) string {
@@ -1440,5 +2343,11 @@ func (r *Renderer) Capabilities() render.Capabilities {
 		ArrayOperators:      false,
 		InArray:             true,
 		RowLocking:          render.RowLockingNone,
+		FullOuterJoin:       true,
+		RightJoin:           true,
+		SkipLocked:          false,
+		WithTies:            true, // TOP (n) WITH TIES
+
+		GroupConcatTruncates: false,
 	}
 }
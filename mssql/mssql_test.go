@@ -1,6 +1,7 @@
 package mssql
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -297,7 +298,7 @@ func TestRender_OffsetFetch(t *testing.T) {
 	}
 }
 
-func TestRender_LimitWithoutOrderBy_RejectsError(t *testing.T) {
+func TestRender_LimitWithoutOrderBy_UsesTop(t *testing.T) {
 	r := New()
 	limit := 10
 	ast := &types.AST{
@@ -307,9 +308,68 @@ func TestRender_LimitWithoutOrderBy_RejectsError(t *testing.T) {
 		Limit:     &types.PaginationValue{Static: &limit},
 	}
 
-	_, err := r.Render(ast)
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "SELECT TOP (10) [id] FROM [users]"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_LimitParamUsesTop(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+		WhereClause: types.Condition{
+			Field:    types.Field{Name: "age"},
+			Operator: types.GT,
+			Value:    types.Param{Name: "min_age"},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "id"}, Direction: types.ASC}},
+		Limit:    &types.PaginationValue{Param: &types.Param{Name: "n"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "SELECT TOP (:n) [id] FROM [users] WHERE [age] > :min_age ORDER BY [id] ASC"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "n,min_age" {
+		t.Errorf("RequiredParams = %v, want [n min_age]", result.RequiredParams)
+	}
+}
+
+func TestRender_OffsetOnly(t *testing.T) {
+	r := New()
+	offset := 20
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "id"}, Direction: types.ASC}},
+		Offset:    &types.PaginationValue{Static: &offset},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "SELECT [id] FROM [users] ORDER BY [id] ASC OFFSET 20 ROWS"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	ast.Ordering = nil
+	_, err = r.Render(ast)
 	if err == nil {
-		t.Fatal("expected error for LIMIT without ORDER BY")
+		t.Fatal("expected error for OFFSET without ORDER BY")
 	}
 	if !strings.Contains(err.Error(), "ORDER BY") {
 		t.Errorf("error = %q, want to mention ORDER BY", err.Error())
@@ -506,6 +566,33 @@ func TestRender_DateNow(t *testing.T) {
 	}
 }
 
+func TestRender_DateNowTz(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateNowTz,
+				},
+				Alias: "current_time",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// SQL Server uses GETDATE()
+	expected := "SELECT SYSDATETIMEOFFSET() AS [current_time] FROM [users]"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_DateExtract(t *testing.T) {
 	r := New()
 	createdAt := types.Field{Name: "created_at"}
@@ -536,6 +623,32 @@ func TestRender_DateExtract(t *testing.T) {
 	}
 }
 
+func TestRender_RejectsDateBin(t *testing.T) {
+	r := New()
+	createdAt := types.Field{Name: "created_at"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateBin,
+					Field:    &createdAt,
+					Interval: &types.Param{Name: "bucket"},
+					Origin:   &types.Param{Name: "origin"},
+				},
+				Alias: "bucket_start",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "DATE_BIN" {
+		t.Errorf("Render() error = %v, want DATE_BIN UnsupportedFeatureError", err)
+	}
+}
+
 func TestRender_DateTruncMonth(t *testing.T) {
 	r := New()
 	createdAt := types.Field{Name: "created_at"}
@@ -1133,6 +1246,44 @@ func TestRenderCompound_Except(t *testing.T) {
 	}
 }
 
+func TestRenderCompound_ExceptOrderByOutputColumn(t *testing.T) {
+	r := New()
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "users", Alias: "u"},
+			Fields:    []types.Field{{Name: "name", Table: "u"}},
+		},
+		Operands: []types.SetOperand{
+			{
+				Operation: types.SetExcept,
+				AST: &types.AST{
+					Operation: types.OpSelect,
+					Target:    types.Table{Name: "banned_users"},
+					Fields:    []types.Field{{Name: "name"}},
+				},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "name", Table: "u"}, Direction: types.ASC}},
+	}
+
+	result, err := r.RenderCompound(query)
+	if err != nil {
+		t.Fatalf("RenderCompound() error = %v", err)
+	}
+
+	// The qualifier is dropped: a set operation result has no table scope.
+	expected := `(SELECT u.[name] FROM [users] u) EXCEPT (SELECT [name] FROM [banned_users]) ORDER BY [name] ASC`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	query.Ordering[0].Field = types.Field{Name: "email"}
+	if _, err := r.RenderCompound(query); err == nil {
+		t.Error("expected error for ORDER BY a column the first query does not select")
+	}
+}
+
 func TestRenderCompound_WithOrderByLimit(t *testing.T) {
 	r := New()
 	limitVal := 10
@@ -1288,6 +1439,12 @@ func TestCapabilities(t *testing.T) {
 	if caps.RowLocking != render.RowLockingNone {
 		t.Errorf("RowLocking = %v, want RowLockingNone", caps.RowLocking)
 	}
+	if !caps.FullOuterJoin {
+		t.Error("FullOuterJoin should be true")
+	}
+	if !caps.RightJoin {
+		t.Error("RightJoin should be true")
+	}
 }
 
 // =============================================================================
@@ -2530,6 +2687,65 @@ func TestRender_NotLike(t *testing.T) {
 	}
 }
 
+func TestRender_RejectsNaturalJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Joins:     []types.Join{{Type: types.NaturalJoin, Table: types.Table{Name: "profiles"}}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for NATURAL JOIN, got nil")
+	}
+	if !strings.Contains(err.Error(), "NATURAL JOIN") {
+		t.Errorf("error = %q, want to contain 'NATURAL JOIN'", err.Error())
+	}
+}
+
+func TestRender_JoinUsingTranslatesToOn(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Joins: []types.Join{
+			{Type: types.LeftJoin, Table: types.Table{Name: "orders", Alias: "o"}, Using: []types.Field{{Name: "user_id"}, {Name: "region"}}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM [users] u LEFT JOIN [orders] o ON u.[user_id] = o.[user_id] AND u.[region] = o.[region]`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JoinUsingAfterAnotherJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Joins: []types.Join{
+			{Type: types.InnerJoin, Table: types.Table{Name: "orders", Alias: "o"}, Using: []types.Field{{Name: "user_id"}}},
+			{Type: types.InnerJoin, Table: types.Table{Name: "refunds", Alias: "r"}, Using: []types.Field{{Name: "order_id"}}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for USING after another join, got nil")
+	}
+	if !strings.Contains(err.Error(), "USING after another join") {
+		t.Errorf("error = %q, want to contain 'USING after another join'", err.Error())
+	}
+}
+
 func TestRender_LeftJoin(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -2644,3 +2860,1019 @@ func TestRender_ParameterizedOffset(t *testing.T) {
 		t.Errorf("SQL = %q, want to contain 'OFFSET :offset ROWS'", result.SQL)
 	}
 }
+
+func TestRender_GroupByRollup(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupRollup,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [region], [product] FROM [sales] GROUP BY ROLLUP([region], [product])`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupByCube(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}},
+		GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupCube,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [region] FROM [sales] GROUP BY CUBE([region], [product])`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupByGroupingSets(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:    types.OpSelect,
+		Target:       types.Table{Name: "sales"},
+		Fields:       []types.Field{{Name: "region"}},
+		GroupByMode:  types.GroupSets,
+		GroupingSets: [][]types.Field{{{Name: "region"}, {Name: "product"}}, {}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [region] FROM [sales] GROUP BY GROUPING SETS(([region], [product]), ())`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupingWithRollup(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		fields   []types.Field
+		expected string
+	}{
+		{"single column", []types.Field{{Name: "region"}}, `SELECT GROUPING([region]) AS [grp] FROM [sales] GROUP BY ROLLUP([region], [product])`},
+		{"multiple columns", []types.Field{{Name: "region"}, {Name: "product"}}, `SELECT GROUPING_ID([region], [product]) AS [grp] FROM [sales] GROUP BY ROLLUP([region], [product])`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "sales"},
+				FieldExpressions: []types.FieldExpression{
+					{Grouping: &types.GroupingExpression{Fields: tt.fields}, Alias: "grp"},
+				},
+				GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+				GroupByMode: types.GroupRollup,
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsLateralJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Joins: []types.Join{
+			{
+				Type:     types.CrossJoin,
+				Table:    types.Table{Alias: "x"},
+				Subquery: &types.Subquery{AST: &types.AST{Operation: types.OpSelect, Target: types.Table{Name: "orders"}}},
+				Lateral:  true,
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for LATERAL, got nil")
+	}
+	if !strings.Contains(err.Error(), "LATERAL") {
+		t.Errorf("error = %q, want to contain 'LATERAL'", err.Error())
+	}
+}
+
+func TestRender_AggregateFilterRewrittenAsCase(t *testing.T) {
+	r := New()
+	active := types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "is_active"}}
+	tests := []struct {
+		name     string
+		expr     types.FieldExpression
+		expected string
+	}{
+		{"sum", types.FieldExpression{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Filter: active, Alias: "total"},
+			`SELECT SUM(CASE WHEN [active] = :is_active THEN [amount] END) AS [total] FROM [users]`},
+		{"count distinct", types.FieldExpression{Aggregate: types.AggCountDistinct, Field: types.Field{Name: "user_id"}, Filter: active, Alias: "engaged"},
+			`SELECT COUNT(DISTINCT CASE WHEN [active] = :is_active THEN [user_id] END) AS [engaged] FROM [users]`},
+		{"count star", types.FieldExpression{Aggregate: types.AggCountField, Filter: active, Alias: "n"},
+			`SELECT COUNT(CASE WHEN [active] = :is_active THEN 1 END) AS [n] FROM [users]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation:        types.OpSelect,
+				Target:           types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{tt.expr},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "is_active" {
+				t.Errorf("RequiredParams = %v, want [is_active]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_DerivedTableTarget(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpSelect,
+		TargetSubquery: sub,
+		TargetAlias:    "sub",
+		Fields:         []types.Field{{Name: "user_id"}},
+		WhereClause:    types.Condition{Field: types.Field{Name: "total"}, Operator: types.GT, Value: types.Param{Name: "min_total"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [user_id] FROM (SELECT [user_id], SUM([amount]) AS [total] FROM [orders] WHERE [status] = :sq1_status GROUP BY [user_id]) AS sub WHERE [total] > :min_total`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 2 || result.RequiredParams[0] != "sq1_status" || result.RequiredParams[1] != "min_total" {
+		t.Errorf("RequiredParams = %v, want [sq1_status min_total]", result.RequiredParams)
+	}
+}
+
+func TestRender_DerivedTableTargetCount(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpCount,
+		TargetSubquery: sub,
+		TargetAlias:    "sub",
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT COUNT(*) FROM (SELECT [user_id], SUM([amount]) AS [total] FROM [orders] WHERE [status] = :sq1_status GROUP BY [user_id]) AS sub`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_DerivedTableTargetRequiresAlias(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpSelect,
+		TargetSubquery: sub,
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for derived table target without alias, got nil")
+	}
+}
+
+func TestRender_RejectsTupleCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.TupleCondition{
+			Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+			Operator: types.IN,
+			Subquery: &types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "members"},
+				Fields:    []types.Field{{Name: "user_id"}, {Name: "org_id"}},
+			}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for tuple subquery condition, got nil")
+	}
+	if !strings.Contains(err.Error(), "tuple") {
+		t.Errorf("error = %q, want to contain 'tuple'", err.Error())
+	}
+}
+
+func TestRender_TupleConditionExpanded(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator types.Operator
+		expected string
+	}{
+		{"equal", types.EQ, `SELECT * FROM [users] WHERE ([id] = :id AND [org_id] = :org)`},
+		{"not equal", types.NE, `SELECT * FROM [users] WHERE ([id] <> :id OR [org_id] <> :org)`},
+		{"greater", types.GT, `SELECT * FROM [users] WHERE ([id] > :id OR ([id] = :id AND [org_id] > :org))`},
+		{"less or equal", types.LE, `SELECT * FROM [users] WHERE ([id] < :id OR ([id] = :id AND [org_id] <= :org))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				WhereClause: types.TupleCondition{
+					Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+					Operator: tt.operator,
+					Values:   []types.Param{{Name: "id"}, {Name: "org"}},
+				},
+			}
+
+			result, err := New().Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsJSONObject(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{{Key: "id", Field: &types.Field{Name: "id"}}},
+				},
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for JSON object, got nil")
+	}
+	if !strings.Contains(err.Error(), "JSON object") {
+		t.Errorf("error = %q, want to contain 'JSON object'", err.Error())
+	}
+}
+
+func TestRender_ExistsSubquerySelectOne(t *testing.T) {
+	r := New()
+	limit := 1
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.SubqueryCondition{
+			Operator: types.EXISTS,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				Fields:    []types.Field{{Name: "id"}},
+				Ordering:  []types.OrderBy{{Field: types.Field{Name: "id"}, Direction: types.DESC}},
+				Limit:     &types.PaginationValue{Static: &limit},
+			}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT * FROM [users] WHERE EXISTS (SELECT 1 FROM [orders])"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_RejectsGroupsFrame(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggSum,
+					Field:     &types.Field{Name: "points"},
+					Window: types.WindowSpec{
+						OrderBy:    []types.OrderBy{{Field: types.Field{Name: "round"}, Direction: types.ASC}},
+						FrameUnit:  types.FrameGroups,
+						FrameStart: types.FrameUnboundedPreceding,
+						FrameEnd:   types.FrameCurrentRow,
+					},
+				},
+				Alias: "total",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for GROUPS frame, got nil")
+	}
+	if !strings.Contains(err.Error(), "GROUPS") {
+		t.Errorf("error = %q, want to contain %q", err.Error(), "GROUPS")
+	}
+}
+
+func TestRender_NullSafeJoinCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "ledger", Alias: "a"},
+		Fields:    []types.Field{{Name: "id"}},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "bank", Alias: "b"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "ref", Table: "a"},
+					Operator:   types.IsNotDistinctFrom,
+					RightField: types.Field{Name: "ref", Table: "b"},
+				},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [id] FROM [ledger] a INNER JOIN [bank] b ON EXISTS (SELECT a.[ref] INTERSECT SELECT b.[ref])`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_IsDistinctFromParam(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "ledger"},
+		WhereClause: types.Condition{Field: types.Field{Name: "ref"}, Operator: types.IsDistinctFrom, Value: types.Param{Name: "ref"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM [ledger] WHERE NOT EXISTS (SELECT [ref] INTERSECT SELECT :ref)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_CastWithTypeParams(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "amount"}, CastType: types.CastNumeric, Precision: 10, Scale: 2},
+				Alias: "amt",
+			},
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "name"}, CastType: types.CastVarchar, Length: 50},
+				Alias: "short",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT CAST([amount] AS DECIMAL(10,2)) AS [amt], CAST([name] AS NVARCHAR(50)) AS [short] FROM [orders]`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_QuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		Fields:    []types.Field{{Name: "id"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.OR,
+			Conditions: []types.ConditionItem{
+				types.Condition{Field: types.Field{Name: "starts_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+				types.Condition{Field: types.Field{Name: "kind"}, Operator: types.EQ, Value: types.Param{Name: "kind"}},
+				types.Condition{Field: types.Field{Name: "ends_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT [id] FROM [events] WHERE ([starts_at] >= ? OR [kind] = ? OR [ends_at] >= ?)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"since", "kind", "since"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("RequiredParams = %v, want 2 unique names", result.RequiredParams)
+	}
+}
+
+func TestRender_RejectsArrayConstructor(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{
+			name: "select",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "posts"},
+				FieldExpressions: []types.FieldExpression{{
+					Array: &types.ArrayExpression{Values: []types.Param{{Name: "a"}, {Name: "b"}}},
+				}},
+			},
+		},
+		{
+			name: "insert",
+			ast: &types.AST{
+				Operation: types.OpInsert,
+				Target:    types.Table{Name: "posts"},
+				Values:    []map[types.Field]types.Param{{{Name: "title"}: {Name: "title"}}},
+				ValueArrays: []map[types.Field]types.ArrayExpression{{
+					{Name: "tags"}: {Values: []types.Param{{Name: "tag"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Render(tt.ast)
+			if err == nil {
+				t.Fatal("expected error for ARRAY constructor, got nil")
+			}
+			if !strings.Contains(err.Error(), "ARRAY constructors") {
+				t.Errorf("error = %q, want to contain 'ARRAY constructors'", err.Error())
+			}
+		})
+	}
+}
+
+func TestRender_Truncate(t *testing.T) {
+	r := New()
+
+	result, err := r.Render(&types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, RestartIdentity: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "TRUNCATE TABLE [staging]"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	_, err = r.Render(&types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, Cascade: true})
+	if err == nil || !strings.Contains(err.Error(), "TRUNCATE CASCADE") {
+		t.Errorf("error = %v, want TRUNCATE CASCADE unsupported", err)
+	}
+}
+
+func TestRender_WithTies(t *testing.T) {
+	limit := 3
+	offset := 6
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "score"}, Direction: types.DESC}},
+		Limit:     &types.PaginationValue{Static: &limit},
+		WithTies:  true,
+	}
+
+	result, err := New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := `SELECT TOP (3) WITH TIES * FROM [scores] ORDER BY [score] DESC`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	ast.Offset = &types.PaginationValue{Static: &offset}
+	_, err = New().Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "WITH TIES with OFFSET" {
+		t.Errorf("Render() error = %v, want WITH TIES with OFFSET UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_StringPadReverseInitcap(t *testing.T) {
+	r := New()
+	padArgs := []types.Param{{Name: "width"}, {Name: "fill"}}
+	tests := []struct {
+		function types.StringFunc
+		args     []types.Param
+		want     string
+	}{
+		{types.StringLPad, padArgs, "SELECT RIGHT(REPLICATE(:fill, :width) + LEFT([name], :width), :width) AS [formatted] FROM [users]"},
+		{types.StringRPad, padArgs, "SELECT LEFT([name] + REPLICATE(:fill, :width), :width) AS [formatted] FROM [users]"},
+		{types.StringReverse, nil, "SELECT REVERSE([name]) AS [formatted] FROM [users]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						String: &types.StringExpression{
+							Function: tt.function,
+							Field:    types.Field{Name: "name"},
+							Args:     tt.args,
+						},
+						Alias: "formatted",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.want {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsInitcap(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{Function: types.StringInitcap, Field: types.Field{Name: "name"}},
+				Alias:  "title",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "INITCAP" {
+		t.Errorf("Render() error = %v, want INITCAP UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_RejectsSplitPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringSplitPart,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "delim"}, {Name: "part"}},
+				},
+				Alias: "domain",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "SPLIT_PART" {
+		t.Errorf("Render() error = %v, want SPLIT_PART UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_ValuesTable(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target: types.Table{
+			Alias: "t",
+			Values: &types.ValuesTable{
+				Columns: []string{"id", "name"},
+				Rows: [][]types.Param{
+					{{Name: "id1"}, {Name: "name1"}},
+					{{Name: "id2"}, {Name: "name2"}},
+				},
+			},
+		},
+		Fields: []types.Field{{Name: "id", Table: "t"}, {Name: "name", Table: "t"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT t.[id], t.[name] FROM (VALUES (:id1, :name1), (:id2, :name2)) AS t([id], [name])"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "id1,name1,id2,name2" {
+		t.Errorf("RequiredParams = %v, want [id1 name1 id2 name2]", result.RequiredParams)
+	}
+}
+
+func TestRender_WithDefaultOrderBy(t *testing.T) {
+	limit, offset := 10, 20
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Limit:     &types.PaginationValue{Static: &limit},
+		Offset:    &types.PaginationValue{Static: &offset},
+	}
+
+	_, err := New().Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "OFFSET without ORDER BY" {
+		t.Errorf("Render() error = %v, want OFFSET without ORDER BY UnsupportedFeatureError", err)
+	}
+
+	r := New().WithDefaultOrderBy(types.Field{Name: "id"})
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := "SELECT [name] FROM [users] ORDER BY [id] ASC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	// An explicit ORDER BY wins over the fallback
+	ast.Ordering = []types.OrderBy{{Field: types.Field{Name: "name"}, Direction: types.DESC}}
+	result, err = r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected = "SELECT [name] FROM [users] ORDER BY [name] DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringPosition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringPosition,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "needle"}},
+				},
+				Alias: "at",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT CHARINDEX(:needle, [email]) AS [at] FROM [users]"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "team"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggStringAgg,
+					Field:     types.Field{Name: "name"},
+					Separator: &types.Param{Name: "sep"},
+					OrderBy:   []types.OrderBy{{Field: types.Field{Name: "name"}, Direction: types.ASC}},
+				},
+				Alias: "names",
+			},
+		},
+		GroupBy: []types.Field{{Name: "team"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := "SELECT [team], STRING_AGG([name], :sep) WITHIN GROUP (ORDER BY [name] ASC) AS [names] FROM [users] GROUP BY [team]"
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "sep" {
+		t.Errorf("RequiredParams = %v, want [sep]", result.RequiredParams)
+	}
+}
+
+func TestRender_DateAdd(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		function types.DateFunc
+		part     types.DatePart
+		expected string
+	}{
+		{"add days", types.DateAdd, types.PartDay, "SELECT DATEADD(DAY, :days, [created_at]) AS [due] FROM [orders]"},
+		{"subtract days", types.DateSub, types.PartDay, "SELECT DATEADD(DAY, -:days, [created_at]) AS [due] FROM [orders]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						Date: &types.DateExpression{
+							Function: tt.function,
+							Field:    &types.Field{Name: "created_at"},
+							Part:     tt.part,
+							Amount:   &types.Param{Name: "days"},
+						},
+						Alias: "due",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "days" {
+				t.Errorf("RequiredParams = %v, want [days]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsUpdateSetRow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpUpdate,
+		Target:    types.Table{Name: "accounts"},
+		Updates:   map[types.Field]types.Param{{Name: "note"}: {Name: "note"}},
+		UpdateRows: []types.RowUpdate{{
+			Fields: []types.Field{{Name: "plan"}, {Name: "seats"}},
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "plans"},
+				Fields:      []types.Field{{Name: "name"}, {Name: "seats"}},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "plan_id"}},
+			}},
+		}},
+		WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "id"}},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "multi-column SET from a subquery" {
+		t.Errorf("error = %v, want multi-column SET unsupported", err)
+	}
+}
+
+func TestRender_DateDiff(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		part     types.DatePart
+		end      types.Value
+		expected string
+		param    string
+	}{
+		{"day", types.PartDay, types.Field{Name: "shipped_at"}, `SELECT DATEDIFF(DAY, [created_at], [shipped_at]) AS [elapsed] FROM [orders]`, ""},
+		{"hour", types.PartHour, types.Param{Name: "now"}, `SELECT DATEDIFF(HOUR, [created_at], :now) AS [elapsed] FROM [orders]`, "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						DateDiff: &types.DateDiffExpression{
+							End:   tt.end,
+							Start: types.Field{Name: "created_at"},
+							Part:  tt.part,
+						},
+						Alias: "elapsed",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.RequiredParams, ",") != tt.param {
+				t.Errorf("RequiredParams = %v, want [%s]", result.RequiredParams, tt.param)
+			}
+		})
+	}
+}
+
+func TestValidate_AgreesWithRender(t *testing.T) {
+	r := New()
+	users := types.Table{Name: "users"}
+	name := types.Field{Name: "name"}
+	age := types.Field{Name: "age"}
+	created := types.Field{Name: "created_at"}
+	offset := 10
+	selecting := func(expr types.FieldExpression) *types.AST {
+		return &types.AST{Operation: types.OpSelect, Target: users, FieldExpressions: []types.FieldExpression{expr}}
+	}
+
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"array", selecting(types.FieldExpression{Array: &types.ArrayExpression{Values: []types.Param{{Name: "tag"}}}})},
+		{"jsonobject", selecting(types.FieldExpression{JSONObject: &types.JSONObjectExpression{Pairs: []types.JSONObjectPair{{Key: "n", Field: &name}}}})},
+		{"initcap", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+		{"split part", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringSplitPart, Field: name, Args: []types.Param{{Name: "delim"}, {Name: "n"}}}})},
+		{"date bin", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateBin, Field: &created, Interval: &types.Param{Name: "width"}, Origin: &types.Param{Name: "origin"}}})},
+		{"date trunc week", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateTrunc, Field: &created, Part: types.PartWeek}})},
+		{"extract epoch", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateExtract, Field: &created, Part: types.PartEpoch}})},
+		{"array agg", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggArrayAgg, Field: name}})},
+		{"string agg without separator", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggStringAgg, Field: name}})},
+		{"distinct on", &types.AST{Operation: types.OpSelect, Target: users, Fields: []types.Field{name}, DistinctOn: []types.Field{name}}},
+		{"nested in arithmetic", selecting(types.FieldExpression{Arithmetic: &types.ArithmeticExpression{
+			Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: age}},
+			Operator: types.ArithAdd,
+			Right:    types.ArithmeticOperand{Expr: &types.FieldExpression{Date: &types.DateExpression{Function: types.DateTrunc, Field: &created, Part: types.PartWeek}}},
+		}})},
+		{"offset without order by", &types.AST{Operation: types.OpSelect, Target: users, Fields: []types.Field{name}, Offset: &types.PaginationValue{Static: &offset}}},
+		{"using after another join", &types.AST{Operation: types.OpSelect, Target: users, Joins: []types.Join{
+			{Type: types.InnerJoin, Table: types.Table{Name: "orders"}, Using: []types.Field{{Name: "user_id"}}},
+			{Type: types.InnerJoin, Table: types.Table{Name: "refunds"}, Using: []types.Field{{Name: "order_id"}}},
+		}}},
+		{"nested in subquery", &types.AST{
+			Operation: types.OpSelect,
+			Target:    users,
+			Fields:    []types.Field{name},
+			WhereClause: types.SubqueryCondition{
+				Field:    &name,
+				Operator: types.IN,
+				Subquery: types.Subquery{AST: selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := r.Validate(tt.ast)
+			if len(errs) == 0 {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			_, err := r.Render(tt.ast)
+			if err == nil || err.Error() != errs[0].Error() {
+				t.Errorf("Render() error = %v, want %v", err, errs[0])
+			}
+		})
+	}
+}
+
+func TestRender_PadQuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	tests := []struct {
+		name           string
+		function       types.StringFunc
+		expected       string
+		wantPositional []string
+	}{
+		{"lpad", types.StringLPad, `SELECT RIGHT(REPLICATE(?, ?) + LEFT([username], ?), ?) FROM [users]`, []string{"c", "n", "n", "n"}},
+		{"rpad", types.StringRPad, `SELECT LEFT([username] + REPLICATE(?, ?), ?) FROM [users]`, []string{"c", "n", "n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{{
+					String: &types.StringExpression{
+						Function: tt.function,
+						Field:    types.Field{Name: "username"},
+						Args:     []types.Param{{Name: "n"}, {Name: "c"}},
+					},
+				}},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.PositionalParams, ",") != strings.Join(tt.wantPositional, ",") {
+				t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, tt.wantPositional)
+			}
+		})
+	}
+}
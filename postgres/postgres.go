@@ -19,6 +19,7 @@ type renderContext struct {
 	paramCallback func(types.Param) string
 	paramPrefix   string
 	depth         int
+	selectOne     bool // Render the select list as 1 (EXISTS ignores it)
 }
 
 // newRenderContext creates a new render context.
@@ -47,42 +48,88 @@ func (ctx *renderContext) withSubquery() (*renderContext, error) {
 
 // addParam adds a parameter with proper namespacing.
 func (ctx *renderContext) addParam(param types.Param) string {
-	if ctx.paramPrefix != "" {
-		param = types.Param{Name: ctx.paramPrefix + param.Name}
+	if ctx.paramPrefix != "" && !param.IsLiteral() {
+		param = types.Param{Name: ctx.paramPrefix + param.Name, Cast: param.Cast}
 	}
 	return ctx.paramCallback(param)
 }
 
+// castParam appends the param's type hint to its placeholder, e.g. :id::UUID,
+// so PostgreSQL can resolve overloaded operators and functions.
+func castParam(placeholder string, param types.Param) string {
+	if param.Cast == "" {
+		return placeholder
+	}
+	return placeholder + "::" + string(param.Cast)
+}
+
 // Renderer implements the PostgreSQL dialect renderer.
-type Renderer struct{}
+type Renderer struct {
+	opts  Options
+	funcs render.Functions
+}
+
+func init() {
+	render.Register("postgres", func() render.Renderer { return New() })
+}
+
+// Options configures optional rendering behavior.
+type Options = render.Options
+
+// RenderOptions configures a single render call.
+type RenderOptions = render.RenderOptions
 
 // New creates a new PostgreSQL renderer.
 func New() *Renderer {
 	return &Renderer{}
 }
 
+// NewWithOptions creates a new PostgreSQL renderer with the given options.
+func NewWithOptions(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// RegisterFunction allows a custom scalar function, such as a stored function or
+// an extension function, to be called through astql.Func with exactly argCount
+// arguments. The name may be schema-qualified (ext.my_fn) and renders unquoted.
+func (r *Renderer) RegisterFunction(name string, argCount int) error {
+	return r.funcs.Register(name, argCount)
+}
+
 // Render converts an AST to a QueryResult with PostgreSQL SQL.
 func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
+	return r.RenderWithOptions(ast, RenderOptions{})
+}
+
+// RenderWithOptions converts an AST to a QueryResult, applying per-call options.
+func (r *Renderer) RenderWithOptions(ast *types.AST, opts RenderOptions) (*types.QueryResult, error) {
 	if err := ast.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid AST: %w", err)
 	}
+	if r.opts.LowercaseIdentifiers {
+		ast = render.LowercaseIdentifiers(ast)
+	}
+	if err := r.validateAST(ast); err != nil {
+		return nil, err
+	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
 
 	// Helper to add a parameter and return its placeholder
 	addParam := func(param types.Param) string {
-		// Use named parameters for sqlx
-		placeholder := ":" + param.Name
-
-		// Track unique parameter names
-		if !usedParams[param.Name] {
-			params = append(params, param.Name)
-			usedParams[param.Name] = true
+		if param.IsLiteral() {
+			return r.renderLiteral(param.Literal)
 		}
+		return castParam(params.Placeholder(param.Name), param)
+	}
 
-		return placeholder
+	if opts.Explain != render.ExplainNone {
+		prefix, err := r.explainPrefix(opts.Explain)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(prefix)
 	}
 
 	// Create render context for handling subqueries
@@ -99,69 +146,110 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 			return nil, err
 		}
 	case types.OpUpdate:
-		if err := r.renderUpdate(ast, &sql, addParam); err != nil {
+		if err := r.renderReturningCount(ast, &sql, func() error {
+			return r.renderUpdate(ast, &sql, addParam)
+		}); err != nil {
 			return nil, err
 		}
 	case types.OpDelete:
-		if err := r.renderDelete(ast, &sql, addParam); err != nil {
+		if err := r.renderReturningCount(ast, &sql, func() error {
+			return r.renderDelete(ast, &sql, addParam)
+		}); err != nil {
 			return nil, err
 		}
 	case types.OpCount:
 		if err := r.renderCount(ast, &sql, addParam); err != nil {
 			return nil, err
 		}
+	case types.OpTruncate:
+		r.renderTruncate(ast, &sql)
 	default:
 		return nil, fmt.Errorf("unsupported operation: %s", ast.Operation)
 	}
 
+	text := sql.String()
+	if opts.Pretty {
+		text = render.ApplyPretty(text)
+	}
+
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              render.ApplyKeywordCase(text, opts.KeywordCase),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        ast.Operation,
+		Tables:           ast.Tables(),
 	}, nil
 }
 
+// explainPrefix returns the EXPLAIN prefix for the mode, with JSON output for plan diffing.
+func (r *Renderer) explainPrefix(mode render.ExplainMode) (string, error) {
+	switch mode {
+	case render.ExplainPlan:
+		return "EXPLAIN (FORMAT JSON) ", nil
+	case render.ExplainAnalyze:
+		return "EXPLAIN (ANALYZE, FORMAT JSON) ", nil
+	}
+	return "", fmt.Errorf("unknown explain mode: %d", mode)
+}
+
 // RenderCompound converts a CompoundQuery to a QueryResult with SQL and parameters.
 // Parameters are namespaced per sub-query (q0_, q1_, etc.) to prevent collisions.
 func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error) {
+	if r.opts.LowercaseIdentifiers {
+		query = render.LowercaseCompoundIdentifiers(query)
+	}
+
+	// Validate each AST in the compound query
+	if err := r.validateAST(query.Base); err != nil {
+		return nil, err
+	}
+	for _, operand := range query.Operands {
+		if err := r.validateAST(operand.AST); err != nil {
+			return nil, err
+		}
+	}
+
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
+
+	lparen, rparen, err := render.CompoundOperandParens(query, r.opts.BareCompoundOperands)
+	if err != nil {
+		return nil, err
+	}
 
 	queryIndex := 0
 
 	// Helper to create param callback with prefix
 	makeParamCallback := func(prefix string) func(types.Param) string {
 		return func(param types.Param) string {
-			name := prefix + param.Name
-			placeholder := ":" + name
-			if !usedParams[name] {
-				params = append(params, name)
-				usedParams[name] = true
+			if param.IsLiteral() {
+				return r.renderLiteral(param.Literal)
 			}
-			return placeholder
+			return castParam(params.Placeholder(prefix+param.Name), param)
 		}
 	}
 
 	// Render base query
 	baseCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
-	sql.WriteString("(")
+	sql.WriteString(lparen)
 	if err := r.renderSelect(query.Base, &sql, baseCtx); err != nil {
 		return nil, err
 	}
-	sql.WriteString(")")
+	sql.WriteString(rparen)
 	queryIndex++
 
 	// Render each operand
 	for _, operand := range query.Operands {
 		sql.WriteString(" ")
 		sql.WriteString(string(operand.Operation))
-		sql.WriteString(" (")
+		sql.WriteString(" ")
+		sql.WriteString(lparen)
 
 		opCtx := newRenderContext(makeParamCallback(fmt.Sprintf("q%d_", queryIndex)))
 		if err := r.renderSelect(operand.AST, &sql, opCtx); err != nil {
 			return nil, err
 		}
-		sql.WriteString(")")
+		sql.WriteString(rparen)
 		queryIndex++
 	}
 
@@ -174,15 +262,19 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 		var orderParts []string
 		for i := range query.Ordering {
 			order := &query.Ordering[i]
+			field, err := render.CompoundOrderField(query, order.Field)
+			if err != nil {
+				return nil, err
+			}
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
-					r.renderFieldCtx(order.Field, finalCtx),
+					r.renderFieldCtx(field, finalCtx),
 					r.renderOperator(order.Operator),
 					finalCtx.addParam(order.Param),
 					order.Direction)
 			} else {
-				part = fmt.Sprintf("%s %s", r.renderFieldCtx(order.Field, finalCtx), order.Direction)
+				part = fmt.Sprintf("%s %s", r.renderFieldCtx(field, finalCtx), order.Direction)
 			}
 			// Append NULLS FIRST/LAST if specified
 			if order.Nulls != "" {
@@ -206,12 +298,16 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              sql.String(),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        types.OpSelect,
+		Tables:           query.Tables(),
 	}, nil
 }
 
 func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+
 	sql.WriteString("SELECT ")
 
 	if len(ast.DistinctOn) > 0 {
@@ -227,7 +323,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	// Render fields and expressions
-	if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
+	if ctx.selectOne {
+		sql.WriteString("1")
+	} else if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
 		sql.WriteString("*")
 	} else {
 		var selections []string
@@ -250,20 +348,14 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	// Render JOINs
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		// CROSS JOIN doesn't have ON clause
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
@@ -276,13 +368,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	// GROUP BY
-	if len(ast.GroupBy) > 0 {
+	if ast.HasGroupBy() {
 		sql.WriteString(" GROUP BY ")
-		var groupFields []string
-		for _, field := range ast.GroupBy {
-			groupFields = append(groupFields, r.renderFieldCtx(field, ctx))
-		}
-		sql.WriteString(strings.Join(groupFields, ", "))
+		sql.WriteString(r.renderGroupBy(ast, ctx))
 	}
 
 	// HAVING
@@ -298,6 +386,12 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		}
 	}
 
+	// WINDOW
+	if len(ast.Windows) > 0 {
+		sql.WriteString(" WINDOW ")
+		sql.WriteString(r.renderWindowDefinitions(ast.Windows, ctx))
+	}
+
 	// ORDER BY
 	if len(ast.Ordering) > 0 {
 		sql.WriteString(" ORDER BY ")
@@ -325,27 +419,175 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		sql.WriteString(strings.Join(orderParts, ", "))
 	}
 
-	// LIMIT
-	if ast.Limit != nil {
-		sql.WriteString(" LIMIT ")
+	if ast.WithTies {
+		// WITH TIES only exists in the standard OFFSET/FETCH form
+		if ast.Offset != nil {
+			sql.WriteString(" OFFSET ")
+			sql.WriteString(r.renderPaginationValue(ast.Offset, ctx))
+			sql.WriteString(" ROWS")
+		}
+		sql.WriteString(" FETCH FIRST ")
 		sql.WriteString(r.renderPaginationValue(ast.Limit, ctx))
-	}
+		sql.WriteString(" ROWS WITH TIES")
+	} else {
+		// LIMIT
+		if ast.Limit != nil {
+			sql.WriteString(" LIMIT ")
+			sql.WriteString(r.renderPaginationValue(ast.Limit, ctx))
+		}
 
-	// OFFSET
-	if ast.Offset != nil {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(r.renderPaginationValue(ast.Offset, ctx))
+		// OFFSET
+		if ast.Offset != nil {
+			sql.WriteString(" OFFSET ")
+			sql.WriteString(r.renderPaginationValue(ast.Offset, ctx))
+		}
 	}
 
 	// Row locking (FOR UPDATE, FOR SHARE, etc.)
 	if ast.Lock != nil {
 		sql.WriteString(" ")
 		sql.WriteString(string(*ast.Lock))
+		if len(ast.LockOf) > 0 {
+			refs := make([]string, 0, len(ast.LockOf))
+			for _, table := range ast.LockOf {
+				// Aliases render bare, matching the FROM/JOIN clauses
+				if table.Alias != "" {
+					refs = append(refs, table.Alias)
+				} else {
+					refs = append(refs, r.quoteIdentifier(table.Name))
+				}
+			}
+			sql.WriteString(" OF ")
+			sql.WriteString(strings.Join(refs, ", "))
+		}
+		if ast.SkipLocked {
+			sql.WriteString(" SKIP LOCKED")
+		}
+	}
+
+	return nil
+}
+
+// renderGroupBy renders the GROUP BY list, including ROLLUP, CUBE and GROUPING SETS.
+func (r *Renderer) renderGroupBy(ast *types.AST, ctx *renderContext) string {
+	renderList := func(fields []types.Field) string {
+		parts := make([]string, 0, len(fields))
+		for _, field := range fields {
+			parts = append(parts, r.renderFieldCtx(field, ctx))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	switch ast.GroupByMode {
+	case types.GroupRollup, types.GroupCube:
+		return fmt.Sprintf("%s(%s)", ast.GroupByMode, renderList(ast.GroupBy))
+	case types.GroupSets:
+		sets := make([]string, 0, len(ast.GroupingSets))
+		for _, set := range ast.GroupingSets {
+			sets = append(sets, "("+renderList(set)+")")
+		}
+		return fmt.Sprintf("GROUPING SETS(%s)", strings.Join(sets, ", "))
+	default:
+		list := renderList(ast.GroupByFields())
+		if coalesced := r.renderCoalescedGroups(ast, ctx); coalesced != "" {
+			if list == "" {
+				return coalesced
+			}
+			return list + ", " + coalesced
+		}
+		return list
+	}
+}
+
+// renderCoalescedGroups renders COALESCE(field, :sentinel) GROUP BY entries.
+func (r *Renderer) renderCoalescedGroups(ast *types.AST, ctx *renderContext) string {
+	parts := make([]string, 0, len(ast.CoalescedGroupBy))
+	for _, group := range ast.CoalescedGroupBy {
+		parts = append(parts, fmt.Sprintf("COALESCE(%s, %s)", r.renderFieldCtx(group.Field, ctx), ctx.addParam(group.Sentinel)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// validateDistinctOrderBy checks that every ORDER BY key of a SELECT DISTINCT
+// appears in the select list, as PostgreSQL requires. Keys may name a selected
+// field or the alias of a selected expression. SELECT DISTINCT * is not checked.
+func validateDistinctOrderBy(ast *types.AST) error {
+	if !ast.Distinct || len(ast.Ordering) == 0 {
+		return nil
+	}
+	if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
+		return nil
 	}
 
+	for _, order := range ast.Ordering {
+		if order.Operator != "" {
+			return fmt.Errorf("SELECT DISTINCT cannot ORDER BY the expression %s %s :%s: ORDER BY expressions must appear in the select list",
+				order.Field.Name, order.Operator, order.Param.Name)
+		}
+		if !inSelectList(ast, order.Field) {
+			return fmt.Errorf("SELECT DISTINCT cannot ORDER BY %s: ORDER BY expressions must appear in the select list; add it to Fields or order by a selected column",
+				qualifiedName(order.Field))
+		}
+	}
 	return nil
 }
 
+// inSelectList reports whether field is a selected field or names a selected expression alias.
+func inSelectList(ast *types.AST, field types.Field) bool {
+	for _, selected := range ast.Fields {
+		if sameField(selected, field) {
+			return true
+		}
+	}
+	if field.Table == "" && !field.HasJSONAccess() {
+		for _, expr := range ast.FieldExpressions {
+			if expr.Alias == field.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameField compares two field references, including JSON access keys.
+func sameField(a, b types.Field) bool {
+	paramName := func(p *types.Param) string {
+		if p == nil {
+			return ""
+		}
+		return p.Name
+	}
+	return a.Name == b.Name && a.Table == b.Table &&
+		a.JSONKey == b.JSONKey && a.JSONPath == b.JSONPath &&
+		paramName(a.JSONBTextKey) == paramName(b.JSONBTextKey) &&
+		paramName(a.JSONBPathKey) == paramName(b.JSONBPathKey)
+}
+
+// qualifiedName returns table.name, or name when the field is unqualified.
+func qualifiedName(field types.Field) string {
+	if field.Table != "" {
+		return field.Table + "." + field.Name
+	}
+	return field.Name
+}
+
+// renderLiteral renders a literal value inline. PostgreSQL has native boolean literals.
+func (r *Renderer) renderLiteral(lit types.Literal) string {
+	switch lit {
+	case types.LiteralTrue:
+		return "TRUE"
+	case types.LiteralFalse:
+		return "FALSE"
+	case types.LiteralOne:
+		return "1"
+	case types.LiteralDefault:
+		return "DEFAULT"
+	default:
+		// Unreachable: AST validation rejects unknown literals.
+		return "NULL"
+	}
+}
+
 func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
 	sql.WriteString("INSERT INTO ")
 	sql.WriteString(r.renderTable(ast.Target))
@@ -354,17 +596,9 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		return fmt.Errorf("INSERT requires at least one value set")
 	}
 
-	// Extract field names from first value set
-	fields := make([]string, 0, len(ast.Values[0]))
-	fieldObjs := make([]types.Field, 0, len(ast.Values[0]))
-	for field := range ast.Values[0] {
-		fieldObjs = append(fieldObjs, field)
-	}
-
-	// Sort fields by name for deterministic output
-	sort.Slice(fieldObjs, func(i, j int) bool {
-		return fieldObjs[i].Name < fieldObjs[j].Name
-	})
+	// Columns sorted by name for deterministic output, including subquery cells
+	fieldObjs := ast.InsertColumns()
+	fields := make([]string, 0, len(fieldObjs))
 
 	for _, field := range fieldObjs {
 		fields = append(fields, r.quoteIdentifier(field.Name))
@@ -376,11 +610,27 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 
 	// Render value sets
 	valueSets := make([]string, 0, len(ast.Values))
-	for _, valueSet := range ast.Values {
+	ctx := newRenderContext(addParam)
+	for i, valueSet := range ast.Values {
 		var values []string
 		for _, field := range fieldObjs {
-			param := valueSet[field]
-			values = append(values, addParam(param))
+			if sub, ok := ast.ValueSubquery(i, field); ok {
+				var subSQL strings.Builder
+				if err := r.renderSubquery(sub, &subSQL, ctx); err != nil {
+					return err
+				}
+				values = append(values, "("+subSQL.String()+")")
+				continue
+			}
+			if array, ok := ast.ValueArray(i, field); ok {
+				arraySQL, err := r.renderArray(array, ctx)
+				if err != nil {
+					return err
+				}
+				values = append(values, arraySQL)
+				continue
+			}
+			values = append(values, addParam(valueSet[field]))
 		}
 		valueSets = append(valueSets, "("+strings.Join(values, ", ")+")")
 	}
@@ -395,6 +645,13 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		}
 		sql.WriteString(strings.Join(conflictFields, ", "))
 		sql.WriteString(") ")
+		if ast.OnConflict.ConflictPredicate != nil {
+			sql.WriteString("WHERE ")
+			if err := r.renderCondition(ast.OnConflict.ConflictPredicate, sql, ctx); err != nil {
+				return err
+			}
+			sql.WriteString(" ")
+		}
 
 		switch ast.OnConflict.Action {
 		case types.DoNothing:
@@ -417,9 +674,21 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 			var updates []string
 			for _, field := range conflictUpdateFields {
 				param := ast.OnConflict.Updates[field]
-				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), addParam(param)))
+				var value string
+				if param.IsExcluded() {
+					value = "EXCLUDED." + r.quoteIdentifier(param.Excluded.Field.Name)
+				} else {
+					value = addParam(param)
+				}
+				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), value))
 			}
 			sql.WriteString(strings.Join(updates, ", "))
+			if ast.OnConflict.DoUpdateWhere != nil {
+				sql.WriteString(" WHERE ")
+				if err := r.renderCondition(ast.OnConflict.DoUpdateWhere, sql, ctx); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -482,6 +751,21 @@ func (r *Renderer) renderUpdate(ast *types.AST, sql *strings.Builder, addParam f
 		updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), rendered))
 	}
 
+	// Render row assignments from subqueries
+	for _, row := range ast.UpdateRows {
+		columns := make([]string, 0, len(row.Fields))
+		for _, field := range row.Fields {
+			columns = append(columns, r.quoteIdentifier(field.Name))
+		}
+		var sub strings.Builder
+		sub.WriteString("(" + strings.Join(columns, ", ") + ") = (")
+		if err := r.renderSubquery(row.Subquery, &sub, newRenderContext(addParam)); err != nil {
+			return err
+		}
+		sub.WriteString(")")
+		updates = append(updates, sub.String())
+	}
+
 	sql.WriteString(strings.Join(updates, ", "))
 
 	// WHERE clause
@@ -534,30 +818,54 @@ func (r *Renderer) renderDelete(ast *types.AST, sql *strings.Builder, addParam f
 	return nil
 }
 
+// renderReturningCount runs renderDML, wrapping it in a data-modifying CTE
+// that is counted when ast.ReturningCount is set:
+// WITH "affected" AS (UPDATE ... RETURNING 1) SELECT COUNT(*) FROM "affected".
+func (r *Renderer) renderReturningCount(ast *types.AST, sql *strings.Builder, renderDML func() error) error {
+	if !ast.ReturningCount {
+		return renderDML()
+	}
+	affected := r.quoteIdentifier("affected")
+	sql.WriteString("WITH ")
+	sql.WriteString(affected)
+	sql.WriteString(" AS (")
+	if err := renderDML(); err != nil {
+		return err
+	}
+	sql.WriteString(" RETURNING 1) SELECT COUNT(*) FROM ")
+	sql.WriteString(affected)
+	return nil
+}
+
+// renderTruncate renders TRUNCATE TABLE with the optional RESTART IDENTITY and CASCADE.
+func (r *Renderer) renderTruncate(ast *types.AST, sql *strings.Builder) {
+	sql.WriteString("TRUNCATE TABLE ")
+	sql.WriteString(r.quoteIdentifier(ast.Target.Name))
+	if ast.RestartIdentity {
+		sql.WriteString(" RESTART IDENTITY")
+	}
+	if ast.Cascade {
+		sql.WriteString(" CASCADE")
+	}
+}
+
 func (r *Renderer) renderCount(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
+	ctx := newRenderContext(addParam)
 	sql.WriteString("SELECT " + countStarSQL + " FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	// Render JOINs (COUNT can have JOINs)
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		// CROSS JOIN doesn't have ON clause
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			ctx := newRenderContext(addParam)
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
 	// WHERE clause
 	if ast.WhereClause != nil {
 		sql.WriteString(" WHERE ")
-		ctx := newRenderContext(addParam)
 		if err := r.renderCondition(ast.WhereClause, sql, ctx); err != nil {
 			return err
 		}
@@ -595,6 +903,14 @@ func (r *Renderer) renderFieldCtx(field types.Field, ctx *renderContext) string
 		base = quotedName
 	}
 
+	// Literal keys are checked by Field.Validate, so they are safe to quote inline
+	if field.JSONKey != "" {
+		return fmt.Sprintf("%s->>'%s'", base, field.JSONKey)
+	}
+	if field.JSONPath != "" {
+		return fmt.Sprintf("%s#>>'{%s}'", base, field.JSONPath)
+	}
+
 	// Handle JSONB field access with parameterized keys
 	if field.JSONBTextKey != nil {
 		if ctx == nil {
@@ -612,6 +928,93 @@ func (r *Renderer) renderFieldCtx(field types.Field, ctx *renderContext) string
 	return base
 }
 
+// renderFrom renders the FROM target: a table or a derived table with its alias.
+func (r *Renderer) renderFrom(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	if ast.Target.Values != nil {
+		sql.WriteString(r.renderValuesTable(ast.Target, ctx))
+		return nil
+	}
+	if ast.TargetSubquery == nil {
+		sql.WriteString(r.renderTable(ast.Target))
+		return nil
+	}
+	sql.WriteString("(")
+	if err := r.renderSubquery(types.Subquery{AST: ast.TargetSubquery}, sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(") AS ")
+	sql.WriteString(ast.TargetAlias)
+	return nil
+}
+
+// renderValuesTable renders an inline VALUES source with its alias and
+// column list.
+func (r *Renderer) renderValuesTable(table types.Table, ctx *renderContext) string {
+	rows := make([]string, 0, len(table.Values.Rows))
+	for _, row := range table.Values.Rows {
+		values := make([]string, 0, len(row))
+		for _, value := range row {
+			values = append(values, ctx.addParam(value))
+		}
+		rows = append(rows, "("+strings.Join(values, ", ")+")")
+	}
+	columns := make([]string, 0, len(table.Values.Columns))
+	for _, col := range table.Values.Columns {
+		columns = append(columns, r.quoteIdentifier(col))
+	}
+	return "(VALUES " + strings.Join(rows, ", ") + ") AS " + table.Alias + "(" + strings.Join(columns, ", ") + ")"
+}
+
+// renderJoin renders a single JOIN clause against a table or derived table.
+func (r *Renderer) renderJoin(join types.Join, sql *strings.Builder, ctx *renderContext) error {
+	sql.WriteString(" ")
+	sql.WriteString(string(join.Type))
+	sql.WriteString(" ")
+	if join.Lateral {
+		sql.WriteString("LATERAL ")
+	}
+	if join.Subquery != nil {
+		sql.WriteString("(")
+		if err := r.renderSubquery(*join.Subquery, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(") AS ")
+		sql.WriteString(join.Table.Alias)
+		if len(join.Columns) > 0 {
+			columns := make([]string, 0, len(join.Columns))
+			for _, col := range join.Columns {
+				columns = append(columns, r.quoteIdentifier(col))
+			}
+			sql.WriteString("(" + strings.Join(columns, ", ") + ")")
+		}
+	} else if join.Table.Values != nil {
+		sql.WriteString(r.renderValuesTable(join.Table, ctx))
+	} else {
+		sql.WriteString(r.renderTable(join.Table))
+	}
+
+	// USING replaces ON; CROSS JOIN and NATURAL JOIN have neither
+	if len(join.Using) > 0 {
+		sql.WriteString(" USING ")
+		sql.WriteString(r.renderUsing(join.Using))
+	} else if join.Type.RequiresOn() {
+		sql.WriteString(" ON ")
+		if err := r.renderCondition(join.On, sql, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderUsing renders a JOIN USING column list with unqualified column names.
+func (r *Renderer) renderUsing(fields []types.Field) string {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, r.quoteIdentifier(field.Name))
+	}
+	return "(" + strings.Join(columns, ", ") + ")"
+}
+
 // renderField renders a simple field (no JSONB access).
 // For fields with JSONB access, use renderFieldCtx instead.
 func (r *Renderer) renderField(field types.Field) string {
@@ -675,6 +1078,10 @@ func (r *Renderer) renderAggregateExpressionCtx(aggregate types.AggregateFunc, f
 }
 
 func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *renderContext) (string, error) {
+	if err := r.validateFeatures(expr); err != nil {
+		return "", err
+	}
+
 	var result string
 
 	switch {
@@ -720,9 +1127,19 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 			return "", err
 		}
 		result = dateStr
+	case expr.DateDiff != nil:
+		// Render the part units between two dates
+		diffStr, err := r.renderDateDiff(*expr.DateDiff, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = diffStr
 	case expr.Cast != nil:
 		// Render type cast
-		result = fmt.Sprintf("CAST(%s AS %s)", r.renderFieldCtx(expr.Cast.Field, ctx), string(expr.Cast.CastType))
+		if err := expr.Cast.Validate(); err != nil {
+			return "", err
+		}
+		result = fmt.Sprintf("CAST(%s AS %s%s)", r.renderFieldCtx(expr.Cast.Field, ctx), string(expr.Cast.CastType), expr.Cast.TypeParams())
 	case expr.Window != nil:
 		// Render window function
 		windowStr, err := r.renderWindowExpression(*expr.Window, ctx)
@@ -735,6 +1152,46 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 		paramStr := ctx.addParam(expr.Binary.Param)
 		opStr := r.renderOperator(expr.Binary.Operator)
 		result = fmt.Sprintf("%s %s %s", r.renderFieldCtx(expr.Binary.Field, ctx), opStr, paramStr)
+	case expr.Grouping != nil:
+		// Render GROUPING() for subtotal rows
+		groupFields := make([]string, 0, len(expr.Grouping.Fields))
+		for _, field := range expr.Grouping.Fields {
+			groupFields = append(groupFields, r.renderFieldCtx(field, ctx))
+		}
+		result = fmt.Sprintf("GROUPING(%s)", strings.Join(groupFields, ", "))
+	case expr.Arithmetic != nil:
+		// Render arithmetic between expressions
+		arithStr, err := r.renderArithmetic(*expr.Arithmetic, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = arithStr
+	case expr.Func != nil:
+		// Render a registered custom function
+		funcStr, err := r.renderFunc(*expr.Func, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = funcStr
+	case expr.Array != nil:
+		arrayStr, err := r.renderArray(*expr.Array, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = arrayStr
+	case expr.JSONObject != nil:
+		// Render JSON object construction
+		jsonStr, err := r.renderJSONObject(*expr.JSONObject, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = jsonStr
+	case expr.OrderedAgg != nil:
+		aggStr, err := r.renderOrderedAggregate(*expr.OrderedAgg, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = aggStr
 	case expr.Aggregate != "":
 		result = r.renderAggregateExpressionCtx(expr.Aggregate, expr.Field, ctx)
 		// Add FILTER clause if present
@@ -758,6 +1215,130 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 	return result, nil
 }
 
+// renderOrderedAggregate renders STRING_AGG(field, :sep ORDER BY ...) or
+// ARRAY_AGG([DISTINCT] field ORDER BY ...).
+func (r *Renderer) renderOrderedAggregate(expr types.OrderedAggregate, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	sql.WriteString(string(expr.Function))
+	sql.WriteString("(")
+	if expr.Distinct {
+		sql.WriteString("DISTINCT ")
+	}
+	sql.WriteString(r.renderFieldCtx(expr.Field, ctx))
+	if expr.Separator != nil {
+		sql.WriteString(", ")
+		sql.WriteString(ctx.addParam(*expr.Separator))
+	}
+	if len(expr.OrderBy) > 0 {
+		var orderParts []string
+		for i := range expr.OrderBy {
+			order := &expr.OrderBy[i]
+			var part string
+			if order.Operator != "" {
+				part = fmt.Sprintf("%s %s %s %s",
+					r.renderFieldCtx(order.Field, ctx),
+					r.renderOperator(order.Operator),
+					ctx.addParam(order.Param),
+					order.Direction)
+			} else {
+				part = fmt.Sprintf("%s %s", r.renderFieldCtx(order.Field, ctx), order.Direction)
+			}
+			if order.Nulls != "" {
+				part += " " + string(order.Nulls)
+			}
+			orderParts = append(orderParts, part)
+		}
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(orderParts, ", "))
+	}
+	sql.WriteString(")")
+	return sql.String(), nil
+}
+
+// renderArithmetic renders left <op> right. Nested arithmetic operands are
+// parenthesized so the expression tree's grouping is preserved.
+func (r *Renderer) renderArithmetic(expr types.ArithmeticExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	left, err := r.renderArithmeticOperand(expr.Left, ctx)
+	if err != nil {
+		return "", err
+	}
+	right, err := r.renderArithmeticOperand(expr.Right, ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, expr.Operator, right), nil
+}
+
+func (r *Renderer) renderArithmeticOperand(operand types.ArithmeticOperand, ctx *renderContext) (string, error) {
+	if operand.Param != nil {
+		return ctx.addParam(*operand.Param), nil
+	}
+	result, err := r.renderFieldExpression(*operand.Expr, ctx)
+	if err != nil {
+		return "", err
+	}
+	if operand.Expr.Arithmetic != nil {
+		result = "(" + result + ")"
+	}
+	return result, nil
+}
+
+// renderJSONObject renders json_build_object('key', value, ...).
+// Keys are validated identifiers, so they are safe to emit as string literals.
+func (r *Renderer) renderJSONObject(expr types.JSONObjectExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Pairs)*2)
+	for _, pair := range expr.Pairs {
+		args = append(args, "'"+pair.Key+"'")
+		if pair.Field != nil {
+			args = append(args, r.renderFieldCtx(*pair.Field, ctx))
+		} else {
+			args = append(args, ctx.addParam(*pair.Param))
+		}
+	}
+	return fmt.Sprintf("json_build_object(%s)", strings.Join(args, ", ")), nil
+}
+
+// renderArray renders ARRAY[:a, :b, ...].
+func (r *Renderer) renderArray(expr types.ArrayExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	elems := make([]string, 0, len(expr.Values))
+	for _, value := range expr.Values {
+		elems = append(elems, ctx.addParam(value))
+	}
+	return "ARRAY[" + strings.Join(elems, ", ") + "]", nil
+}
+
+// renderFunc renders a call to a custom function registered with RegisterFunction.
+func (r *Renderer) renderFunc(expr types.FuncExpression, ctx *renderContext) (string, error) {
+	if err := r.funcs.Check("postgres", expr); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Args))
+	for _, arg := range expr.Args {
+		if arg.Field != nil {
+			args = append(args, r.renderFieldCtx(*arg.Field, ctx))
+		} else {
+			args = append(args, ctx.addParam(*arg.Param))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", expr.Name, strings.Join(args, ", ")), nil
+}
+
 func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builder, ctx *renderContext) error {
 	switch c := cond.(type) {
 	case types.Condition:
@@ -782,10 +1363,22 @@ func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builde
 			r.renderFieldCtx(c.LeftField, ctx),
 			r.renderOperator(c.Operator),
 			r.renderFieldCtx(c.RightField, ctx))
+	case types.Comparison:
+		if err := r.renderValueComparison(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.SubqueryCondition:
 		if err := r.renderSubqueryCondition(c, sql, ctx); err != nil {
 			return err
 		}
+	case types.TupleCondition:
+		if err := r.renderTupleCondition(c, sql, ctx); err != nil {
+			return err
+		}
+	case types.InListCondition:
+		if err := r.renderInListCondition(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.AggregateCondition:
 		sql.WriteString(r.renderAggregateCondition(c, ctx))
 	case types.BetweenCondition:
@@ -874,6 +1467,38 @@ func (r *Renderer) renderBetweenCondition(cond types.BetweenCondition, ctx *rend
 	return fmt.Sprintf("%s %s %s AND %s", field, op, ctx.addParam(cond.Low), ctx.addParam(cond.High))
 }
 
+// renderValueComparison renders field op value for a param, field, or
+// scalar subquery on the right-hand side.
+func (r *Renderer) renderValueComparison(cond types.Comparison, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	sql.WriteString(r.renderFieldCtx(cond.Field, ctx))
+	sql.WriteString(" ")
+	sql.WriteString(r.renderOperator(cond.Operator))
+	sql.WriteString(" ")
+	return r.renderValue(cond.Value, sql, ctx)
+}
+
+// renderValue renders the right-hand side of a Comparison.
+func (r *Renderer) renderValue(value types.Value, sql *strings.Builder, ctx *renderContext) error {
+	switch v := value.(type) {
+	case types.Param:
+		sql.WriteString(ctx.addParam(v))
+	case types.Field:
+		sql.WriteString(r.renderFieldCtx(v, ctx))
+	case types.Subquery:
+		sql.WriteString("(")
+		if err := r.renderSubquery(v, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(")")
+	default:
+		return fmt.Errorf("unknown value type: %T", v)
+	}
+	return nil
+}
+
 func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *strings.Builder, ctx *renderContext) error {
 	switch cond.Operator {
 	case types.EXISTS, types.NotExists:
@@ -887,20 +1512,120 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 		}
 		sql.WriteString(r.renderFieldCtx(*cond.Field, ctx))
 		sql.WriteString(" ")
-		sql.WriteString(string(cond.Operator))
+		if cond.Quantifier != types.QuantNone {
+			// Quantified comparison: field op ANY|ALL (subquery)
+			if !cond.Operator.IsComparison() {
+				return fmt.Errorf("operator %s cannot be used with %s", cond.Operator, cond.Quantifier)
+			}
+			sql.WriteString(r.renderOperator(cond.Operator))
+			sql.WriteString(" ")
+			sql.WriteString(string(cond.Quantifier))
+		} else {
+			sql.WriteString(string(cond.Operator))
+		}
 		sql.WriteString(" ")
 	}
 
 	// Render the subquery
 	sql.WriteString("(")
-	if err := r.renderSubquery(cond.Subquery, sql, ctx); err != nil {
+	if cond.Operator == types.EXISTS || cond.Operator == types.NotExists {
+		if err := r.renderExistsSubquery(cond.Subquery, sql, ctx); err != nil {
+			return err
+		}
+	} else if err := r.renderSubquery(r.inSubquery(cond.Operator, cond.Subquery), sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(")")
+
+	return nil
+}
+
+// renderInListCondition renders field IN (:a, :b). An empty list renders a
+// constant guard predicate unless StrictEmptyIn is set.
+func (r *Renderer) renderInListCondition(cond types.InListCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	if len(cond.Values) == 0 {
+		guard, err := render.EmptyInGuard(cond, r.opts.StrictEmptyIn)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(guard)
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		placeholders = append(placeholders, ctx.addParam(value))
+	}
+	if cond.ValuesTable {
+		fmt.Fprintf(sql, "%s %s (%s)", r.renderFieldCtx(cond.Field, ctx), cond.Operator, render.ValuesRows(placeholders))
+		return nil
+	}
+	fmt.Fprintf(sql, "%s %s (%s)", r.renderFieldCtx(cond.Field, ctx), cond.Operator, strings.Join(placeholders, ", "))
+	return nil
+}
+
+// renderTupleCondition renders a row value comparison: ("a", "b") op (:p1, :p2) or (subquery).
+func (r *Renderer) renderTupleCondition(cond types.TupleCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
 		return err
 	}
+
+	sql.WriteString("(")
+	for i, field := range cond.Fields {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(r.renderFieldCtx(field, ctx))
+	}
+	sql.WriteString(") ")
+
+	if cond.Operator == types.IN || cond.Operator == types.NotIn {
+		sql.WriteString(string(cond.Operator))
+	} else {
+		sql.WriteString(r.renderOperator(cond.Operator))
+	}
+	sql.WriteString(" (")
+
+	if cond.Subquery != nil {
+		if err := r.renderSubquery(r.inSubquery(cond.Operator, *cond.Subquery), sql, ctx); err != nil {
+			return err
+		}
+	} else {
+		for i, value := range cond.Values {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(ctx.addParam(value))
+		}
+	}
 	sql.WriteString(")")
 
 	return nil
 }
 
+// renderExistsSubquery renders an EXISTS subquery as SELECT 1 without a redundant LIMIT 1.
+func (r *Renderer) renderExistsSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
+	subCtx, err := ctx.withSubquery()
+	if err != nil {
+		return err
+	}
+
+	ast, selectOne := types.ExistsSubquery(subquery.AST)
+	subCtx.selectOne = selectOne
+	return r.renderSelect(ast, sql, subCtx)
+}
+
+// inSubquery applies the StripInSubqueryDistinct option to IN/NOT IN subqueries.
+func (r *Renderer) inSubquery(op types.Operator, subquery types.Subquery) types.Subquery {
+	if !r.opts.StripInSubqueryDistinct || (op != types.IN && op != types.NotIn) {
+		return subquery
+	}
+	return types.Subquery{AST: types.StripDistinct(subquery.AST)}
+}
+
 func (r *Renderer) renderSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
 	// Create a new context for the subquery
 	subCtx, err := ctx.withSubquery()
@@ -1059,6 +1784,39 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 			sql.WriteString(r.renderField(f))
 		}
 		sql.WriteString(")")
+	case types.StringLPad, types.StringRPad:
+		sql.WriteString(string(expr.Function))
+		sql.WriteString("(")
+		sql.WriteString(r.renderField(expr.Field))
+		for _, arg := range expr.Args {
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(arg))
+		}
+		sql.WriteString(")")
+	case types.StringReverse:
+		sql.WriteString("REVERSE(")
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringInitcap:
+		sql.WriteString("INITCAP(")
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringPosition:
+		sql.WriteString("POSITION(")
+		if len(expr.Args) >= 1 {
+			sql.WriteString(ctx.addParam(expr.Args[0]))
+			sql.WriteString(" IN ")
+		}
+		sql.WriteString(r.renderField(expr.Field))
+		sql.WriteString(")")
+	case types.StringSplitPart:
+		sql.WriteString("SPLIT_PART(")
+		sql.WriteString(r.renderField(expr.Field))
+		for _, arg := range expr.Args {
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(arg))
+		}
+		sql.WriteString(")")
 	default:
 		return "", fmt.Errorf("unsupported string function: %s", expr.Function)
 	}
@@ -1066,12 +1824,15 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 	return sql.String(), nil
 }
 
-func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderContext) (string, error) {
+func (r *Renderer) renderDateExpression(expr types.DateExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
 	switch expr.Function {
 	case types.DateNow:
 		sql.WriteString("NOW()")
+	case types.DateNowTz:
+		// now() returns timestamptz
+		sql.WriteString("now()")
 	case types.DateCurrentDate:
 		sql.WriteString("CURRENT_DATE")
 	case types.DateCurrentTime:
@@ -1096,6 +1857,32 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 		sql.WriteString("', ")
 		sql.WriteString(r.renderField(*expr.Field))
 		sql.WriteString(")")
+	case types.DateBin:
+		if expr.Field == nil || expr.Interval == nil || expr.Origin == nil {
+			return "", fmt.Errorf("DATE_BIN requires an interval, a field, and an origin")
+		}
+		sql.WriteString("DATE_BIN(")
+		sql.WriteString(ctx.addParam(*expr.Interval))
+		sql.WriteString(", ")
+		sql.WriteString(r.renderField(*expr.Field))
+		sql.WriteString(", ")
+		sql.WriteString(ctx.addParam(*expr.Origin))
+		sql.WriteString(")")
+	case types.DateAdd, types.DateSub:
+		if err := expr.Validate(); err != nil {
+			return "", err
+		}
+		op := " + "
+		if expr.Function == types.DateSub {
+			op = " - "
+		}
+		sql.WriteString(r.renderField(*expr.Field))
+		sql.WriteString(op)
+		sql.WriteString("(")
+		sql.WriteString(ctx.addParam(*expr.Amount))
+		sql.WriteString(" * INTERVAL '")
+		sql.WriteString(intervalUnit(expr.Part))
+		sql.WriteString("')")
 	default:
 		return "", fmt.Errorf("unsupported date function: %s", expr.Function)
 	}
@@ -1103,6 +1890,83 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 	return sql.String(), nil
 }
 
+// renderDateDiff renders the whole Part units from Start to End. Fixed-length
+// units divide the elapsed seconds; calendar units read AGE(), which
+// accounts for months of different lengths. Operands are rendered once per
+// use, in SQL order, so positional placeholders bind correctly.
+func (r *Renderer) renderDateDiff(expr types.DateDiffExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	// pair renders "end<sep>start" with fresh params for both sides
+	pair := func(sep string) (string, error) {
+		var sql strings.Builder
+		if err := r.renderValue(expr.End, &sql, ctx); err != nil {
+			return "", err
+		}
+		sql.WriteString(sep)
+		if err := r.renderValue(expr.Start, &sql, ctx); err != nil {
+			return "", err
+		}
+		return sql.String(), nil
+	}
+
+	switch expr.Part {
+	case types.PartYear, types.PartQuarter, types.PartMonth:
+		years, err := pair(", ")
+		if err != nil {
+			return "", err
+		}
+		if expr.Part == types.PartYear {
+			return fmt.Sprintf("EXTRACT(YEAR FROM AGE(%s))", years), nil
+		}
+		months, err := pair(", ")
+		if err != nil {
+			return "", err
+		}
+		total := fmt.Sprintf("EXTRACT(YEAR FROM AGE(%s)) * 12 + EXTRACT(MONTH FROM AGE(%s))", years, months)
+		if expr.Part == types.PartQuarter {
+			return fmt.Sprintf("TRUNC((%s) / 3)", total), nil
+		}
+		return "(" + total + ")", nil
+	}
+
+	diff, err := pair(" - ")
+	if err != nil {
+		return "", err
+	}
+	elapsed := fmt.Sprintf("EXTRACT(EPOCH FROM (%s))", diff)
+	if seconds := partSeconds(expr.Part); seconds > 1 {
+		return fmt.Sprintf("TRUNC(%s / %d)", elapsed, seconds), nil
+	}
+	return "TRUNC(" + elapsed + ")", nil
+}
+
+// partSeconds returns the length in seconds of a fixed-length date part.
+func partSeconds(part types.DatePart) int {
+	switch part {
+	case types.PartWeek:
+		return 7 * 86400
+	case types.PartDay:
+		return 86400
+	case types.PartHour:
+		return 3600
+	case types.PartMinute:
+		return 60
+	default:
+		return 1
+	}
+}
+
+// intervalUnit returns a one-unit interval literal for a date part.
+// PostgreSQL has no quarter interval, so a quarter is three months.
+func intervalUnit(part types.DatePart) string {
+	if part == types.PartQuarter {
+		return "3 months"
+	}
+	return "1 " + strings.ToLower(string(part))
+}
+
 func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
@@ -1160,25 +2024,67 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		}
 	}
 
-	// Render OVER clause
+	if expr.NullTreatment != "" {
+		treatment, err := r.renderNullTreatment(expr)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(treatment)
+	}
+
+	// Render OVER clause, referencing a named window when set
+	if expr.WindowRef != "" {
+		sql.WriteString(" OVER ")
+		sql.WriteString(r.quoteIdentifier(expr.WindowRef))
+		return sql.String(), nil
+	}
 	sql.WriteString(" OVER (")
+	sql.WriteString(r.renderWindowSpec(expr.Window, ctx))
+	sql.WriteString(")")
+
+	return sql.String(), nil
+}
+
+// renderNullTreatment renders IGNORE NULLS or RESPECT NULLS after the argument list.
+// PostgreSQL accepts neither clause; RESPECT NULLS is its
+// default behavior and renders as nothing.
+func (r *Renderer) renderNullTreatment(expr types.WindowExpression) (string, error) {
+	return "", r.validateNullTreatment(expr)
+}
 
+// renderWindowDefinitions renders the named windows as name AS (...), sorted by name.
+func (r *Renderer) renderWindowDefinitions(windows map[string]types.WindowSpec, ctx *renderContext) string {
+	names := make([]string, 0, len(windows))
+	for name := range windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defs := make([]string, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, fmt.Sprintf("%s AS (%s)", r.quoteIdentifier(name), r.renderWindowSpec(windows[name], ctx)))
+	}
+	return strings.Join(defs, ", ")
+}
+
+// renderWindowSpec renders a window definition body: PARTITION BY, ORDER BY and frame.
+func (r *Renderer) renderWindowSpec(spec types.WindowSpec, ctx *renderContext) string {
 	var overParts []string
 
 	// PARTITION BY
-	if len(expr.Window.PartitionBy) > 0 {
+	if len(spec.PartitionBy) > 0 {
 		var partitionFields []string
-		for _, field := range expr.Window.PartitionBy {
+		for _, field := range spec.PartitionBy {
 			partitionFields = append(partitionFields, r.renderField(field))
 		}
 		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionFields, ", "))
 	}
 
 	// ORDER BY
-	if len(expr.Window.OrderBy) > 0 {
+	if len(spec.OrderBy) > 0 {
 		var orderParts []string
-		for i := range expr.Window.OrderBy {
-			order := &expr.Window.OrderBy[i]
+		for i := range spec.OrderBy {
+			order := &spec.OrderBy[i]
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
@@ -1198,20 +2104,28 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 	}
 
 	// Frame clause
-	if expr.Window.FrameStart != "" {
-		framePart := "ROWS BETWEEN " + string(expr.Window.FrameStart) + " AND "
-		if expr.Window.FrameEnd != "" {
-			framePart += string(expr.Window.FrameEnd)
-		} else {
-			framePart += "CURRENT ROW"
+	if spec.FrameStart != "" {
+		end := string(types.FrameCurrentRow)
+		if spec.FrameEnd != "" {
+			end = r.renderFrameBound(spec.FrameEnd, spec.FrameEndOffset, ctx)
+		}
+		framePart := fmt.Sprintf("%s BETWEEN %s AND %s", spec.Unit(),
+			r.renderFrameBound(spec.FrameStart, spec.FrameStartOffset, ctx), end)
+		if spec.FrameExclude != "" {
+			framePart += " EXCLUDE " + string(spec.FrameExclude)
 		}
 		overParts = append(overParts, framePart)
 	}
 
-	sql.WriteString(strings.Join(overParts, " "))
-	sql.WriteString(")")
+	return strings.Join(overParts, " ")
+}
 
-	return sql.String(), nil
+// renderFrameBound renders a frame bound, prefixing offset bounds with their parameter.
+func (r *Renderer) renderFrameBound(bound types.FrameBound, offset *types.Param, ctx *renderContext) string {
+	if offset != nil {
+		return ctx.addParam(*offset) + " " + string(bound)
+	}
+	return string(bound)
 }
 
 func (r *Renderer) renderOperator(op types.Operator) string {
@@ -1271,6 +2185,145 @@ func (r *Renderer) renderOperator(op types.Operator) string {
 	}
 }
 
+// Validate reports every PostgreSQL rejection in ast at once, in the order
+// Render would meet them, or nil when the AST renders on PostgreSQL. Render
+// stops at the first of these errors; Validate is the dry run that lists
+// them all. Structural problems are left to ast.Validate.
+func (r *Renderer) Validate(ast *types.AST) []error {
+	if r.opts.LowercaseIdentifiers {
+		ast = render.LowercaseIdentifiers(ast)
+	}
+	var errs []error
+	r.collectAST(ast, &errs)
+	return errs
+}
+
+// validateAST returns the first PostgreSQL rejection in ast.
+func (r *Renderer) validateAST(ast *types.AST) error {
+	var errs []error
+	r.collectAST(ast, &errs)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// collectAST appends an error for each PostgreSQL rejection in ast and the
+// subqueries it renders. EXISTS and IN subqueries are checked as they render,
+// after DISTINCT and ORDER BY are trimmed.
+func (r *Renderer) collectAST(ast *types.AST, errs *[]error) {
+	if ast == nil {
+		return
+	}
+
+	if ast.Operation == types.OpSelect {
+		if err := validateDistinctOrderBy(ast); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+
+	for i := range ast.FieldExpressions {
+		if err := r.validateFieldExpression(ast.FieldExpressions[i]); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+	for _, expr := range ast.UpdateExpressions {
+		if err := r.validateFieldExpression(expr); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+
+	r.collectAST(ast.TargetSubquery, errs)
+	for _, join := range ast.Joins {
+		if join.Subquery != nil {
+			r.collectAST(join.Subquery.AST, errs)
+		}
+		r.collectCondition(join.On, errs)
+	}
+	r.collectCondition(ast.WhereClause, errs)
+	for _, having := range ast.Having {
+		r.collectCondition(having, errs)
+	}
+	if ast.OnConflict != nil {
+		r.collectCondition(ast.OnConflict.ConflictPredicate, errs)
+		r.collectCondition(ast.OnConflict.DoUpdateWhere, errs)
+	}
+	for _, cells := range ast.ValueSubqueries {
+		for _, sub := range cells {
+			r.collectAST(sub.AST, errs)
+		}
+	}
+	for _, row := range ast.UpdateRows {
+		r.collectAST(row.Subquery.AST, errs)
+	}
+}
+
+// collectCondition collects errors from the subqueries of a condition tree.
+func (r *Renderer) collectCondition(cond types.ConditionItem, errs *[]error) {
+	switch c := cond.(type) {
+	case types.ConditionGroup:
+		for _, sub := range c.Conditions {
+			r.collectCondition(sub, errs)
+		}
+	case types.SubqueryCondition:
+		if c.Operator == types.EXISTS || c.Operator == types.NotExists {
+			trimmed, _ := types.ExistsSubquery(c.Subquery.AST)
+			r.collectAST(trimmed, errs)
+			return
+		}
+		r.collectAST(r.inSubquery(c.Operator, c.Subquery).AST, errs)
+	case types.Comparison:
+		if sub, ok := c.Value.(types.Subquery); ok {
+			r.collectAST(sub.AST, errs)
+		}
+	case types.TupleCondition:
+		if c.Subquery != nil {
+			r.collectAST(r.inSubquery(c.Operator, *c.Subquery).AST, errs)
+		}
+	}
+}
+
+// validateFieldExpression checks expr and its nested arithmetic operands.
+func (r *Renderer) validateFieldExpression(expr types.FieldExpression) error {
+	if err := r.validateFeatures(expr); err != nil {
+		return err
+	}
+	if expr.Arithmetic != nil {
+		for _, operand := range []types.ArithmeticOperand{expr.Arithmetic.Left, expr.Arithmetic.Right} {
+			if operand.Expr != nil {
+				if err := r.validateFieldExpression(*operand.Expr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateFeatures returns the first PostgreSQL rejection expr has at its own
+// level. renderFieldExpression calls it too, so Validate and Render reject the
+// same expressions.
+func (r *Renderer) validateFeatures(expr types.FieldExpression) error {
+	switch {
+	case expr.Window != nil:
+		return r.validateNullTreatment(*expr.Window)
+	case expr.OrderedAgg != nil:
+		if expr.OrderedAgg.Function == types.AggStringAgg && expr.OrderedAgg.Separator == nil {
+			return fmt.Errorf("%s requires a separator", expr.OrderedAgg.Function)
+		}
+	}
+	return nil
+}
+
+// validateNullTreatment rejects IGNORE NULLS, which PostgreSQL does not accept.
+func (r *Renderer) validateNullTreatment(expr types.WindowExpression) error {
+	if expr.NullTreatment == "" || expr.NullTreatment == types.NullsRespect {
+		return nil
+	}
+	return render.NewUnsupportedFeatureError("postgres", string(expr.NullTreatment)+" on "+string(expr.Function),
+		"use a subquery that filters NULLs, or carry values forward with a running COUNT partition")
+}
+
 // Capabilities returns the SQL features supported by PostgreSQL.
 func (r *Renderer) Capabilities() render.Capabilities {
 	return render.Capabilities{
@@ -1284,5 +2337,11 @@ func (r *Renderer) Capabilities() render.Capabilities {
 		ArrayOperators:      true,
 		InArray:             true,
 		RowLocking:          render.RowLockingFull,
+		FullOuterJoin:       true,
+		RightJoin:           true,
+		SkipLocked:          true,
+		WithTies:            true,
+
+		GroupConcatTruncates: false,
 	}
 }
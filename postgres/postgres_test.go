@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/zoobzio/astql/internal/render"
@@ -86,6 +87,37 @@ func TestRender_Insert(t *testing.T) {
 	}
 }
 
+func TestRender_InsertDefaultValues(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{
+				{Name: "name"}:  {Name: "name1"},
+				{Name: "email"}: {Name: "email1"},
+			},
+			{
+				{Name: "name"}:  {Name: "name2"},
+				{Name: "email"}: {Literal: types.LiteralDefault},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `INSERT INTO "users" ("email", "name") VALUES (:email1, :name1), (DEFAULT, :name2)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "email1,name1,name2" {
+		t.Errorf("RequiredParams = %v, want [email1 name1 name2]", result.RequiredParams)
+	}
+}
+
 func TestRender_Update(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -194,6 +226,46 @@ func TestRender_Delete(t *testing.T) {
 	}
 }
 
+func TestRender_Truncate(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		ast      *types.AST
+		expected string
+	}{
+		{
+			name:     "plain",
+			ast:      &types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}},
+			expected: `TRUNCATE TABLE "staging"`,
+		},
+		{
+			name:     "restart identity",
+			ast:      &types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, RestartIdentity: true},
+			expected: `TRUNCATE TABLE "staging" RESTART IDENTITY`,
+		},
+		{
+			name:     "restart identity cascade",
+			ast:      &types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}, RestartIdentity: true, Cascade: true},
+			expected: `TRUNCATE TABLE "staging" RESTART IDENTITY CASCADE`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Render(tt.ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 0 {
+				t.Errorf("RequiredParams = %v, want none", result.RequiredParams)
+			}
+		})
+	}
+}
+
 func TestRender_IN_PostgresArraySyntax(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -536,6 +608,32 @@ func TestRender_DateNow(t *testing.T) {
 	}
 }
 
+func TestRender_DateNowTz(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateNowTz,
+				},
+				Alias: "current_time",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT now() AS "current_time" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_DateCurrentDate(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -591,6 +689,39 @@ func TestRender_DateExtract(t *testing.T) {
 	}
 }
 
+func TestRender_DateBin(t *testing.T) {
+	r := New()
+	createdAt := types.Field{Name: "created_at"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateBin,
+					Field:    &createdAt,
+					Interval: &types.Param{Name: "bucket"},
+					Origin:   &types.Param{Name: "origin"},
+				},
+				Alias: "bucket_start",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT DATE_BIN(:bucket, "created_at", :origin) AS "bucket_start" FROM "events"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "bucket,origin" {
+		t.Errorf("RequiredParams = %v, want [bucket origin]", result.RequiredParams)
+	}
+}
+
 func TestRender_DateTrunc(t *testing.T) {
 	r := New()
 	createdAt := types.Field{Name: "created_at"}
@@ -1133,6 +1264,117 @@ func TestRender_LeftJoin(t *testing.T) {
 	}
 }
 
+func TestRender_FullJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Joins: []types.Join{
+			{
+				Type:  types.FullJoin,
+				Table: types.Table{Name: "profiles"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "users"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "profiles"},
+				},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "name" FROM "users" FULL OUTER JOIN "profiles" ON users."id" = profiles."user_id"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_NaturalJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Joins:     []types.Join{{Type: types.NaturalJoin, Table: types.Table{Name: "profiles"}}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "name" FROM "users" NATURAL JOIN "profiles"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JoinUsing(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		joinType types.JoinType
+		expected string
+	}{
+		{"inner", types.InnerJoin, `SELECT u."name" FROM "users" u INNER JOIN "orders" o USING ("user_id", "tenant_id")`},
+		{"left", types.LeftJoin, `SELECT u."name" FROM "users" u LEFT JOIN "orders" o USING ("user_id", "tenant_id")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users", Alias: "u"},
+				Fields:    []types.Field{{Name: "name", Table: "u"}},
+				Joins: []types.Join{
+					{
+						Type:  tt.joinType,
+						Table: types.Table{Name: "orders", Alias: "o"},
+						Using: []types.Field{{Name: "user_id", Table: "u"}, {Name: "tenant_id"}},
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_JoinUsingWithOn(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "orders"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "users"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "orders"},
+				},
+				Using: []types.Field{{Name: "user_id"}},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for join with both ON and USING, got nil")
+	}
+}
+
 func TestRender_HavingCondition(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -1284,6 +1526,72 @@ func TestRender_OnConflictDoUpdate(t *testing.T) {
 	}
 }
 
+func TestRender_OnConflictPredicates(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Name: "email_val"}, {Name: "name"}: {Name: "name_val"}},
+		},
+		OnConflict: &types.ConflictClause{
+			Columns:           []types.Field{{Name: "email"}},
+			ConflictPredicate: types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "active"}},
+			Action:            types.DoUpdate,
+			Updates: map[types.Field]types.Param{
+				{Name: "name"}: {Name: "new_name"},
+			},
+			DoUpdateWhere: types.Condition{Field: types.Field{Name: "locked"}, Operator: types.EQ, Value: types.Param{Name: "locked"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `INSERT INTO "users" ("email", "name") VALUES (:email_val, :name_val) ON CONFLICT ("email") WHERE "active" = :active DO UPDATE SET "name" = :new_name WHERE "locked" = :locked`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantParams := "email_val,name_val,active,new_name,locked"
+	if got := strings.Join(result.RequiredParams, ","); got != wantParams {
+		t.Errorf("RequiredParams = %v, want %s", result.RequiredParams, wantParams)
+	}
+}
+
+func TestRender_OnConflictExcludedRef(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Name: "email_val"}, {Name: "name"}: {Name: "name_val"}},
+		},
+		OnConflict: &types.ConflictClause{
+			Columns: []types.Field{{Name: "email"}},
+			Action:  types.DoUpdate,
+			Updates: map[types.Field]types.Param{
+				{Name: "name"}:       {Excluded: &types.ExcludedRef{Field: types.Field{Name: "name"}}},
+				{Name: "updated_by"}: {Name: "actor"},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `INSERT INTO "users" ("email", "name") VALUES (:email_val, :name_val) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "updated_by" = :actor`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "email_val,name_val,actor" {
+		t.Errorf("RequiredParams = %v, want [email_val name_val actor]", result.RequiredParams)
+	}
+}
+
 func TestRender_ForUpdate(t *testing.T) {
 	r := New()
 	lockForUpdate := types.LockForUpdate
@@ -1326,28 +1634,70 @@ func TestRender_ForShare(t *testing.T) {
 	}
 }
 
-func TestRender_Operators(t *testing.T) {
-	tests := []struct {
-		name     string
-		operator types.Operator
-		expected string
-	}{
-		{"NE", types.NE, `SELECT "id" FROM "users" WHERE "status" != :status`},
-		{"GT", types.GT, `SELECT "id" FROM "users" WHERE "status" > :status`},
-		{"GE", types.GE, `SELECT "id" FROM "users" WHERE "status" >= :status`},
-		{"LT", types.LT, `SELECT "id" FROM "users" WHERE "status" < :status`},
-		{"LE", types.LE, `SELECT "id" FROM "users" WHERE "status" <= :status`},
-		{"LIKE", types.LIKE, `SELECT "id" FROM "users" WHERE "status" LIKE :status`},
-		{"ILIKE", types.ILIKE, `SELECT "id" FROM "users" WHERE "status" ILIKE :status`},
+func TestRender_ForNoKeyUpdate(t *testing.T) {
+	r := New()
+	lockForShare := types.LockForNoKeyUpdate
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+		Lock:      &lockForShare,
 	}
 
-	r := New()
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ast := &types.AST{
-				Operation: types.OpSelect,
-				Target:    types.Table{Name: "users"},
-				Fields:    []types.Field{{Name: "id"}},
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "users" FOR NO KEY UPDATE`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ForKeyShare(t *testing.T) {
+	r := New()
+	lockForShare := types.LockForKeyShare
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+		Lock:      &lockForShare,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "users" FOR KEY SHARE`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_Operators(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator types.Operator
+		expected string
+	}{
+		{"NE", types.NE, `SELECT "id" FROM "users" WHERE "status" != :status`},
+		{"GT", types.GT, `SELECT "id" FROM "users" WHERE "status" > :status`},
+		{"GE", types.GE, `SELECT "id" FROM "users" WHERE "status" >= :status`},
+		{"LT", types.LT, `SELECT "id" FROM "users" WHERE "status" < :status`},
+		{"LE", types.LE, `SELECT "id" FROM "users" WHERE "status" <= :status`},
+		{"LIKE", types.LIKE, `SELECT "id" FROM "users" WHERE "status" LIKE :status`},
+		{"ILIKE", types.ILIKE, `SELECT "id" FROM "users" WHERE "status" ILIKE :status`},
+	}
+
+	r := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				Fields:    []types.Field{{Name: "id"}},
 				WhereClause: types.Condition{
 					Field:    types.Field{Name: "status"},
 					Operator: tt.operator,
@@ -1510,6 +1860,44 @@ func TestRenderCompound_Except(t *testing.T) {
 	}
 }
 
+func TestRenderCompound_ExceptOrderByOutputColumn(t *testing.T) {
+	r := New()
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "users", Alias: "u"},
+			Fields:    []types.Field{{Name: "name", Table: "u"}},
+		},
+		Operands: []types.SetOperand{
+			{
+				Operation: types.SetExcept,
+				AST: &types.AST{
+					Operation: types.OpSelect,
+					Target:    types.Table{Name: "banned_users"},
+					Fields:    []types.Field{{Name: "name"}},
+				},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "name", Table: "u"}, Direction: types.ASC}},
+	}
+
+	result, err := r.RenderCompound(query)
+	if err != nil {
+		t.Fatalf("RenderCompound() error = %v", err)
+	}
+
+	// The qualifier is dropped: a set operation result has no table scope.
+	expected := `(SELECT u."name" FROM "users" u) EXCEPT (SELECT "name" FROM "banned_users") ORDER BY "name" ASC`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	query.Ordering[0].Field = types.Field{Name: "email"}
+	if _, err := r.RenderCompound(query); err == nil {
+		t.Error("expected error for ORDER BY a column the first query does not select")
+	}
+}
+
 func TestRenderCompound_WithOrderByLimit(t *testing.T) {
 	r := New()
 	limitVal := 10
@@ -1665,4 +2053,2048 @@ func TestCapabilities(t *testing.T) {
 	if caps.RowLocking != render.RowLockingFull {
 		t.Errorf("RowLocking = %v, want RowLockingFull", caps.RowLocking)
 	}
+	if !caps.FullOuterJoin {
+		t.Error("FullOuterJoin should be true")
+	}
+	if !caps.RightJoin {
+		t.Error("RightJoin should be true")
+	}
+	if caps.GroupConcatTruncates {
+		t.Error("GroupConcatTruncates should be false")
+	}
+}
+
+func TestRender_GroupByRollup(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:        types.OpSelect,
+		Target:           types.Table{Name: "sales"},
+		Fields:           []types.Field{{Name: "region"}, {Name: "product"}},
+		FieldExpressions: []types.FieldExpression{{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"}},
+		GroupBy:          []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode:      types.GroupRollup,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "region", "product", SUM("amount") AS "total" FROM "sales" GROUP BY ROLLUP("region", "product")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupByCoalesce(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:        types.OpSelect,
+		Target:           types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"}},
+		CoalescedGroupBy: []types.CoalescedGroup{{Field: types.Field{Name: "region"}, Sentinel: types.Param{Name: "unknown"}}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT SUM("amount") AS "total" FROM "sales" GROUP BY COALESCE("region", :unknown)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "unknown" {
+		t.Errorf("RequiredParams = %v, want [unknown]", result.RequiredParams)
+	}
+}
+
+func TestRender_GroupByCoalesceWithRollup(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:        types.OpSelect,
+		Target:           types.Table{Name: "sales"},
+		GroupBy:          []types.Field{{Name: "product"}},
+		GroupByMode:      types.GroupRollup,
+		CoalescedGroupBy: []types.CoalescedGroup{{Field: types.Field{Name: "region"}, Sentinel: types.Param{Name: "unknown"}}},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for coalesced GROUP BY with ROLLUP")
+	}
+}
+
+func TestRender_GroupByCube(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupCube,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "region", "product" FROM "sales" GROUP BY CUBE("region", "product")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupByGroupingSets(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		Fields:      []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupSets,
+		GroupingSets: [][]types.Field{
+			{{Name: "region"}},
+			{{Name: "product"}},
+			{},
+		},
+		Having: []types.ConditionItem{
+			types.AggregateCondition{Func: types.AggCountField, Operator: types.GT, Value: types.Param{Name: "min"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "region", "product" FROM "sales" GROUP BY GROUPING SETS(("region"), ("product"), ()) HAVING COUNT(*) > :min`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_GroupByGroupingSets_Empty(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "sales"},
+		GroupByMode: types.GroupSets,
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for GROUPING SETS without sets, got nil")
+	}
+}
+
+func TestRender_GroupingWithRollup(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		Fields:    []types.Field{{Name: "region"}, {Name: "product"}},
+		FieldExpressions: []types.FieldExpression{
+			{Grouping: &types.GroupingExpression{Fields: []types.Field{{Name: "region"}, {Name: "product"}}}, Alias: "grp"},
+		},
+		GroupBy:     []types.Field{{Name: "region"}, {Name: "product"}},
+		GroupByMode: types.GroupRollup,
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "region", "product", GROUPING("region", "product") AS "grp" FROM "sales" GROUP BY ROLLUP("region", "product")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_LateralDerivedTableWithColumnAliases(t *testing.T) {
+	r := New()
+	limit := 3
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders", Alias: "o"},
+		Fields:    []types.Field{{Name: "id", Table: "o"}, {Name: "total", Table: "o"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.AND,
+			Conditions: []types.ConditionItem{
+				types.FieldComparison{
+					LeftField:  types.Field{Name: "user_id", Table: "o"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "id", Table: "u"},
+				},
+				types.Condition{Field: types.Field{Name: "status", Table: "o"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "total", Table: "o"}, Direction: types.DESC}},
+		Limit:    &types.PaginationValue{Static: &limit},
+	}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Fields: []types.Field{
+			{Name: "name", Table: "u"},
+			{Name: "order_id", Table: "x"},
+			{Name: "order_total", Table: "x"},
+		},
+		Joins: []types.Join{
+			{
+				Type:     types.CrossJoin,
+				Table:    types.Table{Alias: "x"},
+				Subquery: &types.Subquery{AST: sub},
+				Lateral:  true,
+				Columns:  []string{"order_id", "order_total"},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT u."name", x."order_id", x."order_total" FROM "users" u CROSS JOIN LATERAL (SELECT o."id", o."total" FROM "orders" o WHERE (o."user_id" = u."id" AND o."status" = :sq1_status) ORDER BY o."total" DESC LIMIT 3) AS x("order_id", "order_total")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "sq1_status" {
+		t.Errorf("RequiredParams = %v, want [sq1_status]", result.RequiredParams)
+	}
+}
+
+func TestRender_DerivedTableJoinRequiresAlias(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Joins: []types.Join{
+			{
+				Type:     types.CrossJoin,
+				Subquery: &types.Subquery{AST: &types.AST{Operation: types.OpSelect, Target: types.Table{Name: "orders"}}},
+				Lateral:  true,
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for derived table join without alias, got nil")
+	}
+}
+
+func TestRender_JoinRejectsUnknownType(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Joins: []types.Join{
+			{Type: "INNER JOIN posts ON 1=1; DROP TABLE users; --", Table: types.Table{Name: "posts"}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "unknown join type") {
+		t.Errorf("Render() error = %v, want unknown join type", err)
+	}
+}
+
+func TestRender_DerivedTableTarget(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpSelect,
+		TargetSubquery: sub,
+		TargetAlias:    "sub",
+		Fields:         []types.Field{{Name: "user_id"}},
+		WhereClause:    types.Condition{Field: types.Field{Name: "total"}, Operator: types.GT, Value: types.Param{Name: "min_total"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "user_id" FROM (SELECT "user_id", SUM("amount") AS "total" FROM "orders" WHERE "status" = :sq1_status GROUP BY "user_id") AS sub WHERE "total" > :min_total`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 2 || result.RequiredParams[0] != "sq1_status" || result.RequiredParams[1] != "min_total" {
+		t.Errorf("RequiredParams = %v, want [sq1_status min_total]", result.RequiredParams)
+	}
+}
+
+func TestRender_DerivedTableTargetCount(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpCount,
+		TargetSubquery: sub,
+		TargetAlias:    "sub",
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT COUNT(*) FROM (SELECT "user_id", SUM("amount") AS "total" FROM "orders" WHERE "status" = :sq1_status GROUP BY "user_id") AS sub`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_DerivedTableTargetRequiresAlias(t *testing.T) {
+	r := New()
+	sub := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggSum, Field: types.Field{Name: "amount"}, Alias: "total"},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "status"}, Operator: types.EQ, Value: types.Param{Name: "status"}},
+		GroupBy:     []types.Field{{Name: "user_id"}},
+	}
+	ast := &types.AST{
+		Operation:      types.OpSelect,
+		TargetSubquery: sub,
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for derived table target without alias, got nil")
+	}
+}
+
+func TestRender_SubqueryAll(t *testing.T) {
+	r := New()
+	subquery := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "products"},
+		Fields:    []types.Field{{Name: "price"}},
+		WhereClause: types.Condition{
+			Field:    types.Field{Name: "category"},
+			Operator: types.EQ,
+			Value:    types.Param{Name: "cat"},
+		},
+	}
+
+	priceField := types.Field{Name: "price"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "products"},
+		Fields:    []types.Field{{Name: "name"}},
+		WhereClause: types.SubqueryCondition{
+			Field:      &priceField,
+			Operator:   types.GT,
+			Quantifier: types.QuantAll,
+			Subquery:   types.Subquery{AST: subquery},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "name" FROM "products" WHERE "price" > ALL (SELECT "price" FROM "products" WHERE "category" = :sq1_cat)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_SubqueryAny(t *testing.T) {
+	r := New()
+	subquery := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+	}
+
+	idField := types.Field{Name: "id"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		WhereClause: types.SubqueryCondition{
+			Field:      &idField,
+			Operator:   types.EQ,
+			Quantifier: types.QuantAny,
+			Subquery:   types.Subquery{AST: subquery},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "name" FROM "users" WHERE "id" = ANY (SELECT "user_id" FROM "orders")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_SubqueryQuantifierRequiresComparison(t *testing.T) {
+	r := New()
+	idField := types.Field{Name: "id"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.SubqueryCondition{
+			Field:      &idField,
+			Operator:   types.IN,
+			Quantifier: types.QuantAny,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				Fields:    []types.Field{{Name: "user_id"}},
+			}},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for IN with ANY")
+	}
+}
+
+func TestRender_HavingAliasReexpands(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Aggregate: types.AggSum,
+				Field:     types.Field{Name: "total"},
+				Alias:     "order_total",
+			},
+		},
+		GroupBy: []types.Field{{Name: "user_id"}},
+		Having: []types.ConditionItem{
+			types.AggregateCondition{
+				Func:     types.AggSum,
+				Field:    &types.Field{Name: "total"},
+				Operator: types.GE,
+				Value:    types.Param{Name: "min_total"},
+				Alias:    "order_total",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "user_id", SUM("total") AS "order_total" FROM "orders" GROUP BY "user_id" HAVING SUM("total") >= :min_total`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_TupleInSubquery(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.TupleCondition{
+			Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+			Operator: types.IN,
+			Subquery: &types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "members"},
+				Fields:    []types.Field{{Name: "user_id"}, {Name: "org_id"}},
+				WhereClause: types.Condition{
+					Field:    types.Field{Name: "role"},
+					Operator: types.EQ,
+					Value:    types.Param{Name: "role"},
+				},
+			}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("id", "org_id") IN (SELECT "user_id", "org_id" FROM "members" WHERE "role" = :sq1_role)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_TupleEquality(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.TupleCondition{
+			Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+			Operator: types.EQ,
+			Values:   []types.Param{{Name: "id"}, {Name: "org"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("id", "org_id") = (:id, :org)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObject(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id", Field: &types.Field{Name: "id"}},
+						{Key: "name", Field: &types.Field{Name: "name"}},
+						{Key: "source", Param: &types.Param{Name: "source"}},
+					},
+				},
+				Alias: "payload",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT json_build_object('id', "id", 'name', "name", 'source', :source) AS "payload" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObjectRejectsUnsafeKey(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id'); DROP TABLE users; --", Field: &types.Field{Name: "id"}},
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for unsafe JSON object key")
+	}
+}
+
+func TestRender_JSONKeyRejectsUnsafeKey(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name  string
+		field types.Field
+	}{
+		{"text key", types.Field{Name: "data", JSONKey: "a' OR '1'='1"}},
+		{"path key", types.Field{Name: "data", JSONPath: "a,b}'); DROP TABLE users; --"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "users"},
+				WhereClause: types.Condition{Field: tt.field, Operator: types.EQ, Value: types.Param{Name: "v"}},
+			}
+			if _, err := r.Render(ast); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+				t.Errorf("Render() error = %v, want invalid JSON key", err)
+			}
+		})
+	}
+}
+
+func inDistinctSubqueryAST() *types.AST {
+	idField := types.Field{Name: "id"}
+	return &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.SubqueryCondition{
+			Field:    &idField,
+			Operator: types.IN,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				Fields:    []types.Field{{Name: "user_id"}},
+				Distinct:  true,
+			}},
+		},
+	}
+}
+
+func TestRender_InSubqueryDistinctPreservedByDefault(t *testing.T) {
+	result, err := New().Render(inDistinctSubqueryAST())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" IN (SELECT DISTINCT "user_id" FROM "orders")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_InSubqueryStripDistinct(t *testing.T) {
+	r := NewWithOptions(Options{StripInSubqueryDistinct: true})
+	result, err := r.Render(inDistinctSubqueryAST())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" IN (SELECT "user_id" FROM "orders")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_InSubqueryStripDistinctKeepsLimited(t *testing.T) {
+	ast := inDistinctSubqueryAST()
+	limit := 10
+	ast.WhereClause.(types.SubqueryCondition).Subquery.AST.Limit = &types.PaginationValue{Static: &limit}
+
+	r := NewWithOptions(Options{StripInSubqueryDistinct: true})
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" IN (SELECT DISTINCT "user_id" FROM "orders" LIMIT 10)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ArithmeticAggregates(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "invoices"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Arithmetic: &types.ArithmeticExpression{
+					Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: types.Field{Name: "paid"}, Aggregate: types.AggSum}},
+					Operator: types.ArithDiv,
+					Right:    types.ArithmeticOperand{Expr: &types.FieldExpression{Aggregate: types.AggCountField}},
+				},
+				Alias: "avg_paid",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT SUM("paid") / COUNT(*) AS "avg_paid" FROM "invoices"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ArithmeticFieldOverWindow(t *testing.T) {
+	r := New()
+	amount := types.Field{Name: "amount"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Arithmetic: &types.ArithmeticExpression{
+					Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: amount}},
+					Operator: types.ArithDiv,
+					Right: types.ArithmeticOperand{Expr: &types.FieldExpression{
+						Window: &types.WindowExpression{Aggregate: types.AggSum, Field: &amount},
+					}},
+				},
+				Alias: "pct",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "amount" / SUM("amount") OVER () AS "pct" FROM "sales"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ArithmeticNestedWithParam(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Arithmetic: &types.ArithmeticExpression{
+					Left: types.ArithmeticOperand{Expr: &types.FieldExpression{
+						Arithmetic: &types.ArithmeticExpression{
+							Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: types.Field{Name: "amount"}}},
+							Operator: types.ArithSub,
+							Right:    types.ArithmeticOperand{Expr: &types.FieldExpression{Field: types.Field{Name: "discount"}}},
+						},
+					}},
+					Operator: types.ArithMul,
+					Right:    types.ArithmeticOperand{Param: &types.Param{Name: "rate"}},
+				},
+				Alias: "net",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT ("amount" - "discount") * :rate AS "net" FROM "sales"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "rate" {
+		t.Errorf("RequiredParams = %v, want [rate]", result.RequiredParams)
+	}
+}
+
+func TestRender_ArithmeticInvalidOperator(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Arithmetic: &types.ArithmeticExpression{
+					Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: types.Field{Name: "amount"}}},
+					Operator: "||",
+					Right:    types.ArithmeticOperand{Param: &types.Param{Name: "x"}},
+				},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for invalid arithmetic operator")
+	}
+}
+
+func TestRenderWithOptions_LowerKeywords(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "Users"},
+		Fields:    []types.Field{{Name: "userId"}, {Name: "Email"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.AND,
+			Conditions: []types.ConditionItem{
+				types.Condition{Field: types.Field{Name: "Age"}, Operator: types.GE, Value: types.Param{Name: "minAge"}},
+				types.Condition{Field: types.Field{Name: "Email"}, Operator: types.IsNotNull},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "userId"}, Direction: types.DESC}},
+	}
+
+	result, err := r.RenderWithOptions(ast, RenderOptions{KeywordCase: render.KeywordLower})
+	if err != nil {
+		t.Fatalf("RenderWithOptions() error = %v", err)
+	}
+
+	expected := `select "userId", "Email" from "Users" where ("Age" >= :minAge and "Email" is not null) order by "userId" desc`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "minAge" {
+		t.Errorf("RequiredParams = %v, want [minAge]", result.RequiredParams)
+	}
+
+	upper, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if upper.SQL != `SELECT "userId", "Email" FROM "Users" WHERE ("Age" >= :minAge AND "Email" IS NOT NULL) ORDER BY "userId" DESC` {
+		t.Errorf("default SQL = %q", upper.SQL)
+	}
+}
+
+func TestRender_ForUpdateOf(t *testing.T) {
+	r := New()
+	lock := types.LockForUpdate
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Fields:    []types.Field{{Name: "id", Table: "u"}},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "orders", Alias: "o"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "u"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "o"},
+				},
+			},
+		},
+		Lock:   &lock,
+		LockOf: []types.Table{{Name: "orders", Alias: "o"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT u."id" FROM "users" u INNER JOIN "orders" o ON u."id" = o."user_id" FOR UPDATE OF o`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_NamedWindow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{Window: &types.WindowExpression{Function: types.WinRowNumber, WindowRef: "w"}, Alias: "rn"},
+			{Window: &types.WindowExpression{Aggregate: types.AggSum, Field: &types.Field{Name: "total"}, WindowRef: "w"}, Alias: "running"},
+		},
+		Windows: map[string]types.WindowSpec{
+			"w": {
+				PartitionBy: []types.Field{{Name: "user_id"}},
+				OrderBy:     []types.OrderBy{{Field: types.Field{Name: "created_at"}, Direction: types.ASC}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT ROW_NUMBER() OVER "w" AS "rn", SUM("total") OVER "w" AS "running" FROM "orders" WINDOW "w" AS (PARTITION BY "user_id" ORDER BY "created_at" ASC)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_InsertValueSubquery(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "tickets"},
+		Values:    []map[types.Field]types.Param{{{Name: "title"}: {Name: "title"}}},
+		ValueSubqueries: []map[types.Field]types.Subquery{{
+			{Name: "seq"}: {AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "tickets"},
+				FieldExpressions: []types.FieldExpression{{
+					Arithmetic: &types.ArithmeticExpression{
+						Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Field: types.Field{Name: "seq"}, Aggregate: types.AggMax}},
+						Operator: types.ArithAdd,
+						Right:    types.ArithmeticOperand{Param: &types.Param{Name: "step"}},
+					},
+				}},
+			}},
+		}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `INSERT INTO "tickets" ("seq", "title") VALUES ((SELECT MAX("seq") + :sq1_step FROM "tickets"), :title)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 2 || result.RequiredParams[0] != "sq1_step" || result.RequiredParams[1] != "title" {
+		t.Errorf("RequiredParams = %v, want [sq1_step title]", result.RequiredParams)
+	}
+}
+
+func TestRender_WindowRangeFrameMovingAverage(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "readings"},
+		Fields:    []types.Field{{Name: "taken_at"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggAvg,
+					Field:     &types.Field{Name: "value"},
+					Window: types.WindowSpec{
+						OrderBy:          []types.OrderBy{{Field: types.Field{Name: "taken_at"}, Direction: types.ASC}},
+						FrameUnit:        types.FrameRange,
+						FrameStart:       types.FramePreceding,
+						FrameStartOffset: &types.Param{Name: "window_span"},
+						FrameEnd:         types.FrameCurrentRow,
+					},
+				},
+				Alias: "moving_avg",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "taken_at", AVG("value") OVER (ORDER BY "taken_at" ASC RANGE BETWEEN :window_span PRECEDING AND CURRENT ROW) AS "moving_avg" FROM "readings"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "window_span" {
+		t.Errorf("RequiredParams = %v, want [window_span]", result.RequiredParams)
+	}
+}
+
+func TestRender_WindowRangeOffsetRequiresOrderBy(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "readings"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggAvg,
+					Field:     &types.Field{Name: "value"},
+					Window: types.WindowSpec{
+						FrameUnit:        types.FrameRange,
+						FrameStart:       types.FramePreceding,
+						FrameStartOffset: &types.Param{Name: "window_span"},
+						FrameEnd:         types.FrameCurrentRow,
+					},
+				},
+				Alias: "moving_avg",
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for RANGE offset without ORDER BY")
+	}
+}
+
+func TestRender_WindowGroupsFrame(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggSum,
+					Field:     &types.Field{Name: "points"},
+					Window: types.WindowSpec{
+						OrderBy:    []types.OrderBy{{Field: types.Field{Name: "round"}, Direction: types.ASC}},
+						FrameUnit:  types.FrameGroups,
+						FrameStart: types.FrameUnboundedPreceding,
+						FrameEnd:   types.FrameCurrentRow,
+					},
+				},
+				Alias: "total",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT SUM("points") OVER (ORDER BY "round" ASC GROUPS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS "total" FROM "scores"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_WindowFrameExclude(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Aggregate: types.AggAvg,
+					Field:     &types.Field{Name: "points"},
+					Window: types.WindowSpec{
+						OrderBy:      []types.OrderBy{{Field: types.Field{Name: "round"}, Direction: types.ASC}},
+						FrameStart:   types.FrameUnboundedPreceding,
+						FrameEnd:     types.FrameCurrentRow,
+						FrameExclude: types.ExcludeCurrentRow,
+					},
+				},
+				Alias: "prior_avg",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT AVG("points") OVER (ORDER BY "round" ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE CURRENT ROW) AS "prior_avg" FROM "scores"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_WindowFrameExcludeRequiresFrame(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Window: &types.WindowExpression{
+					Function: types.WinRowNumber,
+					Window:   types.WindowSpec{FrameExclude: types.ExcludeTies},
+				},
+				Alias: "rn",
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for EXCLUDE without a frame")
+	}
+}
+
+func TestRender_InListCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.InListCondition{
+			Field:    types.Field{Name: "id"},
+			Operator: types.IN,
+			Values:   []types.Param{{Name: "a"}, {Name: "b"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "id" IN (:a, :b)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("RequiredParams = %v, want [a b]", result.RequiredParams)
+	}
+}
+
+func TestRender_EmptyInListGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       types.Operator
+		expected string
+	}{
+		{"IN", types.IN, `SELECT * FROM "users" WHERE 1 = 0`},
+		{"NOT IN", types.NotIn, `SELECT * FROM "users" WHERE 1 = 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "users"},
+				WhereClause: types.InListCondition{Field: types.Field{Name: "id"}, Operator: tt.op},
+			}
+
+			result, err := New().Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_EmptyInListStrict(t *testing.T) {
+	r := NewWithOptions(Options{StrictEmptyIn: true})
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "users"},
+		WhereClause: types.InListCondition{Field: types.Field{Name: "id"}, Operator: types.IN},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for empty IN list in strict mode")
+	}
+}
+
+func TestRender_NullSafeJoinCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "ledger", Alias: "a"},
+		Fields:    []types.Field{{Name: "id"}},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "bank", Alias: "b"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "ref", Table: "a"},
+					Operator:   types.IsNotDistinctFrom,
+					RightField: types.Field{Name: "ref", Table: "b"},
+				},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "ledger" a INNER JOIN "bank" b ON a."ref" IS NOT DISTINCT FROM b."ref"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_CastWithTypeParams(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "amount"}, CastType: types.CastNumeric, Precision: 10, Scale: 2},
+				Alias: "amt",
+			},
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "name"}, CastType: types.CastVarchar, Length: 50},
+				Alias: "short",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT CAST("amount" AS NUMERIC(10,2)) AS "amt", CAST("name" AS VARCHAR(50)) AS "short" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_CastRejectsMismatchedTypeParams(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{Cast: &types.CastExpression{Field: types.Field{Name: "name"}, CastType: types.CastText, Length: 50}},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for length on a TEXT cast")
+	}
+}
+
+func TestRender_CastRejectsUnknownType(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{Cast: &types.CastExpression{Field: types.Field{Name: "name"}, CastType: "TEXT) FROM orders; --"}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "unknown cast type") {
+		t.Errorf("Render() error = %v, want unknown cast type", err)
+	}
+}
+
+func TestRender_DistinctOrderBySelectedColumn(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:        types.OpSelect,
+		Target:           types.Table{Name: "users"},
+		Distinct:         true,
+		Fields:           []types.Field{{Name: "country"}},
+		FieldExpressions: []types.FieldExpression{{Aggregate: types.AggCountField, Field: types.Field{Name: "id"}, Alias: "total"}},
+		GroupBy:          []types.Field{{Name: "country"}},
+		Ordering: []types.OrderBy{
+			{Field: types.Field{Name: "country"}, Direction: types.ASC},
+			{Field: types.Field{Name: "total"}, Direction: types.DESC},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT DISTINCT "country", COUNT("id") AS "total" FROM "users" GROUP BY "country" ORDER BY "country" ASC, "total" DESC`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_DistinctOrderByUnselectedColumn(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Distinct:  true,
+		Fields:    []types.Field{{Name: "country"}},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "created_at"}, Direction: types.DESC}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for DISTINCT ordered by an unselected column")
+	}
+	if !strings.Contains(err.Error(), "created_at") || !strings.Contains(err.Error(), "select list") {
+		t.Errorf("error = %q, want it to name created_at and the select list", err.Error())
+	}
+}
+
+func TestRender_DollarPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderDollar})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		Fields:    []types.Field{{Name: "id"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.OR,
+			Conditions: []types.ConditionItem{
+				types.Condition{Field: types.Field{Name: "starts_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+				types.Condition{Field: types.Field{Name: "kind"}, Operator: types.EQ, Value: types.Param{Name: "kind"}},
+				types.Condition{Field: types.Field{Name: "ends_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "events" WHERE ("starts_at" >= $1 OR "kind" = $2 OR "ends_at" >= $1)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"since", "kind"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("RequiredParams = %v, want 2 unique names", result.RequiredParams)
+	}
+}
+
+func TestRender_ArrayConstructor(t *testing.T) {
+	r := New()
+
+	tests := []struct {
+		name     string
+		ast      *types.AST
+		expected string
+		params   []string
+	}{
+		{
+			name: "select",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "posts"},
+				FieldExpressions: []types.FieldExpression{{
+					Array: &types.ArrayExpression{Values: []types.Param{{Name: "a"}, {Name: "b"}}},
+					Alias: "tags",
+				}},
+			},
+			expected: `SELECT ARRAY[:a, :b] AS "tags" FROM "posts"`,
+			params:   []string{"a", "b"},
+		},
+		{
+			name: "insert",
+			ast: &types.AST{
+				Operation: types.OpInsert,
+				Target:    types.Table{Name: "posts"},
+				Values:    []map[types.Field]types.Param{{{Name: "title"}: {Name: "title"}}},
+				ValueArrays: []map[types.Field]types.ArrayExpression{{
+					{Name: "tags"}: {Values: []types.Param{{Name: "tag1"}, {Name: "tag2"}, {Literal: types.LiteralTrue}}},
+				}},
+			},
+			expected: `INSERT INTO "posts" ("tags", "title") VALUES (ARRAY[:tag1, :tag2, TRUE], :title)`,
+			params:   []string{"tag1", "tag2", "title"},
+		},
+		{
+			name: "update",
+			ast: &types.AST{
+				Operation: types.OpUpdate,
+				Target:    types.Table{Name: "posts"},
+				UpdateExpressions: map[types.Field]types.FieldExpression{
+					{Name: "tags"}: {Array: &types.ArrayExpression{Values: []types.Param{{Name: "tag"}}}},
+				},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "id"}},
+			},
+			expected: `UPDATE "posts" SET "tags" = ARRAY[:tag] WHERE "id" = :id`,
+			params:   []string{"tag", "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Render(tt.ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.RequiredParams, ",") != strings.Join(tt.params, ",") {
+				t.Errorf("RequiredParams = %v, want %v", result.RequiredParams, tt.params)
+			}
+		})
+	}
+}
+
+func TestRender_ArrayConstructorEmpty(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:        types.OpSelect,
+		Target:           types.Table{Name: "posts"},
+		FieldExpressions: []types.FieldExpression{{Array: &types.ArrayExpression{}}},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for empty ARRAY constructor, got nil")
+	}
+}
+
+func TestRender_WithTies(t *testing.T) {
+	limit := 3
+	offset := 6
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "score"}, Direction: types.DESC}},
+		Limit:     &types.PaginationValue{Static: &limit},
+		WithTies:  true,
+	}
+
+	result, err := New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := `SELECT * FROM "scores" ORDER BY "score" DESC FETCH FIRST 3 ROWS WITH TIES`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	ast.Offset = &types.PaginationValue{Static: &offset}
+	result, err = New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected = `SELECT * FROM "scores" ORDER BY "score" DESC OFFSET 6 ROWS FETCH FIRST 3 ROWS WITH TIES`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ReturningCount(t *testing.T) {
+	ast := &types.AST{
+		Operation: types.OpUpdate,
+		Target:    types.Table{Name: "users"},
+		Updates:   map[types.Field]types.Param{{Name: "active"}: {Literal: types.LiteralFalse}},
+		WhereClause: types.Condition{
+			Field:    types.Field{Name: "last_login"},
+			Operator: types.LT,
+			Value:    types.Param{Name: "cutoff"},
+		},
+		ReturningCount: true,
+	}
+
+	result, err := New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected := `WITH "affected" AS (UPDATE "users" SET "active" = FALSE WHERE "last_login" < :cutoff RETURNING 1) SELECT COUNT(*) FROM "affected"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "cutoff" {
+		t.Errorf("RequiredParams = %v, want [cutoff]", result.RequiredParams)
+	}
+
+	ast = &types.AST{
+		Operation:      types.OpDelete,
+		Target:         types.Table{Name: "sessions"},
+		ReturningCount: true,
+	}
+	result, err = New().Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	expected = `WITH "affected" AS (DELETE FROM "sessions" RETURNING 1) SELECT COUNT(*) FROM "affected"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringPadReverseInitcap(t *testing.T) {
+	r := New()
+	padArgs := []types.Param{{Name: "width"}, {Name: "fill"}}
+	tests := []struct {
+		function types.StringFunc
+		args     []types.Param
+		want     string
+	}{
+		{types.StringLPad, padArgs, "SELECT LPAD(\"name\", :width, :fill) AS \"formatted\" FROM \"users\""},
+		{types.StringRPad, padArgs, "SELECT RPAD(\"name\", :width, :fill) AS \"formatted\" FROM \"users\""},
+		{types.StringReverse, nil, "SELECT REVERSE(\"name\") AS \"formatted\" FROM \"users\""},
+		{types.StringInitcap, nil, "SELECT INITCAP(\"name\") AS \"formatted\" FROM \"users\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						String: &types.StringExpression{
+							Function: tt.function,
+							Field:    types.Field{Name: "name"},
+							Args:     tt.args,
+						},
+						Alias: "formatted",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.want {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_ValuesTable(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target: types.Table{
+			Alias: "t",
+			Values: &types.ValuesTable{
+				Columns: []string{"id", "name"},
+				Rows: [][]types.Param{
+					{{Name: "id1"}, {Name: "name1"}},
+					{{Name: "id2"}, {Name: "name2"}},
+				},
+			},
+		},
+		Fields: []types.Field{{Name: "id", Table: "t"}, {Name: "name", Table: "t"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT t."id", t."name" FROM (VALUES (:id1, :name1), (:id2, :name2)) AS t("id", "name")`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "id1,name1,id2,name2" {
+		t.Errorf("RequiredParams = %v, want [id1 name1 id2 name2]", result.RequiredParams)
+	}
+}
+
+func TestRender_StringPosition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringPosition,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "needle"}},
+				},
+				Alias: "at",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT POSITION(:needle IN "email") AS "at" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringSplitPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringSplitPart,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "delim"}, {Name: "part"}},
+				},
+				Alias: "domain",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT SPLIT_PART("email", :delim, :part) AS "domain" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "delim,part" {
+		t.Errorf("RequiredParams = %v, want [delim part]", result.RequiredParams)
+	}
+}
+
+func TestRender_ComparisonValues(t *testing.T) {
+	r := New()
+	avgAge := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{Aggregate: types.AggAvg, Field: types.Field{Name: "age"}},
+		},
+		WhereClause: types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "active"}},
+	}
+
+	tests := []struct {
+		name       string
+		value      types.Value
+		wantSQL    string
+		wantParams string
+	}{
+		{"param", types.Param{Name: "min_age"}, `SELECT "id" FROM "users" WHERE "age" > :min_age`, "min_age"},
+		{"literal", types.Param{Literal: types.LiteralOne}, `SELECT "id" FROM "users" WHERE "age" > 1`, ""},
+		{"field", types.Field{Name: "retire_age", Table: "users"}, `SELECT "id" FROM "users" WHERE "age" > users."retire_age"`, ""},
+		{"subquery", types.Subquery{AST: avgAge}, `SELECT "id" FROM "users" WHERE "age" > (SELECT AVG("age") FROM "users" WHERE "active" = :sq1_active)`, "sq1_active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "users"},
+				Fields:      []types.Field{{Name: "id"}},
+				WhereClause: types.Comparison{Field: types.Field{Name: "age"}, Operator: types.GT, Value: tt.value},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.wantSQL)
+			}
+			if got := strings.Join(result.RequiredParams, ","); got != tt.wantParams {
+				t.Errorf("RequiredParams = %v, want %q", result.RequiredParams, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestRender_ComparisonRejectsNonComparisonOperator(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation:   types.OpSelect,
+		Target:      types.Table{Name: "users"},
+		WhereClause: types.Comparison{Field: types.Field{Name: "name"}, Operator: types.LIKE, Value: types.Param{Name: "pattern"}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "cannot be used in a comparison") {
+		t.Errorf("Render() error = %v, want comparison operator error", err)
+	}
+}
+
+func TestRender_StringAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "team"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggStringAgg,
+					Field:     types.Field{Name: "name"},
+					Separator: &types.Param{Name: "sep"},
+					OrderBy:   []types.OrderBy{{Field: types.Field{Name: "name"}, Direction: types.ASC}},
+				},
+				Alias: "names",
+			},
+		},
+		GroupBy: []types.Field{{Name: "team"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "team", STRING_AGG("name", :sep ORDER BY "name" ASC) AS "names" FROM "users" GROUP BY "team"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "sep" {
+		t.Errorf("RequiredParams = %v, want [sep]", result.RequiredParams)
+	}
+}
+
+func TestRender_TypedParams(t *testing.T) {
+	r := New()
+	uuidParam := func(name string) types.Param { return types.Param{Name: name, Cast: types.CastUUID} }
+
+	tests := []struct {
+		name     string
+		ast      *types.AST
+		expected string
+	}{
+		{
+			name: "where",
+			ast: &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "users"},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: uuidParam("id")},
+			},
+			expected: `SELECT * FROM "users" WHERE "id" = :id::UUID`,
+		},
+		{
+			name: "join",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users", Alias: "u"},
+				Joins: []types.Join{{
+					Type:  types.InnerJoin,
+					Table: types.Table{Name: "posts", Alias: "p"},
+					On:    types.Condition{Field: types.Field{Name: "tenant_id", Table: "p"}, Operator: types.EQ, Value: uuidParam("tenant")},
+				}},
+			},
+			expected: `SELECT * FROM "users" u INNER JOIN "posts" p ON p."tenant_id" = :tenant::UUID`,
+		},
+		{
+			name: "expression",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{{
+					Coalesce: &types.CoalesceExpression{Values: []types.Param{uuidParam("fallback")}},
+					Alias:    "ref",
+				}},
+			},
+			expected: `SELECT COALESCE(:fallback::UUID) AS "ref" FROM "users"`,
+		},
+		{
+			name: "subquery",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				WhereClause: types.SubqueryCondition{
+					Field:    &types.Field{Name: "id"},
+					Operator: types.IN,
+					Subquery: types.Subquery{AST: &types.AST{
+						Operation:   types.OpSelect,
+						Target:      types.Table{Name: "posts"},
+						Fields:      []types.Field{{Name: "user_id"}},
+						WhereClause: types.Condition{Field: types.Field{Name: "tenant_id"}, Operator: types.EQ, Value: uuidParam("tenant")},
+					}},
+				},
+			},
+			expected: `SELECT * FROM "users" WHERE "id" IN (SELECT "user_id" FROM "posts" WHERE "tenant_id" = :sq1_tenant::UUID)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Render(tt.ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_ArrayAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "posts"},
+		Fields:    []types.Field{{Name: "user_id"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function: types.AggArrayAgg,
+					Field:    types.Field{Name: "tag"},
+					Distinct: true,
+					OrderBy:  []types.OrderBy{{Field: types.Field{Name: "tag"}, Direction: types.ASC}},
+				},
+				Alias: "tags",
+			},
+		},
+		GroupBy: []types.Field{{Name: "user_id"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "user_id", ARRAY_AGG(DISTINCT "tag" ORDER BY "tag" ASC) AS "tags" FROM "posts" GROUP BY "user_id"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_ArrayAgg_RejectsSeparator(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "posts"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggArrayAgg,
+					Field:     types.Field{Name: "tag"},
+					Separator: &types.Param{Name: "sep"},
+				},
+				Alias: "tags",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "does not take a separator") {
+		t.Errorf("Render() error = %v, want separator error", err)
+	}
+}
+
+func TestRender_DateAdd(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		function types.DateFunc
+		part     types.DatePart
+		expected string
+	}{
+		{"add days", types.DateAdd, types.PartDay, `SELECT "created_at" + (:days * INTERVAL '1 day') AS "due" FROM "orders"`},
+		{"subtract days", types.DateSub, types.PartDay, `SELECT "created_at" - (:days * INTERVAL '1 day') AS "due" FROM "orders"`},
+		{"add quarters", types.DateAdd, types.PartQuarter, `SELECT "created_at" + (:days * INTERVAL '3 months') AS "due" FROM "orders"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						Date: &types.DateExpression{
+							Function: tt.function,
+							Field:    &types.Field{Name: "created_at"},
+							Part:     tt.part,
+							Amount:   &types.Param{Name: "days"},
+						},
+						Alias: "due",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "days" {
+				t.Errorf("RequiredParams = %v, want [days]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_DateAdd_RejectsDerivedPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateAdd,
+					Field:    &types.Field{Name: "created_at"},
+					Part:     types.PartDayOfWeek,
+					Amount:   &types.Param{Name: "n"},
+				},
+				Alias: "due",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "cannot add DOW units") {
+		t.Errorf("Render() error = %v, want interval unit error", err)
+	}
+}
+
+func TestRender_UpdateSetRow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpUpdate,
+		Target:    types.Table{Name: "accounts"},
+		Updates:   map[types.Field]types.Param{{Name: "note"}: {Name: "note"}},
+		UpdateRows: []types.RowUpdate{{
+			Fields: []types.Field{{Name: "plan"}, {Name: "seats"}},
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "plans"},
+				Fields:      []types.Field{{Name: "name"}, {Name: "seats"}},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "plan_id"}},
+			}},
+		}},
+		WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "id"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `UPDATE "accounts" SET "note" = :note, ("plan", "seats") = (SELECT "name", "seats" FROM "plans" WHERE "id" = :sq1_plan_id) WHERE "id" = :id`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "note,sq1_plan_id,id" {
+		t.Errorf("RequiredParams = %v, want [note sq1_plan_id id]", result.RequiredParams)
+	}
+}
+
+func TestRender_DateDiff(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		part     types.DatePart
+		end      types.Value
+		expected string
+		param    string
+	}{
+		{"day", types.PartDay, types.Field{Name: "shipped_at"}, `SELECT TRUNC(EXTRACT(EPOCH FROM ("shipped_at" - "created_at")) / 86400) AS "elapsed" FROM "orders"`, ""},
+		{"hour", types.PartHour, types.Param{Name: "now"}, `SELECT TRUNC(EXTRACT(EPOCH FROM (:now - "created_at")) / 3600) AS "elapsed" FROM "orders"`, "now"},
+		{"month", types.PartMonth, types.Field{Name: "shipped_at"}, `SELECT (EXTRACT(YEAR FROM AGE("shipped_at", "created_at")) * 12 + EXTRACT(MONTH FROM AGE("shipped_at", "created_at"))) AS "elapsed" FROM "orders"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						DateDiff: &types.DateDiffExpression{
+							End:   tt.end,
+							Start: types.Field{Name: "created_at"},
+							Part:  tt.part,
+						},
+						Alias: "elapsed",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.RequiredParams, ",") != tt.param {
+				t.Errorf("RequiredParams = %v, want [%s]", result.RequiredParams, tt.param)
+			}
+		})
+	}
+}
+
+func TestRender_DateDiff_RejectsDerivedPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				DateDiff: &types.DateDiffExpression{
+					End:   types.Field{Name: "shipped_at"},
+					Start: types.Field{Name: "created_at"},
+					Part:  types.PartEpoch,
+				},
+				Alias: "elapsed",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || !strings.Contains(err.Error(), "cannot count EPOCH units") {
+		t.Errorf("Render() error = %v, want interval unit error", err)
+	}
+}
+
+func TestValidate_AgreesWithRender(t *testing.T) {
+	r := New()
+	users := types.Table{Name: "users"}
+	name := types.Field{Name: "name"}
+	age := types.Field{Name: "age"}
+	created := types.Field{Name: "created_at"}
+	selecting := func(expr types.FieldExpression) *types.AST {
+		return &types.AST{Operation: types.OpSelect, Target: users, FieldExpressions: []types.FieldExpression{expr}}
+	}
+
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"ignore nulls", selecting(types.FieldExpression{Window: &types.WindowExpression{
+			Function:      types.WinLag,
+			Field:         &age,
+			Window:        types.WindowSpec{OrderBy: []types.OrderBy{{Field: created, Direction: types.ASC}}},
+			NullTreatment: types.NullsIgnore,
+		}})},
+		{"string agg without separator", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggStringAgg, Field: name}})},
+		{"distinct order by", &types.AST{
+			Operation: types.OpSelect,
+			Target:    users,
+			Distinct:  true,
+			Fields:    []types.Field{name},
+			Ordering:  []types.OrderBy{{Field: age, Direction: types.ASC}},
+		}},
+		{"nested in subquery", &types.AST{
+			Operation: types.OpSelect,
+			Target:    users,
+			Fields:    []types.Field{name},
+			WhereClause: types.SubqueryCondition{
+				Field:    &name,
+				Operator: types.IN,
+				Subquery: types.Subquery{AST: selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggStringAgg, Field: name}})},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := r.Validate(tt.ast)
+			if len(errs) == 0 {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			_, err := r.Render(tt.ast)
+			if err == nil || err.Error() != errs[0].Error() {
+				t.Errorf("Render() error = %v, want %v", err, errs[0])
+			}
+		})
+	}
+}
+
+func TestRender_DateDiffQuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{{
+			DateDiff: &types.DateDiffExpression{
+				End:   types.Param{Name: "as_of"},
+				Start: types.Param{Name: "since"},
+				Part:  types.PartMonth,
+			},
+		}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT (EXTRACT(YEAR FROM AGE(?, ?)) * 12 + EXTRACT(MONTH FROM AGE(?, ?))) FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"as_of", "since", "as_of", "since"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
 }
@@ -1,19 +1,108 @@
 package astql
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/zoobzio/astql/internal/render"
 	"github.com/zoobzio/astql/internal/types"
 )
 
 // Renderer defines the interface for SQL dialect-specific rendering.
 // Implementations convert an AST to dialect-specific SQL with named parameters.
-type Renderer interface {
-	// Render converts an AST to a QueryResult with dialect-specific SQL.
-	Render(ast *types.AST) (*types.QueryResult, error)
+type Renderer = render.Renderer
+
+// UnsupportedFeatureError is returned by a renderer for a feature its dialect cannot express.
+type UnsupportedFeatureError = render.UnsupportedFeatureError
+
+// RendererByName returns a new renderer for a registered dialect name
+// ("postgres", "mariadb", "sqlite", "mssql"). A dialect is registered when its
+// package is imported, so a blank import is enough to make it available.
+func RendererByName(name string) (Renderer, error) {
+	renderer, ok := render.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer '%s' (registered: %s)", name, strings.Join(render.Names(), ", "))
+	}
+	return renderer, nil
+}
+
+// RenderOutcome is one dialect's result from RenderAll: either Result or Err is set.
+type RenderOutcome struct {
+	Result *QueryResult
+	Err    error
+}
+
+// RenderAll renders the AST with every registered dialect, keyed by dialect name.
+// Dialects register themselves when their package is imported, so import the
+// dialects of interest (a blank import is enough). Intended for portability
+// checks in tests and for generating documentation.
+func RenderAll(ast *types.AST) map[string]RenderOutcome {
+	outcomes := make(map[string]RenderOutcome)
+	for _, name := range render.Names() {
+		renderer, _ := render.Lookup(name)
+		result, err := renderer.Render(ast)
+		outcomes[name] = RenderOutcome{Result: result, Err: err}
+	}
+	return outcomes
+}
+
+// RenderOptions configures a single render call.
+type RenderOptions = render.RenderOptions
+
+// KeywordCase selects how SQL keywords are cased in rendered output.
+type KeywordCase = render.KeywordCase
+
+// Re-export keyword case constants for public API.
+const (
+	KeywordUpper    = render.KeywordUpper
+	KeywordLower    = render.KeywordLower
+	KeywordPreserve = render.KeywordPreserve
+)
+
+// Format breaks rendered SQL onto multiple lines for logs and debugging,
+// in the same layout as RenderOptions.Pretty. It only changes whitespace
+// between clauses, so placeholders and quoted identifiers are untouched.
+// Unlike the option it also works on compound query output.
+func Format(sql string) string {
+	return render.ApplyPretty(sql)
+}
+
+// ExplainMode selects whether a rendered statement is wrapped in EXPLAIN.
+type ExplainMode = render.ExplainMode
+
+// Re-export explain mode constants for public API.
+const (
+	ExplainNone    = render.ExplainNone
+	ExplainPlan    = render.ExplainPlan
+	ExplainAnalyze = render.ExplainAnalyze
+)
+
+// PlaceholderStyle selects how parameters are written into rendered SQL.
+// Set it with a dialect's NewWithOptions.
+type PlaceholderStyle = render.PlaceholderStyle
+
+// Re-export placeholder style constants for public API.
+const (
+	PlaceholderColon    = render.PlaceholderColon
+	PlaceholderQuestion = render.PlaceholderQuestion
+	PlaceholderDollar   = render.PlaceholderDollar
+	PlaceholderAtP      = render.PlaceholderAtP
+)
 
-	// RenderCompound converts a CompoundQuery (UNION, INTERSECT, EXCEPT) to SQL.
-	RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error)
+// ColumnList returns the double-quoted column names of an INSERT AST in the
+// order the renderers emit them, for building statements such as
+// COPY "users" ("email", "name") FROM STDIN.
+// It returns nil for non-INSERT ASTs or inserts without values.
+func ColumnList(ast *types.AST) []string {
+	if ast == nil || ast.Operation != types.OpInsert || len(ast.Values) == 0 {
+		return nil
+	}
 
-	// Capabilities returns the SQL features supported by this dialect.
-	Capabilities() render.Capabilities
+	// Same deterministic order as the INSERT column list
+	fields := ast.InsertColumns()
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = `"` + strings.ReplaceAll(field.Name, `"`, `""`) + `"`
+	}
+	return columns
 }
@@ -1,6 +1,8 @@
 package astql_test
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -324,6 +326,63 @@ func TestRender_Select_MultipleJoins(t *testing.T) {
 	}
 }
 
+// Test OR join conditions are parenthesized when combined with AND in every dialect.
+func TestRender_Select_JoinOrCondition(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		LeftJoin(
+			instance.T("posts", "p"),
+			instance.And(
+				instance.Or(
+					astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+					astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("id"), "p")),
+				),
+				instance.C(instance.WithTable(instance.F("published"), "p"), "=", instance.P("is_published")),
+			),
+		)
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		expected string
+	}{
+		{
+			name:     "PostgreSQL",
+			renderer: postgres.New(),
+			expected: `SELECT u."username" FROM "users" u LEFT JOIN "posts" p ON ((u."id" = p."user_id" OR u."id" = p."id") AND p."published" = :is_published)`,
+		},
+		{
+			name:     "MariaDB",
+			renderer: createMariaDBRenderer(),
+			expected: "SELECT u.`username` FROM `users` u LEFT JOIN `posts` p ON ((u.`id` = p.`user_id` OR u.`id` = p.`id`) AND p.`published` = :is_published)",
+		},
+		{
+			name:     "SQLite",
+			renderer: createSQLiteRenderer(),
+			expected: `SELECT u."username" FROM "users" u LEFT JOIN "posts" p ON ((u."id" = p."user_id" OR u."id" = p."id") AND p."published" = :is_published)`,
+		},
+		{
+			name:     "MSSQL",
+			renderer: createMSSQLRenderer(),
+			expected: `SELECT u.[username] FROM [users] u LEFT JOIN [posts] p ON ((u.[id] = p.[user_id] OR u.[id] = p.[id]) AND p.[published] = :is_published)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := query.Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+		})
+	}
+}
+
 // Test INSERT queries.
 func TestRender_Insert_Basic(t *testing.T) {
 	instance := createRenderTestInstance(t)
@@ -350,6 +409,98 @@ func TestRender_Insert_Basic(t *testing.T) {
 	}
 }
 
+func TestRender_Insert_BoolLiteral(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		expected string
+	}{
+		{"postgres", postgres.New(), `INSERT INTO "users" ("active", "username") VALUES (TRUE, :username)`},
+		{"mariadb", mariadb.New(), "INSERT INTO `users` (`active`, `username`) VALUES (1, :username)"},
+		{"sqlite", sqlite.New(), `INSERT INTO "users" ("active", "username") VALUES (1, :username)`},
+		{"mssql", mssql.New(), `INSERT INTO [users] ([active], [username]) VALUES (1, :username)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := instance.ValueMap()
+			vm[instance.F("active")] = instance.Bool(true)
+			vm[instance.F("username")] = instance.P("username")
+
+			result, err := astql.Insert(instance.T("users")).Values(vm).Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "username" {
+				t.Errorf("RequiredParams = %v, want [username]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_Update_BoolLiteral(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Update(instance.T("users")).
+		Set(instance.F("active"), instance.Bool(false)).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `UPDATE "users" SET "active" = FALSE WHERE "id" = :id`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestColumnList_MatchesInsertOrder(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	vm := instance.ValueMap()
+	vm[instance.F("username")] = instance.P("username")
+	vm[instance.F("email")] = instance.P("email")
+	vm[instance.F("active")] = instance.P("active")
+
+	builder := astql.Insert(instance.T("users")).Values(vm)
+	ast, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	columns := astql.ColumnList(ast)
+	joined := strings.Join(columns, ", ")
+	if joined != `"active", "email", "username"` {
+		t.Errorf("ColumnList = %s, want \"active\", \"email\", \"username\"", joined)
+	}
+
+	result, err := builder.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(result.SQL, "("+joined+")") {
+		t.Errorf("ColumnList %s does not match INSERT column list: %s", joined, result.SQL)
+	}
+}
+
+func TestColumnList_NonInsert(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	ast, err := astql.Select(instance.T("users")).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if columns := astql.ColumnList(ast); columns != nil {
+		t.Errorf("ColumnList = %v, want nil for SELECT", columns)
+	}
+}
+
 func TestRender_Insert_WithReturning(t *testing.T) {
 	instance := createRenderTestInstance(t)
 
@@ -1141,6 +1292,105 @@ func TestRender_Select_RightJoin(t *testing.T) {
 	}
 }
 
+// Test RIGHT JOIN across dialects; SQLite rejects it.
+func TestRender_Select_RightJoinDialects(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		RightJoin(instance.T("posts"), astql.CF(instance.F("id"), "=", instance.F("user_id")))
+
+	tests := []struct {
+		renderer astql.Renderer
+		expected string
+	}{
+		{mariadb.New(), "SELECT `username` FROM `users` RIGHT JOIN `posts` ON `id` = `user_id`"},
+		{mssql.New(), `SELECT [username] FROM [users] RIGHT JOIN [posts] ON [id] = [user_id]`},
+	}
+	for _, tt := range tests {
+		result, err := query.Render(tt.renderer)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if result.SQL != tt.expected {
+			t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+		}
+	}
+
+	_, err := query.Render(sqlite.New())
+	var unsupported astql.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "RIGHT JOIN" {
+		t.Errorf("sqlite error = %v, want unsupported RIGHT JOIN", err)
+	}
+}
+
+func TestRender_Select_IgnoreNullsDialects(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		SelectExpr(astql.FirstValue(instance.F("email")).
+			IgnoreNulls().
+			OrderBy(instance.F("id"), astql.ASC).
+			As("first_email"))
+
+	result, err := query.Render(mssql.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT FIRST_VALUE([email]) IGNORE NULLS OVER (ORDER BY [id] ASC) AS [first_email] FROM [users]`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	for _, renderer := range []astql.Renderer{postgres.New(), mariadb.New(), sqlite.New()} {
+		_, err := query.Render(renderer)
+		var unsupported astql.UnsupportedFeatureError
+		if !errors.As(err, &unsupported) || unsupported.Feature != "IGNORE NULLS on FIRST_VALUE" {
+			t.Errorf("%T error = %v, want unsupported IGNORE NULLS on FIRST_VALUE", renderer, err)
+		}
+	}
+}
+
+func TestRender_Select_RespectNulls(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		SelectExpr(astql.Lag(instance.F("email"), instance.P("offset")).
+			RespectNulls().
+			OrderBy(instance.F("id"), astql.ASC).
+			As("prev_email"))
+
+	result, err := query.Render(mssql.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT LAG([email], :offset) RESPECT NULLS OVER (ORDER BY [id] ASC) AS [prev_email] FROM [users]`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	// RESPECT NULLS is the default elsewhere, so it is omitted rather than rejected.
+	result, err = query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected = `SELECT LAG("email", :offset) OVER (ORDER BY "id" ASC) AS "prev_email" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestRender_Select_IgnoreNullsRequiresValueFunction(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	_, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.RowNumber().IgnoreNulls().OrderBy(instance.F("id"), astql.ASC).As("rn")).
+		Render(mssql.New())
+	if err == nil || !strings.Contains(err.Error(), "ROW_NUMBER does not accept IGNORE NULLS") {
+		t.Errorf("error = %v, want ROW_NUMBER rejection", err)
+	}
+}
+
 // Test CROSS JOIN.
 func TestRender_Select_CrossJoin(t *testing.T) {
 	instance := createRenderTestInstance(t)
@@ -1871,6 +2121,90 @@ func TestRender_Select_JSONBText_InWhere(t *testing.T) {
 	}
 }
 
+// Test JSONText renders field->>'key' with a literal key.
+func TestRender_Select_JSONText(t *testing.T) {
+	instance := createJSONBTestInstance(t)
+
+	statusField := instance.JSONText(instance.F("metadata"), "status")
+	result, err := astql.Select(instance.T("documents")).
+		Fields(instance.F("id")).
+		Where(instance.C(statusField, "=", instance.P("status_value"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "id" FROM "documents" WHERE "metadata"->>'status' = :status_value`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "status_value" {
+		t.Errorf("Expected params [status_value], got %v", result.RequiredParams)
+	}
+}
+
+// Test JSONPath renders field#>>'{a,b}' for nested extraction.
+func TestRender_Select_JSONPath_Nested(t *testing.T) {
+	instance := createJSONBTestInstance(t)
+
+	cityField := instance.JSONPath(instance.F("metadata"), "address", "city")
+	result, err := astql.Select(instance.T("documents")).
+		Fields(instance.F("id"), cityField).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT "id", "metadata"#>>'{address,city}' FROM "documents"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+// Test JSONText/JSONPath reject keys that could break out of the string literal.
+func TestRender_JSONText_InjectionRejected(t *testing.T) {
+	instance := createJSONBTestInstance(t)
+
+	if _, err := instance.TryJSONText(instance.F("metadata"), "status'; DROP TABLE documents; --"); err == nil {
+		t.Error("Expected error for injection in JSON key")
+	}
+	if _, err := instance.TryJSONPath(instance.F("metadata"), "address", "city}'"); err == nil {
+		t.Error("Expected error for injection in JSON path key")
+	}
+	if _, err := instance.TryJSONPath(instance.F("metadata")); err == nil {
+		t.Error("Expected error for empty JSON path")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for injection in JSON key")
+		}
+	}()
+	instance.JSONText(instance.F("metadata"), "a' OR '1'='1")
+}
+
+// Test SQLite rejects literal JSON extraction with the json_extract() hint.
+func TestRender_JSONText_SQLiteError(t *testing.T) {
+	instance := createJSONBTestInstance(t)
+
+	fields := []types.Field{
+		instance.JSONText(instance.F("metadata"), "status"),
+		instance.JSONPath(instance.F("metadata"), "address", "city"),
+	}
+	for _, field := range fields {
+		_, err := astql.Select(instance.T("documents")).
+			Fields(instance.F("id"), field).
+			Render(createSQLiteRenderer())
+		if err == nil {
+			t.Fatal("Expected error for JSON field with SQLite renderer")
+		}
+		if !strings.Contains(err.Error(), "json_extract()") {
+			t.Errorf("Expected json_extract() hint, got: %v", err)
+		}
+	}
+}
+
 // Test non-postgres renderers error on JSONB fields.
 func TestRender_JSONB_NonPostgresError(t *testing.T) {
 	instance := createJSONBTestInstance(t)
@@ -2181,6 +2515,447 @@ func TestRender_CompoundQuery_OrderByNulls(t *testing.T) {
 	}
 }
 
+func TestRender_CustomFunction(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.Func("ext.similarity", instance.F("username"), instance.P("term")), "score"))
+
+	pg := postgres.New()
+	if err := pg.RegisterFunction("ext.similarity", 2); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	result, err := query.Render(pg)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT ext.similarity("username", :term) AS "score" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "term" {
+		t.Errorf("RequiredParams = %v, want [term]", result.RequiredParams)
+	}
+
+	ms := mssql.New()
+	if err := ms.RegisterFunction("ext.similarity", 2); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	result, err = query.Render(ms)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected = `SELECT ext.similarity([username], :term) AS [score] FROM [users]`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestRender_CustomFunctionUnregistered(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.Func("my_fn", instance.F("username"), instance.P("term")), "v"))
+
+	_, err := query.Render(postgres.New())
+	if err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("error = %v, want unregistered function error", err)
+	}
+
+	// Registration is per renderer instance.
+	other := sqlite.New()
+	if err := other.RegisterFunction("my_fn", 1); err != nil {
+		t.Fatalf("RegisterFunction failed: %v", err)
+	}
+	_, err = query.Render(other)
+	if err == nil || !strings.Contains(err.Error(), "takes 1 arguments, got 2") {
+		t.Errorf("error = %v, want argument count error", err)
+	}
+}
+
+func TestFunc_RejectsUnsafeName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for unsafe function name")
+		}
+	}()
+	astql.Func("my_fn(); DROP TABLE users; --")
+}
+
+func TestRender_CompoundQuery_DollarPlaceholders(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query1 := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		Where(instance.C(instance.F("id"), astql.GT, instance.P("min_id")))
+	query2 := astql.Select(instance.T("users")).
+		Fields(instance.F("username")).
+		Where(instance.C(instance.F("id"), astql.GT, instance.P("min_id")))
+
+	renderer := postgres.NewWithOptions(postgres.Options{Placeholder: astql.PlaceholderDollar})
+	result, err := astql.Union(query1, query2).Limit(10).Render(renderer)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `(SELECT "username" FROM "users" WHERE "id" > $1) UNION (SELECT "username" FROM "users" WHERE "id" > $2) LIMIT 10`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	want := []string{"q0_min_id", "q1_min_id"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(want, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, want)
+	}
+}
+
+func TestQueryResult_BindOrdered(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Where(instance.Or(
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("email")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("name")),
+		))
+	values := map[string]any{"name": "ada", "email": "ada@example.com", "unused": 1}
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		expected string
+	}{
+		{"question", mariadb.NewWithOptions(mariadb.Options{Placeholder: astql.PlaceholderQuestion}), "[ada ada@example.com ada]"},
+		{"dollar", postgres.NewWithOptions(postgres.Options{Placeholder: astql.PlaceholderDollar}), "[ada ada@example.com]"},
+		{"colon", postgres.New(), "[ada ada@example.com]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := query.Render(tt.renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			args, err := result.BindOrdered(values)
+			if err != nil {
+				t.Fatalf("BindOrdered failed: %v", err)
+			}
+			if got := fmt.Sprint(args); got != tt.expected {
+				t.Errorf("args = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryResult_BindOrderedMissing(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.And(
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("email")),
+			instance.C(instance.F("id"), astql.GT, instance.P("min_id")),
+		)).
+		Render(mariadb.NewWithOptions(mariadb.Options{Placeholder: astql.PlaceholderQuestion}))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	_, err = result.BindOrdered(map[string]any{"email": "ada@example.com"})
+	if err == nil || err.Error() != "missing values for parameters: name, min_id" {
+		t.Errorf("error = %v, want missing name and min_id", err)
+	}
+}
+
+func TestQueryResult_RebindForSQLServer(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Where(instance.Or(
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("name")),
+		))
+
+	named, err := query.Render(mssql.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	sql, names, err := named.Rebind(astql.PlaceholderAtP)
+	if err != nil {
+		t.Fatalf("Rebind failed: %v", err)
+	}
+
+	direct, err := query.Render(mssql.NewWithOptions(mssql.Options{Placeholder: astql.PlaceholderAtP}))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT * FROM [users] WHERE ([username] = @p1 OR [email] = @p1)`
+	if sql != expected || direct.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s\n%s", expected, sql, direct.SQL)
+	}
+	if fmt.Sprint(names) != "[name]" || fmt.Sprint(direct.PositionalParams) != "[name]" {
+		t.Errorf("names = %v / %v, want [name]", names, direct.PositionalParams)
+	}
+}
+
+func TestQueryResult_ForPgx(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.Or(
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("email")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("name")),
+		)).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	sql, names, err := result.ForPgx()
+	if err != nil {
+		t.Fatalf("ForPgx failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" WHERE ("username" = $1 OR "email" = $2 OR "email" = $1)`
+	if sql != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, sql)
+	}
+	if fmt.Sprint(names) != "[name email]" {
+		t.Errorf("names = %v, want [name email]", names)
+	}
+}
+
+func TestQueryResult_ForMySQL(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.Or(
+			instance.C(instance.F("username"), astql.EQ, instance.P("name")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("email")),
+			instance.C(instance.F("email"), astql.EQ, instance.P("name")),
+		)).
+		Render(mariadb.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	sql, names, err := result.ForMySQL()
+	if err != nil {
+		t.Fatalf("ForMySQL failed: %v", err)
+	}
+	expected := "SELECT * FROM `users` WHERE (`username` = ? OR `email` = ? OR `email` = ?)"
+	if sql != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, sql)
+	}
+	if fmt.Sprint(names) != "[name email name]" {
+		t.Errorf("names = %v, want [name email name]", names)
+	}
+}
+
+func TestRender_Explain(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Fields(instance.F("id")).
+		Where(instance.C(instance.F("username"), astql.EQ, instance.P("name")))
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		mode     astql.ExplainMode
+		expected string
+	}{
+		{"postgres plan", postgres.New(), astql.ExplainPlan, `EXPLAIN (FORMAT JSON) SELECT "id" FROM "users" WHERE "username" = :name`},
+		{"postgres analyze", postgres.New(), astql.ExplainAnalyze, `EXPLAIN (ANALYZE, FORMAT JSON) SELECT "id" FROM "users" WHERE "username" = :name`},
+		{"mariadb plan", mariadb.New(), astql.ExplainPlan, "EXPLAIN FORMAT=JSON SELECT `id` FROM `users` WHERE `username` = :name"},
+		{"mariadb analyze", mariadb.New(), astql.ExplainAnalyze, "ANALYZE FORMAT=JSON SELECT `id` FROM `users` WHERE `username` = :name"},
+		{"sqlite plan", sqlite.New(), astql.ExplainPlan, `EXPLAIN QUERY PLAN SELECT "id" FROM "users" WHERE "username" = :name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := query.RenderWithOptions(tt.renderer, astql.RenderOptions{Explain: tt.mode})
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "name" {
+				t.Errorf("RequiredParams = %v, want [name]", result.RequiredParams)
+			}
+		})
+	}
+
+	// The default path is unchanged.
+	result, err := query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.HasPrefix(result.SQL, "EXPLAIN") {
+		t.Errorf("Render() SQL = %q, want no EXPLAIN prefix", result.SQL)
+	}
+}
+
+func TestRender_ExplainUnsupported(t *testing.T) {
+	instance := createRenderTestInstance(t)
+	query := astql.Select(instance.T("users"))
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		mode     astql.ExplainMode
+		feature  string
+	}{
+		{"sqlite analyze", sqlite.New(), astql.ExplainAnalyze, "EXPLAIN ANALYZE"},
+		{"mssql plan", mssql.New(), astql.ExplainPlan, "EXPLAIN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := query.RenderWithOptions(tt.renderer, astql.RenderOptions{Explain: tt.mode})
+			var unsupported astql.UnsupportedFeatureError
+			if !errors.As(err, &unsupported) || unsupported.Feature != tt.feature {
+				t.Errorf("error = %v, want unsupported %s", err, tt.feature)
+			}
+		})
+	}
+}
+
+func TestRender_Pretty(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		SelectExpr(astql.CountField(instance.WithTable(instance.F("id"), "p"))).
+		InnerJoin(
+			instance.T("posts", "p"),
+			astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+		).
+		LeftJoin(
+			instance.T("comments", "c"),
+			astql.CF(instance.WithTable(instance.F("id"), "p"), "=", instance.WithTable(instance.F("post_id"), "c")),
+		).
+		Where(instance.C(instance.WithTable(instance.F("active"), "u"), astql.EQ, instance.P("active"))).
+		GroupBy(instance.WithTable(instance.F("username"), "u")).
+		HavingAgg(astql.HavingCount(astql.GT, instance.P("min_posts"))).
+		OrderBy(instance.WithTable(instance.F("username"), "u"), astql.ASC).
+		Limit(10)
+
+	compact, err := query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	pretty, err := query.RenderWithOptions(postgres.New(), astql.RenderOptions{Pretty: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := `SELECT u."username", COUNT(p."id")
+FROM "users" u
+  INNER JOIN "posts" p ON u."id" = p."user_id"
+  LEFT JOIN "comments" c ON p."id" = c."post_id"
+WHERE u."active" = :active
+GROUP BY u."username"
+HAVING COUNT(*) > :min_posts
+ORDER BY u."username" ASC
+LIMIT 10`
+	if pretty.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, pretty.SQL)
+	}
+	if strings.Contains(compact.SQL, "\n") {
+		t.Errorf("Render() SQL = %q, want a single line", compact.SQL)
+	}
+	if strings.Join(strings.Fields(pretty.SQL), " ") != compact.SQL {
+		t.Errorf("pretty SQL differs from compact beyond whitespace:\n%s\n%s", pretty.SQL, compact.SQL)
+	}
+	if fmt.Sprint(pretty.RequiredParams) != fmt.Sprint(compact.RequiredParams) {
+		t.Errorf("RequiredParams = %v, want %v", pretty.RequiredParams, compact.RequiredParams)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		SelectExpr(astql.CountField(instance.WithTable(instance.F("id"), "p"))).
+		LeftJoin(
+			instance.T("posts", "p"),
+			astql.CF(instance.WithTable(instance.F("id"), "u"), "=", instance.WithTable(instance.F("user_id"), "p")),
+		).
+		Where(instance.C(instance.WithTable(instance.F("age"), "u"), astql.GE, instance.P("min_age"))).
+		GroupBy(instance.WithTable(instance.F("username"), "u")).
+		HavingAgg(astql.HavingCount(astql.GT, instance.P("min_posts"))).
+		OrderBy(instance.WithTable(instance.F("username"), "u"), astql.ASC).
+		Limit(10)
+
+	renderers := map[string]astql.Renderer{
+		"postgres": postgres.New(),
+		"mariadb":  mariadb.New(),
+		"sqlite":   sqlite.New(),
+		"mssql":    mssql.New(),
+	}
+	for name, renderer := range renderers {
+		t.Run(name, func(t *testing.T) {
+			result, err := query.Render(renderer)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			formatted := astql.Format(result.SQL)
+			if strings.Count(formatted, "\n") < 5 {
+				t.Errorf("Format() did not break clauses:\n%s", formatted)
+			}
+			if strings.Join(strings.Fields(formatted), " ") != result.SQL {
+				t.Errorf("Format() changed more than whitespace:\n%s\n%s", formatted, result.SQL)
+			}
+		})
+	}
+
+	compound, err := astql.Select(instance.T("users")).
+		Fields(instance.F("id")).
+		Where(instance.C(instance.F("age"), astql.GT, instance.P("age"))).
+		Union(astql.Select(instance.T("posts")).Fields(instance.F("id"))).
+		OrderBy(instance.F("id"), astql.ASC).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `(SELECT "id" FROM "users" WHERE "age" > :q0_age) UNION (SELECT "id" FROM "posts")
+ORDER BY "id" ASC`
+	if got := astql.Format(compound.SQL); got != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestRender_ExplainKeywordCase(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		RenderWithOptions(postgres.New(), astql.RenderOptions{Explain: astql.ExplainAnalyze, KeywordCase: astql.KeywordLower})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `explain (analyze, format json) select * from "users"`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
+
+func TestRender_PlaceholderColonIsDefault(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	result, err := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasSuffix(result.SQL, `"id" = :id`) {
+		t.Errorf("SQL = %q, want :id placeholder", result.SQL)
+	}
+	if result.PositionalParams != nil {
+		t.Errorf("PositionalParams = %v, want nil", result.PositionalParams)
+	}
+}
+
 // Test JSONB field in RETURNING clause for UPDATE.
 func TestRender_Update_JSONB_Returning(t *testing.T) {
 	instance := createJSONBTestInstance(t)
@@ -2748,3 +3523,263 @@ func TestRender_JSONB_InAggregateCondition_NonPostgresError(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderAll_EntryPerDialect(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	ast := astql.Select(instance.T("users")).
+		Fields(instance.F("id"), instance.F("email")).
+		DistinctOn(instance.F("email")).
+		OrderBy(instance.F("email"), astql.ASC).
+		MustBuild()
+
+	outcomes := astql.RenderAll(ast)
+	for _, dialect := range []string{"postgres", "mariadb", "sqlite", "mssql"} {
+		outcome, ok := outcomes[dialect]
+		if !ok {
+			t.Errorf("RenderAll missing %s", dialect)
+			continue
+		}
+		if (outcome.Result == nil) == (outcome.Err == nil) {
+			t.Errorf("%s: expected exactly one of Result or Err, got %+v", dialect, outcome)
+			continue
+		}
+		if outcome.Err != nil {
+			var unsupported astql.UnsupportedFeatureError
+			if !errors.As(outcome.Err, &unsupported) {
+				t.Errorf("%s: expected UnsupportedFeatureError, got %v", dialect, outcome.Err)
+			}
+		}
+	}
+
+	expected := `SELECT DISTINCT ON ("email") "id", "email" FROM "users" ORDER BY "email" ASC`
+	if got := outcomes["postgres"]; got.Err != nil || got.Result.SQL != expected {
+		t.Errorf("postgres: Expected SQL:\n%s\nGot:\n%+v", expected, got)
+	}
+	if outcomes["sqlite"].Err == nil {
+		t.Error("sqlite: expected DISTINCT ON to be unsupported")
+	}
+}
+
+func TestRendererByName(t *testing.T) {
+	instance := createRenderTestInstance(t)
+	ast := astql.Select(instance.T("users")).Fields(instance.F("id")).MustBuild()
+
+	tests := map[string]string{
+		"postgres": `SELECT "id" FROM "users"`,
+		"mariadb":  "SELECT `id` FROM `users`",
+		"sqlite":   `SELECT "id" FROM "users"`,
+		"mssql":    `SELECT [id] FROM [users]`,
+	}
+	for name, expected := range tests {
+		renderer, err := astql.RendererByName(name)
+		if err != nil {
+			t.Fatalf("RendererByName(%q) error = %v", name, err)
+		}
+		result, err := renderer.Render(ast)
+		if err != nil {
+			t.Fatalf("%s: Render failed: %v", name, err)
+		}
+		if result.SQL != expected {
+			t.Errorf("%s: Expected SQL:\n%s\nGot:\n%s", name, expected, result.SQL)
+		}
+	}
+}
+
+func TestRendererByName_Unknown(t *testing.T) {
+	_, err := astql.RendererByName("oracle")
+	if err == nil {
+		t.Fatal("Expected error for unknown renderer")
+	}
+	if !strings.Contains(err.Error(), "oracle") || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("Expected error naming the dialect and registered renderers, got %v", err)
+	}
+}
+
+func TestQueryResult_OperationAndTables(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	selectResult, err := astql.Select(instance.T("users", "u")).
+		Fields(instance.WithTable(instance.F("username"), "u")).
+		InnerJoin(instance.T("posts", "p"), astql.CF(instance.WithTable(instance.F("id"), "u"), astql.EQ, instance.WithTable(instance.F("user_id"), "p"))).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if selectResult.Operation != types.OpSelect {
+		t.Errorf("Operation = %v, want %v", selectResult.Operation, types.OpSelect)
+	}
+	if len(selectResult.Tables) != 2 || selectResult.Tables[0] != "users" || selectResult.Tables[1] != "posts" {
+		t.Errorf("Tables = %v, want [users posts]", selectResult.Tables)
+	}
+
+	vm := instance.ValueMap()
+	vm[instance.F("username")] = instance.P("username")
+	insertResult, err := astql.Insert(instance.T("users")).Values(vm).Render(sqlite.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if insertResult.Operation != types.OpInsert {
+		t.Errorf("Operation = %v, want %v", insertResult.Operation, types.OpInsert)
+	}
+	if len(insertResult.Tables) != 1 || insertResult.Tables[0] != "users" {
+		t.Errorf("Tables = %v, want [users]", insertResult.Tables)
+	}
+}
+
+func TestQueryResult_TablesIncludeSubqueries(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	authors := astql.Sub(astql.Select(instance.T("posts")).Fields(instance.F("user_id")))
+	result, err := astql.Select(instance.T("users")).
+		Where(astql.CSub(instance.F("id"), astql.IN, authors)).
+		Render(mariadb.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(result.Tables) != 2 || result.Tables[0] != "users" || result.Tables[1] != "posts" {
+		t.Errorf("Tables = %v, want [users posts]", result.Tables)
+	}
+}
+
+func TestQueryResult_TablesIncludeFilterSubqueries(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	authors := astql.Sub(astql.Select(instance.T("posts")).Fields(instance.F("user_id")))
+	result, err := astql.Select(instance.T("users")).
+		SelectExpr(astql.As(astql.SumFilter(instance.F("age"), astql.CSub(instance.F("id"), astql.IN, authors)), "author_age")).
+		Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(result.Tables) != 2 || result.Tables[0] != "users" || result.Tables[1] != "posts" {
+		t.Errorf("Tables = %v, want [users posts]", result.Tables)
+	}
+}
+
+func TestRender_DequeueLock(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("posts")).
+		Fields(instance.F("id")).
+		Where(instance.C(instance.F("published"), astql.EQ, instance.P("published"))).
+		OrderBy(instance.F("id"), astql.ASC).
+		DequeueLock(instance.P("n"))
+
+	tests := []struct {
+		renderer astql.Renderer
+		expected string
+	}{
+		{postgres.New(), `SELECT "id" FROM "posts" WHERE "published" = :published ORDER BY "id" ASC LIMIT :n FOR UPDATE SKIP LOCKED`},
+		{mariadb.New(), "SELECT `id` FROM `posts` WHERE `published` = :published ORDER BY `id` ASC LIMIT :n FOR UPDATE SKIP LOCKED"},
+	}
+	for _, tt := range tests {
+		result, err := query.Render(tt.renderer)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if result.SQL != tt.expected {
+			t.Errorf("Expected SQL:\n%s\nGot:\n%s", tt.expected, result.SQL)
+		}
+	}
+
+	for _, renderer := range []astql.Renderer{sqlite.New(), mssql.New()} {
+		if renderer.Capabilities().SkipLocked {
+			t.Errorf("%T: SkipLocked capability should be false", renderer)
+		}
+		_, err := query.Render(renderer)
+		var unsupported astql.UnsupportedFeatureError
+		if !errors.As(err, &unsupported) {
+			t.Errorf("%T: error = %v, want unsupported feature error", renderer, err)
+		}
+	}
+}
+
+func TestRender_SkipLockedRequiresLock(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	_, err := astql.Select(instance.T("posts")).SkipLocked().Render(postgres.New())
+	if err == nil {
+		t.Error("Expected error for SKIP LOCKED without a row lock")
+	}
+}
+
+func TestRender_LowercaseIdentifiers(t *testing.T) {
+	project := dbml.NewProject("test")
+	users := dbml.NewTable("Users")
+	users.AddColumn(dbml.NewColumn("ID", "bigint"))
+	users.AddColumn(dbml.NewColumn("UserName", "varchar"))
+	project.AddTable(users)
+	instance, err := astql.NewFromDBML(project)
+	if err != nil {
+		t.Fatalf("Failed to create instance: %v", err)
+	}
+
+	query := astql.Select(instance.T("Users", "u")).
+		Fields(instance.WithTable(instance.F("UserName"), "u")).
+		SelectExpr(astql.As(astql.CountField(instance.F("ID")), "UserCount")).
+		GroupBy(instance.WithTable(instance.F("UserName"), "u")).
+		Where(instance.C(instance.F("ID"), astql.GT, instance.P("MinID")))
+
+	defaults, err := query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT u."UserName", COUNT("ID") AS "UserCount" FROM "Users" u WHERE "ID" > :MinID GROUP BY u."UserName"`
+	if defaults.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, defaults.SQL)
+	}
+
+	lowered, err := query.Render(postgres.NewWithOptions(postgres.Options{LowercaseIdentifiers: true}))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected = `SELECT u."username", COUNT("id") AS "UserCount" FROM "users" u WHERE "id" > :MinID GROUP BY u."username"`
+	if lowered.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, lowered.SQL)
+	}
+	if len(lowered.RequiredParams) != 1 || lowered.RequiredParams[0] != "MinID" {
+		t.Errorf("RequiredParams = %v, want [MinID]", lowered.RequiredParams)
+	}
+
+	union, err := astql.Select(instance.T("Users")).Fields(instance.F("UserName")).
+		Union(astql.Select(instance.T("Users")).Fields(instance.F("UserName"))).
+		OrderBy(instance.F("UserName"), astql.ASC).
+		Render(sqlite.NewWithOptions(sqlite.Options{LowercaseIdentifiers: true}))
+	if err != nil {
+		t.Fatalf("RenderCompound failed: %v", err)
+	}
+	expected = `SELECT "username" FROM "users" UNION SELECT "username" FROM "users" ORDER BY "username" ASC`
+	if union.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, union.SQL)
+	}
+}
+
+func TestRender_TypedParam(t *testing.T) {
+	instance := createRenderTestInstance(t)
+
+	query := astql.Select(instance.T("users")).
+		Where(instance.C(instance.F("id"), astql.EQ, instance.TypedP("id", astql.CastUUID)))
+
+	result, err := query.Render(postgres.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `SELECT * FROM "users" WHERE "id" = :id::UUID`
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "id" {
+		t.Errorf("RequiredParams = %v, want [id]", result.RequiredParams)
+	}
+
+	// Other dialects ignore the hint.
+	result, err = query.Render(mariadb.New())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected = "SELECT * FROM `users` WHERE `id` = :id"
+	if result.SQL != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, result.SQL)
+	}
+}
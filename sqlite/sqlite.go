@@ -19,6 +19,8 @@ type renderContext struct {
 	paramCallback func(types.Param) string
 	paramPrefix   string
 	depth         int
+	selectOne     bool                        // Render the select list as 1 (EXISTS ignores it)
+	windows       map[string]types.WindowSpec // Named windows of the SELECT being rendered, expanded inline
 }
 
 // newRenderContext creates a new render context.
@@ -47,22 +49,52 @@ func (ctx *renderContext) withSubquery() (*renderContext, error) {
 
 // addParam adds a parameter with proper namespacing.
 func (ctx *renderContext) addParam(param types.Param) string {
-	if ctx.paramPrefix != "" {
+	if ctx.paramPrefix != "" && !param.IsLiteral() {
 		param = types.Param{Name: ctx.paramPrefix + param.Name}
 	}
 	return ctx.paramCallback(param)
 }
 
 // Renderer implements the SQLite dialect renderer.
-type Renderer struct{}
+type Renderer struct {
+	opts  Options
+	funcs render.Functions
+}
+
+func init() {
+	render.Register("sqlite", func() render.Renderer { return New() })
+}
+
+// Options configures optional rendering behavior.
+type Options = render.Options
+
+// RenderOptions configures a single render call.
+type RenderOptions = render.RenderOptions
 
 // New creates a new SQLite renderer.
 func New() *Renderer {
 	return &Renderer{}
 }
 
+// NewWithOptions creates a new SQLite renderer with the given options.
+func NewWithOptions(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// RegisterFunction allows a custom scalar function, such as a stored function or
+// an extension function, to be called through astql.Func with exactly argCount
+// arguments. The name may be schema-qualified (ext.my_fn) and renders unquoted.
+func (r *Renderer) RegisterFunction(name string, argCount int) error {
+	return r.funcs.Register(name, argCount)
+}
+
 // Render converts an AST to a QueryResult with SQLite SQL.
 func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
+	return r.RenderWithOptions(ast, RenderOptions{})
+}
+
+// RenderWithOptions converts an AST to a QueryResult, applying per-call options.
+func (r *Renderer) RenderWithOptions(ast *types.AST, opts RenderOptions) (*types.QueryResult, error) {
 	// Validate unsupported features
 	if err := r.validateAST(ast); err != nil {
 		return nil, err
@@ -71,18 +103,26 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 	if err := ast.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid AST: %w", err)
 	}
+	if r.opts.LowercaseIdentifiers {
+		ast = render.LowercaseIdentifiers(ast)
+	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
 
 	addParam := func(param types.Param) string {
-		placeholder := ":" + param.Name
-		if !usedParams[param.Name] {
-			params = append(params, param.Name)
-			usedParams[param.Name] = true
+		if param.IsLiteral() {
+			return r.renderLiteral(param.Literal)
+		}
+		return params.Placeholder(param.Name)
+	}
+
+	if opts.Explain != render.ExplainNone {
+		prefix, err := r.explainPrefix(opts.Explain)
+		if err != nil {
+			return nil, err
 		}
-		return placeholder
+		sql.WriteString(prefix)
 	}
 
 	ctx := newRenderContext(addParam)
@@ -112,14 +152,39 @@ func (r *Renderer) Render(ast *types.AST) (*types.QueryResult, error) {
 		return nil, fmt.Errorf("unsupported operation: %s", ast.Operation)
 	}
 
+	text := sql.String()
+	if opts.Pretty {
+		text = render.ApplyPretty(text)
+	}
+
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              render.ApplyKeywordCase(text, opts.KeywordCase),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        ast.Operation,
+		Tables:           ast.Tables(),
 	}, nil
 }
 
+// explainPrefix returns the EXPLAIN prefix for the mode. SQLite only reports
+// the estimated plan.
+func (r *Renderer) explainPrefix(mode render.ExplainMode) (string, error) {
+	switch mode {
+	case render.ExplainPlan:
+		return "EXPLAIN QUERY PLAN ", nil
+	case render.ExplainAnalyze:
+		return "", render.NewUnsupportedFeatureError("sqlite", "EXPLAIN ANALYZE",
+			"use ExplainPlan for EXPLAIN QUERY PLAN")
+	}
+	return "", fmt.Errorf("unknown explain mode: %d", mode)
+}
+
 // RenderCompound converts a CompoundQuery to a QueryResult with SQL and parameters.
 func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResult, error) {
+	if r.opts.LowercaseIdentifiers {
+		query = render.LowercaseCompoundIdentifiers(query)
+	}
+
 	// Validate each AST in the compound query
 	if err := r.validateAST(query.Base); err != nil {
 		return nil, err
@@ -131,20 +196,16 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	var sql strings.Builder
-	var params []string
-	usedParams := make(map[string]bool)
+	params := render.NewParams(r.opts.Placeholder)
 
 	queryIndex := 0
 
 	makeParamCallback := func(prefix string) func(types.Param) string {
 		return func(param types.Param) string {
-			name := prefix + param.Name
-			placeholder := ":" + name
-			if !usedParams[name] {
-				params = append(params, name)
-				usedParams[name] = true
+			if param.IsLiteral() {
+				return r.renderLiteral(param.Literal)
 			}
-			return placeholder
+			return params.Placeholder(prefix + param.Name)
 		}
 	}
 
@@ -175,15 +236,19 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 		var orderParts []string
 		for i := range query.Ordering {
 			order := &query.Ordering[i]
+			field, err := render.CompoundOrderField(query, order.Field)
+			if err != nil {
+				return nil, err
+			}
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
-					r.renderField(order.Field),
+					r.renderField(field),
 					r.renderOperator(order.Operator),
 					finalCtx.addParam(order.Param),
 					order.Direction)
 			} else {
-				part = fmt.Sprintf("%s %s", r.renderField(order.Field), order.Direction)
+				part = fmt.Sprintf("%s %s", r.renderField(field), order.Direction)
 			}
 			orderParts = append(orderParts, part)
 		}
@@ -201,111 +266,229 @@ func (r *Renderer) RenderCompound(query *types.CompoundQuery) (*types.QueryResul
 	}
 
 	return &types.QueryResult{
-		SQL:            sql.String(),
-		RequiredParams: params,
+		SQL:              sql.String(),
+		RequiredParams:   params.Names(),
+		PositionalParams: params.Positional(),
+		Operation:        types.OpSelect,
+		Tables:           query.Tables(),
 	}, nil
 }
 
-// validateAST checks for SQLite-unsupported features.
+// Validate reports every SQLite incompatibility in ast at once, in the
+// order Render would meet them, or nil when the AST renders on SQLite.
+// Render stops at the first of these errors; Validate is the dry run that
+// lists them all. Structural problems are left to ast.Validate.
+func (r *Renderer) Validate(ast *types.AST) []error {
+	var errs []error
+	r.collectAST(ast, &errs)
+	return errs
+}
+
+// validateAST returns the first SQLite-unsupported feature in ast.
 func (r *Renderer) validateAST(ast *types.AST) error {
+	if errs := r.Validate(ast); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// collectAST appends an error for each SQLite-unsupported feature in ast.
+// Conditions, expressions, and window specs report their first problem.
+func (r *Renderer) collectAST(ast *types.AST, errs *[]error) {
+	if ast.ReturningCount {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "RETURNING count",
+			"read changes() or the driver's rows-affected count instead"))
+	}
+
+	if ast.Operation == types.OpTruncate {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "TRUNCATE",
+			"use Delete without a WHERE clause, which SQLite runs as a truncate"))
+	}
+
+	if len(ast.ValueArrays) > 0 {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "ARRAY constructors",
+			"pass the array as a single JSON param instead"))
+	}
+
+	if usesDefault(ast) {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "DEFAULT values",
+			"omit the column so SQLite applies its default, inserting rows with different defaults separately"))
+	}
+
 	if len(ast.DistinctOn) > 0 {
-		return render.NewUnsupportedFeatureError("sqlite", "DISTINCT ON",
-			"use GROUP BY with MIN/MAX aggregates instead")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "DISTINCT ON",
+			"use GROUP BY with MIN/MAX aggregates instead"))
+	}
+
+	if ast.WithTies {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "FETCH FIRST ... WITH TIES",
+			"filter on RANK() in a derived table instead"))
 	}
 
 	if ast.Lock != nil {
-		return render.NewUnsupportedFeatureError("sqlite", "row-level locking (FOR UPDATE/SHARE)",
-			"SQLite uses database-level locking")
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "row-level locking (FOR UPDATE/SHARE)",
+			"SQLite uses database-level locking"))
+	}
+
+	if ast.GroupByMode != types.GroupPlain {
+		*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", fmt.Sprintf("GROUP BY %s", ast.GroupByMode),
+			"use UNION ALL of separately grouped queries instead"))
+	}
+
+	if ast.TargetSubquery != nil {
+		r.collectAST(ast.TargetSubquery, errs)
 	}
 
 	// Check for JSONB fields in all field locations
 	for _, field := range ast.Fields {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.FieldExpressions {
 		if err := r.validateFieldExpression(&ast.FieldExpressions[i]); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.GroupBy {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
+		}
+	}
+
+	for _, group := range ast.CoalescedGroupBy {
+		if err := r.checkJSONBField(group.Field); err != nil {
+			*errs = append(*errs, err)
 		}
 	}
 
 	for i := range ast.Ordering {
 		if err := r.checkJSONBField(ast.Ordering[i].Field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, field := range ast.Returning {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.Updates {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for field := range ast.UpdateExpressions {
 		if err := r.checkJSONBField(field); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 		exprCopy := ast.UpdateExpressions[field]
 		if err := r.validateFieldExpression(&exprCopy); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	for _, valueSet := range ast.Values {
 		for field := range valueSet {
 			if err := r.checkJSONBField(field); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
 	}
 
+	for _, cells := range ast.ValueSubqueries {
+		for _, sub := range cells {
+			r.collectAST(sub.AST, errs)
+		}
+	}
+
 	// Check for unsupported operators and JSONB in conditions
 	if ast.WhereClause != nil {
 		if err := r.validateCondition(ast.WhereClause); err != nil {
-			return err
+			*errs = append(*errs, err)
+		}
+	}
+
+	if ast.OnConflict != nil {
+		for _, cond := range []types.ConditionItem{ast.OnConflict.ConflictPredicate, ast.OnConflict.DoUpdateWhere} {
+			if cond == nil {
+				continue
+			}
+			if err := r.validateCondition(cond); err != nil {
+				*errs = append(*errs, err)
+			}
 		}
 	}
 
 	for _, join := range ast.Joins {
+		if join.Lateral {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "LATERAL derived tables",
+				"use a correlated subquery in the SELECT list instead"))
+		}
+		if len(join.Columns) > 0 {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "derived table column alias lists",
+				"alias the columns inside the derived table's SELECT list instead"))
+		}
+		if join.Type == types.RightJoin && !r.Capabilities().RightJoin {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "RIGHT JOIN",
+				"swap the table order and use LEFT JOIN instead"))
+		}
+		if join.Type == types.FullOuterJoin && !r.Capabilities().FullOuterJoin {
+			*errs = append(*errs, render.NewUnsupportedFeatureError("sqlite", "FULL OUTER JOIN",
+				"use a LEFT JOIN combined with UNION ALL of the unmatched right rows"))
+		}
 		if join.On != nil {
 			if err := r.validateCondition(join.On); err != nil {
-				return err
+				*errs = append(*errs, err)
 			}
 		}
+		if join.Subquery != nil && join.Subquery.AST != nil {
+			r.collectAST(join.Subquery.AST, errs)
+		}
 	}
 
 	for _, having := range ast.Having {
 		if err := r.validateCondition(having); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
 	// Check for unsupported operators in ORDER BY expressions
 	for i := range ast.Ordering {
 		if err := r.validateOperator(ast.Ordering[i].Operator); err != nil {
-			return err
+			*errs = append(*errs, err)
 		}
 	}
 
-	return nil
 }
 
-// validateFieldExpression validates a field expression for JSONB fields.
+// usesDefault reports whether any INSERT or UPDATE value is the DEFAULT keyword.
+func usesDefault(ast *types.AST) bool {
+	for _, valueSet := range ast.Values {
+		for _, param := range valueSet {
+			if param.IsDefault() {
+				return true
+			}
+		}
+	}
+	for _, param := range ast.Updates {
+		if param.IsDefault() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldExpression validates a field expression for JSONB fields
+// and SQLite-unsupported functions.
 func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
+	if err := r.validateFeatures(*expr); err != nil {
+		return err
+	}
 	if err := r.checkJSONBField(expr.Field); err != nil {
 		return err
 	}
@@ -339,6 +522,16 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.DateDiff != nil {
+		for _, side := range []types.Value{expr.DateDiff.End, expr.DateDiff.Start} {
+			if f, ok := side.(types.Field); ok {
+				if err := r.checkJSONBField(f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	if expr.Cast != nil {
 		if err := r.checkJSONBField(expr.Cast.Field); err != nil {
 			return err
@@ -363,9 +556,113 @@ func (r *Renderer) validateFieldExpression(expr *types.FieldExpression) error {
 		}
 	}
 
+	if expr.OrderedAgg != nil {
+		if err := r.checkJSONBField(expr.OrderedAgg.Field); err != nil {
+			return err
+		}
+		for i := range expr.OrderedAgg.OrderBy {
+			if err := r.checkJSONBField(expr.OrderedAgg.OrderBy[i].Field); err != nil {
+				return err
+			}
+		}
+	}
+
+	if expr.Arithmetic != nil {
+		for _, operand := range []types.ArithmeticOperand{expr.Arithmetic.Left, expr.Arithmetic.Right} {
+			if operand.Expr != nil {
+				if err := r.validateFieldExpression(operand.Expr); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 }
 
+// validateFeatures returns the first SQLite-unsupported function or clause
+// expr uses at its own level; nested arithmetic operands are reached through
+// validateFieldExpression. renderFieldExpression calls it too, so Validate
+// and Render reject the same expressions.
+func (r *Renderer) validateFeatures(expr types.FieldExpression) error {
+	switch {
+	case expr.Math != nil:
+		return r.validateMathExpression(*expr.Math)
+	case expr.String != nil:
+		return r.validateStringExpression(*expr.String)
+	case expr.Date != nil:
+		if expr.Date.Function == types.DateBin {
+			return render.NewUnsupportedFeatureError("sqlite", "DATE_BIN",
+				"bucket on epoch seconds with datetime((unixepoch(ts) / :seconds) * :seconds, 'unixepoch') in a raw query")
+		}
+	case expr.DateDiff != nil:
+		switch expr.DateDiff.Part {
+		case types.PartYear, types.PartQuarter, types.PartMonth:
+			// Months and years vary in length, so julianday() cannot count them
+			return render.NewUnsupportedFeatureError("sqlite", fmt.Sprintf("DATE_DIFF in %s units", expr.DateDiff.Part),
+				"compare strftime('%Y') and strftime('%m') of both dates instead")
+		}
+	case expr.Window != nil:
+		return r.validateNullTreatment(*expr.Window)
+	case expr.Grouping != nil:
+		return render.NewUnsupportedFeatureError("sqlite", "GROUPING() function",
+			"GROUPING() requires ROLLUP, CUBE or GROUPING SETS support")
+	case expr.Array != nil:
+		return render.NewUnsupportedFeatureError("sqlite", "ARRAY constructors",
+			"pass the array as a single JSON param instead")
+	case expr.OrderedAgg != nil:
+		if expr.OrderedAgg.Function == types.AggArrayAgg && !r.Capabilities().ArrayOperators {
+			return render.NewUnsupportedFeatureError("sqlite", "ARRAY_AGG",
+				"use json_group_array for a JSON array, or group_concat for a delimited string")
+		}
+		if len(expr.OrderedAgg.OrderBy) > 0 {
+			return render.NewUnsupportedFeatureError("sqlite", "ORDER BY in aggregate",
+				"order the rows in a subquery before aggregating")
+		}
+	}
+	return nil
+}
+
+// validateMathExpression rejects math functions SQLite lacks without the math extension.
+func (r *Renderer) validateMathExpression(expr types.MathExpression) error {
+	switch expr.Function {
+	case types.MathPower:
+		return render.NewUnsupportedFeatureError("sqlite", "POWER function",
+			"load the math extension or compute in application code")
+	case types.MathSqrt:
+		return render.NewUnsupportedFeatureError("sqlite", "SQRT function",
+			"load the math extension or compute in application code")
+	}
+	return nil
+}
+
+// validateStringExpression rejects string functions SQLite has no built-in for.
+func (r *Renderer) validateStringExpression(expr types.StringExpression) error {
+	switch expr.Function {
+	case types.StringLPad, types.StringRPad:
+		return render.NewUnsupportedFeatureError("sqlite", string(expr.Function),
+			"SQLite has no padding functions; pad in application code or build the padding with substr() and ||")
+	case types.StringReverse:
+		return render.NewUnsupportedFeatureError("sqlite", "REVERSE",
+			"SQLite has no built-in REVERSE; register an application-defined function or reverse in application code")
+	case types.StringInitcap:
+		return render.NewUnsupportedFeatureError("sqlite", "INITCAP",
+			"capitalize in application code, or combine upper(substr(s, 1, 1)) with lower(substr(s, 2)) for single words")
+	case types.StringSplitPart:
+		return render.NewUnsupportedFeatureError("sqlite", "SPLIT_PART",
+			"SQLite has no split function; split in application code or walk the string with a recursive CTE over instr() and substr()")
+	}
+	return nil
+}
+
+// validateNullTreatment rejects IGNORE NULLS, which SQLite does not accept.
+func (r *Renderer) validateNullTreatment(expr types.WindowExpression) error {
+	if expr.NullTreatment == "" || expr.NullTreatment == types.NullsRespect {
+		return nil
+	}
+	return render.NewUnsupportedFeatureError("sqlite", string(expr.NullTreatment)+" on "+string(expr.Function),
+		"use a subquery that filters NULLs, or carry values forward with a running COUNT partition")
+}
+
 // validateCondition recursively checks conditions for unsupported operators and JSONB fields.
 func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 	switch c := cond.(type) {
@@ -388,7 +685,23 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			return err
 		}
 		return r.validateOperator(c.Operator)
+	case types.Comparison:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
+		switch v := c.Value.(type) {
+		case types.Field:
+			return r.checkJSONBField(v)
+		case types.Subquery:
+			if v.AST != nil {
+				return r.validateAST(v.AST)
+			}
+		}
 	case types.SubqueryCondition:
+		if c.Quantifier != types.QuantNone {
+			return render.NewUnsupportedFeatureError("sqlite", fmt.Sprintf("%s subquery comparisons", c.Quantifier),
+				"use MIN/MAX in the subquery or EXISTS instead")
+		}
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
 				return err
@@ -402,6 +715,23 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 				return err
 			}
 		}
+	case types.TupleCondition:
+		for _, field := range c.Fields {
+			if err := r.checkJSONBField(field); err != nil {
+				return err
+			}
+		}
+		// Row values are supported since SQLite 3.15; IN is only valid against a subquery
+		if c.Subquery == nil {
+			if err := r.validateOperator(c.Operator); err != nil {
+				return err
+			}
+		}
+		if c.Subquery != nil && c.Subquery.AST != nil {
+			if err := r.validateAST(c.Subquery.AST); err != nil {
+				return err
+			}
+		}
 	case types.AggregateCondition:
 		if c.Field != nil {
 			if err := r.checkJSONBField(*c.Field); err != nil {
@@ -409,6 +739,10 @@ func (r *Renderer) validateCondition(cond types.ConditionItem) error {
 			}
 		}
 		return r.validateOperator(c.Operator)
+	case types.InListCondition:
+		if err := r.checkJSONBField(c.Field); err != nil {
+			return err
+		}
 	case types.BetweenCondition:
 		if err := r.checkJSONBField(c.Field); err != nil {
 			return err
@@ -440,13 +774,16 @@ func (r *Renderer) validateOperator(op types.Operator) error {
 }
 
 func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	ctx.windows = ast.Windows
 	sql.WriteString("SELECT ")
 
 	if ast.Distinct {
 		sql.WriteString("DISTINCT ")
 	}
 
-	if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
+	if ctx.selectOne {
+		sql.WriteString("1")
+	} else if len(ast.Fields) == 0 && len(ast.FieldExpressions) == 0 {
 		sql.WriteString("*")
 	} else {
 		var selections []string
@@ -470,18 +807,13 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
@@ -492,13 +824,9 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 		}
 	}
 
-	if len(ast.GroupBy) > 0 {
+	if ast.HasGroupBy() {
 		sql.WriteString(" GROUP BY ")
-		var groupFields []string
-		for _, field := range ast.GroupBy {
-			groupFields = append(groupFields, r.renderField(field))
-		}
-		sql.WriteString(strings.Join(groupFields, ", "))
+		sql.WriteString(r.renderGroupBy(ast, ctx))
 	}
 
 	if len(ast.Having) > 0 {
@@ -549,6 +877,37 @@ func (r *Renderer) renderSelect(ast *types.AST, sql *strings.Builder, ctx *rende
 	return nil
 }
 
+// renderGroupBy renders the GROUP BY list. SQLite only supports plain
+// grouping; ROLLUP, CUBE and GROUPING SETS are rejected in validateAST.
+func (r *Renderer) renderGroupBy(ast *types.AST, ctx *renderContext) string {
+	grouped := ast.GroupByFields()
+	groupFields := make([]string, 0, len(grouped)+len(ast.CoalescedGroupBy))
+	for _, field := range grouped {
+		groupFields = append(groupFields, r.renderField(field))
+	}
+	for _, group := range ast.CoalescedGroupBy {
+		groupFields = append(groupFields, fmt.Sprintf("COALESCE(%s, %s)", r.renderField(group.Field), ctx.addParam(group.Sentinel)))
+	}
+	return strings.Join(groupFields, ", ")
+}
+
+// renderLiteral renders a literal value inline. SQLite stores booleans as integers.
+func (r *Renderer) renderLiteral(lit types.Literal) string {
+	switch lit {
+	case types.LiteralTrue:
+		return "1"
+	case types.LiteralFalse:
+		return "0"
+	case types.LiteralOne:
+		return "1"
+	case types.LiteralDefault:
+		return "DEFAULT"
+	default:
+		// Unreachable: AST validation rejects unknown literals.
+		return "NULL"
+	}
+}
+
 func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
 	sql.WriteString("INSERT INTO ")
 	sql.WriteString(r.renderTable(ast.Target))
@@ -557,15 +916,9 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		return fmt.Errorf("INSERT requires at least one value set")
 	}
 
-	fields := make([]string, 0, len(ast.Values[0]))
-	fieldObjs := make([]types.Field, 0, len(ast.Values[0]))
-	for field := range ast.Values[0] {
-		fieldObjs = append(fieldObjs, field)
-	}
-
-	sort.Slice(fieldObjs, func(i, j int) bool {
-		return fieldObjs[i].Name < fieldObjs[j].Name
-	})
+	// Columns sorted by name for deterministic output, including subquery cells
+	fieldObjs := ast.InsertColumns()
+	fields := make([]string, 0, len(fieldObjs))
 
 	for _, field := range fieldObjs {
 		fields = append(fields, r.quoteIdentifier(field.Name))
@@ -576,11 +929,19 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 	sql.WriteString(") VALUES ")
 
 	valueSets := make([]string, 0, len(ast.Values))
-	for _, valueSet := range ast.Values {
+	ctx := newRenderContext(addParam)
+	for i, valueSet := range ast.Values {
 		var values []string
 		for _, field := range fieldObjs {
-			param := valueSet[field]
-			values = append(values, addParam(param))
+			if sub, ok := ast.ValueSubquery(i, field); ok {
+				var subSQL strings.Builder
+				if err := r.renderSubquery(sub, &subSQL, ctx); err != nil {
+					return err
+				}
+				values = append(values, "("+subSQL.String()+")")
+				continue
+			}
+			values = append(values, addParam(valueSet[field]))
 		}
 		valueSets = append(valueSets, "("+strings.Join(values, ", ")+")")
 	}
@@ -595,6 +956,13 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 		}
 		sql.WriteString(strings.Join(conflictFields, ", "))
 		sql.WriteString(") ")
+		if ast.OnConflict.ConflictPredicate != nil {
+			sql.WriteString("WHERE ")
+			if err := r.renderCondition(ast.OnConflict.ConflictPredicate, sql, ctx); err != nil {
+				return err
+			}
+			sql.WriteString(" ")
+		}
 
 		switch ast.OnConflict.Action {
 		case types.DoNothing:
@@ -614,9 +982,21 @@ func (r *Renderer) renderInsert(ast *types.AST, sql *strings.Builder, addParam f
 			var updates []string
 			for _, field := range conflictUpdateFields {
 				param := ast.OnConflict.Updates[field]
-				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), addParam(param)))
+				var value string
+				if param.IsExcluded() {
+					value = "EXCLUDED." + r.quoteIdentifier(param.Excluded.Field.Name)
+				} else {
+					value = addParam(param)
+				}
+				updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), value))
 			}
 			sql.WriteString(strings.Join(updates, ", "))
+			if ast.OnConflict.DoUpdateWhere != nil {
+				sql.WriteString(" WHERE ")
+				if err := r.renderCondition(ast.OnConflict.DoUpdateWhere, sql, ctx); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -671,6 +1051,21 @@ func (r *Renderer) renderUpdate(ast *types.AST, sql *strings.Builder, addParam f
 		updates = append(updates, fmt.Sprintf("%s = %s", r.quoteIdentifier(field.Name), rendered))
 	}
 
+	// Render row assignments from subqueries
+	for _, row := range ast.UpdateRows {
+		columns := make([]string, 0, len(row.Fields))
+		for _, field := range row.Fields {
+			columns = append(columns, r.quoteIdentifier(field.Name))
+		}
+		var sub strings.Builder
+		sub.WriteString("(" + strings.Join(columns, ", ") + ") = (")
+		if err := r.renderSubquery(row.Subquery, &sub, newRenderContext(addParam)); err != nil {
+			return err
+		}
+		sub.WriteString(")")
+		updates = append(updates, sub.String())
+	}
+
 	sql.WriteString(strings.Join(updates, ", "))
 
 	if ast.WhereClause != nil {
@@ -718,26 +1113,20 @@ func (r *Renderer) renderDelete(ast *types.AST, sql *strings.Builder, addParam f
 }
 
 func (r *Renderer) renderCount(ast *types.AST, sql *strings.Builder, addParam func(types.Param) string) error {
+	ctx := newRenderContext(addParam)
 	sql.WriteString("SELECT " + countStarSQL + " FROM ")
-	sql.WriteString(r.renderTable(ast.Target))
+	if err := r.renderFrom(ast, sql, ctx); err != nil {
+		return err
+	}
 
 	for _, join := range ast.Joins {
-		sql.WriteString(" ")
-		sql.WriteString(string(join.Type))
-		sql.WriteString(" ")
-		sql.WriteString(r.renderTable(join.Table))
-		if join.Type != types.CrossJoin {
-			sql.WriteString(" ON ")
-			ctx := newRenderContext(addParam)
-			if err := r.renderCondition(join.On, sql, ctx); err != nil {
-				return err
-			}
+		if err := r.renderJoin(join, sql, ctx); err != nil {
+			return err
 		}
 	}
 
 	if ast.WhereClause != nil {
 		sql.WriteString(" WHERE ")
-		ctx := newRenderContext(addParam)
 		if err := r.renderCondition(ast.WhereClause, sql, ctx); err != nil {
 			return err
 		}
@@ -760,6 +1149,94 @@ func (r *Renderer) renderTable(table types.Table) string {
 	return quotedName
 }
 
+// renderFrom renders the FROM target: a table or a derived table with its alias.
+func (r *Renderer) renderFrom(ast *types.AST, sql *strings.Builder, ctx *renderContext) error {
+	if ast.Target.Values != nil {
+		sql.WriteString(r.renderValuesTable(ast.Target, ctx))
+		return nil
+	}
+	if ast.TargetSubquery == nil {
+		sql.WriteString(r.renderTable(ast.Target))
+		return nil
+	}
+	sql.WriteString("(")
+	if err := r.renderSubquery(types.Subquery{AST: ast.TargetSubquery}, sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(") AS ")
+	sql.WriteString(ast.TargetAlias)
+	return nil
+}
+
+// renderValuesTable renders an inline VALUES source with its alias. SQLite has
+// no derived table column lists, so the rows become a UNION ALL of SELECTs
+// whose first branch names the columns.
+func (r *Renderer) renderValuesTable(table types.Table, ctx *renderContext) string {
+	selects := make([]string, 0, len(table.Values.Rows))
+	for i, row := range table.Values.Rows {
+		values := make([]string, 0, len(row))
+		for j, value := range row {
+			value := ctx.addParam(value)
+			if i == 0 {
+				value += " AS " + r.quoteIdentifier(table.Values.Columns[j])
+			}
+			values = append(values, value)
+		}
+		selects = append(selects, "SELECT "+strings.Join(values, ", "))
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ") AS " + table.Alias
+}
+
+// renderJoin renders a single JOIN clause against a table or derived table.
+func (r *Renderer) renderJoin(join types.Join, sql *strings.Builder, ctx *renderContext) error {
+	sql.WriteString(" ")
+	sql.WriteString(string(join.Type))
+	sql.WriteString(" ")
+	if join.Lateral {
+		sql.WriteString("LATERAL ")
+	}
+	if join.Subquery != nil {
+		sql.WriteString("(")
+		if err := r.renderSubquery(*join.Subquery, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(") AS ")
+		sql.WriteString(join.Table.Alias)
+		if len(join.Columns) > 0 {
+			columns := make([]string, 0, len(join.Columns))
+			for _, col := range join.Columns {
+				columns = append(columns, r.quoteIdentifier(col))
+			}
+			sql.WriteString("(" + strings.Join(columns, ", ") + ")")
+		}
+	} else if join.Table.Values != nil {
+		sql.WriteString(r.renderValuesTable(join.Table, ctx))
+	} else {
+		sql.WriteString(r.renderTable(join.Table))
+	}
+
+	// USING replaces ON; CROSS JOIN and NATURAL JOIN have neither
+	if len(join.Using) > 0 {
+		sql.WriteString(" USING ")
+		sql.WriteString(r.renderUsing(join.Using))
+	} else if join.Type.RequiresOn() {
+		sql.WriteString(" ON ")
+		if err := r.renderCondition(join.On, sql, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderUsing renders a JOIN USING column list with unqualified column names.
+func (r *Renderer) renderUsing(fields []types.Field) string {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, r.quoteIdentifier(field.Name))
+	}
+	return "(" + strings.Join(columns, ", ") + ")"
+}
+
 func (r *Renderer) renderField(field types.Field) string {
 	quotedName := r.quoteIdentifier(field.Name)
 	if field.Table != "" {
@@ -770,7 +1247,7 @@ func (r *Renderer) renderField(field types.Field) string {
 
 // checkJSONBField returns an error if the field uses JSONB access operators.
 func (r *Renderer) checkJSONBField(field types.Field) error {
-	if field.JSONBTextKey != nil || field.JSONBPathKey != nil {
+	if field.HasJSONAccess() {
 		return render.NewUnsupportedFeatureError("sqlite", "JSONB field access operators",
 			"use json_extract() instead")
 	}
@@ -812,6 +1289,10 @@ func (r *Renderer) renderAggregateExpression(aggregate types.AggregateFunc, fiel
 }
 
 func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *renderContext) (string, error) {
+	if err := r.validateFeatures(expr); err != nil {
+		return "", err
+	}
+
 	var result string
 
 	switch {
@@ -851,8 +1332,17 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 			return "", err
 		}
 		result = dateStr
+	case expr.DateDiff != nil:
+		diffStr, err := r.renderDateDiff(*expr.DateDiff, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = diffStr
 	case expr.Cast != nil:
-		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.mapCastType(expr.Cast.CastType))
+		if err := expr.Cast.Validate(); err != nil {
+			return "", err
+		}
+		result = fmt.Sprintf("CAST(%s AS %s)", r.renderField(expr.Cast.Field), r.renderCastType(*expr.Cast))
 	case expr.Window != nil:
 		windowStr, err := r.renderWindowExpression(*expr.Window, ctx)
 		if err != nil {
@@ -872,6 +1362,33 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 		paramStr := ctx.addParam(expr.Binary.Param)
 		opStr := r.renderOperator(expr.Binary.Operator)
 		result = fmt.Sprintf("%s %s %s", r.renderField(expr.Binary.Field), opStr, paramStr)
+	case expr.Arithmetic != nil:
+		// Render arithmetic between expressions
+		arithStr, err := r.renderArithmetic(*expr.Arithmetic, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = arithStr
+	case expr.Func != nil:
+		// Render a registered custom function
+		funcStr, err := r.renderFunc(*expr.Func, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = funcStr
+	case expr.JSONObject != nil:
+		// Render JSON object construction
+		jsonStr, err := r.renderJSONObject(*expr.JSONObject, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = jsonStr
+	case expr.OrderedAgg != nil:
+		aggStr, err := r.renderOrderedAggregate(*expr.OrderedAgg, ctx)
+		if err != nil {
+			return "", err
+		}
+		result = aggStr
 	case expr.Aggregate != "":
 		result = r.renderAggregateExpression(expr.Aggregate, expr.Field)
 		if expr.Filter != nil {
@@ -894,10 +1411,65 @@ func (r *Renderer) renderFieldExpression(expr types.FieldExpression, ctx *render
 	return result, nil
 }
 
+// renderOrderedAggregate renders STRING_AGG as group_concat(field, :sep).
+// Without a separator group_concat joins values with a comma.
+func (r *Renderer) renderOrderedAggregate(expr types.OrderedAggregate, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	var sql strings.Builder
+	sql.WriteString("GROUP_CONCAT(")
+	sql.WriteString(r.renderField(expr.Field))
+	if expr.Separator != nil {
+		sql.WriteString(", ")
+		sql.WriteString(ctx.addParam(*expr.Separator))
+	}
+	sql.WriteString(")")
+	return sql.String(), nil
+}
+
+// renderArithmetic renders left <op> right. Nested arithmetic operands are
+// parenthesized so the expression tree's grouping is preserved.
+func (r *Renderer) renderArithmetic(expr types.ArithmeticExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	left, err := r.renderArithmeticOperand(expr.Left, ctx)
+	if err != nil {
+		return "", err
+	}
+	right, err := r.renderArithmeticOperand(expr.Right, ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, expr.Operator, right), nil
+}
+
+func (r *Renderer) renderArithmeticOperand(operand types.ArithmeticOperand, ctx *renderContext) (string, error) {
+	if operand.Param != nil {
+		return ctx.addParam(*operand.Param), nil
+	}
+	result, err := r.renderFieldExpression(*operand.Expr, ctx)
+	if err != nil {
+		return "", err
+	}
+	if operand.Expr.Arithmetic != nil {
+		result = "(" + result + ")"
+	}
+	return result, nil
+}
+
+// renderCastType renders the SQLite cast target. Type parameters are dropped
+// because SQLite type affinity ignores precision, scale and length.
+func (r *Renderer) renderCastType(cast types.CastExpression) string {
+	return r.mapCastType(cast.CastType)
+}
+
 // mapCastType maps PostgreSQL cast types to SQLite equivalents.
 func (r *Renderer) mapCastType(castType types.CastType) string {
 	switch castType {
-	case types.CastText:
+	case types.CastText, types.CastVarchar:
 		return "TEXT"
 	case types.CastInteger, types.CastSmallint:
 		return "INTEGER"
@@ -915,6 +1487,42 @@ func (r *Renderer) mapCastType(castType types.CastType) string {
 	}
 }
 
+// renderJSONObject renders json_object('key', value, ...).
+// Keys are validated identifiers, so they are safe to emit as string literals.
+func (r *Renderer) renderJSONObject(expr types.JSONObjectExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Pairs)*2)
+	for _, pair := range expr.Pairs {
+		args = append(args, "'"+pair.Key+"'")
+		if pair.Field != nil {
+			args = append(args, r.renderField(*pair.Field))
+		} else {
+			args = append(args, ctx.addParam(*pair.Param))
+		}
+	}
+	return fmt.Sprintf("json_object(%s)", strings.Join(args, ", ")), nil
+}
+
+// renderFunc renders a call to a custom function registered with RegisterFunction.
+func (r *Renderer) renderFunc(expr types.FuncExpression, ctx *renderContext) (string, error) {
+	if err := r.funcs.Check("sqlite", expr); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(expr.Args))
+	for _, arg := range expr.Args {
+		if arg.Field != nil {
+			args = append(args, r.renderField(*arg.Field))
+		} else {
+			args = append(args, ctx.addParam(*arg.Param))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", expr.Name, strings.Join(args, ", ")), nil
+}
+
 func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builder, ctx *renderContext) error {
 	switch c := cond.(type) {
 	case types.Condition:
@@ -938,10 +1546,22 @@ func (r *Renderer) renderCondition(cond types.ConditionItem, sql *strings.Builde
 			r.renderField(c.LeftField),
 			r.renderOperator(c.Operator),
 			r.renderField(c.RightField))
+	case types.Comparison:
+		if err := r.renderValueComparison(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.SubqueryCondition:
 		if err := r.renderSubqueryCondition(c, sql, ctx); err != nil {
 			return err
 		}
+	case types.TupleCondition:
+		if err := r.renderTupleCondition(c, sql, ctx); err != nil {
+			return err
+		}
+	case types.InListCondition:
+		if err := r.renderInListCondition(c, sql, ctx); err != nil {
+			return err
+		}
 	case types.AggregateCondition:
 		sql.WriteString(r.renderAggregateCondition(c, ctx.addParam))
 	case types.BetweenCondition:
@@ -1022,6 +1642,38 @@ func (r *Renderer) renderBetweenCondition(cond types.BetweenCondition, addParam
 	return fmt.Sprintf("%s %s %s AND %s", field, op, addParam(cond.Low), addParam(cond.High))
 }
 
+// renderValueComparison renders field op value for a param, field, or
+// scalar subquery on the right-hand side.
+func (r *Renderer) renderValueComparison(cond types.Comparison, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	sql.WriteString(r.renderField(cond.Field))
+	sql.WriteString(" ")
+	sql.WriteString(r.renderOperator(cond.Operator))
+	sql.WriteString(" ")
+	return r.renderValue(cond.Value, sql, ctx)
+}
+
+// renderValue renders the right-hand side of a Comparison.
+func (r *Renderer) renderValue(value types.Value, sql *strings.Builder, ctx *renderContext) error {
+	switch v := value.(type) {
+	case types.Param:
+		sql.WriteString(ctx.addParam(v))
+	case types.Field:
+		sql.WriteString(r.renderField(v))
+	case types.Subquery:
+		sql.WriteString("(")
+		if err := r.renderSubquery(v, sql, ctx); err != nil {
+			return err
+		}
+		sql.WriteString(")")
+	default:
+		return fmt.Errorf("unknown value type: %T", v)
+	}
+	return nil
+}
+
 func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *strings.Builder, ctx *renderContext) error {
 	switch cond.Operator {
 	case types.EXISTS, types.NotExists:
@@ -1033,19 +1685,119 @@ func (r *Renderer) renderSubqueryCondition(cond types.SubqueryCondition, sql *st
 		}
 		sql.WriteString(r.renderField(*cond.Field))
 		sql.WriteString(" ")
-		sql.WriteString(string(cond.Operator))
+		if cond.Quantifier != types.QuantNone {
+			// Quantified comparison: field op ANY|ALL (subquery)
+			if !cond.Operator.IsComparison() {
+				return fmt.Errorf("operator %s cannot be used with %s", cond.Operator, cond.Quantifier)
+			}
+			sql.WriteString(r.renderOperator(cond.Operator))
+			sql.WriteString(" ")
+			sql.WriteString(string(cond.Quantifier))
+		} else {
+			sql.WriteString(string(cond.Operator))
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("(")
-	if err := r.renderSubquery(cond.Subquery, sql, ctx); err != nil {
+	if cond.Operator == types.EXISTS || cond.Operator == types.NotExists {
+		if err := r.renderExistsSubquery(cond.Subquery, sql, ctx); err != nil {
+			return err
+		}
+	} else if err := r.renderSubquery(r.inSubquery(cond.Operator, cond.Subquery), sql, ctx); err != nil {
+		return err
+	}
+	sql.WriteString(")")
+
+	return nil
+}
+
+// renderInListCondition renders field IN (:a, :b). An empty list renders a
+// constant guard predicate unless StrictEmptyIn is set.
+func (r *Renderer) renderInListCondition(cond types.InListCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	if len(cond.Values) == 0 {
+		guard, err := render.EmptyInGuard(cond, r.opts.StrictEmptyIn)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(guard)
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		placeholders = append(placeholders, ctx.addParam(value))
+	}
+	if cond.ValuesTable {
+		fmt.Fprintf(sql, "%s %s (%s)", r.renderField(cond.Field), cond.Operator, render.ValuesRows(placeholders))
+		return nil
+	}
+	fmt.Fprintf(sql, "%s %s (%s)", r.renderField(cond.Field), cond.Operator, strings.Join(placeholders, ", "))
+	return nil
+}
+
+// renderTupleCondition renders a row value comparison: ("a", "b") op (:p1, :p2) or (subquery).
+func (r *Renderer) renderTupleCondition(cond types.TupleCondition, sql *strings.Builder, ctx *renderContext) error {
+	if err := cond.Validate(); err != nil {
 		return err
 	}
+
+	sql.WriteString("(")
+	for i, field := range cond.Fields {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(r.renderField(field))
+	}
+	sql.WriteString(") ")
+
+	if cond.Operator == types.IN || cond.Operator == types.NotIn {
+		sql.WriteString(string(cond.Operator))
+	} else {
+		sql.WriteString(r.renderOperator(cond.Operator))
+	}
+	sql.WriteString(" (")
+
+	if cond.Subquery != nil {
+		if err := r.renderSubquery(r.inSubquery(cond.Operator, *cond.Subquery), sql, ctx); err != nil {
+			return err
+		}
+	} else {
+		for i, value := range cond.Values {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(ctx.addParam(value))
+		}
+	}
 	sql.WriteString(")")
 
 	return nil
 }
 
+// renderExistsSubquery renders an EXISTS subquery as SELECT 1 without a redundant LIMIT 1.
+func (r *Renderer) renderExistsSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
+	subCtx, err := ctx.withSubquery()
+	if err != nil {
+		return err
+	}
+
+	ast, selectOne := types.ExistsSubquery(subquery.AST)
+	subCtx.selectOne = selectOne
+	return r.renderSelect(ast, sql, subCtx)
+}
+
+// inSubquery applies the StripInSubqueryDistinct option to IN/NOT IN subqueries.
+func (r *Renderer) inSubquery(op types.Operator, subquery types.Subquery) types.Subquery {
+	if !r.opts.StripInSubqueryDistinct || (op != types.IN && op != types.NotIn) {
+		return subquery
+	}
+	return types.Subquery{AST: types.StripDistinct(subquery.AST)}
+}
+
 func (r *Renderer) renderSubquery(subquery types.Subquery, sql *strings.Builder, ctx *renderContext) error {
 	subCtx, err := ctx.withSubquery()
 	if err != nil {
@@ -1126,14 +1878,6 @@ func (r *Renderer) renderMathExpression(expr types.MathExpression, ctx *renderCo
 		sql.WriteString("ABS(")
 		sql.WriteString(r.renderField(expr.Field))
 		sql.WriteString(")")
-	case types.MathPower:
-		// SQLite doesn't have POWER, use POW from math extension or reject
-		return "", render.NewUnsupportedFeatureError("sqlite", "POWER function",
-			"load the math extension or compute in application code")
-	case types.MathSqrt:
-		// SQLite doesn't have SQRT natively
-		return "", render.NewUnsupportedFeatureError("sqlite", "SQRT function",
-			"load the math extension or compute in application code")
 	default:
 		return "", fmt.Errorf("unsupported math function: %s", expr.Function)
 	}
@@ -1200,6 +1944,15 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 			sql.WriteString(r.renderField(f))
 		}
 		sql.WriteString(")")
+	case types.StringPosition:
+		// SQLite uses INSTR(haystack, needle)
+		sql.WriteString("INSTR(")
+		sql.WriteString(r.renderField(expr.Field))
+		if len(expr.Args) >= 1 {
+			sql.WriteString(", ")
+			sql.WriteString(ctx.addParam(expr.Args[0]))
+		}
+		sql.WriteString(")")
 	default:
 		return "", fmt.Errorf("unsupported string function: %s", expr.Function)
 	}
@@ -1207,13 +1960,16 @@ func (r *Renderer) renderStringExpression(expr types.StringExpression, ctx *rend
 	return sql.String(), nil
 }
 
-func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderContext) (string, error) {
+func (r *Renderer) renderDateExpression(expr types.DateExpression, ctx *renderContext) (string, error) {
 	var sql strings.Builder
 
 	switch expr.Function {
 	case types.DateNow:
 		// SQLite uses DATETIME('now') for current timestamp
 		sql.WriteString("DATETIME('now')")
+	case types.DateNowTz:
+		// SQLite has no zone-aware type; datetime('now') is always UTC
+		sql.WriteString("datetime('now')")
 	case types.DateCurrentDate:
 		sql.WriteString("DATE('now')")
 	case types.DateCurrentTime:
@@ -1248,6 +2004,28 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 		sql.WriteString("', ")
 		sql.WriteString(r.renderField(*expr.Field))
 		sql.WriteString(")")
+	case types.DateAdd, types.DateSub:
+		if err := expr.Validate(); err != nil {
+			return "", err
+		}
+		// datetime() takes a modifier string such as '7 days'; weeks and
+		// quarters have no modifier, so they scale to days and months
+		amount := ctx.addParam(*expr.Amount)
+		if expr.Function == types.DateSub {
+			amount = "-" + amount
+		}
+		scale, unit := r.datePartToModifier(expr.Part)
+		if scale > 1 {
+			// || binds tighter than *, so the product needs parentheses
+			amount = fmt.Sprintf("(%s * %d)", amount, scale)
+		}
+		sql.WriteString("datetime(")
+		sql.WriteString(r.renderField(*expr.Field))
+		sql.WriteString(", ")
+		sql.WriteString(amount)
+		sql.WriteString(" || ' ")
+		sql.WriteString(unit)
+		sql.WriteString("')")
 	default:
 		return "", fmt.Errorf("unsupported date function: %s", expr.Function)
 	}
@@ -1255,6 +2033,58 @@ func (r *Renderer) renderDateExpression(expr types.DateExpression, _ *renderCont
 	return sql.String(), nil
 }
 
+// renderDateDiff scales the julianday() difference, in fractional days, to
+// the Part and truncates it to whole units. Calendar parts are rejected by
+// validateDateDiff.
+func (r *Renderer) renderDateDiff(expr types.DateDiffExpression, ctx *renderContext) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	var end, start strings.Builder
+	if err := r.renderValue(expr.End, &end, ctx); err != nil {
+		return "", err
+	}
+	if err := r.renderValue(expr.Start, &start, ctx); err != nil {
+		return "", err
+	}
+
+	days := fmt.Sprintf("julianday(%s) - julianday(%s)", end.String(), start.String())
+	switch expr.Part {
+	case types.PartWeek:
+		days = fmt.Sprintf("(%s) / 7", days)
+	case types.PartHour:
+		days = fmt.Sprintf("(%s) * 24", days)
+	case types.PartMinute:
+		days = fmt.Sprintf("(%s) * 1440", days)
+	case types.PartSecond:
+		days = fmt.Sprintf("(%s) * 86400", days)
+	}
+	return fmt.Sprintf("CAST(%s AS INTEGER)", days), nil
+}
+
+// datePartToModifier maps an interval DatePart to a datetime() modifier
+// unit and the number of those units one part spans.
+func (r *Renderer) datePartToModifier(part types.DatePart) (int, string) {
+	switch part {
+	case types.PartYear:
+		return 1, "years"
+	case types.PartQuarter:
+		return 3, "months"
+	case types.PartMonth:
+		return 1, "months"
+	case types.PartWeek:
+		return 7, "days"
+	case types.PartHour:
+		return 1, "hours"
+	case types.PartMinute:
+		return 1, "minutes"
+	case types.PartSecond:
+		return 1, "seconds"
+	default:
+		return 1, "days"
+	}
+}
+
 // datePartToStrftime maps DatePart to SQLite strftime format for EXTRACT.
 func (r *Renderer) datePartToStrftime(part types.DatePart) string {
 	switch part {
@@ -1359,22 +2189,54 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		}
 	}
 
+	if expr.NullTreatment != "" {
+		treatment, err := r.renderNullTreatment(expr)
+		if err != nil {
+			return "", err
+		}
+		sql.WriteString(treatment)
+	}
+
+	// Render OVER clause. Named windows are expanded inline from the query's
+	// WINDOW definitions.
+	spec := expr.Window
+	if expr.WindowRef != "" {
+		named, ok := ctx.windows[expr.WindowRef]
+		if !ok {
+			return "", fmt.Errorf("undefined window '%s'", expr.WindowRef)
+		}
+		spec = named
+	}
 	sql.WriteString(" OVER (")
+	sql.WriteString(r.renderWindowSpec(spec, ctx))
+	sql.WriteString(")")
 
+	return sql.String(), nil
+}
+
+// renderNullTreatment renders IGNORE NULLS or RESPECT NULLS after the argument list.
+// SQLite accepts neither clause; RESPECT NULLS is its
+// default behavior and renders as nothing.
+func (r *Renderer) renderNullTreatment(expr types.WindowExpression) (string, error) {
+	return "", r.validateNullTreatment(expr)
+}
+
+// renderWindowSpec renders a window definition body: PARTITION BY, ORDER BY and frame.
+func (r *Renderer) renderWindowSpec(spec types.WindowSpec, ctx *renderContext) string {
 	var overParts []string
 
-	if len(expr.Window.PartitionBy) > 0 {
+	if len(spec.PartitionBy) > 0 {
 		var partitionFields []string
-		for _, field := range expr.Window.PartitionBy {
+		for _, field := range spec.PartitionBy {
 			partitionFields = append(partitionFields, r.renderField(field))
 		}
 		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionFields, ", "))
 	}
 
-	if len(expr.Window.OrderBy) > 0 {
+	if len(spec.OrderBy) > 0 {
 		var orderParts []string
-		for i := range expr.Window.OrderBy {
-			order := &expr.Window.OrderBy[i]
+		for i := range spec.OrderBy {
+			order := &spec.OrderBy[i]
 			var part string
 			if order.Operator != "" {
 				part = fmt.Sprintf("%s %s %s %s",
@@ -1393,20 +2255,28 @@ func (r *Renderer) renderWindowExpression(expr types.WindowExpression, ctx *rend
 		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
 	}
 
-	if expr.Window.FrameStart != "" {
-		framePart := "ROWS BETWEEN " + string(expr.Window.FrameStart) + " AND "
-		if expr.Window.FrameEnd != "" {
-			framePart += string(expr.Window.FrameEnd)
-		} else {
-			framePart += "CURRENT ROW"
+	if spec.FrameStart != "" {
+		end := string(types.FrameCurrentRow)
+		if spec.FrameEnd != "" {
+			end = r.renderFrameBound(spec.FrameEnd, spec.FrameEndOffset, ctx)
+		}
+		framePart := fmt.Sprintf("%s BETWEEN %s AND %s", spec.Unit(),
+			r.renderFrameBound(spec.FrameStart, spec.FrameStartOffset, ctx), end)
+		if spec.FrameExclude != "" {
+			framePart += " EXCLUDE " + string(spec.FrameExclude)
 		}
 		overParts = append(overParts, framePart)
 	}
 
-	sql.WriteString(strings.Join(overParts, " "))
-	sql.WriteString(")")
+	return strings.Join(overParts, " ")
+}
 
-	return sql.String(), nil
+// renderFrameBound renders a frame bound, prefixing offset bounds with their parameter.
+func (r *Renderer) renderFrameBound(bound types.FrameBound, offset *types.Param, ctx *renderContext) string {
+	if offset != nil {
+		return ctx.addParam(*offset) + " " + string(bound)
+	}
+	return string(bound)
 }
 
 func (r *Renderer) renderOperator(op types.Operator) string {
@@ -1435,6 +2305,10 @@ func (r *Renderer) renderOperator(op types.Operator) string {
 		return "EXISTS"
 	case types.NotExists:
 		return "NOT EXISTS"
+	case types.IsNotDistinctFrom:
+		return "IS" // SQLite's IS compares NULLs as equal
+	case types.IsDistinctFrom:
+		return "IS NOT"
 	default:
 		return string(op)
 	}
@@ -1453,5 +2327,11 @@ func (r *Renderer) Capabilities() render.Capabilities {
 		ArrayOperators:      false,
 		InArray:             false,
 		RowLocking:          render.RowLockingNone,
+		FullOuterJoin:       false, // Requires SQLite 3.39+; not assumed
+		RightJoin:           false, // Requires SQLite 3.39+; not assumed
+		SkipLocked:          false,
+		WithTies:            false,
+
+		GroupConcatTruncates: false,
 	}
 }
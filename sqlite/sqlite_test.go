@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -264,6 +265,25 @@ func TestRender_RejectsDistinctOn(t *testing.T) {
 	}
 }
 
+func TestRender_RejectsDefaultValue(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Literal: types.LiteralDefault}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for DEFAULT value, got nil")
+	}
+	if !strings.Contains(err.Error(), "DEFAULT values") {
+		t.Errorf("error = %q, want to contain 'DEFAULT values'", err.Error())
+	}
+}
+
 func TestRender_RejectsForUpdate(t *testing.T) {
 	r := New()
 	lock := types.LockForUpdate
@@ -305,6 +325,32 @@ func TestRender_RejectsIN(t *testing.T) {
 	}
 }
 
+func TestRender_InListCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.InListCondition{
+			Field:    types.Field{Name: "status"},
+			Operator: types.NotIn,
+			Values:   []types.Param{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE "status" NOT IN (:a, :b, :c)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "a,b,c" {
+		t.Errorf("RequiredParams = %v, want [a b c]", result.RequiredParams)
+	}
+}
+
 func TestRender_RejectsILIKE(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -327,6 +373,53 @@ func TestRender_RejectsILIKE(t *testing.T) {
 	}
 }
 
+func TestValidate_CollectsAllUnsupported(t *testing.T) {
+	r := New()
+	lock := types.LockForUpdate
+	ast := &types.AST{
+		Operation:  types.OpSelect,
+		Target:     types.Table{Name: "users"},
+		Fields:     []types.Field{{Name: "id"}},
+		DistinctOn: []types.Field{{Name: "email"}},
+		Lock:       &lock,
+		WhereClause: types.Condition{
+			Field:    types.Field{Name: "name"},
+			Operator: types.ILIKE,
+			Value:    types.Param{Name: "pattern"},
+		},
+	}
+
+	errs := r.Validate(ast)
+	if len(errs) != 3 {
+		t.Fatalf("Validate() returned %d errors, want 3: %v", len(errs), errs)
+	}
+	for i, want := range []string{"DISTINCT ON", "row-level locking", "ILIKE"} {
+		var unsupported render.UnsupportedFeatureError
+		if !errors.As(errs[i], &unsupported) {
+			t.Errorf("errs[%d] = %v, want UnsupportedFeatureError", i, errs[i])
+		}
+		if !strings.Contains(errs[i].Error(), want) {
+			t.Errorf("errs[%d] = %q, want to contain %q", i, errs[i].Error(), want)
+		}
+	}
+
+	_, err := r.Render(ast)
+	if err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Render() error = %v, want %v", err, errs[0])
+	}
+}
+
+func TestValidate_Supported(t *testing.T) {
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "id"}},
+	}
+	if errs := New().Validate(ast); errs != nil {
+		t.Errorf("Validate() = %v, want nil", errs)
+	}
+}
+
 func TestRender_RejectsRegex(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -878,6 +971,33 @@ func TestRender_DateNow(t *testing.T) {
 	}
 }
 
+func TestRender_DateNowTz(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateNowTz,
+				},
+				Alias: "current_time",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// SQLite uses DATETIME('now') instead of NOW()
+	expected := `SELECT datetime('now') AS "current_time" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
 func TestRender_DateCurrentDate(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -935,6 +1055,32 @@ func TestRender_DateExtract(t *testing.T) {
 	}
 }
 
+func TestRender_RejectsDateBin(t *testing.T) {
+	r := New()
+	createdAt := types.Field{Name: "created_at"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Date: &types.DateExpression{
+					Function: types.DateBin,
+					Field:    &createdAt,
+					Interval: &types.Param{Name: "bucket"},
+					Origin:   &types.Param{Name: "origin"},
+				},
+				Alias: "bucket_start",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "DATE_BIN" {
+		t.Errorf("Render() error = %v, want DATE_BIN UnsupportedFeatureError", err)
+	}
+}
+
 func TestRender_DateTrunc(t *testing.T) {
 	r := New()
 	createdAt := types.Field{Name: "created_at"}
@@ -1456,6 +1602,95 @@ func TestRender_LeftJoin(t *testing.T) {
 	}
 }
 
+func TestRender_RejectsFullOuterJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "name"}},
+		Joins: []types.Join{
+			{
+				Type:  types.FullJoin,
+				Table: types.Table{Name: "profiles"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "users"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "profiles"},
+				},
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for FULL OUTER JOIN, got nil")
+	}
+	if !strings.Contains(err.Error(), "FULL OUTER JOIN") {
+		t.Errorf("error = %q, want to contain 'FULL OUTER JOIN'", err.Error())
+	}
+}
+
+func TestRender_JoinUsing(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		joinType types.JoinType
+		expected string
+	}{
+		{"inner", types.InnerJoin, `SELECT u."name" FROM "users" u INNER JOIN "orders" o USING ("user_id", "tenant_id")`},
+		{"left", types.LeftJoin, `SELECT u."name" FROM "users" u LEFT JOIN "orders" o USING ("user_id", "tenant_id")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users", Alias: "u"},
+				Fields:    []types.Field{{Name: "name", Table: "u"}},
+				Joins: []types.Join{
+					{
+						Type:  tt.joinType,
+						Table: types.Table{Name: "orders", Alias: "o"},
+						Using: []types.Field{{Name: "user_id", Table: "u"}, {Name: "tenant_id"}},
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_JoinUsingWithOn(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "orders"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "id", Table: "users"},
+					Operator:   types.EQ,
+					RightField: types.Field{Name: "user_id", Table: "orders"},
+				},
+				Using: []types.Field{{Name: "user_id"}},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Fatal("expected error for join with both ON and USING, got nil")
+	}
+}
+
 func TestRender_MultipleJoins(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -1694,6 +1929,40 @@ func TestRender_OnConflictDoUpdate(t *testing.T) {
 	}
 }
 
+func TestRender_OnConflictPredicates(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpInsert,
+		Target:    types.Table{Name: "users"},
+		Values: []map[types.Field]types.Param{
+			{{Name: "email"}: {Name: "email_val"}, {Name: "name"}: {Name: "name_val"}},
+		},
+		OnConflict: &types.ConflictClause{
+			Columns:           []types.Field{{Name: "email"}},
+			ConflictPredicate: types.Condition{Field: types.Field{Name: "active"}, Operator: types.EQ, Value: types.Param{Name: "active"}},
+			Action:            types.DoUpdate,
+			Updates: map[types.Field]types.Param{
+				{Name: "name"}: {Name: "new_name"},
+			},
+			DoUpdateWhere: types.Condition{Field: types.Field{Name: "locked"}, Operator: types.EQ, Value: types.Param{Name: "locked"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `INSERT INTO "users" ("email", "name") VALUES (:email_val, :name_val) ON CONFLICT ("email") WHERE "active" = :active DO UPDATE SET "name" = :new_name WHERE "locked" = :locked`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantParams := "email_val,name_val,active,new_name,locked"
+	if got := strings.Join(result.RequiredParams, ","); got != wantParams {
+		t.Errorf("RequiredParams = %v, want %s", result.RequiredParams, wantParams)
+	}
+}
+
 func TestRender_FieldWithTable(t *testing.T) {
 	r := New()
 	ast := &types.AST{
@@ -1810,6 +2079,44 @@ func TestRenderCompound_Except(t *testing.T) {
 	}
 }
 
+func TestRenderCompound_ExceptOrderByOutputColumn(t *testing.T) {
+	r := New()
+	query := &types.CompoundQuery{
+		Base: &types.AST{
+			Operation: types.OpSelect,
+			Target:    types.Table{Name: "users", Alias: "u"},
+			Fields:    []types.Field{{Name: "name", Table: "u"}},
+		},
+		Operands: []types.SetOperand{
+			{
+				Operation: types.SetExcept,
+				AST: &types.AST{
+					Operation: types.OpSelect,
+					Target:    types.Table{Name: "banned_users"},
+					Fields:    []types.Field{{Name: "name"}},
+				},
+			},
+		},
+		Ordering: []types.OrderBy{{Field: types.Field{Name: "name", Table: "u"}, Direction: types.ASC}},
+	}
+
+	result, err := r.RenderCompound(query)
+	if err != nil {
+		t.Fatalf("RenderCompound() error = %v", err)
+	}
+
+	// The qualifier is dropped: a set operation result has no table scope.
+	expected := `SELECT u."name" FROM "users" u EXCEPT SELECT "name" FROM "banned_users" ORDER BY "name" ASC`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+
+	query.Ordering[0].Field = types.Field{Name: "email"}
+	if _, err := r.RenderCompound(query); err == nil {
+		t.Error("expected error for ORDER BY a column the first query does not select")
+	}
+}
+
 func TestRenderCompound_WithOrderByLimit(t *testing.T) {
 	r := New()
 	limitVal := 10
@@ -1964,4 +2271,756 @@ func TestCapabilities(t *testing.T) {
 	if caps.RowLocking != render.RowLockingNone {
 		t.Errorf("RowLocking = %v, want RowLockingNone", caps.RowLocking)
 	}
+	if caps.FullOuterJoin {
+		t.Error("FullOuterJoin should be false")
+	}
+	if caps.RightJoin {
+		t.Error("RightJoin should be false")
+	}
+}
+
+func TestRender_RejectsGroupingModes(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"rollup", &types.AST{
+			Operation:   types.OpSelect,
+			Target:      types.Table{Name: "sales"},
+			GroupBy:     []types.Field{{Name: "region"}},
+			GroupByMode: types.GroupRollup,
+		}},
+		{"cube", &types.AST{
+			Operation:   types.OpSelect,
+			Target:      types.Table{Name: "sales"},
+			GroupBy:     []types.Field{{Name: "region"}},
+			GroupByMode: types.GroupCube,
+		}},
+		{"grouping sets", &types.AST{
+			Operation:    types.OpSelect,
+			Target:       types.Table{Name: "sales"},
+			GroupByMode:  types.GroupSets,
+			GroupingSets: [][]types.Field{{{Name: "region"}}, {}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Render(tt.ast)
+			if err == nil {
+				t.Fatal("expected unsupported feature error, got nil")
+			}
+			if !strings.Contains(err.Error(), string(tt.ast.GroupByMode)) {
+				t.Errorf("error = %q, want to contain %q", err.Error(), tt.ast.GroupByMode)
+			}
+		})
+	}
+}
+
+func TestRender_RejectsGrouping(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "sales"},
+		FieldExpressions: []types.FieldExpression{
+			{Grouping: &types.GroupingExpression{Fields: []types.Field{{Name: "region"}}}},
+		},
+		GroupBy: []types.Field{{Name: "region"}},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for GROUPING(), got nil")
+	}
+	if !strings.Contains(err.Error(), "GROUPING()") {
+		t.Errorf("error = %q, want to contain 'GROUPING()'", err.Error())
+	}
+}
+
+func TestRender_RejectsLateralJoin(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users", Alias: "u"},
+		Joins: []types.Join{
+			{
+				Type:     types.CrossJoin,
+				Table:    types.Table{Alias: "x"},
+				Subquery: &types.Subquery{AST: &types.AST{Operation: types.OpSelect, Target: types.Table{Name: "orders"}}},
+				Lateral:  true,
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for LATERAL, got nil")
+	}
+	if !strings.Contains(err.Error(), "LATERAL") {
+		t.Errorf("error = %q, want to contain 'LATERAL'", err.Error())
+	}
+}
+
+func TestRender_RejectsQuantifiedSubquery(t *testing.T) {
+	r := New()
+	priceField := types.Field{Name: "price"}
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "products"},
+		WhereClause: types.SubqueryCondition{
+			Field:      &priceField,
+			Operator:   types.GT,
+			Quantifier: types.QuantAll,
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "products"},
+				Fields:    []types.Field{{Name: "price"}},
+			}},
+		},
+	}
+
+	_, err := r.Render(ast)
+	if err == nil {
+		t.Fatal("expected error for ALL subquery, got nil")
+	}
+	if !strings.Contains(err.Error(), "ALL") {
+		t.Errorf("error = %q, want to contain 'ALL'", err.Error())
+	}
+}
+
+func TestRender_TupleEquality(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		WhereClause: types.TupleCondition{
+			Fields:   []types.Field{{Name: "id"}, {Name: "org_id"}},
+			Operator: types.EQ,
+			Values:   []types.Param{{Name: "id"}, {Name: "org"}},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT * FROM "users" WHERE ("id", "org_id") = (:id, :org)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObject(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id", Field: &types.Field{Name: "id"}},
+						{Key: "name", Field: &types.Field{Name: "name"}},
+						{Key: "source", Param: &types.Param{Name: "source"}},
+					},
+				},
+				Alias: "payload",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT json_object('id', "id", 'name', "name", 'source', :source) AS "payload" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_JSONObjectRejectsUnsafeKey(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				JSONObject: &types.JSONObjectExpression{
+					Pairs: []types.JSONObjectPair{
+						{Key: "id'); DROP TABLE users; --", Field: &types.Field{Name: "id"}},
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := r.Render(ast); err == nil {
+		t.Error("expected error for unsafe JSON object key")
+	}
+}
+
+func TestRender_NamedWindowInlined(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{Window: &types.WindowExpression{Function: types.WinRowNumber, WindowRef: "w"}, Alias: "rn"},
+			{Window: &types.WindowExpression{Aggregate: types.AggSum, Field: &types.Field{Name: "total"}, WindowRef: "w"}, Alias: "running"},
+		},
+		Windows: map[string]types.WindowSpec{
+			"w": {
+				PartitionBy: []types.Field{{Name: "user_id"}},
+				OrderBy:     []types.OrderBy{{Field: types.Field{Name: "created_at"}, Direction: types.ASC}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "created_at" ASC) AS "rn", SUM("total") OVER (PARTITION BY "user_id" ORDER BY "created_at" ASC) AS "running" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_NullSafeJoinCondition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "ledger", Alias: "a"},
+		Fields:    []types.Field{{Name: "id"}},
+		Joins: []types.Join{
+			{
+				Type:  types.InnerJoin,
+				Table: types.Table{Name: "bank", Alias: "b"},
+				On: types.FieldComparison{
+					LeftField:  types.Field{Name: "ref", Table: "a"},
+					Operator:   types.IsNotDistinctFrom,
+					RightField: types.Field{Name: "ref", Table: "b"},
+				},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "ledger" a INNER JOIN "bank" b ON a."ref" IS b."ref"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_CastWithTypeParams(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "amount"}, CastType: types.CastNumeric, Precision: 10, Scale: 2},
+				Alias: "amt",
+			},
+			{
+				Cast:  &types.CastExpression{Field: types.Field{Name: "name"}, CastType: types.CastVarchar, Length: 50},
+				Alias: "short",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT CAST("amount" AS REAL) AS "amt", CAST("name" AS TEXT) AS "short" FROM "orders"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_QuestionPlaceholders(t *testing.T) {
+	r := NewWithOptions(Options{Placeholder: render.PlaceholderQuestion})
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "events"},
+		Fields:    []types.Field{{Name: "id"}},
+		WhereClause: types.ConditionGroup{
+			Logic: types.OR,
+			Conditions: []types.ConditionItem{
+				types.Condition{Field: types.Field{Name: "starts_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+				types.Condition{Field: types.Field{Name: "kind"}, Operator: types.EQ, Value: types.Param{Name: "kind"}},
+				types.Condition{Field: types.Field{Name: "ends_at"}, Operator: types.GE, Value: types.Param{Name: "since"}},
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "id" FROM "events" WHERE ("starts_at" >= ? OR "kind" = ? OR "ends_at" >= ?)`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	wantPositional := []string{"since", "kind", "since"}
+	if strings.Join(result.PositionalParams, ",") != strings.Join(wantPositional, ",") {
+		t.Errorf("PositionalParams = %v, want %v", result.PositionalParams, wantPositional)
+	}
+	if len(result.RequiredParams) != 2 {
+		t.Errorf("RequiredParams = %v, want 2 unique names", result.RequiredParams)
+	}
+}
+
+func TestRender_RejectsArrayConstructor(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{
+			name: "select",
+			ast: &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "posts"},
+				FieldExpressions: []types.FieldExpression{{
+					Array: &types.ArrayExpression{Values: []types.Param{{Name: "a"}, {Name: "b"}}},
+				}},
+			},
+		},
+		{
+			name: "insert",
+			ast: &types.AST{
+				Operation: types.OpInsert,
+				Target:    types.Table{Name: "posts"},
+				Values:    []map[types.Field]types.Param{{{Name: "title"}: {Name: "title"}}},
+				ValueArrays: []map[types.Field]types.ArrayExpression{{
+					{Name: "tags"}: {Values: []types.Param{{Name: "tag"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Render(tt.ast)
+			if err == nil {
+				t.Fatal("expected error for ARRAY constructor, got nil")
+			}
+			if !strings.Contains(err.Error(), "ARRAY constructors") {
+				t.Errorf("error = %q, want to contain 'ARRAY constructors'", err.Error())
+			}
+		})
+	}
+}
+
+func TestRender_RejectsTruncate(t *testing.T) {
+	r := New()
+
+	_, err := r.Render(&types.AST{Operation: types.OpTruncate, Target: types.Table{Name: "staging"}})
+	if err == nil {
+		t.Fatal("expected error for TRUNCATE, got nil")
+	}
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("error = %v, want UnsupportedFeatureError", err)
+	}
+	if !strings.Contains(err.Error(), "Delete without a WHERE clause") {
+		t.Errorf("error = %q, want guidance to use Delete", err.Error())
+	}
+}
+
+func TestRender_WithTies(t *testing.T) {
+	limit := 3
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "scores"},
+		Ordering:  []types.OrderBy{{Field: types.Field{Name: "score"}, Direction: types.DESC}},
+		Limit:     &types.PaginationValue{Static: &limit},
+		WithTies:  true,
+	}
+
+	_, err := New().Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || !strings.Contains(unsupported.Feature, "WITH TIES") {
+		t.Errorf("Render() error = %v, want WITH TIES UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_RejectsStringFormatting(t *testing.T) {
+	r := New()
+	padArgs := []types.Param{{Name: "width"}, {Name: "fill"}}
+	tests := []struct {
+		function types.StringFunc
+		args     []types.Param
+	}{
+		{types.StringLPad, padArgs},
+		{types.StringRPad, padArgs},
+		{types.StringReverse, nil},
+		{types.StringInitcap, nil},
+		{types.StringSplitPart, []types.Param{{Name: "delim"}, {Name: "part"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.function), func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "users"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						String: &types.StringExpression{
+							Function: tt.function,
+							Field:    types.Field{Name: "name"},
+							Args:     tt.args,
+						},
+						Alias: "formatted",
+					},
+				},
+			}
+
+			_, err := r.Render(ast)
+			var unsupported render.UnsupportedFeatureError
+			if !errors.As(err, &unsupported) || unsupported.Feature != string(tt.function) {
+				t.Errorf("Render() error = %v, want %s UnsupportedFeatureError", err, tt.function)
+			}
+		})
+	}
+}
+
+func TestRender_ValuesTable(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target: types.Table{
+			Alias: "t",
+			Values: &types.ValuesTable{
+				Columns: []string{"id", "name"},
+				Rows: [][]types.Param{
+					{{Name: "id1"}, {Name: "name1"}},
+					{{Name: "id2"}, {Name: "name2"}},
+				},
+			},
+		},
+		Fields: []types.Field{{Name: "id", Table: "t"}, {Name: "name", Table: "t"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT t."id", t."name" FROM (SELECT :id1 AS "id", :name1 AS "name" UNION ALL SELECT :id2, :name2) AS t`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if got := strings.Join(result.RequiredParams, ","); got != "id1,name1,id2,name2" {
+		t.Errorf("RequiredParams = %v, want [id1 name1 id2 name2]", result.RequiredParams)
+	}
+}
+
+func TestRender_StringPosition(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				String: &types.StringExpression{
+					Function: types.StringPosition,
+					Field:    types.Field{Name: "email"},
+					Args:     []types.Param{{Name: "needle"}},
+				},
+				Alias: "at",
+			},
+		},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT INSTR("email", :needle) AS "at" FROM "users"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+}
+
+func TestRender_StringAgg(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		Fields:    []types.Field{{Name: "team"}},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggStringAgg,
+					Field:     types.Field{Name: "name"},
+					Separator: &types.Param{Name: "sep"},
+					OrderBy:   nil,
+				},
+				Alias: "names",
+			},
+		},
+		GroupBy: []types.Field{{Name: "team"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `SELECT "team", GROUP_CONCAT("name", :sep) AS "names" FROM "users" GROUP BY "team"`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "sep" {
+		t.Errorf("RequiredParams = %v, want [sep]", result.RequiredParams)
+	}
+}
+
+func TestRender_StringAgg_RejectsOrderBy(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "users"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				OrderedAgg: &types.OrderedAggregate{
+					Function:  types.AggStringAgg,
+					Field:     types.Field{Name: "name"},
+					Separator: &types.Param{Name: "sep"},
+					OrderBy:   []types.OrderBy{{Field: types.Field{Name: "name"}, Direction: types.ASC}},
+				},
+				Alias: "names",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "ORDER BY in aggregate" {
+		t.Errorf("Render() error = %v, want ORDER BY in aggregate UnsupportedFeatureError", err)
+	}
+}
+
+func TestRender_DateAdd(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		function types.DateFunc
+		part     types.DatePart
+		expected string
+	}{
+		{"add days", types.DateAdd, types.PartDay, `SELECT datetime("created_at", :days || ' days') AS "due" FROM "orders"`},
+		{"subtract days", types.DateSub, types.PartDay, `SELECT datetime("created_at", -:days || ' days') AS "due" FROM "orders"`},
+		{"add weeks", types.DateAdd, types.PartWeek, `SELECT datetime("created_at", (:days * 7) || ' days') AS "due" FROM "orders"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						Date: &types.DateExpression{
+							Function: tt.function,
+							Field:    &types.Field{Name: "created_at"},
+							Part:     tt.part,
+							Amount:   &types.Param{Name: "days"},
+						},
+						Alias: "due",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if len(result.RequiredParams) != 1 || result.RequiredParams[0] != "days" {
+				t.Errorf("RequiredParams = %v, want [days]", result.RequiredParams)
+			}
+		})
+	}
+}
+
+func TestRender_UpdateSetRow(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpUpdate,
+		Target:    types.Table{Name: "accounts"},
+		Updates:   map[types.Field]types.Param{{Name: "note"}: {Name: "note"}},
+		UpdateRows: []types.RowUpdate{{
+			Fields: []types.Field{{Name: "plan"}, {Name: "seats"}},
+			Subquery: types.Subquery{AST: &types.AST{
+				Operation:   types.OpSelect,
+				Target:      types.Table{Name: "plans"},
+				Fields:      []types.Field{{Name: "name"}, {Name: "seats"}},
+				WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "plan_id"}},
+			}},
+		}},
+		WhereClause: types.Condition{Field: types.Field{Name: "id"}, Operator: types.EQ, Value: types.Param{Name: "id"}},
+	}
+
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `UPDATE "accounts" SET "note" = :note, ("plan", "seats") = (SELECT "name", "seats" FROM "plans" WHERE "id" = :sq1_plan_id) WHERE "id" = :id`
+	if result.SQL != expected {
+		t.Errorf("SQL = %q, want %q", result.SQL, expected)
+	}
+	if strings.Join(result.RequiredParams, ",") != "note,sq1_plan_id,id" {
+		t.Errorf("RequiredParams = %v, want [note sq1_plan_id id]", result.RequiredParams)
+	}
+}
+
+func TestRender_DateDiff(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name     string
+		part     types.DatePart
+		end      types.Value
+		expected string
+		param    string
+	}{
+		{"day", types.PartDay, types.Field{Name: "shipped_at"}, `SELECT CAST(julianday("shipped_at") - julianday("created_at") AS INTEGER) AS "elapsed" FROM "orders"`, ""},
+		{"hour", types.PartHour, types.Param{Name: "now"}, `SELECT CAST((julianday(:now) - julianday("created_at")) * 24 AS INTEGER) AS "elapsed" FROM "orders"`, "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast := &types.AST{
+				Operation: types.OpSelect,
+				Target:    types.Table{Name: "orders"},
+				FieldExpressions: []types.FieldExpression{
+					{
+						DateDiff: &types.DateDiffExpression{
+							End:   tt.end,
+							Start: types.Field{Name: "created_at"},
+							Part:  tt.part,
+						},
+						Alias: "elapsed",
+					},
+				},
+			}
+
+			result, err := r.Render(ast)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("SQL = %q, want %q", result.SQL, tt.expected)
+			}
+			if strings.Join(result.RequiredParams, ",") != tt.param {
+				t.Errorf("RequiredParams = %v, want [%s]", result.RequiredParams, tt.param)
+			}
+		})
+	}
+}
+
+func TestRender_DateDiff_RejectsCalendarPart(t *testing.T) {
+	r := New()
+	ast := &types.AST{
+		Operation: types.OpSelect,
+		Target:    types.Table{Name: "orders"},
+		FieldExpressions: []types.FieldExpression{
+			{
+				DateDiff: &types.DateDiffExpression{
+					End:   types.Field{Name: "shipped_at"},
+					Start: types.Field{Name: "created_at"},
+					Part:  types.PartMonth,
+				},
+				Alias: "elapsed",
+			},
+		},
+	}
+
+	_, err := r.Render(ast)
+	var unsupported render.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != "DATE_DIFF in MONTH units" {
+		t.Errorf("error = %v, want DATE_DIFF in MONTH units unsupported", err)
+	}
+}
+
+func TestValidate_AgreesWithRender(t *testing.T) {
+	r := New()
+	users := types.Table{Name: "users"}
+	name := types.Field{Name: "name"}
+	age := types.Field{Name: "age"}
+	created := types.Field{Name: "created_at"}
+	selecting := func(expr types.FieldExpression) *types.AST {
+		return &types.AST{Operation: types.OpSelect, Target: users, FieldExpressions: []types.FieldExpression{expr}}
+	}
+
+	tests := []struct {
+		name string
+		ast  *types.AST
+	}{
+		{"grouping", selecting(types.FieldExpression{Grouping: &types.GroupingExpression{Fields: []types.Field{name}}})},
+		{"array", selecting(types.FieldExpression{Array: &types.ArrayExpression{Values: []types.Param{{Name: "tag"}}}})},
+		{"power", selecting(types.FieldExpression{Math: &types.MathExpression{Function: types.MathPower, Field: age, Exponent: &types.Param{Name: "exp"}}})},
+		{"sqrt", selecting(types.FieldExpression{Math: &types.MathExpression{Function: types.MathSqrt, Field: age}})},
+		{"lpad", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringLPad, Field: name, Args: []types.Param{{Name: "n"}, {Name: "fill"}}}})},
+		{"reverse", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringReverse, Field: name}})},
+		{"initcap", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+		{"split part", selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringSplitPart, Field: name, Args: []types.Param{{Name: "delim"}, {Name: "n"}}}})},
+		{"date bin", selecting(types.FieldExpression{Date: &types.DateExpression{Function: types.DateBin, Field: &created, Interval: &types.Param{Name: "width"}, Origin: &types.Param{Name: "origin"}}})},
+		{"date diff months", selecting(types.FieldExpression{DateDiff: &types.DateDiffExpression{End: created, Start: created, Part: types.PartMonth}})},
+		{"ignore nulls", selecting(types.FieldExpression{Window: &types.WindowExpression{
+			Function:      types.WinLag,
+			Field:         &age,
+			Window:        types.WindowSpec{OrderBy: []types.OrderBy{{Field: created, Direction: types.ASC}}},
+			NullTreatment: types.NullsIgnore,
+		}})},
+		{"array agg", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggArrayAgg, Field: name}})},
+		{"ordered string agg", selecting(types.FieldExpression{OrderedAgg: &types.OrderedAggregate{Function: types.AggStringAgg, Field: name, Separator: &types.Param{Name: "sep"}, OrderBy: []types.OrderBy{{Field: name, Direction: types.ASC}}}})},
+		{"distinct on", &types.AST{Operation: types.OpSelect, Target: users, Fields: []types.Field{name}, DistinctOn: []types.Field{name}}},
+		{"nested in arithmetic", selecting(types.FieldExpression{Arithmetic: &types.ArithmeticExpression{
+			Left:     types.ArithmeticOperand{Expr: &types.FieldExpression{Math: &types.MathExpression{Function: types.MathSqrt, Field: age}}},
+			Operator: types.ArithAdd,
+			Right:    types.ArithmeticOperand{Param: &types.Param{Name: "n"}},
+		}})},
+		{"nested in subquery", &types.AST{
+			Operation: types.OpSelect,
+			Target:    users,
+			Fields:    []types.Field{name},
+			WhereClause: types.SubqueryCondition{
+				Field:    &name,
+				Operator: types.IN,
+				Subquery: types.Subquery{AST: selecting(types.FieldExpression{String: &types.StringExpression{Function: types.StringInitcap, Field: name}})},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := r.Validate(tt.ast)
+			if len(errs) == 0 {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			_, err := r.Render(tt.ast)
+			if err == nil || err.Error() != errs[0].Error() {
+				t.Errorf("Render() error = %v, want %v", err, errs[0])
+			}
+		})
+	}
 }
@@ -0,0 +1,143 @@
+package astql
+
+import (
+	"github.com/zoobzio/astql/internal/render"
+	"github.com/zoobzio/astql/internal/types"
+)
+
+// Capabilities describes the SQL features supported by a dialect.
+type Capabilities = render.Capabilities
+
+// SupportedBy returns the renderers whose Capabilities cover every feature
+// the AST uses, in the order given, without rendering. It checks DISTINCT
+// ON, ON CONFLICT, RETURNING, ILIKE, regex and array operators, IN with an
+// array param, row locks and SKIP LOCKED, and RIGHT/FULL OUTER JOIN,
+// including inside subqueries. Use it to fail fast when configuring
+// dialects. A renderer it returns can still reject a feature Capabilities
+// does not describe, so Render remains the final check.
+func SupportedBy(ast *types.AST, renderers ...Renderer) []Renderer {
+	if ast == nil {
+		return nil
+	}
+	var needs Capabilities
+	requireAST(ast, &needs)
+
+	var supported []Renderer
+	for _, r := range renderers {
+		if satisfies(r.Capabilities(), needs) {
+			supported = append(supported, r)
+		}
+	}
+	return supported
+}
+
+// satisfies reports whether have covers every feature set in needs.
+func satisfies(have, needs Capabilities) bool {
+	missing := func(need, has bool) bool { return need && !has }
+	switch {
+	case missing(needs.DistinctOn, have.DistinctOn),
+		missing(needs.Upsert, have.Upsert),
+		missing(needs.ReturningOnInsert, have.ReturningOnInsert),
+		missing(needs.ReturningOnUpdate, have.ReturningOnUpdate),
+		missing(needs.ReturningOnDelete, have.ReturningOnDelete),
+		missing(needs.CaseInsensitiveLike, have.CaseInsensitiveLike),
+		missing(needs.RegexOperators, have.RegexOperators),
+		missing(needs.ArrayOperators, have.ArrayOperators),
+		missing(needs.InArray, have.InArray),
+		missing(needs.FullOuterJoin, have.FullOuterJoin),
+		missing(needs.RightJoin, have.RightJoin),
+		missing(needs.SkipLocked, have.SkipLocked),
+		missing(needs.WithTies, have.WithTies):
+		return false
+	}
+	return have.RowLocking >= needs.RowLocking
+}
+
+// requireAST records the features used by ast and its subqueries.
+func requireAST(ast *types.AST, needs *Capabilities) {
+	if len(ast.DistinctOn) > 0 {
+		needs.DistinctOn = true
+	}
+	if ast.OnConflict != nil {
+		needs.Upsert = true
+	}
+	if len(ast.Returning) > 0 {
+		switch ast.Operation {
+		case types.OpInsert:
+			needs.ReturningOnInsert = true
+		case types.OpUpdate:
+			needs.ReturningOnUpdate = true
+		case types.OpDelete:
+			needs.ReturningOnDelete = true
+		}
+	}
+	if ast.Lock != nil {
+		level := render.RowLockingBasic
+		if *ast.Lock == types.LockForNoKeyUpdate || *ast.Lock == types.LockForKeyShare {
+			level = render.RowLockingFull
+		}
+		needs.RowLocking = max(needs.RowLocking, level)
+	}
+	if ast.WithTies {
+		needs.WithTies = true
+	}
+	if ast.SkipLocked {
+		needs.SkipLocked = true
+	}
+
+	conds := append([]types.ConditionItem{ast.WhereClause}, ast.Having...)
+	for _, join := range ast.Joins {
+		switch join.Type {
+		case types.RightJoin:
+			needs.RightJoin = true
+		case types.FullOuterJoin:
+			needs.FullOuterJoin = true
+		}
+		conds = append(conds, join.On)
+	}
+	for _, expr := range ast.FieldExpressions {
+		conds = append(conds, expr.Filter)
+		if expr.OrderedAgg != nil && expr.OrderedAgg.Function == types.AggArrayAgg {
+			needs.ArrayOperators = true
+		}
+	}
+	for _, cond := range conds {
+		requireCondition(cond, needs)
+	}
+
+	for _, sub := range ast.Subqueries() {
+		requireAST(sub, needs)
+	}
+}
+
+// requireCondition records the operators used by a condition tree.
+func requireCondition(cond types.ConditionItem, needs *Capabilities) {
+	switch c := cond.(type) {
+	case types.Condition:
+		requireOperator(c.Operator, needs)
+		if (c.Operator == types.IN || c.Operator == types.NotIn) && !c.Value.IsLiteral() {
+			needs.InArray = true
+		}
+	case types.AggregateCondition:
+		requireOperator(c.Operator, needs)
+	case types.FieldComparison:
+		requireOperator(c.Operator, needs)
+	case types.Comparison:
+		requireOperator(c.Operator, needs)
+	case types.ConditionGroup:
+		for _, sub := range c.Conditions {
+			requireCondition(sub, needs)
+		}
+	}
+}
+
+func requireOperator(op types.Operator, needs *Capabilities) {
+	switch op {
+	case types.ILIKE, types.NotILike:
+		needs.CaseInsensitiveLike = true
+	case types.RegexMatch, types.RegexIMatch, types.NotRegexMatch, types.NotRegexIMatch:
+		needs.RegexOperators = true
+	case types.ArrayContains, types.ArrayContainedBy, types.ArrayOverlap:
+		needs.ArrayOperators = true
+	}
+}
@@ -0,0 +1,92 @@
+package astql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/mariadb"
+	"github.com/zoobzio/astql/mssql"
+	"github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/astql/sqlite"
+)
+
+// supportedNames returns the dialect name of each renderer.
+func supportedNames(renderers []astql.Renderer) []string {
+	names := make([]string, 0, len(renderers))
+	for _, r := range renderers {
+		switch r.(type) {
+		case *postgres.Renderer:
+			names = append(names, "postgres")
+		case *mariadb.Renderer:
+			names = append(names, "mariadb")
+		case *sqlite.Renderer:
+			names = append(names, "sqlite")
+		case *mssql.Renderer:
+			names = append(names, "mssql")
+		}
+	}
+	return names
+}
+
+func TestSupportedBy(t *testing.T) {
+	instance := createRenderTestInstance(t)
+	all := []astql.Renderer{postgres.New(), mariadb.New(), sqlite.New(), mssql.New()}
+
+	tests := []struct {
+		name    string
+		builder *astql.Builder
+		want    string
+	}{
+		{
+			name:    "portable",
+			builder: astql.Select(instance.T("users")).Where(instance.C(instance.F("id"), astql.EQ, instance.P("id"))),
+			want:    "postgres,mariadb,sqlite,mssql",
+		},
+		{
+			name:    "ILIKE",
+			builder: astql.Select(instance.T("users")).Where(instance.C(instance.F("username"), astql.ILIKE, instance.P("pattern"))),
+			want:    "postgres,mariadb",
+		},
+		{
+			name: "ILIKE in subquery",
+			builder: astql.Select(instance.T("posts")).
+				Where(astql.CSub(instance.F("user_id"), astql.IN, astql.Sub(
+					astql.Select(instance.T("users")).
+						Fields(instance.F("id")).
+						Where(instance.C(instance.F("email"), astql.NotILike, instance.P("domain"))),
+				))),
+			want: "postgres,mariadb",
+		},
+		{
+			name:    "ARRAY_AGG",
+			builder: astql.Select(instance.T("posts")).SelectExpr(astql.ArrayAgg(instance.F("title")).As("titles")),
+			want:    "postgres",
+		},
+		{
+			name:    "DISTINCT ON",
+			builder: astql.Select(instance.T("users")).DistinctOn(instance.F("email")),
+			want:    "postgres",
+		},
+		{
+			name: "RETURNING on UPDATE",
+			builder: astql.Update(instance.T("users")).
+				Set(instance.F("email"), instance.P("email")).
+				Returning(instance.F("id")),
+			want: "postgres,sqlite",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			got := strings.Join(supportedNames(astql.SupportedBy(ast, all...)), ",")
+			if got != tt.want {
+				t.Errorf("SupportedBy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}